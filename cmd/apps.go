@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// appsUnusedForFlag limits `apps list` to apps not opened for this long (e.g., "90d").
+var appsUnusedForFlag string
+
+// appStoreOnlyFlag limits `apps list` to apps installed from the Mac App Store.
+var appStoreOnlyFlag bool
+
+// appsSortFlag is the column `apps list` is sorted by: size, data, total, name or last-used.
+var appsSortFlag string
+
+// appsSortOrders compares two apps for each value of --sort. Sizes sort largest first and
+// last use oldest first, so the best candidates for removal come first.
+var appsSortOrders = map[string]func(a, b cleaner.InstalledApp) bool{
+	"size":  func(a, b cleaner.InstalledApp) bool { return a.Size > b.Size },
+	"data":  func(a, b cleaner.InstalledApp) bool { return a.DataSize > b.DataSize },
+	"total": func(a, b cleaner.InstalledApp) bool { return a.Size+a.DataSize > b.Size+b.DataSize },
+	"name":  func(a, b cleaner.InstalledApp) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) },
+	"last-used": func(a, b cleaner.InstalledApp) bool {
+		return a.LastUsed.Before(b.LastUsed)
+	},
+}
+
+// ====================================================================================================
+// APPS COMMAND DEFINITION
+// ====================================================================================================
+
+// appsCmd groups the commands that inspect installed applications.
+var appsCmd = &cobra.Command{
+	Use:   "apps",
+	Short: "Inspect installed applications.",
+}
+
+// appsListCmd lists installed applications with their size, last use, and source.
+var appsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed applications with size, data size, last use, and source.",
+	Long: `The 'apps list' command lists the application bundles in /Applications and ~/Applications,
+largest first, with the date each app was last opened. The DATA column adds up what the app keeps
+outside its bundle (support files, caches, preferences, containers), which 'wiper wipe' removes
+together with the app.
+
+The source tells how an app was installed: from the Mac App Store, by a Homebrew cask, or manually.
+When the 'mas' CLI is installed (https://github.com/mas-cli/mas), the App Store ID and version are
+shown as well. App Store apps are tied to your Apple ID, so they can be reinstalled from the App
+Store at no cost after removal. Cask apps are better removed with 'brew uninstall --cask'.
+
+Use '--unused-for' to only report apps that haven't been opened for a while, and '--sort' to sort
+by size (default), data, total, name or last-used.`,
+	Example: `
+ wiper apps list
+ wiper apps list --unused-for 90d
+ wiper apps list --sort total
+ wiper apps list --app-store`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var unusedFor time.Duration
+		if appsUnusedForFlag != "" {
+			d, err := utils.ParseDuration(appsUnusedForFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --unused-for: %w", err)
+			}
+			unusedFor = d
+		}
+
+		less, ok := appsSortOrders[appsSortFlag]
+		if !ok {
+			return fmt.Errorf("invalid --sort %q: use size, data, total, name or last-used", appsSortFlag)
+		}
+
+		apps, err := cleaner.ListApplications()
+		if err != nil {
+			return fmt.Errorf("failed to list applications: %w", err)
+		}
+		logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+		sort.SliceStable(apps, func(i, j int) bool { return less(apps[i], apps[j]) })
+
+		cutoff := time.Now().Add(-unusedFor)
+		var rows [][]interface{}
+		var total, dataTotal, reinstallable int64
+		var appStoreCount int
+		for _, app := range apps {
+			if appStoreOnlyFlag && !app.AppStore {
+				continue
+			}
+			// Apps without a known last use are kept, since they may never have been opened.
+			if unusedFor > 0 && app.LastUsed.After(cutoff) {
+				continue
+			}
+
+			source := "manual"
+			if app.Cask != "" {
+				source = fmt.Sprintf("Homebrew (%s)", app.Cask)
+			}
+			if app.AppStore {
+				source = "App Store"
+				if app.AppStoreID != "" {
+					source = fmt.Sprintf("App Store (%s)", app.AppStoreID)
+				}
+				appStoreCount++
+				reinstallable += app.Size
+			}
+			lastUsed := "never"
+			if !app.LastUsed.IsZero() {
+				lastUsed = app.LastUsed.Format("2006-01-02")
+			}
+			version := app.Version
+			if version == "" {
+				version = "-"
+			}
+			rows = append(rows, []interface{}{app.Name, source, version, lastUsed,
+				utils.Yellow(reclaimer.FormatBytes(app.Size)), utils.Yellow(reclaimer.FormatBytes(app.DataSize))})
+			total += app.Size
+			dataTotal += app.DataSize
+		}
+
+		if len(rows) == 0 {
+			logger.Log.Info("No matching applications found.")
+			return nil
+		}
+
+		title := "Installed Applications"
+		if unusedFor > 0 {
+			title = fmt.Sprintf("Applications Unused for %s", appsUnusedForFlag)
+		}
+		reclaimer.PrintListTable(title, []string{"NAME", "SOURCE", "VERSION", "LAST USED", "SIZE", "DATA"}, rows,
+			[]interface{}{utils.Blue("TOTAL"), "", "", "", utils.Blue(reclaimer.FormatBytes(total)), utils.Blue(reclaimer.FormatBytes(dataTotal))})
+		println()
+
+		if appStoreCount > 0 {
+			logger.Log.Infof("%d App Store app(s) (%s) are owned by your Apple ID and can be reinstalled from the App Store at no cost.",
+				appStoreCount, reclaimer.FormatBytes(reinstallable))
+		}
+		logger.Log.Info(`Uninstall an application with: wiper wipe "<name>"`)
+		return nil
+	},
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the apps command and its subcommands with the root command.
+func init() {
+	RootCmd.AddCommand(appsCmd)
+	appsCmd.AddCommand(appsListCmd)
+
+	appsListCmd.Flags().StringVar(&appsUnusedForFlag, "unused-for", "", "Only list apps not opened for this long, e.g. 90d or 6w")
+	appsListCmd.Flags().BoolVar(&appStoreOnlyFlag, "app-store", false, "Only list apps installed from the Mac App Store")
+	appsListCmd.Flags().StringVar(&appsSortFlag, "sort", "size", "Sort by size, data, total, name or last-used")
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/i18n"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// backupsOlderThanFlag only offers backups last updated before this window (e.g., "180d").
+var backupsOlderThanFlag string
+
+// backupsListFlag only lists the backups, without offering to remove any.
+var backupsListFlag bool
+
+// ====================================================================================================
+// BACKUPS COMMAND DEFINITION
+// ====================================================================================================
+
+// backupsCmd represents the backups command.
+// It lists the local iPhone and iPad backups and removes the ones the user picks.
+var backupsCmd = &cobra.Command{
+	Use:   "backups",
+	Short: "List iPhone and iPad backups and remove old ones.",
+	Long: `The 'backups' command lists the iPhone, iPad and iPod touch backups Finder (or iTunes) keeps in
+~/Library/Application Support/MobileSync/Backup, with the device, its iOS version, the date of the
+backup and its size. A single backup often takes 10 to 60 GB, and backups of devices that were
+replaced or sold are kept forever.
+
+Then it asks for each backup, the oldest first, whether to remove it ('all' removes the rest, 'quit'
+stops). Use '--older-than' to only offer backups that weren't updated for a while, '--list' to only
+list them, and '--dry-run' or '--trash' as with the other cleanups. A removed backup can't be used
+to restore its device, so keep at least the latest backup of every device you still use.
+
+macOS protects the backup folder: give your terminal Full Disk Access in System Settings > Privacy &
+Security to read it.`,
+	Example: `
+ wiper backups --list
+ wiper backups --older-than 180d
+ wiper backups --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var olderThan time.Duration
+		if backupsOlderThanFlag != "" {
+			d, err := utils.ParseDuration(backupsOlderThanFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --older-than: %w", err)
+			}
+			olderThan = d
+		}
+
+		logger.Log.Info("Looking for device backups...")
+		backups, err := cleaner.FindDeviceBackups()
+		if err != nil {
+			return fmt.Errorf("failed to list device backups: %w", err)
+		}
+		logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+		if len(backups) == 0 {
+			logger.Log.Infof("No device backups found in %s.", cleaner.DeviceBackupDir())
+			cmd.SilenceUsage = true
+			return errNothingFound
+		}
+		printBackupsTable(backups)
+		if backupsListFlag {
+			return nil
+		}
+
+		var offered []cleaner.DeviceBackup
+		for _, backup := range backups {
+			if olderThan == 0 || time.Since(backup.Date) >= olderThan {
+				offered = append(offered, backup)
+			}
+		}
+		if len(offered) == 0 {
+			logger.Log.Infof("No device backups older than %s.", backupsOlderThanFlag)
+			cmd.SilenceUsage = true
+			return errNothingFound
+		}
+
+		ctx := cmd.Context()
+		history.SetMode("device backups")
+		summary := reclaimer.NewSummaryTable()
+		estimatedSummary := reclaimer.NewSummaryTable()
+		reclaimed, err := cleaner.CleanDeviceBackups(ctx, offered, dryRunFlag, summary, estimatedSummary)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		summary.PrintTable(false, i18n.T("summary.reclaimed_title"))
+		logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+		println()
+
+		if ctx.Err() != nil {
+			logger.Log.Warnf("Cleanup interrupted. Space reclaimed before stopping: %s", utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+			cmd.SilenceUsage = true
+			return fmt.Errorf("cleanup interrupted: %w", ctx.Err())
+		} else if dryRunFlag {
+			logger.Log.Infof(utils.CyanBold("Dry run: removing these backups would reclaim %s"), utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+			return nil
+		}
+		logger.Log.Infof("Cleanup completed. Space reclaimed: %s", utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+		if failed := summary.FailedCount(); failed > 0 {
+			cmd.SilenceUsage = true
+			return &cleanupFailedError{failed: failed}
+		}
+		if len(summary.ByStatus(reclaimer.StatusRemoved)) == 0 && allDeclined(summary.Skipped()) {
+			cmd.SilenceUsage = true
+			return errAborted
+		}
+		return nil
+	},
+}
+
+// printBackupsTable lists the backups with their device, the iOS version they were made with, their
+// date and size.
+func printBackupsTable(backups []cleaner.DeviceBackup) {
+	var rows [][]interface{}
+	var total int64
+	for i, backup := range backups {
+		model, version := backup.ProductType, backup.ProductVersion
+		if model == "" {
+			model = "-"
+		}
+		if version == "" {
+			version = "-"
+		}
+		rows = append(rows, []interface{}{i + 1, backup.DeviceName, model, version,
+			backup.Date.Format("2006-01-02 15:04"), utils.Yellow(reclaimer.FormatBytes(backup.Size))})
+		total += backup.Size
+	}
+	reclaimer.PrintListTable("Device Backups", []string{"#", "DEVICE", "MODEL", "VERSION", "LAST BACKUP", "SIZE"}, rows,
+		[]interface{}{"", utils.Blue("TOTAL"), "", "", "", utils.Blue(reclaimer.FormatBytes(total))})
+	println()
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the backups command with the root command.
+func init() {
+	RootCmd.AddCommand(backupsCmd)
+
+	// StringVar binds the --older-than flag to the backupsOlderThanFlag variable.
+	backupsCmd.Flags().StringVar(&backupsOlderThanFlag, "older-than", "", "Only offer backups last updated before this long ago, e.g. 90d or 6w")
+	// BoolVar binds the --list flag to the backupsListFlag variable.
+	backupsCmd.Flags().BoolVar(&backupsListFlag, "list", false, "Only list the backups, without offering to remove any")
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// CLEANERS COMMAND DEFINITION
+// ====================================================================================================
+
+// cleanersCmd represents the cleaners command.
+// It lists the built-in cleaners and those added by plugins or the configuration file.
+var cleanersCmd = &cobra.Command{
+	Use:   "cleaners",
+	Short: "List the built-in and registered cleaners.",
+	Long: `The 'cleaners' command lists the cleaners wiper knows: the built-in system, large file and
+application cleanups, and the cleaners registered in addition to them.
+
+Cleaners can be compiled into wiper (a Go file calling cleaner.Register from its init function,
+e.g. behind a build tag), or declared in the 'cleaners' list of the configuration file as commands
+that print the items to remove as JSON, e.g. {"path": "/Users/me/Library/Unity/cache",
+"category": "Unity Caches"}. The items of registered cleaners are cleaned with the system cleanup
+('wiper wipe'), with the same ignore list, iCloud and tag checks as the built-in targets.`,
+	Example: `
+ wiper cleaners
+ wiper wipe --dry-run   # includes the items of registered cleaners`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var rows [][]interface{}
+		for _, c := range cleaner.BuiltinCleaners() {
+			rows = append(rows, []interface{}{c.Name(), "built-in", c.Description()})
+		}
+		for _, c := range cleaner.Cleaners() {
+			rows = append(rows, []interface{}{c.Name(), "registered", c.Description()})
+		}
+		reclaimer.PrintListTable("Cleaners", []string{"NAME", "SOURCE", "DESCRIPTION"}, rows, nil)
+		return nil
+	},
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the cleaners command with the root command.
+func init() {
+	RootCmd.AddCommand(cleanersCmd)
+}
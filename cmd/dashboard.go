@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/kodelint/wiper/pkg/dashboard"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// dashboardAddrFlag is the loopback address the dashboard listens on.
+var dashboardAddrFlag string
+
+// ====================================================================================================
+// DASHBOARD COMMAND DEFINITION
+// ====================================================================================================
+
+// dashboardCmd represents the dashboard command.
+// It starts a local web server that shows disk status and reclaimable estimates,
+// and lets the user trigger predefined cleanup profiles from the browser.
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Start a local web dashboard for disk status and cleanup.",
+	Long: `The 'dashboard' command starts an HTTP server on localhost that renders:
+
+1.  Disk Status: Capacity and free space of your volumes.
+2.  Reclaimable Estimates: What the system cleanup would remove, per category.
+3.  History: The space reclaimed per day over the last 30 days (see 'wiper history').
+4.  Cleanup Profiles: Buttons to run the 'safe' or 'full' cleanup profile.
+
+The server only binds to loopback addresses and only answers requests addressed to them. Combine with '--dry-run' to simulate every cleanup.`,
+	Example: `
+ wiper dashboard
+ wiper dashboard --addr 127.0.0.1:9000 --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server, err := dashboard.NewServer(dashboardAddrFlag, dryRunFlag, IgnorePaths)
+		if err != nil {
+			return err
+		}
+		// Ctrl+C shuts the server down instead of killing a cleanup in the middle of an item.
+		ctx, stop := interruptContext(cmd.Context())
+		defer stop()
+		return server.ListenAndServe(ctx)
+	},
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the dashboard command with the root command.
+func init() {
+	RootCmd.AddCommand(dashboardCmd)
+
+	dashboardCmd.Flags().StringVar(&dashboardAddrFlag, "addr", "127.0.0.1:8421", "Loopback address for the dashboard server")
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/i18n"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// brewOrphansFlag lists Homebrew packages that nothing depends on and that haven't been used recently.
+var brewOrphansFlag bool
+
+// unusedForFlag is how long a package must go unused to be reported, in human-readable form (e.g., "90d").
+var unusedForFlag string
+
+// ====================================================================================================
+// DEV COMMAND DEFINITION
+// ====================================================================================================
+
+// devCmd represents the dev command.
+// It finds space held by developer tooling, which is not part of the regular system cleanup
+// because removing it may require reinstalling or rebuilding things.
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Find and remove unused developer tooling.",
+	Long: `The 'dev' command looks for disk space held by developer tools:
+
+1.  Homebrew Orphans: With '--brew-orphans', it lists formulae and casks that no installed package
+   depends on and that haven't been used for a while (see '--unused-for'), with their sizes, and
+   offers to uninstall them with 'brew uninstall'. Casks are judged by when their apps were last
+   opened; packages whose last use macOS doesn't know are kept.
+
+Use the '--dry-run' flag to only list what would be removed.`,
+	Example: `
+ # List Homebrew packages unused for 90 days and offer to uninstall them
+ wiper dev --brew-orphans
+
+ # Only list packages unused for half a year
+ wiper dev --brew-orphans --unused-for 180d --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !brewOrphansFlag {
+			return fmt.Errorf("nothing to do: choose a developer cleanup such as --brew-orphans")
+		}
+
+		var unusedFor time.Duration
+		if unusedForFlag != "" {
+			d, err := utils.ParseDuration(unusedForFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --unused-for: %w", err)
+			}
+			unusedFor = d
+		}
+
+		logger.Log.Info("Looking for unused Homebrew packages...")
+		orphans, err := cleaner.FindBrewOrphans(unusedFor)
+		if err != nil {
+			return fmt.Errorf("failed to list Homebrew packages: %w", err)
+		}
+		logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+		if len(orphans) == 0 {
+			logger.Log.Info("No unused Homebrew packages found.")
+			cmd.SilenceUsage = true
+			return errNothingFound
+		}
+
+		var total int64
+		rows := make([][]interface{}, 0, len(orphans))
+		for _, pkg := range orphans {
+			kind := "formula"
+			if pkg.Cask {
+				kind = "cask"
+			}
+			lastUsed := "unknown"
+			if !pkg.LastUsed.IsZero() {
+				lastUsed = pkg.LastUsed.Format("2006-01-02")
+			}
+			rows = append(rows, []interface{}{pkg.Name, kind, lastUsed, utils.Yellow(reclaimer.FormatBytes(pkg.Size))})
+			total += pkg.Size
+		}
+		reclaimer.PrintListTable("Unused Homebrew Packages", []string{"NAME", "TYPE", "LAST USED", "SIZE"}, rows,
+			[]interface{}{utils.Blue("TOTAL"), "", "", utils.Blue(reclaimer.FormatBytes(total))})
+		println()
+
+		summary := reclaimer.NewSummaryTable()
+		history.SetMode("brew orphans")
+		if dryRunFlag {
+			reclaimed := cleaner.RemoveBrewPackages(orphans, true, summary)
+			logger.Log.Infof(utils.CyanBold("Dry run: uninstalling these packages would reclaim %s"), utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+			return nil
+		}
+		if !cleaner.ConfirmAction(cmd.Context(), i18n.T("prompt.cleanup_all", reclaimer.FormatBytes(total))) {
+			logger.Log.Info("Cleanup cancelled by user.")
+			cmd.SilenceUsage = true
+			return errAborted
+		}
+
+		reclaimed := cleaner.RemoveBrewPackages(orphans, false, summary)
+		summary.PrintTable(false, i18n.T("summary.reclaimed_title"))
+		logger.Log.Infof("Cleanup completed. Space reclaimed: %s", utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+
+		if failed := summary.FailedCount(); failed > 0 {
+			cmd.SilenceUsage = true
+			return &cleanupFailedError{failed: failed}
+		}
+		return nil
+	},
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the dev command with the root command.
+func init() {
+	RootCmd.AddCommand(devCmd)
+
+	devCmd.Flags().BoolVar(&brewOrphansFlag, "brew-orphans", false, "List Homebrew formulae and casks nothing depends on and that haven't been used recently")
+	devCmd.Flags().StringVar(&unusedForFlag, "unused-for", "", "Minimum time since a package was last used, e.g. 30d or 6w (default 90d)")
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/i18n"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// diskNoEstimateFlag only lists the volumes, without scanning what the system cleanup would reclaim.
+var diskNoEstimateFlag bool
+
+// ====================================================================================================
+// DISK COMMAND DEFINITION
+// ====================================================================================================
+
+// diskCmd represents the disk command.
+// It gives an overview of the mounted volumes and of what a cleanup could reclaim on them.
+var diskCmd = &cobra.Command{
+	Use:   "disk",
+	Short: "Show the space of every volume and what wiper could reclaim.",
+	Long: `The 'disk' command lists the mounted volumes with their total, used and free space, and on
+macOS the purgeable space: what macOS frees by itself when space runs low (caches, local Time
+Machine snapshots, iCloud files that can be downloaded again). Finder counts it as available, df
+doesn't, which is why the two disagree.
+
+It then scans the system cleanup targets like 'wiper scan' and shows what each category would
+reclaim, and the RECLAIMABLE column attributes it to the volumes. Nothing is removed. Use
+'--no-estimate' to only list the volumes.`,
+	Example: `
+ wiper disk
+ wiper disk --no-estimate`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		volumes, err := utils.Volumes()
+		if err != nil {
+			return err
+		}
+
+		var estimate *reclaimer.SummaryTable
+		if !diskNoEstimateFlag {
+			logger.Log.Info("Scanning the system cleanup targets...")
+			estimate, err = cleaner.EstimateSystem(cmd.Context(), IgnorePaths)
+			if err != nil {
+				return fmt.Errorf("failed to scan the system: %w", err)
+			}
+		}
+
+		printVolumesTable(volumes, estimate)
+		if estimate != nil {
+			estimate.PrintTable(true, i18n.T("summary.estimated_title"))
+		}
+		logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+		return nil
+	},
+}
+
+// printVolumesTable lists the space of every volume. With an estimate, the items it would reclaim
+// are attributed to the volume they are on.
+func printVolumesTable(volumes []utils.Mount, estimate *reclaimer.SummaryTable) {
+	reclaimable := make(map[string]int64)
+	if estimate != nil {
+		for _, entry := range estimate.ByStatus(reclaimer.StatusDryRun) {
+			if volume, ok := utils.VolumeOf(entry.Path, volumes); ok {
+				reclaimable[volume.Path] += entry.SizeReclaimed
+			}
+		}
+	}
+
+	header := []string{"VOLUME", "FILESYSTEM", "TOTAL", "USED", "FREE"}
+	showPurgeable := runtime.GOOS == "darwin"
+	if showPurgeable {
+		header = append(header, "PURGEABLE")
+	}
+	if estimate != nil {
+		header = append(header, "RECLAIMABLE")
+	}
+	var rows [][]interface{}
+	for _, volume := range volumes {
+		row := []interface{}{volume.Path, volume.FSType}
+		free, total, err := utils.VolumeSpace(volume.Path)
+		if err != nil {
+			logger.Log.Debugf("Failed to read the space of %s: %v", volume.Path, err)
+			row = append(row, "-", "-", "-")
+		} else {
+			row = append(row, reclaimer.FormatBytes(total), reclaimer.FormatBytes(total-free),
+				utils.Green(reclaimer.FormatBytes(free)))
+		}
+		if showPurgeable {
+			purgeable, err := utils.PurgeableSpace(volume.Path)
+			if err != nil {
+				logger.Log.Debugf("Failed to read the purgeable space of %s: %v", volume.Path, err)
+				row = append(row, "-")
+			} else {
+				row = append(row, reclaimer.FormatBytes(purgeable))
+			}
+		}
+		if estimate != nil {
+			row = append(row, utils.Yellow(reclaimer.FormatBytes(reclaimable[volume.Path])))
+		}
+		rows = append(rows, row)
+	}
+	reclaimer.PrintListTable("Volumes", header, rows, nil)
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the disk command with the root command.
+func init() {
+	RootCmd.AddCommand(diskCmd)
+
+	diskCmd.Flags().BoolVar(&diskNoEstimateFlag, "no-estimate", false, "Only list the volumes, without scanning what a cleanup would reclaim")
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/analyzer"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// duExportFlag writes the scanned tree to this file in ncdu's JSON format ("-" for standard output).
+var duExportFlag string
+
+// duImportFlag reads a tree from an ncdu JSON export instead of scanning.
+var duImportFlag string
+
+// duDepthFlag is how many levels below the path entries are ranked (1 lists its own entries).
+var duDepthFlag int
+
+// duDirsFlag ranks directories only, leaving files out.
+var duDirsFlag bool
+
+// duTopFlag is the number of entries listed in the du table.
+var duTopFlag int
+
+// ====================================================================================================
+// DU COMMAND DEFINITION
+// ====================================================================================================
+
+// duCmd represents the du command.
+// It analyzes where disk space goes below a directory, and can exchange results with ncdu.
+var duCmd = &cobra.Command{
+	Use:   "du [path]",
+	Short: "Show which entries of a directory use the most disk space.",
+	Long: `The 'du' command scans a directory (the current directory by default) and lists its
+largest entries by disk usage. Directories are read concurrently (see '--jobs').
+
+Use '--depth' to rank the entries of subdirectories too, e.g. '--depth 3' for three levels or
+'--depth 0' for the whole tree, and '--dirs' to rank directories only: together they show which
+folders hold the space before deciding what to wipe. '--top' sets how many entries are listed.
+
+Results can be exchanged with ncdu (https://dev.yorhel.nl/ncdu):
+  --export writes the scanned tree in ncdu's JSON format, to be browsed with 'ncdu -f <file>'.
+  --import reads an export made by 'ncdu -o <file>' or 'wiper du --export', e.g. on another machine.`,
+	Example: `
+ wiper du ~/Library
+ wiper du ~ --dirs --depth 3 --top 30
+ wiper du / --export root.json
+ wiper du --import root.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if duDepthFlag < 0 {
+			return fmt.Errorf("invalid --depth %d: expected 0 (no limit) or more", duDepthFlag)
+		}
+		if duTopFlag < 1 {
+			return fmt.Errorf("invalid --top %d: expected 1 or more", duTopFlag)
+		}
+
+		var root *analyzer.Node
+		if duImportFlag != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("a path cannot be used together with --import")
+			}
+			tree, meta, err := importNcdu(duImportFlag)
+			if err != nil {
+				return err
+			}
+			logger.Log.Infof("Imported %s (written by %s %s on %s)", tree.Name, meta.ProgName, meta.ProgVersion, meta.Timestamp.Format(time.RFC1123))
+			root = tree
+		} else {
+			path := "."
+			if len(args) == 1 {
+				path = args[0]
+			}
+			logger.Log.Infof("Scanning %s...", path)
+			tree, err := analyzer.Scan(path, analyzer.ScanOptions{IgnorePaths: IgnorePaths})
+			if err != nil {
+				return fmt.Errorf("failed to scan %s: %w", path, err)
+			}
+			root = tree
+		}
+
+		if duExportFlag != "" {
+			if err := exportNcdu(duExportFlag, root); err != nil {
+				return err
+			}
+			if duExportFlag == "-" {
+				return nil // Standard output holds the export, so don't mix in the table.
+			}
+			logger.Log.Infof("Exported the tree to %s (view it with: ncdu -f %s)", duExportFlag, duExportFlag)
+		}
+
+		printDuTable(root)
+		logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+		return nil
+	},
+}
+
+// importNcdu reads an ncdu JSON export from path.
+func importNcdu(path string) (*analyzer.Node, analyzer.NcduMetadata, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, analyzer.NcduMetadata{}, err
+	}
+	defer file.Close()
+	tree, meta, err := analyzer.ReadNcdu(file)
+	if err != nil {
+		return nil, meta, fmt.Errorf("failed to import %s: %w", path, err)
+	}
+	return tree, meta, nil
+}
+
+// exportNcdu writes root as an ncdu JSON export to path, or to standard output for "-".
+func exportNcdu(path string, root *analyzer.Node) error {
+	meta := analyzer.NcduMetadata{ProgName: "wiper", ProgVersion: version, Timestamp: time.Now()}
+	if path == "-" {
+		return analyzer.WriteNcdu(os.Stdout, root, meta)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := analyzer.WriteNcdu(file, root, meta); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to export to %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// printDuTable lists the largest entries below root (see --depth and --dirs) with their share of its total.
+func printDuTable(root *analyzer.Node) {
+	total := root.TotalUsage()
+	entries := root.Ranked(duDepthFlag, duDirsFlag)
+	var rows [][]interface{}
+	for i, entry := range entries {
+		if i == duTopFlag {
+			rows = append(rows, []interface{}{fmt.Sprintf("... %d more", len(entries)-duTopFlag), "", ""})
+			break
+		}
+		name := entry.Path
+		if entry.Node.IsDir {
+			name += "/"
+		}
+		rows = append(rows, []interface{}{name, utils.Yellow(reclaimer.FormatBytes(entry.Usage)), percentOfTotal(entry.Usage, total)})
+	}
+	reclaimer.PrintListTable(fmt.Sprintf("Disk Usage of %s", root.Name), []string{"NAME", "SIZE", "% OF TOTAL"}, rows,
+		[]interface{}{utils.Blue("TOTAL"), utils.Blue(reclaimer.FormatBytes(total)), ""})
+}
+
+// percentOfTotal formats part as a percentage of total.
+func percentOfTotal(part int64, total int64) string {
+	if total <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the du command with the root command.
+func init() {
+	RootCmd.AddCommand(duCmd)
+
+	duCmd.Flags().StringVar(&duExportFlag, "export", "", "Write the scanned tree to this file in ncdu's JSON format (\"-\" for standard output)")
+	duCmd.Flags().StringVar(&duImportFlag, "import", "", "Read the tree from an ncdu JSON export instead of scanning")
+	duCmd.Flags().IntVar(&duDepthFlag, "depth", 1, "Rank the entries up to this many levels below the path (0 for the whole tree)")
+	duCmd.Flags().BoolVar(&duDirsFlag, "dirs", false, "Rank directories only")
+	duCmd.Flags().IntVar(&duTopFlag, "top", 20, "The number of entries listed")
+}
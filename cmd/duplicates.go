@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/i18n"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// duplicatesKeepFlag selects the copy of each group of duplicates that is kept (newest or oldest).
+var duplicatesKeepFlag string
+
+// duplicatesMinSizeFlag is the minimum size of the files compared, in human-readable form (e.g., "10MB").
+var duplicatesMinSizeFlag string
+
+// duplicatesIncludeVolumesFlag also scans the external volumes (e.g., /Volumes/*).
+var duplicatesIncludeVolumesFlag bool
+
+// duplicatesVolumeFlag points the duplicate search at a specific mounted volume (e.g., /Volumes/External).
+var duplicatesVolumeFlag string
+
+// duplicatesInteractiveFlag asks before removing each copy instead of once for all of them.
+var duplicatesInteractiveFlag bool
+
+// duplicatesTableRows is the number of groups listed in the duplicates table.
+const duplicatesTableRows = 20
+
+// ====================================================================================================
+// DUPLICATES COMMAND DEFINITION
+// ====================================================================================================
+
+// duplicatesCmd represents the duplicates command.
+// It finds files with the same content below the given directories and removes the extra copies.
+var duplicatesCmd = &cobra.Command{
+	Use:   "duplicates [directory]...",
+	Short: "Find duplicate files and remove the extra copies.",
+	Long: `The 'duplicates' command scans the given directories for files with the same content. Files
+are compared by size first, then by a SHA-256 hash of their first 64 KiB and finally of their whole
+contents, so most files are never read completely. Hard links to the same file and files smaller
+than '--min-size' (1 MiB by default) are left out. With '--include-volumes', the external volumes
+are scanned too (and the directories become optional), except Time Machine backups and read-only volumes.
+With '--volume', only the given mounted volume is scanned and the change of its free space is reported.
+
+Of every group of duplicates one copy is kept: the most recently modified one, or the oldest with
+'--keep oldest'. The other copies are removed after one confirmation, or after a confirmation each
+with '--interactive'. Like the other cleanups, protected, iCloud-synced and tagged files are skipped,
+'--trash' and '--quarantine' keep the copies recoverable, and '--dry-run' only lists them.
+
+On APFS, copies made with 'cp -c' or Finder's Duplicate share their blocks, so removing them frees
+little space; the summary shows that space separately.`,
+	Example: `
+ wiper duplicates ~/Downloads ~/Documents --dry-run
+ wiper duplicates ~/Pictures --keep oldest --min-size 10MB
+ wiper duplicates ~/Downloads --interactive --trash
+ wiper duplicates ~/Pictures --include-volumes
+ wiper duplicates --volume /Volumes/External`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !duplicatesIncludeVolumesFlag && duplicatesVolumeFlag == "" {
+			return fmt.Errorf("expected at least one directory, --include-volumes or --volume")
+		}
+		if duplicatesVolumeFlag != "" && (len(args) > 0 || duplicatesIncludeVolumesFlag) {
+			return fmt.Errorf("the --volume flag cannot be combined with directories or --include-volumes")
+		}
+		opts := cleaner.DuplicateOptions{Keep: duplicatesKeepFlag, IncludeVolumes: duplicatesIncludeVolumesFlag}
+		if err := cleaner.ValidateKeepRule(opts.Keep); err != nil {
+			return fmt.Errorf("invalid --keep: %w", err)
+		}
+		if duplicatesMinSizeFlag != "" {
+			minSize, err := utils.ParseBytes(duplicatesMinSizeFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --min-size: %w", err)
+			}
+			opts.MinSize = minSize
+		}
+		for _, arg := range args {
+			root, err := filepath.Abs(utils.ExpandPath(arg))
+			if err != nil {
+				return err
+			}
+			opts.Roots = append(opts.Roots, root)
+		}
+
+		// Resolve the target volume up front so an invalid path fails before any scanning.
+		var volume string
+		var freeBefore int64
+		if duplicatesVolumeFlag != "" {
+			resolved, err := utils.ValidateVolume(duplicatesVolumeFlag)
+			if err != nil {
+				return err
+			}
+			volume = resolved
+			logger.Log.Debugf("Target Volume: %s", volume)
+			opts.Roots = []string{volume}
+			if free, _, err := utils.VolumeSpace(volume); err == nil {
+				freeBefore = free
+			}
+		}
+
+		ctx := cmd.Context()
+		history.SetMode("duplicates")
+		if volume != "" {
+			history.SetMode("duplicates on " + volume)
+		}
+		where := strings.Join(opts.Roots, ", ")
+		if opts.IncludeVolumes {
+			where = strings.Join(append(opts.Roots, "the external volumes"), ", ")
+		}
+		logger.Log.Infof("Looking for duplicate files in %s...", where)
+		groups, err := cleaner.FindDuplicates(ctx, IgnorePaths, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				cmd.SilenceUsage = true
+			}
+			return fmt.Errorf("failed to find duplicates: %w", err)
+		}
+		if len(groups) == 0 {
+			logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+			logger.Log.Info("No duplicate files found.")
+			cmd.SilenceUsage = true
+			return errNothingFound
+		}
+		printDuplicatesTable(groups)
+
+		summary := reclaimer.NewSummaryTable()
+		estimatedSummary := reclaimer.NewSummaryTable()
+		if volume != "" {
+			// Percentages are relative to the selected volume rather than the startup disk.
+			summary.Volume = volume
+			estimatedSummary.Volume = volume
+		}
+		reclaimed, err := cleaner.CleanDuplicates(ctx, groups, dryRunFlag, duplicatesInteractiveFlag, summary, estimatedSummary)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		summary.PrintTable(false, i18n.T("summary.reclaimed_title"))
+		logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+		println()
+
+		// Report how the free space on the selected volume changed during the run.
+		if volume != "" && !dryRunFlag {
+			if freeAfter, _, err := utils.VolumeSpace(volume); err == nil {
+				logger.Log.Infof("Free space on %s: %s -> %s (%s)", volume,
+					reclaimer.FormatBytes(freeBefore), reclaimer.FormatBytes(freeAfter), utils.GreenBold(reclaimer.FormatBytes(freeAfter-freeBefore)))
+			}
+		}
+
+		if ctx.Err() != nil {
+			logger.Log.Warnf("Cleanup interrupted. Space reclaimed before stopping: %s", utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+			cmd.SilenceUsage = true
+			return fmt.Errorf("cleanup interrupted: %w", ctx.Err())
+		} else if dryRunFlag {
+			logger.Log.Infof(utils.CyanBold("Dry run: removing the extra copies would reclaim %s"), utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+			return nil
+		}
+		logger.Log.Infof("Cleanup completed. Space reclaimed: %s", utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+		if failed := summary.FailedCount(); failed > 0 {
+			cmd.SilenceUsage = true
+			return &cleanupFailedError{failed: failed}
+		}
+		if len(summary.ByStatus(reclaimer.StatusRemoved)) == 0 && allDeclined(summary.Skipped()) {
+			cmd.SilenceUsage = true
+			return errAborted
+		}
+		return nil
+	},
+}
+
+// printDuplicatesTable lists the largest groups of duplicates with the copy that is kept.
+func printDuplicatesTable(groups []cleaner.DuplicateGroup) {
+	var rows [][]interface{}
+	var total int64
+	for i, group := range groups {
+		total += group.Reclaimable()
+		if i >= duplicatesTableRows {
+			continue
+		}
+		rows = append(rows, []interface{}{group.Files[0].Path, len(group.Files) - 1,
+			reclaimer.FormatBytes(group.Size), utils.Yellow(reclaimer.FormatBytes(group.Reclaimable()))})
+	}
+	if len(groups) > duplicatesTableRows {
+		rows = append(rows, []interface{}{fmt.Sprintf("... %d more", len(groups)-duplicatesTableRows), "", "", ""})
+	}
+	reclaimer.PrintListTable("Duplicate Files", []string{"KEPT COPY", "DUPLICATES", "FILE SIZE", "RECLAIMABLE"}, rows,
+		[]interface{}{utils.Blue("TOTAL"), "", "", utils.Blue(reclaimer.FormatBytes(total))})
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the duplicates command with the root command.
+func init() {
+	RootCmd.AddCommand(duplicatesCmd)
+
+	// StringVar binds the --keep flag to the duplicatesKeepFlag variable.
+	duplicatesCmd.Flags().StringVar(&duplicatesKeepFlag, "keep", cleaner.KeepNewest, "The copy of each group to keep: newest or oldest (by modification time)")
+	// StringVar binds the --min-size flag to the duplicatesMinSizeFlag variable.
+	duplicatesCmd.Flags().StringVar(&duplicatesMinSizeFlag, "min-size", "", "Only compare files of at least this size, e.g. 100KB or 10MB (default 1MiB)")
+	// BoolVar binds the --include-volumes flag to the duplicatesIncludeVolumesFlag variable.
+	duplicatesCmd.Flags().BoolVar(&duplicatesIncludeVolumesFlag, "include-volumes", false, "Also scan external volumes, except Time Machine backups and read-only volumes")
+	// StringVar binds the --volume flag to the duplicatesVolumeFlag variable.
+	duplicatesCmd.Flags().StringVar(&duplicatesVolumeFlag, "volume", "", "Only look for duplicates on a specific mounted volume (e.g., /Volumes/External)")
+	// BoolVarP binds the --interactive flag to the duplicatesInteractiveFlag variable.
+	duplicatesCmd.Flags().BoolVarP(&duplicatesInteractiveFlag, "interactive", "I", false, "Ask before removing each duplicate")
+}
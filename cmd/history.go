@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// historyLimitFlag is the number of most recent runs `history` lists; 0 lists all of them.
+var historyLimitFlag int
+
+// historySinceFlag limits `history` to runs started within this long (e.g., "30d").
+var historySinceFlag string
+
+// historyJSONFlag prints the runs as a JSON array on standard output instead of a table.
+var historyJSONFlag bool
+
+// ====================================================================================================
+// HISTORY COMMAND DEFINITION
+// ====================================================================================================
+
+// historyCmd represents the history command.
+// It lists the cleanups wiper ran before, with what each one reclaimed.
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List past cleanup runs and what they reclaimed.",
+	Long: `Every run that cleans something ('wipe', 'dev --brew-orphans', and the dashboard buttons) is
+recorded in the history: when it ran, what it cleaned, how many items it removed, the space it
+reclaimed, the items it failed to remove, and the error it ended with. Dry runs are recorded with
+their estimates. The history is kept in 'history.jsonl' next to the configuration file
+(~/Library/Application Support/wiper on macOS).
+
+The run ID is the one printed in the logs of the run; use it with 'wiper restore' to list or put
+back the items the run removed.`,
+	Example: `
+ wiper history
+ wiper history --since 30d
+ wiper history --limit 0 --json > wiper-history.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runs, err := history.Runs()
+		if err != nil {
+			return fmt.Errorf("failed to read the history: %w", err)
+		}
+		if historySinceFlag != "" {
+			age, err := utils.ParseDuration(historySinceFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			cutoff := time.Now().Add(-age)
+			for i, run := range runs {
+				if run.Time.Before(cutoff) {
+					runs = runs[:i]
+					break
+				}
+			}
+		}
+		if historyLimitFlag > 0 && len(runs) > historyLimitFlag {
+			runs = runs[:historyLimitFlag]
+		}
+
+		if historyJSONFlag {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if runs == nil {
+				runs = []history.Run{}
+			}
+			return encoder.Encode(runs)
+		}
+		if len(runs) == 0 {
+			logger.Log.Info("No cleanup runs have been recorded yet.")
+			return nil
+		}
+
+		var rows [][]interface{}
+		var total int64
+		for _, run := range runs {
+			command := run.Command
+			if run.Mode != "" {
+				command += ": " + run.Mode
+			}
+			status := utils.Green(run.Status())
+			switch run.Status() {
+			case "dry run", "declined":
+				status = utils.Yellow(run.Status())
+			case "failed", "partial":
+				status = utils.Red(run.Status())
+			}
+			reclaimed := reclaimer.FormatBytes(run.Reclaimed)
+			if run.DryRun {
+				reclaimed = "(" + reclaimed + ")"
+			} else {
+				total += run.Reclaimed
+			}
+			rows = append(rows, []interface{}{run.ID, run.Time.Local().Format("2006-01-02 15:04"), command,
+				run.Items, run.Failed, utils.Yellow(reclaimed), status})
+		}
+		reclaimer.PrintListTable("Cleanup History", []string{"RUN ID", "DATE", "COMMAND", "ITEMS", "FAILED", "RECLAIMED", "STATUS"}, rows,
+			[]interface{}{utils.Blue("TOTAL"), "", "", "", "", utils.Blue(reclaimer.FormatBytes(total)), ""})
+		println()
+		logger.Log.Info("Sizes in parentheses are estimates of dry runs and aren't part of the total.")
+		return nil
+	},
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the history command with the root command.
+func init() {
+	RootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().IntVar(&historyLimitFlag, "limit", 20, "Number of most recent runs to list; 0 lists all of them")
+	historyCmd.Flags().StringVar(&historySinceFlag, "since", "", "Only list runs from this long ago or later, e.g. 30d")
+	historyCmd.Flags().BoolVar(&historyJSONFlag, "json", false, "Print the runs as JSON on standard output")
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/quarantine"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// reportFormatFlag is the format of the report written by `report`. Only "html" is supported.
+var reportFormatFlag string
+
+// reportOutFlag is the file the report is written to; standard output if empty.
+var reportOutFlag string
+
+// ====================================================================================================
+// REPORT COMMAND DEFINITION
+// ====================================================================================================
+
+// reportCmd represents the report command.
+// It renders what a previous cleanup removed, or what a system cleanup would remove, into a report.
+var reportCmd = &cobra.Command{
+	Use:   "report [run-id]",
+	Short: "Render a cleanup run into a standalone HTML report.",
+	Long: `The 'report' command renders a cleanup into a standalone HTML page, for sharing with
+teammates or attaching to an IT ticket: the space reclaimed, a chart of the space per category, and
+a table of every item that can be sorted by path, category, size or status. The page needs no
+network access or external files.
+
+Without a run ID (or with 'last'), the most recent run that removed something is reported; see
+'wiper restore' or 'wiper history' for the run IDs. With '--dry-run', the system cleanup targets
+are scanned instead, and the report shows what a cleanup would reclaim right now.`,
+	Example: `
+ wiper report --format html --out report.html
+ wiper report 20261014-101500-a1b2c3 --out report.html
+ wiper report --dry-run --out estimate.html`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if reportFormatFlag != "html" {
+			return fmt.Errorf("invalid --format %q: only html is supported", reportFormatFlag)
+		}
+
+		var summary *reclaimer.SummaryTable
+		var info reclaimer.ReportInfo
+		if dryRunFlag {
+			if len(args) > 0 {
+				return fmt.Errorf("--dry-run reports a new estimate and can't be combined with a run ID")
+			}
+			// Progress messages would end up in the report when it's written to standard output.
+			if reportOutFlag != "" {
+				logger.Log.Info("Scanning the system cleanup targets...")
+			}
+			estimate, err := cleaner.EstimateSystem(cmd.Context(), IgnorePaths)
+			if err != nil {
+				return fmt.Errorf("failed to scan the system: %w", err)
+			}
+			summary = estimate
+			info = reclaimer.ReportInfo{Title: "wiper scan: system cleanup", RunID: RunID, Time: time.Now(), DryRun: true}
+		} else {
+			runID := latestRunAlias
+			if len(args) > 0 {
+				runID = args[0]
+			}
+			var err error
+			summary, info, err = runReport(runID)
+			if err != nil {
+				return err
+			}
+		}
+		info.Version = version
+		info.Host, _ = os.Hostname()
+
+		if reportOutFlag == "" {
+			return summary.WriteHTML(os.Stdout, info)
+		}
+		if err := writeReportFile(reportOutFlag, func(w io.Writer) error { return summary.WriteHTML(w, info) }); err != nil {
+			return err
+		}
+		logger.Log.Infof("Report written to %s", reportOutFlag)
+		return nil
+	},
+}
+
+// runReport loads what run runID removed from its manifest, and its description from the history.
+// runID may be latestRunAlias for the most recent run with a manifest.
+func runReport(runID string) (*reclaimer.SummaryTable, reclaimer.ReportInfo, error) {
+	var run quarantine.Run
+	if runID == latestRunAlias {
+		runs, err := quarantine.Runs()
+		if err != nil {
+			return nil, reclaimer.ReportInfo{}, fmt.Errorf("failed to read the manifests: %w", err)
+		}
+		if len(runs) == 0 {
+			return nil, reclaimer.ReportInfo{}, fmt.Errorf("no cleanup run has removed anything yet; use --dry-run to report an estimate")
+		}
+		run = runs[0]
+	} else {
+		loaded, err := quarantine.LoadRun(runID)
+		if err != nil {
+			return nil, reclaimer.ReportInfo{}, err
+		}
+		run = loaded
+	}
+
+	summary := reclaimer.NewSummaryTable()
+	for _, entry := range run.Entries {
+		summary.AddRemoved(entry.Path, entry.Size, entry.Category)
+	}
+	info := reclaimer.ReportInfo{Title: "wiper cleanup", RunID: run.ID, Time: run.Time}
+
+	// The history knows what the run cleaned, and how many items it failed to remove.
+	if runs, err := history.Runs(); err == nil {
+		for _, recorded := range runs {
+			if recorded.ID != run.ID {
+				continue
+			}
+			info.Title = "wiper " + recorded.Command
+			if recorded.Mode != "" {
+				info.Title += ": " + recorded.Mode
+			}
+			info.Time = recorded.Time
+			info.Failed = recorded.Failed
+			break
+		}
+	}
+	return summary, info, nil
+}
+
+// writeReportFile creates path and writes a report into it with write.
+func writeReportFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	writer := bufio.NewWriter(file)
+	if err := write(writer); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the report command with the root command.
+func init() {
+	RootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVar(&reportFormatFlag, "format", "html", "Format of the report; only html is supported")
+	reportCmd.Flags().StringVar(&reportOutFlag, "out", "", "File to write the report to (default: standard output)")
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/quarantine"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// restorePathsFlag limits `restore` to items at or below these comma-separated paths.
+var restorePathsFlag string
+
+// purgeFlag deletes the quarantine of the given run instead of restoring it.
+var purgeFlag bool
+
+// purgeOlderThanFlag deletes the quarantine of every run older than this (e.g., "30d").
+var purgeOlderThanFlag string
+
+// latestRunAlias selects the most recent run with restorable items.
+const latestRunAlias = "last"
+
+// ====================================================================================================
+// RESTORE COMMAND DEFINITION
+// ====================================================================================================
+
+// restoreCmd represents the restore command.
+// It lists the manifests of past cleanups and puts quarantined items back where they were.
+var restoreCmd = &cobra.Command{
+	Use:   "restore [run-id]",
+	Short: "Put back items removed by a previous cleanup.",
+	Long: `Every cleanup records what it removed in a manifest: the path, size and category of each
+item, when it was removed, and where it went. Manifests are kept in the 'quarantine' directory next
+to the configuration file (~/Library/Application Support/wiper on macOS), one per run ID.
+
+Items are only restorable if they were kept: run 'wipe --quarantine' (or set 'quarantine' in the
+config file) to move items into the quarantine of the run instead of deleting them. Items on other
+volumes are kept in a '.wiper-quarantine' directory at the root of their volume. Archived
+downloads can be restored as well. Deleted items are listed, but are gone for good, and items
+moved to the Trash are restored from the Trash.
+
+Without a run ID, 'restore' lists the recorded runs. With a run ID (or 'last' for the most recent
+restorable run), it moves the items of that run back. Items whose original path exists again are
+left in the quarantine.
+
+The quarantine only frees space once it is purged: use '--purge' to delete the items of a run
+permanently, or '--purge-older-than' to purge every run older than the given age.`,
+	Example: `
+ # List recorded runs
+ wiper restore
+
+ # Restore everything removed by a run, or only part of it
+ wiper restore 20261014-101500-a1b2c3
+ wiper restore last --path ~/Library/Caches/com.apple.Safari
+
+ # Free the space held by the quarantine
+ wiper restore 20261014-101500-a1b2c3 --purge
+ wiper restore --purge-older-than 30d`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if purgeOlderThanFlag != "" {
+			if len(args) > 0 || purgeFlag {
+				return fmt.Errorf("--purge-older-than purges every old run and can't be combined with a run ID or --purge")
+			}
+			age, err := utils.ParseDuration(purgeOlderThanFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --purge-older-than: %w", err)
+			}
+			return purgeRunsOlderThan(age)
+		}
+		if len(args) == 0 {
+			if purgeFlag {
+				return fmt.Errorf("--purge needs the run ID to purge")
+			}
+			return listRuns()
+		}
+
+		runID := args[0]
+		if runID == latestRunAlias {
+			latest, err := latestRestorableRun()
+			if err != nil {
+				return err
+			}
+			runID = latest
+		}
+		if purgeFlag {
+			freed, err := quarantine.Purge(runID, dryRunFlag)
+			if err != nil {
+				return fmt.Errorf("failed to purge run %s: %w", runID, err)
+			}
+			if dryRunFlag {
+				logger.Log.Infof("Purging run %s would free %s", runID, utils.GreenBold(reclaimer.FormatBytes(freed)))
+			} else {
+				logger.Log.Infof("Purged run %s. Space reclaimed: %s", runID, utils.GreenBold(reclaimer.FormatBytes(freed)))
+			}
+			return nil
+		}
+		return restoreRun(cmd, runID)
+	},
+}
+
+// listRuns prints a table of the recorded runs.
+func listRuns() error {
+	runs, err := quarantine.Runs()
+	if err != nil {
+		return fmt.Errorf("failed to read the manifests: %w", err)
+	}
+	if len(runs) == 0 {
+		logger.Log.Info("No cleanup runs have been recorded yet.")
+		return nil
+	}
+
+	var rows [][]interface{}
+	for _, run := range runs {
+		var restorableSize int64
+		restorable := run.Restorable()
+		for _, entry := range restorable {
+			restorableSize += entry.Size
+		}
+		rows = append(rows, []interface{}{run.ID, run.Time.Local().Format("2006-01-02 15:04"), len(run.Entries),
+			fmt.Sprintf("%d (%s)", len(restorable), reclaimer.FormatBytes(restorableSize)), utils.Yellow(reclaimer.FormatBytes(run.Size()))})
+	}
+	reclaimer.PrintListTable("Recorded Cleanup Runs", []string{"RUN ID", "DATE", "ITEMS", "RESTORABLE", "SIZE"}, rows, nil)
+	println()
+	logger.Log.Info("Restore a run with: wiper restore <run-id>")
+	return nil
+}
+
+// latestRestorableRun returns the ID of the most recent run that still has restorable items.
+func latestRestorableRun() (string, error) {
+	runs, err := quarantine.Runs()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the manifests: %w", err)
+	}
+	for _, run := range runs {
+		if len(run.Restorable()) > 0 {
+			return run.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no recorded run has restorable items")
+}
+
+// restoreRun restores the items of runID and prints what was put back.
+func restoreRun(cmd *cobra.Command, runID string) error {
+	var only []string
+	for _, path := range strings.Split(restorePathsFlag, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			only = append(only, utils.ExpandPath(path))
+		}
+	}
+
+	results, err := quarantine.Restore(runID, only, dryRunFlag)
+	if len(results) == 0 && err == nil {
+		logger.Log.Infof("Run %s has no restorable items.", runID)
+		return nil
+	}
+
+	var rows [][]interface{}
+	var restored int64
+	failed := 0
+	for _, result := range results {
+		status := utils.Green("restored")
+		if dryRunFlag {
+			status = utils.Yellow("would restore")
+		}
+		if result.Err != nil {
+			status = utils.Red(result.Err.Error())
+			failed++
+		} else {
+			restored += result.Entry.Size
+		}
+		for _, loss := range result.LostMetadata {
+			logger.RunWarnings.Add(result.Entry.Category, "metadata not preserved", loss.Path, fmt.Errorf("%s: %w", loss.What, loss.Err))
+		}
+		rows = append(rows, []interface{}{result.Entry.Path, result.Entry.Category, reclaimer.FormatBytes(result.Entry.Size), status})
+	}
+	reclaimer.PrintListTable(fmt.Sprintf("Restore of Run %s", runID), []string{"PATH", "CATEGORY", "SIZE", "STATUS"}, rows,
+		[]interface{}{utils.Blue("TOTAL"), "", utils.Blue(reclaimer.FormatBytes(restored)), ""})
+	println()
+	logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d item(s) could not be restored", failed)
+	}
+	return nil
+}
+
+// purgeRunsOlderThan purges every run whose items were removed more than age ago.
+func purgeRunsOlderThan(age time.Duration) error {
+	runs, err := quarantine.Runs()
+	if err != nil {
+		return fmt.Errorf("failed to read the manifests: %w", err)
+	}
+	cutoff := time.Now().Add(-age)
+	var freed int64
+	purged := 0
+	for _, run := range runs {
+		if run.Time.After(cutoff) {
+			continue
+		}
+		size, err := quarantine.Purge(run.ID, dryRunFlag)
+		if err != nil {
+			logger.Log.Errorf("Failed to purge run %s: %v", run.ID, err)
+			continue
+		}
+		freed += size
+		purged++
+	}
+	if dryRunFlag {
+		logger.Log.Infof("Purging %d run(s) would free %s", purged, utils.GreenBold(reclaimer.FormatBytes(freed)))
+	} else {
+		logger.Log.Infof("Purged %d run(s). Space reclaimed: %s", purged, utils.GreenBold(reclaimer.FormatBytes(freed)))
+	}
+	return nil
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the restore command with the root command.
+func init() {
+	RootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().StringVar(&restorePathsFlag, "path", "", "Only restore items at or below these comma-separated paths")
+	restoreCmd.Flags().BoolVar(&purgeFlag, "purge", false, "Delete the quarantined items of the run permanently instead of restoring them")
+	restoreCmd.Flags().StringVar(&purgeOlderThanFlag, "purge-older-than", "", "Purge every run older than this, e.g. 30d")
+}
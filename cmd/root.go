@@ -1,11 +1,24 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/config"
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/i18n"
 	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/notify"
+	"github.com/kodelint/wiper/pkg/quarantine"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/schedule"
+	"github.com/kodelint/wiper/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +36,42 @@ var (
 	dryRunFlag bool
 	// ignorePathsStr holds the raw comma-separated string of paths from the --ignore flag.
 	ignorePathsStr string
+	// configPathStr holds the path of the configuration file given via the --config flag.
+	configPathStr string
+	// verbosityCount holds how many times -v was given (-v, -vv, -vvv).
+	verbosityCount int
+	// showWarningsFlag prints every per-path warning, like -v.
+	showWarningsFlag bool
+	// showDetailsFlag prints every found or removed item, like -vv.
+	showDetailsFlag bool
+	// tableStyleFlag overrides the table style from the configuration file.
+	tableStyleFlag string
+	// tableSortFlag overrides the order of summary rows from the configuration file, and
+	// tableCountsFlag adds the item count column.
+	tableSortFlag   string
+	tableCountsFlag bool
+	// logFormatFlag overrides the log format from the configuration file (console, text or json).
+	logFormatFlag string
+	// systemLogFlag forwards warnings and errors to the system log (os_log on macOS).
+	systemLogFlag bool
+	// logFileFlag mirrors the logs to this file; "default" selects the platform's log directory.
+	logFileFlag string
+	// logFlag mirrors the logs to the default log file, like --log-file default.
+	logFlag bool
+	// quietFlag suppresses informational logging, leaving the summary tables and the total.
+	quietFlag bool
+	// notifyFlag posts a desktop notification with the result of the cleanup.
+	notifyFlag bool
+	// yesFlag answers every confirmation prompt with yes, for scripts and launchd jobs.
+	yesFlag bool
+	// jobsFlag is the number of concurrent filesystem workers; 0 means the default.
+	jobsFlag int
+	// enableTargetsFlag is a comma-separated list of opt-in cleanup targets to enable (e.g., "photos_caches").
+	enableTargetsFlag string
+	// networkVolumesFlag makes the scans descend into network volumes (SMB, AFP, NFS) instead of skipping them.
+	networkVolumesFlag bool
+	// RunID uniquely identifies this invocation of wiper. It is attached to every log record.
+	RunID string
 	// IgnorePaths will hold the parsed slice of paths, used by subcommands
 	// after being processed in PersistentPreRunE.
 	IgnorePaths []string
@@ -36,22 +85,197 @@ var (
 // It is the entry point for the wiper CLI application.
 var RootCmd = &cobra.Command{
 	Use:   "wiper",
-	Short: "A powerful tool to uninstall applications and clean up macOS.",
-	Long: `wiper is a comprehensive macOS utility designed to help you:
+	Short: "A powerful tool to uninstall applications and clean up macOS and Linux.",
+	Long: `wiper is a comprehensive macOS (and Linux) utility designed to help you:
 
 1.  Uninstall Applications: Completely remove applications and all their leftover files, reclaiming disk space.
 2.  Clean System Junk: Remove temporary files, caches (system, user, browser), and other unnecessary data to optimize your macOS performance.
 
 It provides detailed output and supports dry-run modes to show you what will be removed before any changes are made.`,
 
+	// Execute prints errors itself, so they aren't printed twice.
+	SilenceErrors: true,
+
 	// PersistentPreRunE is a function that is executed before any command (including subcommands).
 	// It is used to initialize common settings or pre-process flags that apply to all commands.
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Commands printing JSON on standard output (e.g., 'scan --json', 'du --export -') log to
+		// standard error.
+		if jsonFlag := cmd.Flags().Lookup("json"); jsonFlag != nil && jsonFlag.Value.String() == "true" {
+			logger.SetOutput(os.Stderr)
+		}
+		if exportFlag := cmd.Flags().Lookup("export"); exportFlag != nil && exportFlag.Value.String() == "-" {
+			logger.SetOutput(os.Stderr)
+		}
 		// Initialize the logger based on the debug flag.
 		// If the debug flag is set, we enable a more verbose logging level.
 		if debugFlag {
 			logger.SetDebug(true)
 		}
+		// Map -v/-vv/-vvv to warnings, per-item details and debug output respectively. Only the flags
+		// conflict with --quiet; the WIPER_SHOW_* environment variables give way to it.
+		if quietFlag {
+			if debugFlag || verbosityCount > 0 || showWarningsFlag || showDetailsFlag {
+				return fmt.Errorf("the --quiet flag cannot be combined with --debug, --verbose, --show-warnings or --show-details")
+			}
+			logger.SetQuiet(true)
+		} else {
+			logger.SetVerbosity(effectiveVerbosity())
+		}
+
+		// launchd names the job it started in XPC_SERVICE_NAME. The log of the scheduled cleanup
+		// would grow forever, so the run rotates it.
+		if os.Getenv("XPC_SERVICE_NAME") == schedule.Label {
+			if logPath, err := scheduleLogPath(); err == nil {
+				if err := schedule.RotateLog(logPath); err != nil {
+					logger.Log.Warnf("Could not rotate the schedule log: %v", err)
+				}
+			}
+		}
+
+		// Load the configuration file. A missing file is only an error if it was requested explicitly.
+		if err := config.Load(configPathStr, configPathStr != ""); err != nil {
+			return err
+		}
+
+		// Select the message catalog: the config file wins, otherwise fall back to LANG/LC_*.
+		if config.Current.Language != "" {
+			i18n.SetLanguage(config.Current.Language)
+		} else {
+			i18n.SetLanguage(i18n.DetectLanguage())
+		}
+		logger.Log.Debugf("Language: %s", i18n.Language())
+
+		// Switch the log handler if a structured format was configured, then tag all records with a run ID.
+		// --log-format takes precedence over the config file.
+		logFormat := config.Current.LogFormat
+		if logFormatFlag != "" {
+			logFormat = logFormatFlag
+		}
+		if err := logger.SetFormat(logFormat); err != nil {
+			return err
+		}
+		if systemLogFlag || config.Current.SystemLog {
+			if err := logger.EnableSystemLog(); err != nil {
+				logger.Log.Warnf("System log integration disabled: %v", err)
+			}
+		}
+		// Mirror the logs to a file; --log-file and --log take precedence over the config file.
+		logFile := config.Current.LogFile
+		if logFlag {
+			logFile = defaultLogFile
+		}
+		if logFileFlag != "" {
+			logFile = logFileFlag
+		}
+		if logFile != "" {
+			if err := enableLogFile(logFile, config.Current); err != nil {
+				logger.Log.Warnf("Log file disabled: %v", err)
+			}
+		}
+		// Apply table rendering options; the flag takes precedence over the config file.
+		tableStyle := config.Current.TableStyle
+		if tableStyleFlag != "" {
+			tableStyle = tableStyleFlag
+		}
+		if err := reclaimer.SetTableStyle(tableStyle); err != nil {
+			return err
+		}
+		reclaimer.SetTableWidth(config.Current.TableWidth)
+		tableSort := config.Current.TableSort
+		if tableSortFlag != "" {
+			tableSort = tableSortFlag
+		}
+		if err := reclaimer.SetTableSort(tableSort); err != nil {
+			return err
+		}
+		reclaimer.SetTableCounts(tableCountsFlag || config.Current.TableCounts)
+
+		// Configure the Downloads target from the config file and --archive-downloads.
+		policy, err := downloadsPolicy(config.Current.Downloads)
+		if err != nil {
+			return err
+		}
+		cleaner.SetDownloadsPolicy(policy)
+
+		// Select the browser profiles to clean; --browser-profiles takes precedence over the config file.
+		browserProfiles := config.Current.BrowserProfiles
+		if browserProfilesFlag != "" {
+			browserProfiles = strings.Split(browserProfilesFlag, ",")
+		}
+		cleaner.SetBrowserProfiles(browserProfiles)
+
+		// Enable the opt-in targets; --enable-targets takes precedence over the config file.
+		enableTargets := config.Current.EnableTargets
+		if enableTargetsFlag != "" {
+			enableTargets = nil
+			for _, id := range strings.Split(enableTargetsFlag, ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					enableTargets = append(enableTargets, id)
+				}
+			}
+		}
+		if err := cleaner.ValidateOptInTargets(enableTargets); err != nil {
+			return fmt.Errorf("invalid --enable-targets: %w", err)
+		}
+		cleaner.SetEnabledTargets(enableTargets)
+
+		// Items carrying the protect tag are never cleaned; --protect-tag takes precedence over the config file.
+		protectTag := config.Current.ProtectTag
+		if protectTagFlag != "" {
+			protectTag = protectTagFlag
+		}
+		cleaner.SetProtectTag(protectTag)
+
+		// Unattended runs (--yes) accept every confirmation prompt.
+		cleaner.SetAssumeYes(yesFlag)
+
+		// Size the worker pool of the filesystem scans; --jobs takes precedence over the config file.
+		jobs := config.Current.Jobs
+		if jobsFlag != 0 {
+			jobs = jobsFlag
+		}
+		if jobs < 0 {
+			return fmt.Errorf("invalid number of jobs %d: must be at least 1", jobs)
+		}
+		utils.SetJobs(jobs)
+		logger.Log.Debugf("Filesystem workers: %d", utils.Jobs())
+
+		// Scans skip network volumes, whose listings can take minutes, unless asked to scan them.
+		utils.SetScanNetworkVolumes(networkVolumesFlag || config.Current.NetworkVolumes)
+
+		// Move items to the Trash instead of deleting them, if --trash or the config file asks for it.
+		utils.SetTrashMode(trashFlag || config.Current.Trash)
+
+		// Record removals in the manifest of this run, keeping the items if --quarantine or the config
+		// file asks for it, and the run in the history once it's done; see Execute.
+		startRun(cmd.Name(), dryRunFlag, quarantineFlag || config.Current.Quarantine)
+		// Background runs are visible in Notification Center with --notify or the config file.
+		if notifyFlag || config.Current.Notify {
+			history.OnFinish(notifyRun)
+		}
+		// Run the shell hooks of the config file around the cleanup.
+		cleaner.SetHooks(cleaner.Hooks{
+			PreClean:      config.Current.Hooks.PreClean,
+			PostClean:     config.Current.Hooks.PostClean,
+			PreDeleteItem: config.Current.Hooks.PreDeleteItem,
+		}, cmd.Name())
+		// Paths of the config file that must never be removed, on top of the built-in ones.
+		utils.AddProtectedPaths(config.Current.ProtectedPaths...)
+		// External cleaners of the config file add their items to the system cleanup.
+		for _, c := range config.Current.Cleaners {
+			if err := cleaner.RegisterExternal(c.Name, c.Description, c.Command); err != nil {
+				return fmt.Errorf("invalid cleaner in the configuration file: %w", err)
+			}
+		}
+		// IT teams collect the results of their Macs through the webhook of the config file.
+		if config.Current.Webhook.URL != "" {
+			webhook, err := notify.NewWebhook(config.Current.Webhook.URL, config.Current.Webhook.Template)
+			if err != nil {
+				return err
+			}
+			history.OnFinish(func(run history.Run) { postWebhook(webhook, run) })
+		}
 
 		// Parse the ignorePathsStr into the IgnorePaths slice.
 		// This logic ensures that the --ignore flag is processed once and the result
@@ -63,6 +287,10 @@ It provides detailed output and supports dry-run modes to show you what will be
 				// Trim leading/trailing whitespace from each path.
 				trimmedPath := strings.TrimSpace(p)
 				if trimmedPath != "" {
+					// Patterns are checked upfront, so a typo doesn't silently ignore nothing.
+					if err := utils.ValidateIgnorePath(trimmedPath); err != nil {
+						return fmt.Errorf("invalid --ignore: %w", err)
+					}
 					// Append the cleaned path to the global slice.
 					IgnorePaths = append(IgnorePaths, trimmedPath)
 				}
@@ -76,6 +304,77 @@ It provides detailed output and supports dry-run modes to show you what will be
 	},
 }
 
+// effectiveVerbosity returns the detail level of -v/-vv/-vvv, raised by --show-warnings and
+// --show-details. The WIPER_SHOW_WARNINGS=true and WIPER_SHOW_DETAILS=true environment variables of
+// earlier versions are still honored, like the flags.
+func effectiveVerbosity() int {
+	verbosity := verbosityCount
+	if showWarningsFlag || os.Getenv("WIPER_SHOW_WARNINGS") == "true" {
+		verbosity = max(verbosity, 1)
+	}
+	if showDetailsFlag || os.Getenv("WIPER_SHOW_DETAILS") == "true" {
+		verbosity = max(verbosity, 2)
+	}
+	return verbosity
+}
+
+// notifyRun posts a desktop notification with the result of a finished run.
+func notifyRun(run history.Run) {
+	if err := notify.Post(notify.RunMessage(run)); err != nil {
+		logger.Log.Warnf("Could not post a notification: %v", err)
+	}
+}
+
+// postWebhook posts the summary of a finished run to the webhook.
+func postWebhook(webhook *notify.Webhook, run history.Run) {
+	host, _ := os.Hostname()
+	if err := webhook.Post(notify.NewWebhookPayload(run, host, version)); err != nil {
+		logger.Log.Warnf("Could not post the run to the webhook: %v", err)
+	}
+}
+
+// startRun begins a run with a new run ID: the logs, the manifest of its removals (keeping the
+// items in the quarantine if keep is true) and its history record all use that ID, so
+// `wiper restore` and `wiper history` agree with the logs about which run removed what.
+func startRun(command string, dryRun bool, keep bool) {
+	RunID = utils.NewRunID()
+	logger.SetRunID(RunID)
+	logger.Log.Debugf("Run ID: %s", RunID)
+	quarantine.Start(RunID, keep)
+	history.Start(RunID, command, dryRun)
+}
+
+// defaultLogFile is the --log-file and log_file value that selects the platform's log directory.
+const defaultLogFile = "default"
+
+// enableLogFile mirrors the logs to path (or the default log file), rotated as configured in cfg.
+func enableLogFile(path string, cfg *config.Config) error {
+	if path == defaultLogFile {
+		defaultPath, err := logger.DefaultLogFilePath()
+		if err != nil {
+			return err
+		}
+		path = defaultPath
+	}
+	maxSize := int64(logger.DefaultLogFileMaxSize)
+	if cfg.LogFileMaxSize != "" {
+		size, err := utils.ParseBytes(cfg.LogFileMaxSize)
+		if err != nil {
+			return fmt.Errorf("invalid log_file_max_size: %w", err)
+		}
+		maxSize = size
+	}
+	backups := logger.DefaultLogFileBackups
+	if cfg.LogFileBackups != nil {
+		backups = *cfg.LogFileBackups
+	}
+	if err := logger.EnableLogFile(utils.ExpandPath(path), maxSize, backups); err != nil {
+		return err
+	}
+	logger.Log.Debugf("Logging to %s", logger.LogFilePath())
+	return nil
+}
+
 // ====================================================================================================
 // APPLICATION ENTRY POINT
 // ====================================================================================================
@@ -84,14 +383,110 @@ It provides detailed output and supports dry-run modes to show you what will be
 // It is the main entry point for the cobra application and is called by the main() function.
 // It only needs to be called once to execute the RootCmd.
 func Execute() {
-	if err := RootCmd.Execute(); err != nil {
-		// If an error occurs during execution, print the error to standard error
-		// and exit the program with a non-zero status code.
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	err := RootCmd.Execute()
+	// A declined cleanup or one that found nothing ends with an exit status, not because it failed.
+	runErr := err
+	var status *exitStatusError
+	if errors.As(err, &status) {
+		if status == errAborted {
+			history.Decline()
+		}
+		runErr = nil
+	}
+	// Commands report the warnings after their summaries; this catches the ones collected before a
+	// command returned early (e.g., a scan that failed halfway), since Report empties the collector.
+	logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+	if histErr := history.Finish(runErr); histErr != nil {
+		logger.Log.Warnf("Could not record the run in the history: %v", histErr)
+	}
+	if err == nil {
+		return
+	}
+	// The command already explained why it found nothing or stopped, so only the status is reported.
+	if status != nil {
+		os.Exit(status.code)
+	}
+	// If an error occurs during execution, print the error to standard error
+	// and exit the program with a non-zero status code.
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	// A cleanup that ran to completion but could not remove everything gets its own
+	// status code, so scripts can tell it apart from a run that failed outright.
+	var failed *cleanupFailedError
+	if errors.As(err, &failed) {
+		os.Exit(exitCodePartialFailure)
 	}
+	// Like a shell, report an interrupted run as terminated by SIGINT.
+	if errors.Is(err, context.Canceled) {
+		os.Exit(exitCodeInterrupted)
+	}
+	os.Exit(exitCodeFatal)
 }
 
+// Exit statuses of wiper, so scripts can tell the outcomes of a cleanup apart.
+const (
+	// exitCodeFatal is used when a command failed outright (e.g., an invalid flag or an unreadable config).
+	exitCodeFatal = 1
+	// exitCodeNothingFound is used when a cleanup found nothing to remove.
+	exitCodeNothingFound = 2
+	// exitCodePartialFailure is used when a cleanup finished but some items could not be removed.
+	exitCodePartialFailure = 3
+	// exitCodeAborted is used when the user declined the cleanup at a confirmation prompt.
+	exitCodeAborted = 4
+	// exitCodeInterrupted is used when a cleanup was stopped with Ctrl+C, like a shell does for SIGINT.
+	exitCodeInterrupted = 130
+)
+
+// interruptContext returns a context that is cancelled on the first Ctrl+C (or SIGTERM), so a
+// cleanup can stop between items and still print what it removed. The signal handler is removed
+// again at that point, so a second Ctrl+C terminates wiper immediately. Call stop when done.
+func interruptContext(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			logger.Log.Warn(utils.Yellow("Interrupted; stopping after the current item. Press Ctrl+C again to quit immediately."))
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
+
+// cleanupFailedError is returned by commands that finished but failed to remove some items.
+type cleanupFailedError struct {
+	failed int
+}
+
+// Error implements the error interface.
+func (e *cleanupFailedError) Error() string {
+	return fmt.Sprintf("cleanup finished with %d failed removal(s); see the failures table above", e.failed)
+}
+
+// exitStatusError ends a command that didn't fail with a non-zero exit status. Execute doesn't
+// print it: the command logs what happened itself.
+type exitStatusError struct {
+	code   int
+	reason string
+}
+
+// Error implements the error interface.
+func (e *exitStatusError) Error() string {
+	return e.reason
+}
+
+var (
+	// errNothingFound is returned by cleanups that found nothing to remove.
+	errNothingFound = &exitStatusError{code: exitCodeNothingFound, reason: "nothing to clean"}
+	// errAborted is returned by cleanups the user declined at a confirmation prompt.
+	errAborted = &exitStatusError{code: exitCodeAborted, reason: "aborted by user"}
+)
+
 // ====================================================================================================
 // INITIALIZATION
 // ====================================================================================================
@@ -110,14 +505,62 @@ func init() {
 	// "Enable debug logging.": The usage description.
 	RootCmd.PersistentFlags().BoolVarP(&debugFlag, "debug", "d", false, "Enable debug logging.")
 
+	// CountVarP increments verbosityCount for every occurrence of -v.
+	// -v shows per-path warnings, -vv adds per-item details, -vvv enables debug logging.
+	RootCmd.PersistentFlags().CountVarP(&verbosityCount, "verbose", "v", "Increase output verbosity (-v warnings, -vv details, -vvv debug).")
+
+	// BoolVar for printing every per-path warning instead of a summary line (same as -v).
+	RootCmd.PersistentFlags().BoolVar(&showWarningsFlag, "show-warnings", false, "Print every per-path warning instead of a summary (same as -v).")
+
+	// BoolVar for printing every found or removed item (same as -vv).
+	RootCmd.PersistentFlags().BoolVar(&showDetailsFlag, "show-details", false, "Print every found or removed item (same as -vv).")
+
 	// BoolVarP for the dry-run flag.
 	RootCmd.PersistentFlags().BoolVarP(&dryRunFlag, "dry-run", "n", false, "Perform a dry run without making any changes.")
 
+	// BoolVarP for quiet output, e.g. in cron jobs.
+	RootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "Only print warnings, errors, the summary tables and the total.")
+
+	// BoolVar for posting a notification when a cleanup finishes.
+	RootCmd.PersistentFlags().BoolVar(&notifyFlag, "notify", false, "Post a desktop notification with the result of the cleanup (Notification Center on macOS).")
+
+	// BoolVarP for answering every confirmation prompt with yes.
+	RootCmd.PersistentFlags().BoolVarP(&yesFlag, "yes", "y", false, "Answer yes to every confirmation prompt, for unattended runs.")
+
 	// StringVarP binds a string flag to a variable.
 	// &ignorePathsStr: The variable to store the flag's string value.
 	// "ignore": The long name of the flag (--ignore).
 	// "i": The short name of the flag (-i).
 	// "": The default value (an empty string).
 	// "Comma-separated list of paths to ignore during cleanup.": The usage description.
-	RootCmd.PersistentFlags().StringVarP(&ignorePathsStr, "ignore", "i", "", "Comma-separated list of paths to ignore during cleanup.")
+	RootCmd.PersistentFlags().StringVarP(&ignorePathsStr, "ignore", "i", "", "Comma-separated list of paths to ignore during cleanup; globs (**/*.sqlite) and regexes (re:\\.db$) are supported.")
+
+	// StringVar for the summary table style (see reclaimer.TableStyles for the accepted names).
+	RootCmd.PersistentFlags().StringVar(&tableStyleFlag, "table-style", "", "Summary table style: colored-dark (default), colored-bright, light, rounded, double, bold, ascii.")
+	// StringVar for the order of summary rows, and BoolVar for the item count column.
+	RootCmd.PersistentFlags().StringVar(&tableSortFlag, "table-sort", "", "Sort summary rows by size (default, largest first), name, or count (most items first).")
+	RootCmd.PersistentFlags().BoolVar(&tableCountsFlag, "table-counts", false, "Show the number of items in each category in summary tables.")
+
+	// StringVar for the log format, e.g. json for log pipelines.
+	RootCmd.PersistentFlags().StringVar(&logFormatFlag, "log-format", "", "Log format: console (default), text (key=value) or json (one object per record), written to standard error.")
+
+	// BoolVar for forwarding warnings and errors to the system log.
+	RootCmd.PersistentFlags().BoolVar(&systemLogFlag, "syslog", false, "Forward warnings and errors to the system log (os_log on macOS).")
+
+	// StringVar for the log file, and BoolVar for the platform's default log file. The path is a
+	// required value, so `--log-file <path>` can't leave the path behind as an argument.
+	RootCmd.PersistentFlags().StringVar(&logFileFlag, "log-file", "", "Also write the logs, including every removed item, to this file ('default' for ~/Library/Logs/wiper/wiper.log).")
+	RootCmd.PersistentFlags().BoolVar(&logFlag, "log", false, "Also write the logs, including every removed item, to ~/Library/Logs/wiper/wiper.log (same as --log-file default).")
+
+	// IntVarP for the number of concurrent filesystem workers used by scans and size calculations.
+	RootCmd.PersistentFlags().IntVarP(&jobsFlag, "jobs", "j", 0, fmt.Sprintf("Number of directories scanned concurrently; 1 scans serially (default %d).", utils.DefaultJobs()))
+
+	// StringVar binds the --enable-targets flag to the enableTargetsFlag variable.
+	RootCmd.PersistentFlags().StringVar(&enableTargetsFlag, "enable-targets", "", "Comma-separated list of opt-in cleanup targets to include, e.g. photos_caches.")
+
+	// BoolVar binds the --network-volumes flag to the networkVolumesFlag variable.
+	RootCmd.PersistentFlags().BoolVar(&networkVolumesFlag, "network-volumes", false, "Also scan the network volumes (SMB, AFP, NFS, ...) found below the scanned directories, which are skipped by default.")
+
+	// StringVar for the configuration file location. It has no shorthand to keep -c free for future use.
+	RootCmd.PersistentFlags().StringVar(&configPathStr, "config", "", "Path to the configuration file (default: ~/Library/Application Support/wiper/config.json).")
 }
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/i18n"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// scanLargeFilesFlag adds the large file scan to the `scan` report.
+var scanLargeFilesFlag bool
+
+// scanThresholdFlag is the minimum size for large files in the `scan` report (e.g., "1GiB").
+var scanThresholdFlag string
+
+// scanJSONFlag prints the `scan` report as JSON on standard output instead of tables.
+var scanJSONFlag bool
+
+// ====================================================================================================
+// DATA STRUCTURES
+// ====================================================================================================
+
+// scanReport is the JSON document printed by `scan --json`.
+type scanReport struct {
+	RunID string    `json:"run_id"`
+	Time  time.Time `json:"time"`
+	// TotalBytes is the space the system cleanup (and large files cleanup, if scanned) would reclaim.
+	TotalBytes int64        `json:"total_bytes"`
+	System     *scanSection `json:"system"`
+	LargeFiles *scanSection `json:"large_files,omitempty"`
+}
+
+// scanSection is the estimate of one cleanup in a scanReport.
+type scanSection struct {
+	TotalBytes int64                      `json:"total_bytes"`
+	Categories []reclaimer.CategoryTotal  `json:"categories"`
+	Items      []reclaimer.ReclaimedEntry `json:"items"`
+	Skipped    []reclaimer.ReclaimedEntry `json:"skipped,omitempty"`
+}
+
+// ====================================================================================================
+// SCAN COMMAND DEFINITION
+// ====================================================================================================
+
+// scanCmd represents the scan command.
+// It runs the scans of the system (and optionally large files) cleanup and reports what they
+// would reclaim, without ever removing anything.
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Report what a cleanup would reclaim, without removing anything.",
+	Long: `The 'scan' command analyzes the system cleanup targets and prints how much space each
+category would reclaim. It never deletes, moves or prompts, which makes it safe for cron jobs
+and CI disk audits. Unlike 'wipe --dry-run', it can't turn into a real cleanup by mistake.
+
+Use '--large-files' to also report the files a large files cleanup would offer, and '--threshold'
+to change their minimum size. A large file can also be part of a system cleanup category, so the
+two estimates may overlap.
+
+Use '--json' to print the report as JSON on standard output: the total, the totals per category,
+and every item with its size, as well as skipped items and why they were skipped. The logs are
+written to standard error then.`,
+	Example: `
+ wiper scan
+ wiper scan --large-files --threshold 1GiB
+ wiper scan --json > wiper-scan.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := cleaner.LargeFileOptions{}
+		if scanThresholdFlag != "" {
+			if !scanLargeFilesFlag {
+				return fmt.Errorf("the --threshold flag needs --large-files")
+			}
+			threshold, err := utils.ParseBytes(scanThresholdFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --threshold: %w", err)
+			}
+			opts.Threshold = threshold
+		}
+
+		// Standard output holds the JSON report, so progress messages are only shown when debugging.
+		logf := logger.Log.Infof
+		if scanJSONFlag {
+			logf = logger.Log.Debugf
+		}
+
+		logf("Scanning the system cleanup targets...")
+		system, err := cleaner.EstimateSystem(cmd.Context(), IgnorePaths)
+		if err != nil {
+			return fmt.Errorf("failed to scan the system: %w", err)
+		}
+		var largeFiles *reclaimer.SummaryTable
+		if scanLargeFilesFlag {
+			logf("Scanning for large files...")
+			largeFiles, err = cleaner.EstimateLargeFiles(cmd.Context(), IgnorePaths, opts)
+			if err != nil {
+				return fmt.Errorf("failed to scan for large files: %w", err)
+			}
+		}
+
+		if scanJSONFlag {
+			return writeScanReport(system, largeFiles)
+		}
+
+		system.PrintTable(true, i18n.T("summary.estimated_title"))
+		total := system.TotalBytes(reclaimer.StatusDryRun)
+		if largeFiles != nil {
+			largeFiles.PrintTable(true, "Detected Large Files")
+			total += largeFiles.TotalBytes(reclaimer.StatusDryRun)
+		}
+		logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+		println()
+		logger.Log.Infof(utils.CyanBold("Scan finished. Reclaimable space: %s"), utils.GreenBold(reclaimer.FormatBytes(total)))
+		logger.Log.Info("Nothing was removed. Run 'wiper wipe' to clean up.")
+		return nil
+	},
+}
+
+// writeScanReport prints the estimates as a scanReport on standard output.
+// largeFiles is nil when large files weren't scanned.
+func writeScanReport(system, largeFiles *reclaimer.SummaryTable) error {
+	report := scanReport{RunID: RunID, Time: time.Now(), System: newScanSection(system)}
+	report.TotalBytes = report.System.TotalBytes
+	if largeFiles != nil {
+		report.LargeFiles = newScanSection(largeFiles)
+		report.TotalBytes += report.LargeFiles.TotalBytes
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode the scan report: %w", err)
+	}
+	return nil
+}
+
+// newScanSection summarizes an estimate for the JSON report.
+func newScanSection(estimate *reclaimer.SummaryTable) *scanSection {
+	section := &scanSection{
+		TotalBytes: estimate.TotalBytes(reclaimer.StatusDryRun),
+		Categories: estimate.TotalsByCategory(reclaimer.StatusDryRun),
+		Items:      estimate.ByStatus(reclaimer.StatusDryRun),
+		Skipped:    estimate.Skipped(),
+	}
+	// Consumers can iterate over the lists without checking for null.
+	if section.Items == nil {
+		section.Items = []reclaimer.ReclaimedEntry{}
+	}
+	return section
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the scan command with the root command.
+func init() {
+	RootCmd.AddCommand(scanCmd)
+
+	scanCmd.Flags().BoolVar(&scanLargeFilesFlag, "large-files", false, "Also report the files a large files cleanup would offer")
+	scanCmd.Flags().StringVar(&scanThresholdFlag, "threshold", "", "Minimum size for --large-files, e.g. 500MB or 1.5GiB (default 100MiB)")
+	scanCmd.Flags().BoolVar(&scanJSONFlag, "json", false, "Print the report as JSON on standard output instead of tables")
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/schedule"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// scheduleIntervalFlag is how often the scheduled cleanup runs: "daily" or "weekly".
+var scheduleIntervalFlag string
+
+// scheduleAtFlag is the local time of day the scheduled cleanup runs at, in HH:MM notation.
+var scheduleAtFlag string
+
+// scheduleWeekdayFlag is the day a weekly cleanup runs on (e.g., "sunday").
+var scheduleWeekdayFlag string
+
+// ====================================================================================================
+// SCHEDULE COMMAND DEFINITION
+// ====================================================================================================
+
+// scheduleCmd represents the schedule command.
+// It installs a LaunchAgent that runs an unattended cleanup every day or week.
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule [-- wipe-flags...]",
+	Short: "Run a cleanup every day or week with launchd.",
+	Long: `The 'schedule' command installs a LaunchAgent (~/Library/LaunchAgents/com.github.kodelint.wiper.plist)
+and loads it, so launchd runs 'wiper wipe --yes --quiet --log' daily or weekly. Every removed item
+is recorded in the log file (~/Library/Logs/wiper/wiper.log) and the run in 'wiper history'; what the
+job prints goes to ~/Library/Logs/wiper/schedule.log, which is moved to schedule.log.1 once it
+exceeds 10 MB. If the Mac is asleep at the scheduled time, the job runs when it wakes up.
+
+Flags after '--' are added to the scheduled 'wipe' command, e.g. '-- --quarantine --require-ac'.
+Running 'schedule' again replaces the installed job. Use 'schedule status' to see the installed job
+and how its last run ended, and 'schedule remove' to uninstall it. With '--dry-run', the LaunchAgent
+is printed instead of installed.`,
+	Example: `
+ wiper schedule
+ wiper schedule --interval weekly --weekday sunday --at 03:00
+ wiper schedule --interval weekly -- --quarantine --require-ac --require-idle 15m
+ wiper schedule -- --notify
+ wiper schedule status
+ wiper schedule remove`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		job, err := scheduledJob(args)
+		if err != nil {
+			return err
+		}
+		if dryRunFlag {
+			_, err := os.Stdout.Write(schedule.Plist(job))
+			return err
+		}
+		if err := schedule.Install(job); err != nil {
+			return err
+		}
+		logger.Log.Infof("Scheduled a cleanup %s: %s", job, strings.Join(job.Args, " "))
+		logger.Log.Info("Check on it with: wiper schedule status")
+		return nil
+	},
+}
+
+// scheduleRemoveCmd uninstalls the scheduled cleanup.
+var scheduleRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Uninstall the scheduled cleanup.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := schedule.Remove(); err != nil {
+			return err
+		}
+		logger.Log.Info("Removed the scheduled cleanup.")
+		return nil
+	},
+}
+
+// scheduleStatusCmd shows the scheduled cleanup and how its last run ended.
+var scheduleStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the scheduled cleanup and how its last run ended.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		job, err := schedule.Installed()
+		if errors.Is(err, schedule.ErrNotInstalled) {
+			logger.Log.Info("No scheduled cleanup is installed. Install one with: wiper schedule")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path, _ := schedule.Path()
+		state, err := schedule.Status()
+		if err != nil {
+			return err
+		}
+
+		loaded := utils.Green("loaded")
+		if !state.Loaded {
+			loaded = utils.Red("not loaded (run 'wiper schedule' again to load it)")
+		}
+		lastExit := state.LastExitCode
+		if lastExit == "" {
+			lastExit = "(not run since it was loaded)"
+		}
+		rows := [][]interface{}{
+			{"Schedule", job.String()},
+			{"Command", strings.Join(append([]string{job.Program}, job.Args...), " ")},
+			{"Launch agent", path},
+			{"State", loaded},
+			{"Last exit code", lastExit},
+			{"Output", job.LogPath},
+		}
+		// The history knows what the last cleanup removed.
+		if runs, err := history.Runs(); err == nil {
+			for _, run := range runs {
+				if run.Command == "wipe" && !run.DryRun {
+					rows = append(rows, []interface{}{"Last cleanup", fmt.Sprintf("%s, %s reclaimed (%s)",
+						run.Time.Local().Format("2006-01-02 15:04"), reclaimer.FormatBytes(run.Reclaimed), run.Status())})
+					break
+				}
+			}
+		}
+		reclaimer.PrintListTable("Scheduled Cleanup", []string{"", ""}, rows, nil)
+		return nil
+	},
+}
+
+// scheduledJob builds the job of the schedule flags, with the extra wipe flags of args.
+func scheduledJob(args []string) (schedule.Job, error) {
+	job := schedule.Job{Interval: strings.ToLower(scheduleIntervalFlag)}
+	if job.Interval != schedule.Daily && job.Interval != schedule.Weekly {
+		return job, fmt.Errorf("invalid --interval %q: expected daily or weekly", scheduleIntervalFlag)
+	}
+	if scheduleWeekdayFlag != "" && job.Interval != schedule.Weekly {
+		return job, fmt.Errorf("--weekday only applies to --interval weekly")
+	}
+	var err error
+	if job.Hour, job.Minute, err = schedule.ParseTime(scheduleAtFlag); err != nil {
+		return job, fmt.Errorf("invalid --at: %w", err)
+	}
+	if job.Interval == schedule.Weekly {
+		weekday := scheduleWeekdayFlag
+		if weekday == "" {
+			weekday = "sunday"
+		}
+		if job.Weekday, err = schedule.ParseWeekday(weekday); err != nil {
+			return job, fmt.Errorf("invalid --weekday: %w", err)
+		}
+	}
+
+	// launchd runs the job with a minimal environment, so the executable is referenced by its full path.
+	program, err := os.Executable()
+	if err != nil {
+		return job, fmt.Errorf("failed to locate the wiper executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(program); err == nil {
+		program = resolved
+	}
+	job.Program = program
+
+	job.Args = []string{"wipe", "--yes", "--quiet", "--log"}
+	if configPathStr != "" {
+		configPath, err := filepath.Abs(utils.ExpandPath(configPathStr))
+		if err != nil {
+			return job, err
+		}
+		job.Args = append(job.Args, "--config", configPath)
+	}
+	job.Args = append(job.Args, args...)
+
+	logPath, err := scheduleLogPath()
+	if err != nil {
+		return job, err
+	}
+	job.LogPath = logPath
+	return job, nil
+}
+
+// scheduleLogPath returns the log launchd writes the output of the scheduled cleanup to, next to
+// the default log file.
+func scheduleLogPath() (string, error) {
+	logPath, err := logger.DefaultLogFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(logPath), "schedule.log"), nil
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the schedule command and its subcommands with the root command.
+func init() {
+	RootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	scheduleCmd.AddCommand(scheduleStatusCmd)
+
+	scheduleCmd.Flags().StringVar(&scheduleIntervalFlag, "interval", schedule.Daily, "How often the cleanup runs: daily or weekly")
+	scheduleCmd.Flags().StringVar(&scheduleAtFlag, "at", "03:00", "Local time of day the cleanup runs at (HH:MM)")
+	scheduleCmd.Flags().StringVar(&scheduleWeekdayFlag, "weekday", "", "Day of a weekly cleanup, e.g. sunday (default sunday)")
+}
@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/kodelint/wiper/pkg/config"
 	"github.com/kodelint/wiper/pkg/logger"
 	"github.com/kodelint/wiper/pkg/utils"
 	"github.com/spf13/cobra"
@@ -42,6 +46,14 @@ const (
 	repoOwner    = "kodelint"
 	repoName     = "wiper"
 	githubAPIURL = "https://api.github.com/repos/%s/%s/releases/latest"
+
+	// updateCheckInterval is how long a release lookup (successful or not) is reused before asking GitHub again.
+	updateCheckInterval = 24 * time.Hour
+	// updateCheckCacheFile is the name of the cache file inside wiper's state directory. It is kept
+	// next to the config file rather than in the user's cache directory, which 'wipe' cleans.
+	updateCheckCacheFile = "update-check.json"
+	// updateCheckEnv disables the update check when set to a true value (e.g., WIPER_NO_UPDATE_CHECK=1).
+	updateCheckEnv = "WIPER_NO_UPDATE_CHECK"
 )
 
 // githubRelease represents the relevant fields from the GitHub API response.
@@ -49,8 +61,96 @@ type githubRelease struct {
 	TagName string `json:"tag_name"`
 }
 
-// checkForNewVersion queries the GitHub API for the latest release and compares it to the current version.
+// updateCheckCache is the cached result of the last release lookup.
+// An empty TagName records a failed lookup, so unreachable hosts are not retried on every run.
+type updateCheckCache struct {
+	CheckedAt time.Time `json:"checked_at"`
+	TagName   string    `json:"tag_name"`
+}
+
+// checkForNewVersion looks up the latest release and compares it to the current version.
+// The lookup is cached for updateCheckInterval and can be disabled via the config file or environment.
 func checkForNewVersion(currentVersion string) {
+	if updateCheckDisabled() {
+		logger.Log.Debug("Update check disabled.")
+		return
+	}
+
+	latestVersion, err := latestReleaseTag()
+	if err != nil {
+		logger.Log.Debugf("Failed to check for updates: %v", err)
+		return
+	}
+
+	if latestVersion == "" {
+		logger.Log.Debug("No release tag found.")
+		return
+	}
+
+	// Compare as semantic versions; a plain string compare would rank 0.9.0 above 0.10.0.
+	cmp, err := utils.CompareVersions(latestVersion, currentVersion)
+	if err != nil {
+		// Development builds (e.g., "development" or a commit hash) can't be compared.
+		logger.Log.Debugf("Skipping update check: %v", err)
+		return
+	}
+
+	if cmp > 0 {
+		fmt.Printf("A new version is available: %s. You are using %s.\n", utils.GreenBold(latestVersion), utils.Cyan(currentVersion))
+		fmt.Printf("Please download the new version from: https://github.com/%s/%s/releases\n", repoOwner, repoName)
+	} else {
+		fmt.Println(utils.GreenBold("You are running the latest version."))
+	}
+}
+
+// updateCheckDisabled reports whether the update check was turned off in the config file
+// (`"update_check": false`) or with the WIPER_NO_UPDATE_CHECK environment variable.
+func updateCheckDisabled() bool {
+	if config.Current.UpdateCheck != nil && !*config.Current.UpdateCheck {
+		return true
+	}
+	if value, ok := os.LookupEnv(updateCheckEnv); ok {
+		disabled, err := strconv.ParseBool(value)
+		return err != nil || disabled // Any value other than a recognizable "false" opts out.
+	}
+	return false
+}
+
+// latestReleaseTag returns the tag of the latest GitHub release, using the cached result when it is
+// recent enough. If GitHub can't be reached, a stale cached tag is returned rather than nothing.
+func latestReleaseTag() (string, error) {
+	cachePath := ""
+	if dir, err := config.Dir(); err == nil {
+		cachePath = filepath.Join(dir, updateCheckCacheFile)
+	}
+
+	cached, cacheErr := readUpdateCheckCache(cachePath)
+	if cacheErr == nil && time.Since(cached.CheckedAt) < updateCheckInterval {
+		logger.Log.Debugf("Using cached release lookup from %s", cached.CheckedAt.Format(time.RFC3339))
+		return cached.TagName, nil
+	}
+
+	tag, err := fetchLatestReleaseTag()
+	// Record failures as well, so machines without GitHub access only wait for the timeout once a day,
+	// but keep the tag of the last successful lookup.
+	checked := updateCheckCache{CheckedAt: time.Now(), TagName: tag}
+	if err != nil {
+		checked.TagName = cached.TagName
+	}
+	if writeErr := writeUpdateCheckCache(cachePath, checked); writeErr != nil {
+		logger.Log.Debugf("Failed to cache release lookup: %v", writeErr)
+	}
+	if err != nil {
+		if cacheErr == nil && cached.TagName != "" {
+			return cached.TagName, nil
+		}
+		return "", err
+	}
+	return tag, nil
+}
+
+// fetchLatestReleaseTag queries the GitHub API for the latest release.
+func fetchLatestReleaseTag() (string, error) {
 	logger.Log.Debug("Checking for new version...")
 
 	// Create an HTTP client with a timeout
@@ -63,36 +163,50 @@ func checkForNewVersion(currentVersion string) {
 
 	resp, err := client.Get(url)
 	if err != nil {
-		logger.Log.Debugf("Failed to check for updates: %v", err)
-		return
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		logger.Log.Debugf("Failed to check for updates, received status code %d", resp.StatusCode)
-		return
+		return "", fmt.Errorf("received status code %d", resp.StatusCode)
 	}
 
 	var release githubRelease
 	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		logger.Log.Debugf("Failed to decode GitHub API response: %v", err)
-		return
+		return "", fmt.Errorf("failed to decode GitHub API response: %w", err)
 	}
+	return strings.TrimSpace(release.TagName), nil
+}
 
-	latestVersion := strings.TrimSpace(release.TagName)
-	latestVersion = strings.TrimPrefix(latestVersion, "v")
-	currentVersion = strings.TrimPrefix(currentVersion, "v")
+// readUpdateCheckCache loads the cached release lookup.
+func readUpdateCheckCache(path string) (updateCheckCache, error) {
+	var cache updateCheckCache
+	if path == "" {
+		return cache, fmt.Errorf("no state directory available")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache, err
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cache, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cache, nil
+}
 
-	if latestVersion != "" && latestVersion != currentVersion {
-		// A more robust version comparison would be needed for complex schemes
-		// but a simple string compare is often sufficient for basic use cases.
-		if latestVersion > currentVersion {
-			fmt.Printf("A new version is available: %s. You are using %s.\n", utils.GreenBold(release.TagName), utils.Cyan(currentVersion))
-			fmt.Printf("Please download the new version from: https://github.com/%s/%s/releases\n", repoOwner, repoName)
-		}
-	} else {
-		fmt.Println(utils.GreenBold("You are running the latest version."))
+// writeUpdateCheckCache stores a release lookup, creating the state directory if necessary.
+func writeUpdateCheckCache(path string, cache updateCheckCache) error {
+	if path == "" {
+		return fmt.Errorf("no state directory available")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
 	}
+	return os.WriteFile(path, data, 0o644)
 }
 
 // ====================================================================================================
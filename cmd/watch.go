@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/quarantine"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ====================================================================================================
+// COMMAND-SPECIFIC FLAGS
+// ====================================================================================================
+
+// watchThresholdFlag is the free space below which `watch` acts: a percentage of the volume
+// (e.g., "10%") or a size (e.g., "20GB").
+var watchThresholdFlag string
+
+// watchIntervalFlag is how often `watch` checks the free space (e.g., "5m").
+var watchIntervalFlag string
+
+// watchCooldownFlag is how long `watch` waits after acting before it acts again (e.g., "1h").
+var watchCooldownFlag string
+
+// watchProfileFlag is the cleanup profile `watch` runs when space is low.
+var watchProfileFlag string
+
+// watchNotifyOnlyFlag makes `watch` only report low space instead of cleaning.
+var watchNotifyOnlyFlag bool
+
+// watchPathFlag is a path on the volume `watch` monitors; the home directory by default.
+var watchPathFlag string
+
+// ====================================================================================================
+// WATCH COMMAND DEFINITION
+// ====================================================================================================
+
+// watchCmd represents the watch command.
+// It monitors the free space of a volume and runs a cleanup profile when it runs low.
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Clean up automatically when free disk space runs low.",
+	Long: `The 'watch' command checks the free space of a volume (the one holding your home directory, or
+'--path') every '--interval', and runs a cleanup profile without prompting when the free space drops
+below '--threshold': a percentage of the volume (e.g., 10%) or a size (e.g., 20GB). The 'safe'
+profile (temporary files and caches) is used unless '--profile full' is given.
+
+With '--notify-only', nothing is removed: wiper warns that space is low and how much the profile
+would reclaim. Either way, each action is recorded in 'wiper history' as a 'watch' run. After acting,
+wiper waits for '--cooldown' before acting again, so a cleanup that can't free enough space doesn't
+run over and over. Stop watching with Ctrl+C.`,
+	Example: `
+ wiper watch --threshold 10%
+ wiper watch --threshold 20GB --interval 1m --profile full
+ wiper watch --threshold 15% --notify-only`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threshold, err := parseFreeSpaceThreshold(watchThresholdFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --threshold: %w", err)
+		}
+		interval, err := utils.ParseDuration(watchIntervalFlag)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid --interval %q: expected a duration such as 5m", watchIntervalFlag)
+		}
+		cooldown, err := utils.ParseDuration(watchCooldownFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --cooldown: %w", err)
+		}
+		if !isProfile(watchProfileFlag) {
+			return fmt.Errorf("invalid --profile %q: expected one of %s", watchProfileFlag, strings.Join(cleaner.Profiles(), ", "))
+		}
+		path := watchPathFlag
+		if path == "" {
+			if path, err = os.UserHomeDir(); err != nil {
+				return err
+			}
+		}
+		path = utils.ExpandPath(path)
+		if _, _, err := utils.VolumeSpace(path); err != nil {
+			return err
+		}
+
+		// Ctrl+C stops watching, or the cleanup in progress between items.
+		ctx, stop := interruptContext(cmd.Context())
+		defer stop()
+
+		logger.Log.Infof("Watching the free space of %s every %s (threshold: %s)", path, interval, threshold)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var lastAction time.Time
+		for {
+			free, total, err := utils.VolumeSpace(path)
+			switch {
+			case err != nil:
+				logger.Log.Warnf("Could not read the free space of %s: %v", path, err)
+			case !threshold.low(free, total):
+				logger.Log.Debugf("Free space on %s: %s of %s", path, reclaimer.FormatBytes(free), reclaimer.FormatBytes(total))
+			case !lastAction.IsZero() && time.Since(lastAction) < cooldown:
+				logger.Log.Debugf("Free space on %s is still low (%s), but the last action was less than %s ago", path, reclaimer.FormatBytes(free), cooldown)
+			default:
+				lastAction = time.Now()
+				watchAct(ctx, path, free, total)
+			}
+
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("Stopped watching.")
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// watchAct runs the watched profile (or estimates it, with --notify-only) because the volume at path
+// only has free of total bytes left, and records it in the history as a run of its own.
+func watchAct(ctx context.Context, path string, free, total int64) {
+	logger.Log.Warnf("Free space on %s is low: %s of %s", path, reclaimer.FormatBytes(free), reclaimer.FormatBytes(total))
+
+	estimateOnly := watchNotifyOnlyFlag || dryRunFlag
+	// Each action is a run of its own, with its own manifest for `wiper restore`.
+	startRun("watch", estimateOnly, quarantine.Enabled())
+	history.SetMode(fmt.Sprintf("profile %s, %s free", watchProfileFlag, reclaimer.FormatBytes(free)))
+	summary := reclaimer.NewSummaryTable()
+	reclaimed, err := cleaner.CleanProfile(ctx, watchProfileFlag, estimateOnly, IgnorePaths, summary)
+	if histErr := history.Finish(err); histErr != nil {
+		logger.Log.Warnf("Could not record the run in the history: %v", histErr)
+	}
+	logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+
+	switch {
+	case err != nil:
+		logger.Log.Errorf("Cleanup profile '%s' failed: %v", watchProfileFlag, err)
+	case estimateOnly:
+		logger.Log.Warnf("The '%s' profile would reclaim %s; run 'wiper wipe' to clean up", watchProfileFlag, utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+	default:
+		logger.Log.Infof(utils.CyanBold("Cleanup completed. Space reclaimed: %s"), utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+		if failed := summary.FailedCount(); failed > 0 {
+			logger.Log.Warnf("%d item(s) could not be removed", failed)
+		}
+	}
+}
+
+// freeSpaceThreshold is the free space below which `watch` acts: a share of the volume, or bytes.
+type freeSpaceThreshold struct {
+	percent float64
+	bytes   int64
+}
+
+// parseFreeSpaceThreshold parses a percentage (e.g., "10%") or a size (e.g., "20GB").
+func parseFreeSpaceThreshold(s string) (freeSpaceThreshold, error) {
+	s = strings.TrimSpace(s)
+	if number, ok := strings.CutSuffix(s, "%"); ok {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil || percent <= 0 || percent >= 100 {
+			return freeSpaceThreshold{}, fmt.Errorf("%q is not a percentage between 0 and 100", s)
+		}
+		return freeSpaceThreshold{percent: percent}, nil
+	}
+	size, err := utils.ParseBytes(s)
+	if err != nil {
+		return freeSpaceThreshold{}, err
+	}
+	if size <= 0 {
+		return freeSpaceThreshold{}, fmt.Errorf("%q must be more than 0 bytes", s)
+	}
+	return freeSpaceThreshold{bytes: size}, nil
+}
+
+// low reports whether free of total bytes is below the threshold.
+func (t freeSpaceThreshold) low(free, total int64) bool {
+	if t.percent > 0 {
+		return total > 0 && float64(free) < float64(total)*t.percent/100
+	}
+	return free < t.bytes
+}
+
+// String returns the threshold as given on the command line.
+func (t freeSpaceThreshold) String() string {
+	if t.percent > 0 {
+		return strconv.FormatFloat(t.percent, 'f', -1, 64) + "% free"
+	}
+	return reclaimer.FormatBytes(t.bytes) + " free"
+}
+
+// isProfile reports whether name is one of the cleanup profiles.
+func isProfile(name string) bool {
+	for _, profile := range cleaner.Profiles() {
+		if profile == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ====================================================================================================
+// INITIALIZATION
+// ====================================================================================================
+
+// init registers the watch command with the root command.
+func init() {
+	RootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().StringVar(&watchThresholdFlag, "threshold", "10%", "Act when free space drops below this share of the volume (e.g., 10%) or size (e.g., 20GB)")
+	watchCmd.Flags().StringVar(&watchIntervalFlag, "interval", "5m", "How often the free space is checked")
+	watchCmd.Flags().StringVar(&watchCooldownFlag, "cooldown", "1h", "How long to wait after a cleanup before cleaning again")
+	watchCmd.Flags().StringVar(&watchProfileFlag, "profile", "safe", "Cleanup profile to run when space is low: "+strings.Join(cleaner.Profiles(), " or "))
+	watchCmd.Flags().BoolVar(&watchNotifyOnlyFlag, "notify-only", false, "Only warn that space is low and how much would be reclaimed, without cleaning")
+	watchCmd.Flags().StringVar(&watchPathFlag, "path", "", "A path on the volume to watch (default: the home directory)")
+}
@@ -1,13 +1,21 @@
 package cmd
 
 import (
-	"fmt" // Used for formatted I/O, primarily for printing messages and errors.
-
-	"github.com/kodelint/wiper/pkg/cleaner"   // Contains the core cleanup logic, such as uninstalling and cleaning files.
-	"github.com/kodelint/wiper/pkg/logger"    // Provides a structured logging interface for debug and info messages.
-	"github.com/kodelint/wiper/pkg/reclaimer" // Manages and formats disk space reclaimed during cleanup.
-	"github.com/kodelint/wiper/pkg/utils"     // A collection of utility functions, such as for colored output.
-	"github.com/spf13/cobra"                  // The primary library for building the command-line interface.
+	"fmt"           // Used for formatted I/O, primarily for printing messages and errors.
+	"os"            // Used to check that --tui runs in a terminal.
+	"path/filepath" // Used to resolve the downloads archive directory to an absolute path.
+	"strings"       // Used to split the comma-separated values of --path and --type.
+
+	"github.com/kodelint/wiper/pkg/cleaner"    // Contains the core cleanup logic, such as uninstalling and cleaning files.
+	"github.com/kodelint/wiper/pkg/config"     // Provides the downloads policy from the config file.
+	"github.com/kodelint/wiper/pkg/history"    // Records what the run cleaned for `wiper history`.
+	"github.com/kodelint/wiper/pkg/i18n"       // Provides localized prompts and summary titles.
+	"github.com/kodelint/wiper/pkg/logger"     // Provides a structured logging interface for debug and info messages.
+	"github.com/kodelint/wiper/pkg/power"      // Checks the power and idle conditions of scheduled runs.
+	"github.com/kodelint/wiper/pkg/quarantine" // Tells whether removed items were kept for `wiper restore`.
+	"github.com/kodelint/wiper/pkg/reclaimer"  // Manages and formats disk space reclaimed during cleanup.
+	"github.com/kodelint/wiper/pkg/utils"      // A collection of utility functions, such as for colored output.
+	"github.com/spf13/cobra"                   // The primary library for building the command-line interface.
 )
 
 // ====================================================================================================
@@ -18,10 +26,131 @@ import (
 // It is a local flag, specific to the `wipe` command.
 var largeFilesFlag bool
 
-// interactiveFlag enables interactive mode, prompting for confirmation before each deletion.
+// interactiveFlag enables interactive mode, prompting for confirmation before each deletion
+// (large files) or each category (system cleanup).
 // It is a local flag for the `wipe` command.
 var interactiveFlag bool
 
+// dockerFlag prunes unused Docker objects through the Docker API instead of cleaning files.
+// It is a local flag for the `wipe` command.
+var dockerFlag bool
+
+// tuiFlag shows the detected items in a checkbox list to pick from, instead of asking yes/no.
+// It is a local flag for the `wipe` command.
+var tuiFlag bool
+
+// volumeFlag points the cleanup at a specific mounted volume (e.g., /Volumes/External).
+// It is a local flag for the `wipe` command.
+var volumeFlag string
+
+// largeFilesPathFlag is a comma-separated list of directories the large file scan covers instead of
+// the default locations (e.g., "/Volumes/Data,~/Projects").
+// It is a local flag for the `wipe` command.
+var largeFilesPathFlag string
+
+// olderThanFlag only lists large files not modified within this window (e.g., "90d"), and
+// byAccessFlag makes it look at the last access time instead. They are local flags for the `wipe` command.
+var (
+	olderThanFlag string
+	byAccessFlag  bool
+)
+
+// typeFlag limits the large file scan to comma-separated file type groups (e.g., "video,diskimage").
+// It is a local flag for the `wipe` command.
+var typeFlag string
+
+// topFlag lists the N largest files instead of every file above the threshold.
+// It is a local flag for the `wipe` command.
+var topFlag int
+
+// includeVolumesFlag adds the external volumes (e.g., /Volumes/*) to the large file scan.
+// It is a local flag for the `wipe` command.
+var includeVolumesFlag bool
+
+// expandFlag is the number of largest paths listed under each category in the summary tables.
+// It is a local flag for the `wipe` command.
+var expandFlag int
+
+// showSkippedFlag lists every skipped path and the reason it was excluded.
+// It is a local flag for the `wipe` command.
+var showSkippedFlag bool
+
+// detailsFlag lists every item with its size, status and error after the summary tables, and
+// detailsLimitFlag is how many of them at most. They are local flags for the `wipe` command.
+var (
+	detailsFlag      bool
+	detailsLimitFlag int
+)
+
+// thresholdFlag is the minimum size for large files, in human-readable form (e.g., "500MB", "1.5GiB").
+// It is a local flag for the `wipe` command.
+var thresholdFlag string
+
+// spotlightFlag finds large files through the Spotlight index instead of walking the filesystem, and
+// makes application uninstalls also search the index for leftovers.
+// It is a local flag for the `wipe` command.
+var spotlightFlag bool
+
+// summaryCSVFlag is the file the reclaim summary is written to as CSV, per item and per category.
+// It is a local flag for the `wipe` command.
+var summaryCSVFlag string
+
+// keepPreferencesFlag keeps an app's preferences and Application Support data when it is uninstalled.
+// It is a local flag for the `wipe` command.
+var keepPreferencesFlag bool
+
+// freeFlag is the amount of space to free in goal mode, in human-readable form (e.g., "30GB").
+// It is a local flag for the `wipe` command.
+var freeFlag string
+
+// requireACFlag, requireIdleFlag and skipLowPowerFlag defer the cleanup until the machine is on
+// AC power, idle for a while, and not in Low Power Mode. They are meant for scheduled runs.
+// They are local flags for the `wipe` command.
+var (
+	requireACFlag    bool
+	requireIdleFlag  string
+	skipLowPowerFlag bool
+)
+
+// timingsFlag prints how long scanning and deleting took per category.
+// It is a local flag for the `wipe` command.
+var timingsFlag bool
+
+// minAgeFlag is the minimum age for system cleanup items, in human-readable form (e.g., "7d", "36h").
+// It is a local flag for the `wipe` command.
+var minAgeFlag string
+
+// archiveDownloadsFlag moves old downloads into this directory instead of deleting them.
+// It is a local flag for the `wipe` command and overrides `downloads.archive_dir` in the config file.
+var archiveDownloadsFlag string
+
+// browserProfilesFlag is a comma-separated list of browser profiles whose caches are cleaned
+// (e.g., "Default,Work"). It is a local flag for the `wipe` command and overrides `browser_profiles`.
+var browserProfilesFlag string
+
+// allowICloudFlag allows deleting items in iCloud-synced locations, which are skipped by default.
+// It is a local flag for the `wipe` command.
+var allowICloudFlag bool
+
+// protectTagFlag is the Finder tag that protects items from the Downloads and large file cleanups.
+// It is a local flag for the `wipe` command and overrides `protect_tag` in the config file.
+var protectTagFlag string
+
+// trashFlag moves cleaned items to the Trash instead of deleting them permanently.
+// It is a local flag for the `wipe` command; `trash` in the config file enables it as well.
+var trashFlag bool
+
+// quarantineFlag moves cleaned items into the quarantine of the run, so `wiper restore` can put them back.
+// It is a local flag for the `wipe` command; `quarantine` in the config file enables it as well.
+var quarantineFlag bool
+
+// secureFlag overwrites the contents of files before deleting them, and securePassesFlag is how often.
+// They are local flags for the `wipe` command, for large file and interactive cleanups.
+var (
+	secureFlag       bool
+	securePassesFlag int
+)
+
 // ====================================================================================================
 // WIPE COMMAND DEFINITION
 // ====================================================================================================
@@ -35,32 +164,149 @@ var wipeCmd = &cobra.Command{
 	Long: `The 'wipe' command performs two primary functions:
 
 1.  Application Uninstallation: If an application name is provided (e.g., 'wiper wipe "Google Chrome"'),
-   it will attempt to uninstall the specified application and remove its associated files.
+   it will attempt to uninstall the specified application and remove its associated files,
+   including its launch agents and daemons, which are unloaded with 'launchctl' first, and its
+   login items. Background items the app registered with macOS itself ("Allow in the Background"
+   in System Settings > General > Login Items) can't be removed by wiper; run with sudo to have
+   them listed with the warnings, and remove them there.
+   For apps installed with a .pkg installer, it also offers to remove the files the package put
+   outside the app (as listed by 'pkgutil --files') and to forget the package receipt.
+   Add '--spotlight' to also search the Spotlight index for files named after the app's bundle
+   identifier anywhere on disk. Add '--keep-preferences' to keep the app's files in
+   ~/Library/Preferences and ~/Library/Application Support, so a reinstalled app keeps its settings.
 
 2.  System Cleanup: If no application name is provided (e.g., 'wiper wipe'),
    it will perform a comprehensive system cleanup, removing junk files, temporary files,
-   and caches from various locations on macOS.
+   and caches from various locations on macOS. On developer Macs, this includes Xcode's DerivedData,
+   and device support files and simulator caches that haven't been touched for months (archives
+   too, with '--enable-targets xcode_archives').
+   The caches of package managers and build tools (npm, Yarn, pnpm, Go, pip, conda, Gradle, Maven,
+   CocoaPods, Carthage) are cleaned too; most of them keep what was used recently.
 
 3.  Large Files Cleanup: If the '--large-files' flag is used (e.g., 'wiper wipe --large-files'),
    it will identify and offer to clean up large files that are not typically part of
-   standard system cleanup.
+   standard system cleanup. Add '--spotlight' to query the Spotlight index instead of
+   walking every directory; locations Spotlight doesn't index are still scanned. Add '--path' to
+   scan other directories instead of the home folders, e.g. an external drive. Add '--older-than 90d'
+   to only list files that weren't modified for 90 days, or weren't opened with '--by-access', and
+   '--type video,diskimage' to only list movies and disk images (see '--help' for the groups).
+   Add '--top 25' to rank the 25 largest files instead of listing every file above the threshold.
+   Add '--include-volumes' to also scan the external volumes; Time Machine backups and read-only
+   volumes are skipped.
+
+4.  Docker Cleanup: If the '--docker' flag is used, it prunes stopped containers, dangling images,
+   unused build cache and unused anonymous volumes through the Docker API, like 'docker system prune'
+   with volumes but without touching tagged images or named volumes. It also reports how much space
+   the Docker Desktop disk image (Docker.raw) takes.
+
+Use the '--free' flag to clean only until a given amount of space is freed. The plan takes the
+safest categories first (caches, temporary files, Trash, old downloads, then large files), largest
+items first, and stops as soon as the goal is met.
 
 Use the '--dry-run' flag to see what will be removed without making actual changes.
+Use the '--trash' flag (or 'trash' in the config file) to move items to the Trash instead of deleting
+them, so they can be recovered. Emptying the Trash itself still deletes permanently. Caches owned by
+a tool (e.g., the Go module cache) are always removed with that tool, also with '--quarantine' and
+'--secure'.
+
+Use the '--secure' flag with '--large-files' or '--interactive' to overwrite the contents of files
+before deleting them ('--secure-passes' times, the last time with random data), for sensitive
+documents rather than caches. On SSDs and APFS, copies of the data may survive overwriting; FileVault
+is the dependable protection there.
+
+Use the '--quarantine' flag (or 'quarantine' in the config file) to keep removed items in wiper's
+quarantine instead, so 'wiper restore <run-id>' can put them back. It takes precedence over '--trash'.
+Every cleanup records what it removed in a manifest, whether or not the items were kept.
+
 Use the '--ignore' flag to specify paths to exclude from system cleanup.
-Use the '--interactive' flag to confirm each deletion individually.`,
+Use the '--interactive' flag to confirm each deletion individually. For the system cleanup it asks
+once per category instead ("Clean User Caches (4.2 GB)? (y/N/all/quit)").
+Use the '--tui' flag to pick the items to clean from a checkbox list instead: items are grouped by
+category, the selected total is updated as you toggle them, and Enter asks once more before cleaning.
+Use the '--volume' flag to limit large files and Trash cleanup to a specific mounted volume.
+
+For scheduled runs, '--require-ac', '--require-idle' and '--skip-low-power' defer the cleanup
+(exiting successfully) unless the machine is on AC power, idle long enough, and not in Low Power Mode.
+
+When Desktop & Documents are synced with iCloud, old downloads and large files in the synced folders
+are skipped, because deleting them also deletes them from iCloud and your other devices.
+Use the '--allow-icloud' flag to include them.
+
+Files and folders tagged "Keep" in Finder are never cleaned by the Downloads and large file cleanups.
+Use the '--protect-tag' flag (or 'protect_tag' in the config file) to choose another tag.
+
+Use the '--details' flag to list every item after the summary, largest first, with its size, what
+happened to it and the error if it couldn't be removed. Long lists are cut after '--details-limit'
+items (100 by default, 0 for all).
+
+Use the '--summary-csv' flag to also write the summary to a CSV file, with a row for every item and
+a total for every category (estimates in a dry run).
+
+The command exits with status 0 when it cleaned something (or would, in a dry run), 1 on a fatal
+error, 2 when there was nothing to clean, 3 when some items could not be removed, 4 when the
+cleanup was declined at a prompt, and 130 when it was interrupted with Ctrl+C.`,
 	Example: `
  # Uninstall an application
  wiper wipe "Google Chrome"
+ wiper wipe "Visual Studio Code" --spotlight --dry-run
+ wiper wipe "Visual Studio Code" --keep-preferences
  wiper wipe "VS Code" --dry-run
 
  # Perform a full system cleanup
  wiper wipe
  wiper wipe --dry-run
+ wiper wipe --yes   # unattended, e.g. from a launchd job
+ wiper wipe --yes --quiet   # only the summary, e.g. for the mail of a cron job
+
+ # Show the 5 largest paths of each category in the summary
+ wiper wipe --dry-run --expand 5
+
+ # List every item with its size and status after the summary
+ wiper wipe --details --details-limit 20
+
+ # Find slow cleanup targets
+ wiper wipe --dry-run --timings
+
+ # Save the summary for a spreadsheet
+ wiper wipe --summary-csv wiper-summary.csv
+
+ # Only report files of 1 GiB and more, or only clean items older than a week
+ wiper wipe --large-files --threshold 1GiB --dry-run
+ wiper wipe --min-age 7d
+
+ # Free 30 GB, starting with the safest categories
+ wiper wipe --free 30GB --dry-run
+
+ # Only clean the caches of some browser profiles
+ wiper wipe --browser-profiles "Default,Work"
+
+ # Archive old downloads to an external drive instead of deleting them
+ wiper wipe --archive-downloads /Volumes/Backup/Downloads
+
+ # Keep removed items so they can be restored with 'wiper restore'
+ wiper wipe --quarantine
+
+ # Overwrite sensitive documents before deleting them
+ wiper wipe --large-files --interactive --secure --secure-passes 3
+
+ # Clean only while plugged in and after 15 minutes without input (e.g., from a scheduler)
+ wiper wipe --require-ac --require-idle 15m --skip-low-power
 
  # Perform a large files cleanup
  wiper wipe --large-files
  wiper wipe --dry-run --large-files
  wiper wipe --large-files --interactive
+ wiper wipe --interactive
+ wiper wipe --tui
+ wiper wipe --large-files --spotlight
+ wiper wipe --large-files --jobs 32
+
+ # Prune unused Docker containers, images, build cache and volumes
+ wiper wipe --docker --dry-run
+
+ # Scan an external drive for large files, or empty its Trash
+ wiper wipe --large-files --volume /Volumes/External
+ wiper wipe --volume /Volumes/External
 
  # Perform system cleanup, ignoring specific paths
  wiper wipe --ignore "/Users/john/Downloads,/System/Library/Caches"`,
@@ -79,6 +325,21 @@ Use the '--interactive' flag to confirm each deletion individually.`,
 		if len(IgnorePaths) > 0 {
 			logger.Log.Debugf("Ignore Paths: %v", IgnorePaths)
 		}
+		cleaner.SetAllowCloudSynced(allowICloudFlag)
+
+		// Scheduled runs wait for a good moment instead of slowing down a busy or unplugged machine.
+		conditions := power.Conditions{RequireAC: requireACFlag, SkipLowPower: skipLowPowerFlag}
+		if requireIdleFlag != "" {
+			minIdle, err := utils.ParseDuration(requireIdleFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --require-idle: %w", err)
+			}
+			conditions.MinIdle = minIdle
+		}
+		if reason := conditions.Check(); reason != "" {
+			logger.Log.Infof("Deferring cleanup: %s", reason)
+			return nil
+		}
 
 		// Log the status of local flags for the wipe command.
 		if largeFilesFlag {
@@ -87,12 +348,108 @@ Use the '--interactive' flag to confirm each deletion individually.`,
 		if interactiveFlag {
 			logger.Log.Debugf("Interactive Mode: %t", interactiveFlag)
 		}
+		if tuiFlag {
+			if interactiveFlag {
+				return fmt.Errorf("the --tui flag cannot be combined with --interactive")
+			}
+			if !utils.IsTerminal(os.Stdin) || !utils.IsTerminal(os.Stdout) {
+				return fmt.Errorf("the --tui flag needs an interactive terminal")
+			}
+			logger.Log.Debugf("Selection List: %t", tuiFlag)
+		}
+		cleaner.SetSelectionUI(tuiFlag)
+		// Sensitive documents are overwritten before they are unlinked, which a Trash or quarantine would defeat.
+		if secureFlag {
+			if !largeFilesFlag && !interactiveFlag {
+				return fmt.Errorf("the --secure flag only applies to --large-files and --interactive cleanups")
+			}
+			if utils.TrashMode() || quarantine.Enabled() {
+				return fmt.Errorf("the --secure flag cannot be combined with --trash or --quarantine")
+			}
+			if securePassesFlag < 1 {
+				return fmt.Errorf("invalid --secure-passes %d: expected 1 or more", securePassesFlag)
+			}
+			utils.SetSecureErase(securePassesFlag)
+		}
+		// Interactive modes ask for choices that --yes can't answer.
+		if yesFlag && (interactiveFlag || tuiFlag) {
+			return fmt.Errorf("the --yes flag cannot be combined with --interactive or --tui")
+		}
+
+		// The Docker cleanup only talks to the Docker daemon, so it doesn't mix with file cleanups.
+		if dockerFlag && (len(args) > 0 || largeFilesFlag || volumeFlag != "" || freeFlag != "") {
+			return fmt.Errorf("the --docker flag cannot be combined with an application name, --large-files, --volume or --free")
+		}
+
+		// Custom scan roots only apply to the large file scan, which --volume also narrows down.
+		if largeFilesPathFlag != "" && (!largeFilesFlag || volumeFlag != "") {
+			return fmt.Errorf("the --path flag needs --large-files and cannot be combined with --volume")
+		}
+
+		if (olderThanFlag != "" || byAccessFlag || typeFlag != "" || topFlag != 0) && !largeFilesFlag {
+			return fmt.Errorf("the --older-than, --by-access, --type and --top flags need --large-files")
+		}
+		if includeVolumesFlag && (!largeFilesFlag || volumeFlag != "") {
+			return fmt.Errorf("the --include-volumes flag needs --large-files and cannot be combined with --volume")
+		}
+		if topFlag < 0 {
+			return fmt.Errorf("invalid --top %d: expected 1 or more", topFlag)
+		}
+		if byAccessFlag && olderThanFlag == "" {
+			return fmt.Errorf("the --by-access flag needs --older-than")
+		}
+
+		// Goal mode plans across the system and large file cleanups by itself.
+		if freeFlag != "" && (len(args) > 0 || largeFilesFlag || volumeFlag != "") {
+			return fmt.Errorf("the --free flag cannot be combined with an application name, --large-files or --volume")
+		}
+
+		// Resolve the target volume up front so an invalid path fails before any scanning.
+		var volume string
+		var freeBefore int64
+		if volumeFlag != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("the --volume flag cannot be used with an application name")
+			}
+			resolved, err := utils.ValidateVolume(volumeFlag)
+			if err != nil {
+				return err
+			}
+			volume = resolved
+			logger.Log.Debugf("Target Volume: %s", volume)
+			if free, _, err := utils.VolumeSpace(volume); err == nil {
+				freeBefore = free
+			}
+		}
+
+		// Enable the per-path drill-down in summary tables if requested.
+		reclaimer.SetDrillDown(expandFlag)
+		reclaimer.SetShowSkipped(showSkippedFlag)
+		if detailsLimitFlag < 0 {
+			return fmt.Errorf("--details-limit must not be negative")
+		}
+		reclaimer.SetDetails(detailsFlag, detailsLimitFlag)
 
 		var reclaimed int64
 		summary := reclaimer.NewSummaryTable()
 		estimatedSummary := reclaimer.NewSummaryTable()
+		if timingsFlag {
+			// Both tables share one recorder: scans are recorded on the estimate, deletions on the summary.
+			timings := reclaimer.NewTimings()
+			summary.Timings = timings
+			estimatedSummary.Timings = timings
+		}
+		if volume != "" {
+			// Percentages are relative to the selected volume rather than the startup disk.
+			summary.Volume = volume
+			estimatedSummary.Volume = volume
+		}
 		var err error
 
+		// Ctrl+C stops the cleanup between items; what was removed until then is still reported below.
+		ctx, stop := interruptContext(cmd.Context())
+		defer stop()
+
 		// =================================================================
 		// Logic Branching: Large Files, Application, or System Cleanup
 		// =================================================================
@@ -104,48 +461,159 @@ Use the '--interactive' flag to confirm each deletion individually.`,
 				return fmt.Errorf("the --large-files flag cannot be used with an application name")
 			}
 			logger.Log.Info("Performing large files cleanup...")
+			history.SetMode("large files")
 
 			// Call the CleanLargeFiles function from the cleaner package.
 			// The dryRunFlag and IgnorePaths are passed to control the cleanup process.
 			// The interactiveFlag is used to prompt for each deletion.
-			reclaimed, err = cleaner.CleanLargeFiles(dryRunFlag, IgnorePaths, summary, estimatedSummary, interactiveFlag)
-			if err != nil {
+			// When a volume is selected, only that volume is scanned.
+			var opts cleaner.LargeFileOptions
+			if volume != "" {
+				opts.ScanRoots = []string{volume}
+			}
+			if largeFilesPathFlag != "" {
+				roots, err := scanRootsFromFlag(largeFilesPathFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --path: %w", err)
+				}
+				opts.ScanRoots = roots
+			}
+			if thresholdFlag != "" {
+				threshold, err := utils.ParseBytes(thresholdFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --threshold: %w", err)
+				}
+				opts.Threshold = threshold
+			}
+			if olderThanFlag != "" {
+				olderThan, err := utils.ParseDuration(olderThanFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --older-than: %w", err)
+				}
+				opts.OlderThan = olderThan
+				opts.ByAccess = byAccessFlag
+			}
+			if typeFlag != "" {
+				for _, name := range strings.Split(typeFlag, ",") {
+					if name = strings.TrimSpace(name); name != "" {
+						opts.Types = append(opts.Types, name)
+					}
+				}
+				if _, err := cleaner.FileTypeExtensions(opts.Types); err != nil {
+					return fmt.Errorf("invalid --type: %w", err)
+				}
+			}
+			opts.Top = topFlag
+			opts.IncludeVolumes = includeVolumesFlag
+			opts.UseSpotlight = spotlightFlag
+			reclaimed, err = cleaner.CleanLargeFiles(ctx, dryRunFlag, IgnorePaths, summary, estimatedSummary, interactiveFlag, opts)
+			if err != nil && ctx.Err() == nil {
 				return fmt.Errorf("failed to clean large files: %w", err)
 			}
 
-			// Case 2: Application Uninstallation
+			// Case 2: Docker Cleanup
+		} else if dockerFlag {
+			logger.Log.Info("Pruning unused Docker objects...")
+			history.SetMode("docker")
+			if interactiveFlag || tuiFlag {
+				logger.Log.Warn("Interactive mode is not supported for the Docker cleanup and will be ignored.")
+			}
+			if utils.TrashMode() || quarantine.Enabled() {
+				logger.Log.Warn("Docker deletes its objects itself; they can't be moved to the Trash or the quarantine.")
+			}
+			reclaimed, err = cleaner.CleanDocker(ctx, dryRunFlag, summary, estimatedSummary)
+			if err != nil && ctx.Err() == nil {
+				return fmt.Errorf("failed to clean Docker: %w", err)
+			}
+
+			// Case 3: Application Uninstallation
 		} else if len(args) == 1 {
 			appName := args[0]
 			// Warn the user that interactive mode is not supported for this action.
-			if interactiveFlag {
+			if interactiveFlag || tuiFlag {
 				logger.Log.Warn("Interactive mode is not supported for application uninstallation and will be ignored.")
 			}
 			logger.Log.Infof("Attempting to uninstall application: %s", appName)
+			history.SetMode("uninstall " + appName)
 
 			// Confirm with the user before proceeding with the uninstallation.
-			prompt := fmt.Sprintf("Do you really want to uninstall application: %s?", appName)
-			if cleaner.ConfirmAction(prompt) {
+			prompt := i18n.T("prompt.uninstall", appName)
+			if cleaner.ConfirmAction(ctx, prompt) {
 				// Call the UninstallApplication function from the cleaner package.
-				reclaimed, err = cleaner.UninstallApplication(appName, dryRunFlag, IgnorePaths, summary, estimatedSummary)
-				if err != nil {
+				opts := cleaner.UninstallOptions{UseSpotlight: spotlightFlag, KeepPreferences: keepPreferencesFlag}
+				reclaimed, err = cleaner.UninstallApplication(ctx, appName, dryRunFlag, IgnorePaths, summary, estimatedSummary, opts)
+				if err != nil && ctx.Err() == nil {
 					return fmt.Errorf("failed to uninstall %s: %w", appName, err)
 				}
-				logger.Log.Infof("Application uninstallation completed. Space reclaimed: %s", reclaimer.FormatBytes(reclaimed))
-			} else {
-				return fmt.Errorf("aborting uninstallation of %s", appName)
+				if ctx.Err() == nil {
+					logger.Log.Infof("Application uninstallation completed. Space reclaimed: %s", reclaimer.FormatBytes(reclaimed))
+				}
+			} else if ctx.Err() == nil {
+				logger.Log.Infof("Aborting uninstallation of %s.", appName)
+				cmd.SilenceUsage = true
+				return errAborted
 			}
 
-			// Case 3: System Cleanup (Default)
-		} else {
-			logger.Log.Info("Performing system-wide cleanup...")
-			// Warn the user that interactive mode is not supported for this action.
+			// Case 4: Volume Trash Cleanup
+		} else if volume != "" {
+			logger.Log.Infof("Emptying Trash on volume %s...", volume)
+			history.SetMode("trash on " + volume)
 			if interactiveFlag {
-				logger.Log.Warn("Interactive mode is not supported for system-wide cleanup and will be ignored.")
+				logger.Log.Warn("Interactive mode is not supported for volume Trash cleanup and will be ignored.")
 			}
 
-			// Call the CleanSystem function from the cleaner package.
-			space, err := cleaner.CleanSystem(dryRunFlag, IgnorePaths, summary, estimatedSummary)
+			space, err := cleaner.CleanVolumeTrash(ctx, volume, dryRunFlag, IgnorePaths, summary, estimatedSummary)
+			if err != nil && ctx.Err() == nil {
+				return fmt.Errorf("failed to clean trash on %s: %w", volume, err)
+			}
+			reclaimed = space
+
+			// Case 5: Free Space Goal
+		} else if freeFlag != "" {
+			goal, err := utils.ParseBytes(freeFlag)
 			if err != nil {
+				return fmt.Errorf("invalid --free: %w", err)
+			}
+			history.SetMode("free " + freeFlag)
+			opts := cleaner.GoalOptions{Goal: goal, System: cleaner.SystemOptions{PerCategory: interactiveFlag}}
+			if minAgeFlag != "" {
+				minAge, err := utils.ParseDuration(minAgeFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --min-age: %w", err)
+				}
+				opts.System.MinAge = minAge
+			}
+			if thresholdFlag != "" {
+				threshold, err := utils.ParseBytes(thresholdFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --threshold: %w", err)
+				}
+				opts.LargeFiles.Threshold = threshold
+			}
+			opts.LargeFiles.UseSpotlight = spotlightFlag
+			space, err := cleaner.CleanToGoal(ctx, dryRunFlag, IgnorePaths, summary, estimatedSummary, opts)
+			if err != nil && ctx.Err() == nil {
+				return fmt.Errorf("failed to free %s: %w", freeFlag, err)
+			}
+			reclaimed = space
+
+			// Case 6: System Cleanup (Default)
+		} else {
+			logger.Log.Info("Performing system-wide cleanup...")
+			history.SetMode("system cleanup")
+
+			// Call the CleanSystem function from the cleaner package.
+			// Interactive mode asks once per category rather than for every cached file.
+			opts := cleaner.SystemOptions{PerCategory: interactiveFlag}
+			if minAgeFlag != "" {
+				minAge, err := utils.ParseDuration(minAgeFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --min-age: %w", err)
+				}
+				opts.MinAge = minAge
+			}
+			space, err := cleaner.CleanSystem(ctx, dryRunFlag, IgnorePaths, summary, estimatedSummary, opts)
+			if err != nil && ctx.Err() == nil {
 				return fmt.Errorf("failed to clean system: %w", err)
 			}
 			reclaimed = space
@@ -156,20 +624,175 @@ Use the '--interactive' flag to confirm each deletion individually.`,
 		// =================================================================
 
 		// Print a summary table of the disk space reclaimed.
-		summary.PrintTable(false, "Reclaimed Disk Summary")
+		summaryTitle := i18n.T("summary.reclaimed_title")
+		if volume != "" {
+			summaryTitle = fmt.Sprintf("%s (%s)", summaryTitle, volume)
+		}
+		summary.PrintTable(false, summaryTitle)
+		summary.Timings.PrintTable(i18n.T("summary.timings_title"))
+		logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
 		println("\n")
 
+		// A dry run only has estimates, which the single summary CSV then holds instead.
+		if summaryCSVFlag != "" {
+			csvSummary := summary
+			if dryRunFlag {
+				csvSummary = estimatedSummary
+			}
+			if err := writeReportFile(summaryCSVFlag, csvSummary.WriteCSV); err != nil {
+				return err
+			}
+			logger.Log.Infof("Summary written to %s", summaryCSVFlag)
+		}
+
+		// Report how the free space on the selected volume changed during the run.
+		if volume != "" && !dryRunFlag {
+			if freeAfter, _, err := utils.VolumeSpace(volume); err == nil {
+				logger.Log.Infof("Free space on %s: %s -> %s (%s)", volume,
+					reclaimer.FormatBytes(freeBefore), reclaimer.FormatBytes(freeAfter), utils.GreenBold(reclaimer.FormatBytes(freeAfter-freeBefore)))
+			}
+		}
+
+		// Finder counts purgeable space as available and df doesn't; say which of the two wiper changes.
+		if !logger.Quiet() && ctx.Err() == nil {
+			reportPurgeable(summary.Volume)
+		}
+
 		// Print the final message based on whether it was a dry run or an actual cleanup.
-		if dryRunFlag {
+		// Errors of an interrupted cleanup only come from the interruption, which is reported here.
+		if ctx.Err() != nil {
+			logger.Log.Warnf("Cleanup interrupted. Space reclaimed before stopping: %s", utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+			cmd.SilenceUsage = true
+			return fmt.Errorf("cleanup interrupted: %w", ctx.Err())
+		} else if logger.Quiet() {
+			// The total is the one line quiet mode keeps, e.g. for the mail of a cron job.
+			if dryRunFlag {
+				fmt.Printf("Estimated space reclaimed: %s\n", reclaimer.FormatBytes(reclaimed))
+			} else {
+				fmt.Printf("Space reclaimed: %s\n", reclaimer.FormatBytes(reclaimed))
+			}
+		} else if dryRunFlag {
 			logger.Log.Infof(utils.CyanBold("Cleanup estimation finished. Estimated space reclaimed: %s"), utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
 		} else {
 			logger.Log.Infof("Cleanup completed. Space reclaimed: %s", utils.GreenBold(reclaimer.FormatBytes(reclaimed)))
+			if quarantine.Enabled() && reclaimed > 0 {
+				logger.Log.Infof("Items were quarantined; restore them with 'wiper restore %s'. The space is freed once the run is purged.", RunID)
+			} else if utils.TrashMode() && reclaimed > 0 {
+				logger.Log.Info("Items were moved to the Trash; the space is freed once the Trash is emptied.")
+			}
 		}
 
+		// Report removal failures through the exit status instead of claiming success.
+		if failed := summary.FailedCount(); failed > 0 {
+			// The failure is not a usage problem, so don't print the command help.
+			cmd.SilenceUsage = true
+			return &cleanupFailedError{failed: failed}
+		}
+
+		// Scripts can tell a run that found nothing from one the user declined. Items skipped for
+		// other reasons (e.g., in use) don't count as declined.
+		found := len(estimatedSummary.ByStatus(reclaimer.StatusDryRun)) > 0 || len(summary.ByStatus(reclaimer.StatusRemoved)) > 0
+		if !found {
+			cmd.SilenceUsage = true
+			return errNothingFound
+		}
+		if !dryRunFlag && len(summary.ByStatus(reclaimer.StatusRemoved)) == 0 && allDeclined(summary.Skipped()) {
+			cmd.SilenceUsage = true
+			return errAborted
+		}
 		return nil
 	},
 }
 
+// allDeclined reports whether every skipped entry was declined by the user.
+func allDeclined(skipped []reclaimer.ReclaimedEntry) bool {
+	for _, entry := range skipped {
+		if entry.Reason != reclaimer.SkipReasonDeclined {
+			return false
+		}
+	}
+	return true
+}
+
+// reportPurgeable explains the difference between the free space of the volume that df reports and
+// the larger "available" space of Finder, which includes APFS purgeable space. Nothing is printed
+// where purgeable space isn't reported or there is none.
+func reportPurgeable(volume string) {
+	purgeable, err := utils.PurgeableSpace(volume)
+	if err != nil {
+		logger.Log.Debugf("Purgeable space unavailable: %v", err)
+		return
+	}
+	free, _, err := utils.VolumeSpace(volume)
+	if err != nil || purgeable == 0 {
+		return
+	}
+	logger.Log.Infof("Free space: %s, as df reports it. Finder shows %s available, because it adds %s of purgeable space.",
+		reclaimer.FormatBytes(free), reclaimer.FormatBytes(free+purgeable), reclaimer.FormatBytes(purgeable))
+	logger.Log.Info("macOS frees purgeable space (local snapshots, caches it manages, iCloud files that can be downloaded again) by itself when space runs low; wiper only changes the free space.")
+}
+
+// scanRootsFromFlag resolves the comma-separated directories of --path to absolute paths and
+// checks that each of them is a directory, so a typo fails before the scan starts.
+func scanRootsFromFlag(value string) ([]string, error) {
+	var roots []string
+	for _, path := range strings.Split(value, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		root, err := filepath.Abs(utils.ExpandPath(path))
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", root)
+		}
+		roots = append(roots, root)
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no directory given")
+	}
+	return roots, nil
+}
+
+// downloadsPolicy converts the downloads section of the config file into a cleaner.DownloadsPolicy.
+// The --archive-downloads flag takes precedence over the configured archive directory.
+func downloadsPolicy(cfg config.DownloadsConfig) (cleaner.DownloadsPolicy, error) {
+	policy := cleaner.DownloadsPolicy{
+		Extensions: cfg.Extensions,
+		ArchiveDir: cfg.ArchiveDir,
+	}
+	if cfg.MinAge != "" {
+		minAge, err := utils.ParseDuration(cfg.MinAge)
+		if err != nil {
+			return policy, fmt.Errorf("invalid downloads.min_age in config file: %w", err)
+		}
+		policy.MinAge = minAge
+	}
+	if cfg.MinSize != "" {
+		minSize, err := utils.ParseBytes(cfg.MinSize)
+		if err != nil {
+			return policy, fmt.Errorf("invalid downloads.min_size in config file: %w", err)
+		}
+		policy.MinSize = minSize
+	}
+	if archiveDownloadsFlag != "" {
+		policy.ArchiveDir = archiveDownloadsFlag
+	}
+	if policy.ArchiveDir != "" {
+		archiveDir, err := filepath.Abs(utils.ExpandPath(policy.ArchiveDir))
+		if err != nil {
+			return policy, fmt.Errorf("invalid downloads archive directory %s: %w", policy.ArchiveDir, err)
+		}
+		policy.ArchiveDir = archiveDir
+	}
+	return policy, nil
+}
+
 // ====================================================================================================
 // INITIALIZATION
 // ====================================================================================================
@@ -187,7 +810,90 @@ func init() {
 	// It binds the --large-files flag to the largeFilesFlag variable.
 	wipeCmd.Flags().BoolVar(&largeFilesFlag, "large-files", false, "Perform a cleanup of large files")
 
+	// StringVar binds the --older-than flag to the olderThanFlag variable.
+	wipeCmd.Flags().StringVar(&olderThanFlag, "older-than", "", "Only list large files not modified within this window, e.g. 90d or 6w (with --large-files)")
+	// BoolVar binds the --by-access flag to the byAccessFlag variable.
+	wipeCmd.Flags().BoolVar(&byAccessFlag, "by-access", false, "Make --older-than look at when files were last opened instead of modified")
+
+	// StringVar binds the --type flag to the typeFlag variable.
+	wipeCmd.Flags().StringVar(&typeFlag, "type", "", "Only list large files of these comma-separated types: "+strings.Join(cleaner.FileTypes(), ", "))
+
+	// IntVar binds the --top flag to the topFlag variable.
+	wipeCmd.Flags().IntVar(&topFlag, "top", 0, "Rank the N largest files of at least 1MiB (or --threshold) instead of listing every file above the threshold")
+
+	// BoolVar binds the --include-volumes flag to the includeVolumesFlag variable.
+	wipeCmd.Flags().BoolVar(&includeVolumesFlag, "include-volumes", false, "Also scan external volumes for large files, except Time Machine backups and read-only volumes")
+
+	// StringVar binds the --path flag to the largeFilesPathFlag variable.
+	wipeCmd.Flags().StringVar(&largeFilesPathFlag, "path", "", "Comma-separated directories for --large-files to scan instead of the default locations (e.g., /Volumes/Data)")
+
 	// BoolVarP defines a boolean flag with both a long name and a short name.
 	// It binds the --interactive or -I flag to the interactiveFlag variable.
-	wipeCmd.Flags().BoolVarP(&interactiveFlag, "interactive", "I", false, "Prompt for confirmation before each deletion (only for --large-files)")
+	wipeCmd.Flags().BoolVarP(&interactiveFlag, "interactive", "I", false, "Prompt before each deletion (--large-files) or each category (system cleanup)")
+
+	// BoolVar defines a boolean flag for the Docker cleanup.
+	wipeCmd.Flags().BoolVar(&dockerFlag, "docker", false, "Prune stopped containers, dangling images, build cache and unused anonymous volumes through the Docker API")
+
+	// BoolVar defines a boolean flag for the checkbox selection list.
+	wipeCmd.Flags().BoolVar(&tuiFlag, "tui", false, "Pick the items to clean from a checkbox list grouped by category")
+
+	// IntVar binds the --expand flag to the expandFlag variable.
+	wipeCmd.Flags().IntVar(&expandFlag, "expand", 0, "List the N largest paths under each category in the summary tables")
+
+	// BoolVar binds the --show-skipped flag to the showSkippedFlag variable.
+	wipeCmd.Flags().BoolVar(&showSkippedFlag, "show-skipped", false, "List skipped paths and why they were excluded (ignored, too new, permission denied, ...)")
+
+	// BoolVar binds the --details flag to the detailsFlag variable.
+	wipeCmd.Flags().BoolVar(&detailsFlag, "details", false, "List every item with its size, status and error after the summary tables")
+	// IntVar binds the --details-limit flag to the detailsLimitFlag variable.
+	wipeCmd.Flags().IntVar(&detailsLimitFlag, "details-limit", 100, "The number of items --details lists at most, largest first (0 lists all)")
+
+	// StringVar binds the --threshold and --min-age flags. Both accept human-readable values.
+	wipeCmd.Flags().StringVar(&thresholdFlag, "threshold", "", "Minimum size for --large-files, e.g. 500MB or 1.5GiB (default 100MiB)")
+	wipeCmd.Flags().StringVar(&minAgeFlag, "min-age", "", "Only clean system items older than this, e.g. 7d, 2w or 36h")
+
+	// BoolVar and StringVar define the conditions for scheduled runs.
+	wipeCmd.Flags().BoolVar(&requireACFlag, "require-ac", false, "Defer the cleanup unless the machine is on AC power")
+	wipeCmd.Flags().StringVar(&requireIdleFlag, "require-idle", "", "Defer the cleanup unless there was no user input for this long (e.g., 15m)")
+	wipeCmd.Flags().BoolVar(&skipLowPowerFlag, "skip-low-power", false, "Defer the cleanup while Low Power Mode is on")
+
+	// StringVar binds the --free flag to the freeFlag variable.
+	wipeCmd.Flags().StringVar(&freeFlag, "free", "", "Only clean until this much space is freed, safest categories first (e.g., 30GB)")
+
+	// BoolVar binds the --spotlight flag to the spotlightFlag variable.
+	wipeCmd.Flags().BoolVar(&spotlightFlag, "spotlight", false, "Find large files using the Spotlight index (much faster; unindexed locations are still scanned), or app leftovers anywhere on disk")
+
+	// StringVar binds the --summary-csv flag to the summaryCSVFlag variable.
+	wipeCmd.Flags().StringVar(&summaryCSVFlag, "summary-csv", "", "Write the reclaim summary, per item and per category, to this CSV file")
+
+	// BoolVar binds the --keep-preferences flag to the keepPreferencesFlag variable.
+	wipeCmd.Flags().BoolVar(&keepPreferencesFlag, "keep-preferences", false, "Keep an uninstalled app's preferences and Application Support data for a later reinstall")
+
+	// BoolVar binds the --timings flag to the timingsFlag variable.
+	wipeCmd.Flags().BoolVar(&timingsFlag, "timings", false, "Show how long scanning and deleting took for each category")
+
+	// StringVar defines the archive directory for old downloads.
+	wipeCmd.Flags().StringVar(&archiveDownloadsFlag, "archive-downloads", "", "Move old downloads into this directory (e.g., on an external volume) instead of deleting them")
+
+	// StringVar defines the browser profiles whose caches are cleaned.
+	wipeCmd.Flags().StringVar(&browserProfilesFlag, "browser-profiles", "", "Only clean the caches of these browser profiles, by name or directory (e.g., \"Default,Work\")")
+
+	// BoolVar allows deleting items in iCloud-synced locations.
+	wipeCmd.Flags().BoolVar(&allowICloudFlag, "allow-icloud", false, "Also clean items in iCloud-synced locations; deletions propagate to iCloud and your other devices")
+
+	// BoolVar binds the --trash flag to the trashFlag variable.
+	wipeCmd.Flags().BoolVar(&trashFlag, "trash", false, "Move items to the Trash instead of deleting them permanently (the space is freed when the Trash is emptied)")
+
+	// BoolVar binds the --quarantine flag to the quarantineFlag variable.
+	wipeCmd.Flags().BoolVar(&quarantineFlag, "quarantine", false, "Keep removed items in the quarantine so 'wiper restore' can put them back (the space is freed when the run is purged)")
+
+	// BoolVar and IntVar define the secure erase of large file and interactive cleanups.
+	wipeCmd.Flags().BoolVar(&secureFlag, "secure", false, "Overwrite files before deleting them, for sensitive documents (with --large-files or --interactive)")
+	wipeCmd.Flags().IntVar(&securePassesFlag, "secure-passes", 1, "Number of times --secure overwrites each file; the last pass writes random data")
+
+	// StringVar defines the tag that protects files and folders from cleanup.
+	wipeCmd.Flags().StringVar(&protectTagFlag, "protect-tag", "", "Never clean files or folders carrying this Finder tag (default \"Keep\")")
+
+	// StringVar binds the --volume flag to the volumeFlag variable.
+	wipeCmd.Flags().StringVar(&volumeFlag, "volume", "", "Limit large files and Trash cleanup to a specific mounted volume (e.g., /Volumes/External)")
 }
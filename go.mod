@@ -15,15 +15,19 @@ require (
 	// applications. It handles commands, flags, and arguments.
 	github.com/spf13/cobra v1.9.1
 
-	// Indirect dependencies required by the direct dependencies above.
-	// They are automatically managed by the Go toolchain.
+	// golang.org/x/sys provides the system calls the standard library doesn't expose on
+	// every platform, such as reading extended attributes (Finder tags).
+	golang.org/x/sys v0.30.0
+)
+
+// Indirect dependencies required by the direct dependencies above.
+// They are automatically managed by the Go toolchain.
+require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/mattn/go-colorable v0.1.13       // indirect
-	github.com/mattn/go-isatty v0.0.20          // indirect
-	github.com/mattn/go-runewidth v0.0.16       // indirect
-	github.com/rivo/uniseg v0.4.7               // indirect
-	github.com/sirupsen/logrus v1.9.3           // indirect
-	github.com/spf13/pflag v1.0.6               // indirect
-	golang.org/x/sys v0.30.0                      // indirect
-	golang.org/x/text v0.22.0                     // indirect
-)
\ No newline at end of file
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mattn/go-runewidth v0.0.16 // indirect
+	github.com/rivo/uniseg v0.4.7 // indirect
+	github.com/spf13/pflag v1.0.6 // indirect
+	golang.org/x/text v0.22.0 // indirect
+)
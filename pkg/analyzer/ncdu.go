@@ -0,0 +1,275 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ====================================================================================================
+// NCDU JSON FORMAT
+// ====================================================================================================
+
+// The ncdu export format (https://dev.yorhel.nl/ncdu/jsonfmt) is a JSON array of
+// [majorver, minorver, metadata, root], where every directory is itself an array whose first
+// element describes the directory and whose remaining elements are its entries, and every file
+// is an object. Files written by WriteNcdu can be browsed with `ncdu -f file.json`.
+const (
+	ncduMajorVersion = 1
+	ncduMinorVersion = 2
+)
+
+// NcduMetadata is the metadata block of an ncdu export.
+type NcduMetadata struct {
+	// ProgName and ProgVersion identify the program that wrote the export.
+	ProgName    string
+	ProgVersion string
+	// Timestamp is when the scan was made.
+	Timestamp time.Time
+}
+
+// ncduInfo is the information object of a single entry.
+type ncduInfo struct {
+	Name      string `json:"name"`
+	Asize     int64  `json:"asize,omitempty"`
+	Dsize     int64  `json:"dsize,omitempty"`
+	Dev       uint64 `json:"dev,omitempty"`
+	Ino       uint64 `json:"ino,omitempty"`
+	Hlnkc     bool   `json:"hlnkc,omitempty"`
+	ReadError bool   `json:"read_error,omitempty"`
+	Excluded  string `json:"excluded,omitempty"`
+	NotReg    bool   `json:"notreg,omitempty"`
+	Mtime     int64  `json:"mtime,omitempty"`
+}
+
+// WriteNcdu writes the tree rooted at root to w in ncdu's JSON export format.
+func WriteNcdu(w io.Writer, root *Node, meta NcduMetadata) error {
+	bw := bufio.NewWriter(w)
+	metaJSON, err := json.Marshal(map[string]interface{}{
+		"progname":  meta.ProgName,
+		"progver":   meta.ProgVersion,
+		"timestamp": meta.Timestamp.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(bw, "[%d,%d,%s,\n", ncduMajorVersion, ncduMinorVersion, metaJSON)
+	if err := writeNcduNode(bw, root, 0); err != nil {
+		return err
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
+
+// writeNcduNode writes a single node and, for directories, its entries.
+// The device is only written when it differs from the parent's, as ncdu does.
+func writeNcduNode(w *bufio.Writer, n *Node, parentDev uint64) error {
+	info := ncduInfo{
+		Name:      n.Name,
+		Asize:     n.Size,
+		Dsize:     n.Usage,
+		Ino:       n.Inode,
+		Hlnkc:     n.HardLinked,
+		ReadError: n.ReadError,
+		Excluded:  n.Excluded,
+		NotReg:    n.NotRegular,
+		Mtime:     n.ModTime,
+	}
+	if n.Device != parentDev {
+		info.Dev = n.Device
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	if !n.IsDir {
+		_, err := w.Write(data)
+		return err
+	}
+	w.WriteByte('[')
+	w.Write(data)
+	for _, child := range n.Children {
+		w.WriteString(",\n")
+		if err := writeNcduNode(w, child, n.Device); err != nil {
+			return err
+		}
+	}
+	_, err = w.WriteString("]")
+	return err
+}
+
+// ReadNcdu reads a tree from an ncdu JSON export, such as one written by `ncdu -o` or WriteNcdu.
+// The file is parsed as a stream, so large exports don't need to fit in memory twice.
+func ReadNcdu(r io.Reader) (*Node, NcduMetadata, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	dec.UseNumber()
+	var meta NcduMetadata
+
+	if err := expectDelim(dec, '['); err != nil {
+		return nil, meta, fmt.Errorf("not an ncdu export: %w", err)
+	}
+	var major int64
+	if err := dec.Decode(&major); err != nil {
+		return nil, meta, fmt.Errorf("not an ncdu export: %w", err)
+	}
+	if major != ncduMajorVersion {
+		return nil, meta, fmt.Errorf("unsupported ncdu export version %d", major)
+	}
+	var minor int64
+	if err := dec.Decode(&minor); err != nil {
+		return nil, meta, fmt.Errorf("invalid ncdu export: %w", err)
+	}
+
+	var rawMeta map[string]interface{}
+	if err := dec.Decode(&rawMeta); err != nil {
+		return nil, meta, fmt.Errorf("invalid ncdu metadata: %w", err)
+	}
+	meta.ProgName, _ = rawMeta["progname"].(string)
+	meta.ProgVersion, _ = rawMeta["progver"].(string)
+	if ts, ok := rawMeta["timestamp"].(json.Number); ok {
+		if seconds, err := ts.Int64(); err == nil {
+			meta.Timestamp = time.Unix(seconds, 0)
+		}
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, meta, fmt.Errorf("invalid ncdu export: %w", err)
+	}
+	root, err := readNcduEntry(dec, tok, 0)
+	if err != nil {
+		return nil, meta, err
+	}
+	return root, meta, nil
+}
+
+// readNcduEntry reads the entry that starts with tok: an object for files, an array for directories.
+func readNcduEntry(dec *json.Decoder, tok json.Token, parentDev uint64) (*Node, error) {
+	switch tok {
+	case json.Delim('{'):
+		return readNcduInfo(dec, parentDev)
+	case json.Delim('['):
+		if err := expectDelim(dec, '{'); err != nil {
+			return nil, fmt.Errorf("invalid ncdu directory: %w", err)
+		}
+		dir, err := readNcduInfo(dec, parentDev)
+		if err != nil {
+			return nil, err
+		}
+		dir.IsDir = true
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			child, err := readNcduEntry(dec, tok, dir.Device)
+			if err != nil {
+				return nil, err
+			}
+			dir.Children = append(dir.Children, child)
+		}
+		return dir, expectDelim(dec, ']')
+	default:
+		return nil, fmt.Errorf("invalid ncdu entry: unexpected %v", tok)
+	}
+}
+
+// readNcduInfo reads the fields of an info object whose opening brace was already consumed.
+// Unknown fields (e.g., uid, gid, or mode from newer ncdu versions) are skipped.
+func readNcduInfo(dec *json.Decoder, parentDev uint64) (*Node, error) {
+	node := &Node{Device: parentDev}
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := keyTok.(string)
+		value, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := value.(json.Delim); ok {
+			if err := skipValue(dec); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch key {
+		case "name":
+			node.Name, _ = value.(string)
+		case "asize":
+			node.Size = jsonInt(value)
+		case "dsize":
+			node.Usage = jsonInt(value)
+		case "dev":
+			node.Device = uint64(jsonInt(value))
+		case "ino":
+			node.Inode = uint64(jsonInt(value))
+		case "mtime":
+			node.ModTime = jsonInt(value)
+		case "hlnkc":
+			node.HardLinked, _ = value.(bool)
+		case "read_error":
+			node.ReadError, _ = value.(bool)
+		case "notreg":
+			node.NotRegular, _ = value.(bool)
+		case "excluded":
+			node.Excluded, _ = value.(string)
+		}
+	}
+	if node.Name == "" {
+		return nil, fmt.Errorf("invalid ncdu entry: missing name")
+	}
+	return node, expectDelim(dec, '}')
+}
+
+// expectDelim consumes the next token and checks that it is the given delimiter.
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("expected %q, found %v", want, tok)
+	}
+	return nil
+}
+
+// skipValue skips the rest of an object or array whose opening delimiter was already consumed.
+func skipValue(dec *json.Decoder) error {
+	depth := 1
+	for depth > 0 {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// jsonInt converts a numeric token to an int64, ignoring values that aren't integers.
+func jsonInt(value json.Token) int64 {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0
+	}
+	if n, err := number.Int64(); err == nil {
+		return n
+	}
+	// Very large unsigned values (e.g., device IDs) don't fit an int64.
+	if u, err := strconv.ParseUint(number.String(), 10, 64); err == nil {
+		return int64(u)
+	}
+	return 0
+}
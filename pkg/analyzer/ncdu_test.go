@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNcduRoundTrip(t *testing.T) {
+	// Device IDs above 2^63 don't fit an int64, as on some network filesystems.
+	const largeDev = 1<<63 + 5
+	root := &Node{Name: "/data", Usage: 4096, Device: largeDev, Inode: 2, ModTime: 1700000000, IsDir: true, Children: []*Node{
+		{Name: "file.bin", Size: 1500, Usage: 4096, Device: largeDev, Inode: 11, ModTime: 1700000100},
+		{Name: "linked", Size: 10, Usage: 4096, Device: largeDev, Inode: 12, HardLinked: true},
+		{Name: "link", Device: largeDev, Inode: 13, NotRegular: true},
+		{Name: "locked", Device: largeDev, Inode: 14, IsDir: true, ReadError: true},
+		{Name: "skipped", Device: largeDev, Excluded: "pattern"},
+		{Name: "mnt", Usage: 4096, Device: 42, Inode: 1, IsDir: true, Children: []*Node{
+			{Name: "other.bin", Size: 7, Usage: 4096, Device: 42, Inode: 3},
+		}},
+	}}
+	meta := NcduMetadata{ProgName: "wiper", ProgVersion: "1.2.3", Timestamp: time.Unix(1700000200, 0)}
+
+	var buf bytes.Buffer
+	if err := WriteNcdu(&buf, root, meta); err != nil {
+		t.Fatalf("WriteNcdu() error = %v", err)
+	}
+	got, gotMeta, err := ReadNcdu(&buf)
+	if err != nil {
+		t.Fatalf("ReadNcdu() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, root) {
+		t.Errorf("ReadNcdu(WriteNcdu(tree)) = %+v, want %+v", got, root)
+	}
+	if gotMeta.ProgName != meta.ProgName || gotMeta.ProgVersion != meta.ProgVersion || !gotMeta.Timestamp.Equal(meta.Timestamp) {
+		t.Errorf("ReadNcdu() metadata = %+v, want %+v", gotMeta, meta)
+	}
+}
+
+func TestReadNcdu(t *testing.T) {
+	// An export of a newer ncdu, with fields wiper doesn't know, some of them nested.
+	export := `[1,2,{"progname":"ncdu","progver":"2.3","timestamp":1700000000},
+[{"name":"/home","dev":18446744073709551615,"asize":4096,"uid":501,"xattrs":{"user.tag":["a",{"b":1}]}},
+{"name":"notes.txt","asize":12,"dsize":4096,"ino":7,"mode":33188,"ext":{"mtime":1700000000}},
+[{"name":"sub","dev":3,"extended":[1,[2,3]]},
+{"name":"deep","asize":1,"ino":8,"hlnkc":true,"nlink":2}]]]`
+
+	root, meta, err := ReadNcdu(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ReadNcdu() error = %v", err)
+	}
+	if meta.ProgName != "ncdu" || meta.ProgVersion != "2.3" || meta.Timestamp.Unix() != 1700000000 {
+		t.Errorf("ReadNcdu() metadata = %+v", meta)
+	}
+	want := &Node{Name: "/home", Size: 4096, Device: 18446744073709551615, IsDir: true, Children: []*Node{
+		{Name: "notes.txt", Size: 12, Usage: 4096, Device: 18446744073709551615, Inode: 7},
+		{Name: "sub", Device: 3, IsDir: true, Children: []*Node{
+			{Name: "deep", Size: 1, Device: 3, Inode: 8, HardLinked: true},
+		}},
+	}}
+	if !reflect.DeepEqual(root, want) {
+		t.Errorf("ReadNcdu() = %+v, want %+v", root, want)
+	}
+}
+
+func TestReadNcduRejectsInvalidExports(t *testing.T) {
+	tests := []struct {
+		name   string
+		export string
+	}{
+		{"not an array", `{"name":"/"}`},
+		{"unsupported version", `[2,0,{},[{"name":"/"}]]`},
+		{"entry without a name", `[1,2,{},[{"asize":1}]]`},
+		{"truncated", `[1,2,{},[{"name":"/"},{"name":"a"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ReadNcdu(strings.NewReader(tt.export)); err == nil {
+				t.Errorf("ReadNcdu(%s) error = nil, want an error", tt.export)
+			}
+		})
+	}
+}
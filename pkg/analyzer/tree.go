@@ -0,0 +1,247 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// DIRECTORY TREE
+// ====================================================================================================
+
+// warningCategory is used for scan warnings reported through logger.RunWarnings.
+const warningCategory = "Disk Usage"
+
+// Node is a file or directory in an analyzed tree. Directory sizes are not stored on the node;
+// use TotalSize and TotalUsage, which sum the subtree and count every hard-linked file once.
+type Node struct {
+	// Name is the entry's base name. The root node carries the full path that was scanned.
+	Name string
+	// Size is the apparent (logical) size in bytes.
+	Size int64
+	// Usage is the space allocated on disk in bytes.
+	Usage int64
+	// Device and Inode identify the entry, when known; they let hard links be recognized.
+	Device uint64
+	Inode  uint64
+	// HardLinked is true for files with more than one hard link.
+	HardLinked bool
+	// ModTime is the modification time as a Unix timestamp.
+	ModTime int64
+	// IsDir is true for directories.
+	IsDir bool
+	// NotRegular is true for entries that are neither regular files nor directories (e.g., symlinks, sockets).
+	NotRegular bool
+	// ReadError is true when the entry (or, for directories, its listing) could not be read.
+	ReadError bool
+	// Excluded names why the entry wasn't scanned: "pattern" (ignored) or "otherfs" (another filesystem).
+	// Empty means the entry was scanned.
+	Excluded string
+	// Children are the entries of a directory.
+	Children []*Node
+}
+
+// ScanOptions configures Scan.
+type ScanOptions struct {
+	// IgnorePaths are skipped and recorded as excluded, matched like --ignore.
+	IgnorePaths []string
+	// CrossFilesystems descends into mount points instead of recording them as excluded.
+	CrossFilesystems bool
+}
+
+// Scan builds the tree of everything below root, reading directories concurrently with the shared
+// worker pool (see utils.WalkParallel and --jobs). Children are ordered by name, as if read one at a
+// time. Unreadable entries are kept with ReadError set and reported through logger.RunWarnings, so a
+// partial scan still produces a usable tree.
+func Scan(root string, opts ScanOptions) (*Node, error) {
+	absRoot, err := filepath.Abs(utils.ExpandPath(root))
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Lstat(absRoot)
+	if err != nil {
+		return nil, err
+	}
+	node := newNode(absRoot, info)
+	if !node.IsDir {
+		return node, nil
+	}
+
+	// The walk calls back one entry at a time, so the directories seen so far need no locking.
+	dirs := map[string]*Node{absRoot: node}
+	err = utils.WalkParallel(absRoot, func(path string, info os.FileInfo, err error) error {
+		if path == absRoot {
+			if err != nil {
+				node.ReadError = true
+				logger.RunWarnings.Add(warningCategory, "unreadable directory", path, err)
+			}
+			return nil
+		}
+		if dir, ok := dirs[path]; ok && err != nil {
+			// A directory that was listed but couldn't be read is reported a second time.
+			dir.ReadError = true
+			logger.RunWarnings.Add(warningCategory, "unreadable directory", path, err)
+			return nil
+		}
+		parent := dirs[filepath.Dir(path)]
+		if err != nil {
+			logger.RunWarnings.Add(warningCategory, "unreadable entry", path, err)
+			parent.Children = append(parent.Children, &Node{Name: filepath.Base(path), ReadError: true})
+			return nil
+		}
+		child := newNode(filepath.Base(path), info)
+		parent.Children = append(parent.Children, child)
+
+		if utils.IsPathIgnored(path, opts.IgnorePaths) {
+			child.Excluded = "pattern"
+			return skipEntry(child)
+		}
+		if !child.IsDir {
+			return nil
+		}
+		if !opts.CrossFilesystems && child.Device != 0 && child.Device != node.Device {
+			child.Excluded = "otherfs"
+			return filepath.SkipDir
+		}
+		dirs[path] = child
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortByName(node)
+	return node, nil
+}
+
+// skipEntry returns filepath.SkipDir for an excluded directory, so its contents aren't walked, and
+// nil for an excluded file, for which SkipDir would skip the rest of its directory.
+func skipEntry(n *Node) error {
+	if n.IsDir {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+// sortByName orders the children of n and of every directory below it by name.
+func sortByName(n *Node) {
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Name < n.Children[j].Name })
+	for _, child := range n.Children {
+		sortByName(child)
+	}
+}
+
+// newNode creates a node for a single entry from its file info.
+func newNode(name string, info os.FileInfo) *Node {
+	node := &Node{
+		Name:       name,
+		Size:       info.Size(),
+		Usage:      utils.FileInfoDiskUsage(info),
+		ModTime:    info.ModTime().Unix(),
+		IsDir:      info.IsDir(),
+		NotRegular: !info.IsDir() && !info.Mode().IsRegular(),
+	}
+	if id, ok := utils.FileInfoIdentity(info); ok {
+		node.Device = id.Device
+		node.Inode = id.Inode
+		node.HardLinked = !node.IsDir && id.Links > 1
+	}
+	return node
+}
+
+// TotalSize returns the apparent size of the node and everything below it.
+func (n *Node) TotalSize() int64 {
+	return n.sum(n.Device, make(map[linkKey]bool), func(node *Node) int64 { return node.Size })
+}
+
+// TotalUsage returns the disk usage of the node and everything below it.
+func (n *Node) TotalUsage() int64 {
+	return n.sum(n.Device, make(map[linkKey]bool), func(node *Node) int64 { return node.Usage })
+}
+
+// linkKey identifies a file by its device and inode, which all of its hard links share.
+type linkKey struct {
+	device, inode uint64
+}
+
+// counted reports whether n is another name of a hard-linked file that was seen already, and
+// remembers it as seen. device is used when n carries none: ncdu exports only record the device
+// of directories whose device differs from their parent's.
+func (n *Node) counted(device uint64, seen map[linkKey]bool) bool {
+	if !n.HardLinked || n.Inode == 0 {
+		return false
+	}
+	if n.Device != 0 {
+		device = n.Device
+	}
+	key := linkKey{device, n.Inode}
+	if seen[key] {
+		return true
+	}
+	seen[key] = true
+	return false
+}
+
+// sum adds value for n and everything below it, leaving out the hard links seen already.
+// device is the device of n's parent.
+func (n *Node) sum(device uint64, seen map[linkKey]bool, value func(*Node) int64) int64 {
+	if n.counted(device, seen) {
+		return 0
+	}
+	if n.Device != 0 {
+		device = n.Device
+	}
+	total := value(n)
+	for _, child := range n.Children {
+		total += child.sum(device, seen, value)
+	}
+	return total
+}
+
+// Entry is a node found below the root of a tree, with its disk usage.
+type Entry struct {
+	// Path is the entry's path relative to the root.
+	Path string
+	Node *Node
+	// Usage is the disk usage of the entry and everything below it.
+	Usage int64
+}
+
+// Ranked returns the entries up to depth levels below n (depth 1 being its children, 0 or less
+// without a limit), largest disk usage first. With dirsOnly, files are left out. Usage is summed in
+// a single pass over the tree, so ranking a deep tree costs no more than measuring it. Like du, a
+// hard-linked file only counts towards the first entry it is found in.
+func (n *Node) Ranked(depth int, dirsOnly bool) []Entry {
+	var entries []Entry
+	seen := make(map[linkKey]bool)
+	var visit func(node *Node, path string, level int, device uint64) int64
+	visit = func(node *Node, path string, level int, device uint64) int64 {
+		if node.counted(device, seen) {
+			return 0
+		}
+		if node.Device != 0 {
+			device = node.Device
+		}
+		total := node.Usage
+		for _, child := range node.Children {
+			childPath := filepath.Join(path, child.Name)
+			usage := visit(child, childPath, level+1, device)
+			total += usage
+			if (depth <= 0 || level < depth) && (!dirsOnly || child.IsDir) {
+				entries = append(entries, Entry{Path: childPath, Node: child, Usage: usage})
+			}
+		}
+		return total
+	}
+	visit(n, "", 0, n.Device)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Usage != entries[j].Usage {
+			return entries[i].Usage > entries[j].Usage
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	return entries
+}
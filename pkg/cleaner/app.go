@@ -1,120 +1,105 @@
 package cleaner
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/kodelint/wiper/pkg/i18n"
 	"github.com/kodelint/wiper/pkg/logger"
 	"github.com/kodelint/wiper/pkg/reclaimer"
 	"github.com/kodelint/wiper/pkg/utils"
 )
 
 // ====================================================================================================
-// GLOBAL VARIABLES
+// APPLICATION UNINSTALLATION FUNCTION
 // ====================================================================================================
 
-// appInstallPaths defines the common directories where macOS applications can be installed.
-// The search for application bundles will be limited to these locations.
-var appInstallPaths = []string{
-	"/Applications",
-	filepath.Join(os.Getenv("HOME"), "Applications"),
+// UninstallOptions configures an application uninstallation.
+// The zero value only searches the platform's leftover locations.
+type UninstallOptions struct {
+	// UseSpotlight also asks the Spotlight index for files named after the app's bundle identifier
+	// anywhere on disk. It needs the bundle, so it finds nothing for apps that were already deleted.
+	UseSpotlight bool
+	// KeepPreferences leaves the leftovers in the platform's settings directories (see
+	// Platform.AppSettingsDirs) in place, so a reinstalled app finds its settings again.
+	KeepPreferences bool
 }
 
-// ====================================================================================================
-// APPLICATION UNINSTALLATION FUNCTION
-// ====================================================================================================
-
 // UninstallApplication attempts to remove a specified macOS application and its leftover files.
 // It returns the total space reclaimed in bytes and an error, if any.
 //
 // Parameters:
+//   - ctx: Cancels the cleanup between items (see processCleanupItems).
 //   - appName: The name of the application to uninstall (e.g., "Google Chrome").
 //   - dryRun: A boolean flag indicating whether to perform a dry run (simulate deletion without changes).
 //   - ignorePaths: A slice of paths to be ignored during the cleanup process.
 //   - summary: A pointer to a SummaryTable to record deleted items and their sizes.
 //   - estimatedSummary: A pointer to a SummaryTable to record estimated items and their sizes (for dry runs).
-func UninstallApplication(appName string, dryRun bool, ignorePaths []string, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable) (int64, error) {
-	// Ensure the application name ends with ".app" for consistent searching.
-	if !strings.HasSuffix(appName, ".app") {
+//   - opts: Additional settings such as the Spotlight search; see UninstallOptions.
+func UninstallApplication(ctx context.Context, appName string, dryRun bool, ignorePaths []string, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable, opts UninstallOptions) (int64, error) {
+	platform := CurrentPlatform()
+	installPaths := platform.AppInstallPaths()
+	baseAppName := strings.TrimSuffix(appName, ".app")
+
+	// Ensure the application name ends with ".app" for consistent searching on platforms with bundles.
+	if len(installPaths) > 0 && !strings.HasSuffix(appName, ".app") {
 		appName += ".app"
 	}
 
 	logger.Log.Infof(utils.Cyan("Searching for '%s' and its associated files..."), appName)
 
-	var itemsToProcess []cleanupItem
-
-	// =================================================================================================
-	// Step 1: Find Application Bundles and Leftover Files
-	// =================================================================================================
-
-	// Find the main application bundle(s) in the defined common installation paths.
-	appBundlePaths := utils.FindPaths(appInstallPaths, appName)
-	if len(appBundlePaths) == 0 {
+	// Find the main application bundle(s) in the platform's common installation paths.
+	// Without bundles (e.g., on Linux, where the package manager owns the program), only leftovers are removed.
+	if len(installPaths) == 0 {
+		logger.Log.Debugf("Applications on %s are not installed as bundles; only leftover files are removed", platform.Name())
+	} else if len(utils.FindPaths(installPaths, appName)) == 0 {
 		logger.Log.Warnf(utils.Yellow("Application '%s' not found in common /Applications directories."), appName)
-	} else {
-		for _, bundlePath := range appBundlePaths {
-			// Check if the path should be ignored.
-			if !utils.IsPathIgnored(bundlePath, ignorePaths) {
-				size, err := utils.GetFileSizeInBytes(bundlePath)
-				if err == nil {
-					itemsToProcess = append(itemsToProcess, cleanupItem{
-						Path:       bundlePath,
-						Size:       size,
-						Category:   "Application Bundle",
-						ActualPath: bundlePath,
-					})
-				}
-			} else {
-				logger.Log.Debugf(utils.Yellow("Skipping ignored application bundle: %s"), bundlePath)
-			}
+		// A typo is more likely than an app that is already gone, so similar names are offered first.
+		if suggestion := pickSuggestion(ctx, installPaths, baseAppName); suggestion != "" {
+			return UninstallApplication(ctx, suggestion, dryRun, ignorePaths, summary, estimatedSummary, opts)
 		}
 	}
 
-	// Search for related files and directories in common leftover locations.
-	// We use `filepath.Glob` with patterns to find files that match a wildcard.
-	logger.Log.Infof(utils.Cyan("Searching for leftover files for '%s'..."), strings.TrimSuffix(appName, ".app"))
+	// =================================================================================================
+	// Step 1: Find Application Bundles and Leftover Files
+	// =================================================================================================
 
-	baseAppName := strings.TrimSuffix(appName, ".app")
-	leftoverSearchPatterns := []string{
-		// Common paths for application support, caches, preferences, and containers.
-		filepath.Join(os.Getenv("HOME"), "Library", "Application Support", baseAppName),
-		filepath.Join(os.Getenv("HOME"), "Library", "Caches", baseAppName),
-		// Preferences files often follow a reverse-domain-name convention (e.g., com.google.chrome.plist).
-		filepath.Join(os.Getenv("HOME"), "Library", "Preferences", "com."+strings.ToLower(strings.ReplaceAll(baseAppName, " ", ""))+".*"),
-		filepath.Join(os.Getenv("HOME"), "Library", "Saved Application State", "com."+strings.ToLower(strings.ReplaceAll(baseAppName, " ", ""))+".*"),
-		filepath.Join(os.Getenv("HOME"), "Library", "Containers", "*"+baseAppName+"*"),
-		filepath.Join(os.Getenv("HOME"), "Library", "Group Containers", "*"+baseAppName+"*"),
-		// System-wide library paths.
-		filepath.Join("/Library", "Application Support", baseAppName),
-		filepath.Join("/Library", "Caches", baseAppName),
-		filepath.Join("/Library", "Preferences", "com."+strings.ToLower(strings.ReplaceAll(baseAppName, " ", ""))+".*"),
+	app := &appCleaner{appName: appName, ignorePaths: ignorePaths, skipped: estimatedSummary, opts: opts}
+	itemsToProcess, err := app.scanItems(ctx)
+	if err != nil {
+		return 0, err
 	}
+	bundleIDs, bundlePaths, seen := app.bundleIDs, app.bundlePaths, app.seen
 
-	for _, pattern := range leftoverSearchPatterns {
-		matches, err := filepath.Glob(pattern)
-		if err != nil {
-			logger.Log.Debugf("Error globbing app data pattern %s: %v", pattern, err)
-			continue
+	// Apps installed with a .pkg installer may have put files outside their bundle (e.g., helper
+	// tools or launch daemons), which the receipts of the packages list.
+	receipts := findPackageReceipts(bundlePaths, bundleIDs)
+	receiptItems := packageReceiptItems(receipts, seen, ignorePaths, estimatedSummary)
+	if len(receiptItems) > 0 {
+		var size int64
+		for _, item := range receiptItems {
+			size += item.Size
 		}
-		for _, match := range matches {
-			if _, err := os.Stat(match); err == nil && !utils.IsPathIgnored(match, ignorePaths) {
-				size, err := utils.GetFileSizeInBytes(match)
-				if err == nil {
-					itemsToProcess = append(itemsToProcess, cleanupItem{
-						Path:       match,
-						Size:       size,
-						Category:   "Application Leftover",
-						ActualPath: match,
-					})
-				}
-			} else if err == nil && utils.IsPathIgnored(match, ignorePaths) {
-				logger.Log.Debugf(utils.Yellow("Skipping ignored leftover path: %s"), match)
+		if dryRun || ConfirmAction(ctx, i18n.T("prompt.remove_package_files", len(receiptItems), reclaimer.FormatBytes(size), len(receipts))) {
+			itemsToProcess = append(itemsToProcess, receiptItems...)
+		} else {
+			receipts = nil
+			for _, item := range receiptItems {
+				estimatedSummary.AddSkipped(item.ActualPath, item.Size, item.Category)
 			}
 		}
 	}
 
+	// Login items aren't files of the app, but keep trying to open it at every login.
+	removeLoginItems(baseAppName, bundlePaths, bundleIDs, dryRun, summary, estimatedSummary)
+
 	if len(itemsToProcess) == 0 {
 		logger.Log.Info("No items found for cleanup.")
 		return 0, nil
@@ -124,18 +109,199 @@ func UninstallApplication(appName string, dryRun bool, ignorePaths []string, sum
 	// Step 2: Process and Clean Up the Found Items
 	// =================================================================================================
 
+	// Launch agents and daemons are stopped first, so launchd doesn't keep running (or restart) them.
+	if !dryRun {
+		unloadLaunchJobs(itemsToProcess)
+	}
+
 	// Call the generic processCleanupItems function to handle the deletion logic.
 	// This function centralizes the logic for dry-run simulation, deletion, and summary updates.
 	// Note: We pass `false` for the interactive flag as this feature is not supported for application uninstallation.
 	reclaimed, err := processCleanupItems(
+		ctx,
 		itemsToProcess,
 		dryRun,
-		false, // interactiveMode is not enabled for app uninstall
+		confirmNone, // the uninstall was already confirmed by the caller
 		summary,
 		estimatedSummary,
 		fmt.Sprintf("Application Cleanup for '%s'", strings.TrimSuffix(appName, ".app")),
-		true, // always show progress for this type of cleanup
 	)
+	if !dryRun && ctx.Err() == nil {
+		forgetPackageReceipts(receipts)
+	}
 
 	return reclaimed, err
 }
+
+// appCleaner is the Cleaner of an application uninstallation: it finds the app's bundles and leftovers.
+type appCleaner struct {
+	// appName is the name of the app, with the ".app" suffix on platforms with bundles.
+	appName     string
+	ignorePaths []string
+	// skipped receives the excluded paths; nil discards them.
+	skipped *reclaimer.SummaryTable
+	opts    UninstallOptions
+
+	// bundleIDs are the identifiers of the found bundles, which name most of their leftovers.
+	bundleIDs   []string
+	bundlePaths []string
+	// seen holds the paths already collected, since the bundle search and several leftover
+	// patterns can match the same path.
+	seen map[string]bool
+}
+
+// Name returns "app".
+func (c *appCleaner) Name() string { return "app" }
+
+// Description says what an application uninstallation removes.
+func (c *appCleaner) Description() string {
+	if c.appName == "" {
+		return "An application and its leftover files (with wipe <application-name>)"
+	}
+	return fmt.Sprintf("%s and its leftover files", c.appName)
+}
+
+// Scan returns the bundles and leftover files of the app.
+func (c *appCleaner) Scan(ctx context.Context) ([]Item, error) { return scanBuiltin(ctx, c) }
+
+// scanItems collects the bundles of the app and its leftover files as cleanupItems, and records
+// the bundles and their identifiers for the package receipts and login items.
+func (c *appCleaner) scanItems(ctx context.Context) ([]cleanupItem, error) {
+	if c.appName == "" {
+		return nil, errors.New("no application to uninstall")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	platform := CurrentPlatform()
+	installPaths := platform.AppInstallPaths()
+	baseAppName := strings.TrimSuffix(c.appName, ".app")
+	ignorePaths, estimatedSummary, opts := c.ignorePaths, c.skipped, c.opts
+	if estimatedSummary == nil {
+		estimatedSummary = reclaimer.NewSummaryTable()
+	}
+	c.bundleIDs, c.bundlePaths, c.seen = nil, nil, make(map[string]bool)
+	var itemsToProcess []cleanupItem
+	// Files hard-linked between the bundle and its leftovers are counted once.
+	links := utils.NewLinkSet()
+
+	// Find the main application bundle(s) in the platform's common installation paths.
+	if len(installPaths) > 0 {
+		for _, bundlePath := range utils.FindPaths(installPaths, c.appName) {
+			if id, err := bundleIdentifier(bundlePath); err == nil {
+				logger.Log.Debugf("Bundle identifier of %s: %s", bundlePath, id)
+				if !slices.Contains(c.bundleIDs, id) {
+					c.bundleIDs = append(c.bundleIDs, id)
+				}
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				logger.Log.Debugf("Could not read the bundle identifier of %s: %v", bundlePath, err)
+			}
+			c.seen[bundlePath] = true
+			c.bundlePaths = append(c.bundlePaths, bundlePath)
+			// Check if the path should be ignored.
+			if !ignoredOrContainsIgnored(bundlePath, "Application Bundle", ignorePaths) {
+				usage, err := links.Usage(bundlePath)
+				if err != nil {
+					logger.RunWarnings.Add("Application Bundle", reclaimer.SkipReasonForError(err), bundlePath, err)
+				} else {
+					itemsToProcess = append(itemsToProcess, cleanupItem{
+						Path:       bundlePath,
+						Size:       usage.Private(),
+						Cloned:     usage.Cloned,
+						Category:   "Application Bundle",
+						ActualPath: bundlePath,
+						Scanned:    utils.FingerprintPath(bundlePath),
+					})
+				}
+			} else {
+				logger.Log.Debugf(utils.Yellow("Skipping ignored application bundle: %s"), bundlePath)
+				estimatedSummary.AddSkippedReason(bundlePath, 0, "Application Bundle", reclaimer.SkipReasonIgnored)
+			}
+		}
+	}
+
+	// Search for related files and directories in the platform's leftover locations.
+	// We use `filepath.Glob` with patterns to find files that match a wildcard.
+	logger.Log.Infof(utils.Cyan("Searching for leftover files for '%s'..."), baseAppName)
+	leftoverSearchPatterns := platform.AppLeftoverPatterns(baseAppName, c.bundleIDs)
+
+	var leftoverPaths []string
+	for _, pattern := range leftoverSearchPatterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			logger.Log.Debugf("Error globbing app data pattern %s: %v", pattern, err)
+			continue
+		}
+		leftoverPaths = append(leftoverPaths, matches...)
+	}
+
+	// The Spotlight index also knows about files outside the usual locations.
+	if opts.UseSpotlight {
+		if len(c.bundleIDs) == 0 {
+			logger.Log.Warnf(utils.Yellow("No bundle identifier found for '%s'; skipping the Spotlight search."), baseAppName)
+		} else if found, ok := spotlightLeftovers(platform, c.bundleIDs, append(slices.Collect(maps.Keys(c.seen)), leftoverPaths...)); ok {
+			logger.Log.Debugf("Found %d leftover candidates using Spotlight", len(found))
+			leftoverPaths = append(leftoverPaths, found...)
+		} else {
+			logger.Log.Warn(utils.Yellow("Spotlight is not available; only the usual leftover locations were searched."))
+		}
+	}
+
+	var settingsDirs []string
+	if opts.KeepPreferences {
+		settingsDirs = platform.AppSettingsDirs()
+	}
+	for _, match := range leftoverPaths {
+		if c.seen[match] {
+			continue
+		}
+		c.seen[match] = true
+		if _, err := os.Stat(match); err == nil && withinAny(match, settingsDirs) {
+			logger.Log.Debugf(utils.Yellow("Keeping settings: %s"), match)
+			size, _ := utils.GetFileSizeInBytes(match)
+			estimatedSummary.AddSkippedReason(match, size, "Application Leftover", reclaimer.SkipReasonKept)
+		} else if err == nil && !ignoredOrContainsIgnored(match, "Application Leftover", ignorePaths) {
+			usage, err := links.Usage(match)
+			if err != nil {
+				logger.RunWarnings.Add("Application Leftover", reclaimer.SkipReasonForError(err), match, err)
+			} else {
+				itemsToProcess = append(itemsToProcess, cleanupItem{
+					Path:       match,
+					Size:       usage.Private(),
+					Cloned:     usage.Cloned,
+					Category:   "Application Leftover",
+					ActualPath: match,
+					Scanned:    utils.FingerprintPath(match),
+				})
+			}
+		} else if err == nil {
+			logger.Log.Debugf(utils.Yellow("Skipping ignored leftover path: %s"), match)
+			estimatedSummary.AddSkippedReason(match, 0, "Application Leftover", reclaimer.SkipReasonIgnored)
+		}
+	}
+
+	return itemsToProcess, nil
+}
+
+// ignoredOrContainsIgnored reports whether path is ignored, or is a directory with an ignored path
+// inside (see utils.IgnoredBelow), so removing it would remove something the user asked to keep.
+// Directories that can't be fully read are treated as containing one, and reported with the run's
+// warnings under category.
+func ignoredOrContainsIgnored(path string, category string, ignorePaths []string) bool {
+	if utils.IsPathIgnored(path, ignorePaths) {
+		return true
+	}
+	if info, err := os.Lstat(path); err != nil || !info.IsDir() || len(ignorePaths) == 0 {
+		return false
+	}
+	ignored, err := utils.IgnoredBelow(path, ignorePaths)
+	if err != nil {
+		logger.RunWarnings.Add(category, reclaimer.SkipReasonForError(err), path, err)
+		return true
+	}
+	if ignored != "" {
+		logger.Log.Debugf("Path %s contains the ignored path %s", path, ignored)
+		return true
+	}
+	return false
+}
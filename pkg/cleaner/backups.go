@@ -0,0 +1,168 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// iOS AND iPadOS DEVICE BACKUPS
+// ====================================================================================================
+
+// deviceBackupsCategory is the category of device backups in the run's warnings.
+const deviceBackupsCategory = "Device Backups"
+
+// DeviceBackup is a backup of an iPhone, iPad or iPod touch made by Finder (or iTunes) on this Mac.
+type DeviceBackup struct {
+	// Path is the directory of the backup, named after the device's identifier (UDID).
+	Path string
+	// DeviceName is the name of the device (e.g., "Alice's iPhone"), or the directory name if the
+	// backup has no readable Info.plist.
+	DeviceName string
+	// ProductType is the model identifier (e.g., "iPhone15,2"), if known.
+	ProductType string
+	// ProductVersion is the iOS or iPadOS version the device had at the time (e.g., "17.4.1"), if known.
+	ProductVersion string
+	// Date is when the backup was last updated.
+	Date time.Time
+	// Size is the disk usage of the backup in bytes.
+	Size int64
+
+	info os.FileInfo
+}
+
+// Label names the backup by its device and date (e.g., "Alice's iPhone, 2024-03-01"), so backups of
+// the same device can be told apart.
+func (b DeviceBackup) Label() string {
+	return fmt.Sprintf("%s, %s", b.DeviceName, b.Date.Format("2006-01-02"))
+}
+
+// DeviceBackupDir returns the directory Finder keeps the local device backups in.
+func DeviceBackupDir() string {
+	return filepath.Join(utils.ExpandPath("~"), "Library", "Application Support", "MobileSync", "Backup")
+}
+
+// FindDeviceBackups lists the backups in DeviceBackupDir, the oldest first. The device and date of
+// each backup are read from its Info.plist; backups without one (e.g., one that was interrupted)
+// are listed with their modification time and reported with the run's warnings.
+//
+// Returns:
+//   - The backups, or none if the directory doesn't exist, and an error if it can't be read. The
+//     directory is protected by macOS, so reading it requires Full Disk Access for the terminal.
+func FindDeviceBackups() ([]DeviceBackup, error) {
+	dir := DeviceBackupDir()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if os.IsPermission(err) {
+		return nil, fmt.Errorf("%w (give your terminal Full Disk Access in System Settings > Privacy & Security)", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []DeviceBackup
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue // e.g., .DS_Store
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Lstat(path)
+		if err != nil {
+			logger.RunWarnings.Add(deviceBackupsCategory, reclaimer.SkipReasonForError(err), path, err)
+			continue
+		}
+		backup := DeviceBackup{Path: path, DeviceName: entry.Name(), Date: info.ModTime(), info: info}
+		if err := backup.readInfo(); err != nil {
+			logger.RunWarnings.Add(deviceBackupsCategory, "no backup information", path, err)
+		}
+		backups = append(backups, backup)
+	}
+
+	// Backups are often tens of gigabytes in many small files, so they are measured concurrently.
+	utils.ParallelFor(len(backups), func(i int) {
+		usage, err := utils.GetFileUsage(backups[i].Path)
+		if err != nil {
+			logger.RunWarnings.Add(deviceBackupsCategory, reclaimer.SkipReasonForError(err), backups[i].Path, err)
+		}
+		backups[i].Size = usage.Private()
+	})
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].Date.Equal(backups[j].Date) {
+			return backups[i].Date.Before(backups[j].Date)
+		}
+		return backups[i].Path < backups[j].Path
+	})
+	return backups, nil
+}
+
+// readInfo fills in the device and date of the backup from its Info.plist.
+func (b *DeviceBackup) readInfo() error {
+	data, err := readPlist(filepath.Join(b.Path, "Info.plist"))
+	if err != nil {
+		return err
+	}
+	name, err := plistString(data, "Device Name")
+	if err != nil {
+		return fmt.Errorf("failed to parse Info.plist: %w", err)
+	}
+	if name != "" {
+		b.DeviceName = name
+	}
+	// The keys were read successfully once, so the rest of the document parses too.
+	b.ProductType, _ = plistString(data, "Product Type")
+	b.ProductVersion, _ = plistString(data, "Product Version")
+	if value, _ := plistValue(data, "Last Backup Date", "date"); value != "" {
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("invalid Last Backup Date %q: %w", value, err)
+		}
+		b.Date = date
+	}
+	return nil
+}
+
+// CleanDeviceBackups removes the given backups, asking for each one unless --yes was given, through
+// the same safety checks and summaries as the other cleanups. Each backup is its own category, so
+// the prompts and summaries name its device and date.
+//
+// Parameters:
+//   - ctx: Cancels the cleanup between backups (see processCleanupItems).
+//   - backups: The backups to offer, usually from FindDeviceBackups.
+//   - dryRun: If true, the backups are only recorded as estimated.
+//   - summary: A pointer to a SummaryTable to record removed backups.
+//   - estimatedSummary: A pointer to a SummaryTable to record estimates and skipped backups.
+//
+// Returns:
+//   - The total space reclaimed in bytes and an error, if any.
+func CleanDeviceBackups(ctx context.Context, backups []DeviceBackup, dryRun bool, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable) (int64, error) {
+	var items []cleanupItem
+	for _, backup := range backups {
+		if utils.IsProtectedPath(backup.Path) {
+			estimatedSummary.AddSkippedReason(backup.Path, backup.Size, backup.Label(), reclaimer.SkipReasonProtected)
+			continue
+		}
+		items = append(items, cleanupItem{
+			Path:       backup.Path,
+			Size:       backup.Size,
+			Category:   backup.Label(),
+			ActualPath: backup.Path,
+			Scanned:    utils.FingerprintOf(backup.info),
+		})
+	}
+
+	reclaimed, err := processCleanupItems(ctx, items, dryRun, confirmEachCategory, summary, estimatedSummary, "Detected Device Backups")
+	if err != nil {
+		return reclaimed, fmt.Errorf("failed to process device backups cleanup: %w", err)
+	}
+	return reclaimed, nil
+}
@@ -0,0 +1,258 @@
+package cleaner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// HOMEBREW ORPHANS
+// ====================================================================================================
+
+// DefaultBrewUnusedFor is how long a Homebrew package must go unused before it is reported as an orphan.
+const DefaultBrewUnusedFor = 90 * 24 * time.Hour
+
+// Category names used for Homebrew packages in summary tables.
+const (
+	brewFormulaCategory = "Homebrew Formulae"
+	brewCaskCategory    = "Homebrew Casks"
+)
+
+// BrewPackage is an installed Homebrew formula or cask that no other package depends on.
+type BrewPackage struct {
+	// Name is the formula or cask token (e.g., "wget").
+	Name string
+	// Cask is true for casks and false for formulae.
+	Cask bool
+	// Path is the package's directory in the Cellar or Caskroom.
+	Path string
+	// Size is the disk usage of Path in bytes.
+	Size int64
+	// LastUsed is when the package was last used: the most recent access time of a formula's
+	// executables, or when the apps of a cask were last opened (kMDItemLastUsedDate).
+	LastUsed time.Time
+}
+
+// Category returns the summary table category of the package.
+func (p BrewPackage) Category() string {
+	if p.Cask {
+		return brewCaskCategory
+	}
+	return brewFormulaCategory
+}
+
+// FindBrewOrphans lists the Homebrew formulae that no other formula depends on (`brew leaves`)
+// and the casks, keeping those that haven't been used for at least unusedFor and that no installed
+// package depends on (`brew uses --installed`). Usage is derived from the access times of a
+// formula's opt link and its executables, and from when the apps of a cask were last opened.
+// Packages whose last use is unknown (e.g., casks without an app) count as used and are kept.
+//
+// Parameters:
+//   - unusedFor: The minimum time since last use. 0 means DefaultBrewUnusedFor.
+//
+// Returns:
+//   - The orphaned packages, largest first, and an error if Homebrew is missing or failed.
+func FindBrewOrphans(unusedFor time.Duration) ([]BrewPackage, error) {
+	if unusedFor <= 0 {
+		unusedFor = DefaultBrewUnusedFor
+	}
+	prefix, err := brewOutput("--prefix")
+	if err != nil {
+		return nil, err
+	}
+	prefix = strings.TrimSpace(prefix)
+
+	leaves, err := brewOutput("leaves")
+	if err != nil {
+		return nil, err
+	}
+	casks, err := brewOutput("list", "--cask", "-1")
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []BrewPackage
+	for _, name := range strings.Fields(leaves) {
+		// Tapped formulae are listed as user/tap/name, but installed under their short name.
+		name = filepath.Base(name)
+		candidates = append(candidates, BrewPackage{
+			Name:     name,
+			Path:     filepath.Join(prefix, "Cellar", name),
+			LastUsed: lastUsed(filepath.Join(prefix, "opt", name), filepath.Join(prefix, "Cellar", name, "*", "bin", "*")),
+		})
+	}
+	appCasks, err := brewCaskApps()
+	if err != nil {
+		return nil, err
+	}
+	caskApps := make(map[string][]string)
+	for app, token := range appCasks {
+		caskApps[token] = append(caskApps[token], app)
+	}
+	for _, name := range strings.Fields(casks) {
+		candidates = append(candidates, BrewPackage{
+			Name:     name,
+			Cask:     true,
+			Path:     filepath.Join(prefix, "Caskroom", name),
+			LastUsed: caskLastUsed(caskApps[name]),
+		})
+	}
+
+	cutoff := time.Now().Add(-unusedFor)
+	var orphans []BrewPackage
+	for _, pkg := range candidates {
+		if pkg.LastUsed.IsZero() {
+			logger.Log.Debugf("Skipping Homebrew package %s: its last use is unknown", pkg.Name)
+			continue
+		}
+		if pkg.LastUsed.After(cutoff) {
+			logger.Log.Debugf("Skipping recently used Homebrew package %s (last used %s)", pkg.Name, pkg.LastUsed.Format("2006-01-02"))
+			continue
+		}
+		// Casks may depend on formulae and other casks, which `brew leaves` doesn't consider.
+		dependents, err := brewOutput("uses", "--installed", pkg.Name)
+		if err != nil {
+			logger.RunWarnings.Add(pkg.Category(), "dependents unknown", pkg.Path, err)
+			continue
+		}
+		if names := strings.Fields(dependents); len(names) > 0 {
+			logger.Log.Debugf("Skipping Homebrew package %s: %s depends on it", pkg.Name, strings.Join(names, ", "))
+			continue
+		}
+		size, err := utils.GetFileSizeInBytes(pkg.Path)
+		if err != nil {
+			logger.RunWarnings.Add(pkg.Category(), reclaimer.SkipReasonForError(err), pkg.Path, err)
+			continue
+		}
+		pkg.Size = size
+		orphans = append(orphans, pkg)
+	}
+
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Size != orphans[j].Size {
+			return orphans[i].Size > orphans[j].Size
+		}
+		return orphans[i].Name < orphans[j].Name
+	})
+	return orphans, nil
+}
+
+// RemoveBrewPackages uninstalls the given packages with `brew uninstall`, recording each
+// outcome in the summary. A failing package doesn't stop the others.
+//
+// Parameters:
+//   - packages: The packages to uninstall, usually from FindBrewOrphans.
+//   - dryRun: If true, the packages are only recorded as estimated.
+//   - summary: A pointer to a SummaryTable to record removed or estimated packages.
+//
+// Returns:
+//   - The total space reclaimed (or estimated, in dry-run mode) in bytes.
+func RemoveBrewPackages(packages []BrewPackage, dryRun bool, summary *reclaimer.SummaryTable) int64 {
+	var reclaimed int64
+	removed, failed := 0, 0
+	defer func() { history.Add(removed, reclaimed, failed) }()
+	for _, pkg := range packages {
+		if dryRun {
+			summary.AddEstimated(pkg.Path, pkg.Size, pkg.Category())
+			reclaimed += pkg.Size
+			removed++
+			continue
+		}
+
+		if !allowRemoval(pkg.Path, pkg.Size, pkg.Category(), summary) {
+			continue
+		}
+		start := time.Now()
+		args := []string{"uninstall", "--formula", pkg.Name}
+		if pkg.Cask {
+			args = []string{"uninstall", "--cask", pkg.Name}
+		}
+		logger.Log.Infof("Running brew %s", strings.Join(args, " "))
+		_, err := brewOutput(args...)
+		summary.Timings.AddDelete(pkg.Category(), time.Since(start))
+		if err != nil {
+			logger.Log.With("category", pkg.Category()).Errorf("Failed to uninstall %s: %v", pkg.Name, err)
+			summary.AddFailed(pkg.Path, pkg.Size, pkg.Category(), err)
+			failed++
+			continue
+		}
+		summary.AddRemoved(pkg.Path, pkg.Size, pkg.Category())
+		reclaimed += pkg.Size
+		removed++
+	}
+	return reclaimed
+}
+
+// brewOutput runs brew with the given arguments and returns its standard output.
+// Homebrew's auto-update is disabled, so listing packages doesn't trigger a slow `brew update`.
+func brewOutput(args ...string) (string, error) {
+	brew, err := exec.LookPath("brew")
+	if err != nil {
+		return "", fmt.Errorf("Homebrew is not installed (brew not found in PATH)")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(brew, args...)
+	cmd.Env = append(os.Environ(), "HOMEBREW_NO_AUTO_UPDATE=1")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("brew %s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("brew %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// caskLastUsed returns when any of the apps of a cask was last opened, or the zero time if none
+// of them is installed or macOS doesn't know.
+func caskLastUsed(apps []string) time.Time {
+	var latest time.Time
+	for _, app := range apps {
+		for _, dir := range []string{"/Applications", utils.ExpandPath("~/Applications")} {
+			bundlePath := filepath.Join(dir, app)
+			if _, err := os.Stat(bundlePath); err != nil {
+				continue
+			}
+			if used := appLastUsed(bundlePath); used.After(latest) {
+				latest = used
+			}
+		}
+	}
+	return latest
+}
+
+// lastUsed returns the most recent access time of link (not followed) and of the entries matching pattern.
+// Only entries are stat'ed and links are never resolved here, so the lookup itself doesn't update
+// the access times it reads; listing a directory would refresh the directory's own access time.
+func lastUsed(link string, pattern string) time.Time {
+	var latest time.Time
+	if link != "" {
+		if info, err := os.Lstat(link); err == nil {
+			latest = utils.AccessTime(info)
+		}
+	}
+	matches, _ := filepath.Glob(pattern)
+	for _, match := range matches {
+		info, err := os.Lstat(match)
+		if err != nil {
+			continue
+		}
+		if atime := utils.AccessTime(info); atime.After(latest) {
+			latest = atime
+		}
+	}
+	return latest
+}
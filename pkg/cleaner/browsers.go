@@ -0,0 +1,199 @@
+package cleaner
+
+import (
+	"bufio"         // Imported for reading profiles.ini line by line
+	"encoding/json" // Imported for parsing Chromium's Local State file
+	"os"            // Imported for reading profile metadata
+	"path/filepath" // Imported for filepath.Join and other path manipulations
+	"sort"          // Imported for a stable profile order
+	"strings"       // Imported for parsing and case-insensitive matching
+
+	"github.com/kodelint/wiper/pkg/logger" // Imported for debug output
+)
+
+// ====================================================================================================
+// BROWSER PROFILES
+// ====================================================================================================
+
+// browserKind identifies how a browser records its profiles.
+type browserKind int
+
+const (
+	// chromiumBrowser is Chrome, Chromium, Brave, and other browsers that list their profiles
+	// in the "Local State" JSON file of their user data directory.
+	chromiumBrowser browserKind = iota
+	// firefoxBrowser lists its profiles in profiles.ini.
+	firefoxBrowser
+)
+
+// browserInstall describes where a browser keeps its profiles and their caches.
+type browserInstall struct {
+	// Name is the browser's display name, used in log output (e.g., "Chrome").
+	Name string
+	// Kind determines how the profile list is read.
+	Kind browserKind
+	// ProfileRoot is the directory containing "Local State" or profiles.ini and, usually, the profiles.
+	ProfileRoot string
+	// CacheSubdirs are cache directories inside each profile directory (e.g., "Cache").
+	CacheSubdirs []string
+	// CacheRoots are directories holding a separate cache directory per profile,
+	// named like the profile's directory (e.g., ~/Library/Caches/Google/Chrome/Default).
+	CacheRoots []string
+}
+
+// browserProfile is a single profile of a browser.
+type browserProfile struct {
+	// Name is the name shown in the browser's profile menu (e.g., "Work").
+	Name string
+	// Dir is the profile directory relative to the browser's ProfileRoot (e.g., "Profile 1"),
+	// or an absolute path for Firefox profiles stored elsewhere.
+	Dir string
+}
+
+// browserProfileSelection lists the profiles whose caches are cleaned, by name or directory.
+// Empty means every profile.
+var browserProfileSelection []string
+
+// SetBrowserProfiles limits browser cache cleaning to the given profiles. Each entry is matched
+// case-insensitively against a profile's display name (e.g., "Work") and its directory name
+// (e.g., "Default", "Profile 1"). An empty list selects every profile.
+func SetBrowserProfiles(profiles []string) {
+	browserProfileSelection = nil
+	for _, profile := range profiles {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			browserProfileSelection = append(browserProfileSelection, profile)
+		}
+	}
+}
+
+// browserCachePaths returns the cache glob patterns of the selected profiles of every browser.
+func browserCachePaths(browsers []browserInstall) []string {
+	var paths []string
+	for _, browser := range browsers {
+		profiles, err := browser.profiles()
+		if err != nil {
+			if !os.IsNotExist(err) {
+				logger.Log.Debugf("Failed to read %s profiles: %v", browser.Name, err)
+			}
+			if len(browserProfileSelection) > 0 {
+				// Without a profile list there is no way to tell which directory belongs to the selection.
+				continue
+			}
+			// Fall back to every profile directory.
+			profiles = []browserProfile{{Name: "*", Dir: "*"}}
+		}
+
+		for _, profile := range profiles {
+			if !profile.selected() {
+				continue
+			}
+			logger.Log.Debugf("Including %s profile %q (%s)", browser.Name, profile.Name, profile.Dir)
+			profileDir := profile.Dir
+			if !filepath.IsAbs(profileDir) {
+				profileDir = filepath.Join(browser.ProfileRoot, profile.Dir)
+				for _, root := range browser.CacheRoots {
+					paths = append(paths, filepath.Join(root, profile.Dir, "*"))
+				}
+			}
+			for _, subdir := range browser.CacheSubdirs {
+				paths = append(paths, filepath.Join(profileDir, subdir, "*"))
+			}
+		}
+	}
+	return paths
+}
+
+// selected reports whether the profile is part of browserProfileSelection.
+func (p browserProfile) selected() bool {
+	if len(browserProfileSelection) == 0 {
+		return true
+	}
+	for _, want := range browserProfileSelection {
+		if strings.EqualFold(want, p.Name) || strings.EqualFold(want, filepath.Base(p.Dir)) {
+			return true
+		}
+	}
+	return false
+}
+
+// profiles reads the browser's profile list.
+func (b browserInstall) profiles() ([]browserProfile, error) {
+	switch b.Kind {
+	case firefoxBrowser:
+		return readFirefoxProfiles(filepath.Join(b.ProfileRoot, "profiles.ini"))
+	default:
+		return readChromiumProfiles(filepath.Join(b.ProfileRoot, "Local State"))
+	}
+}
+
+// chromiumLocalState holds the part of Chromium's "Local State" file that lists the profiles.
+type chromiumLocalState struct {
+	Profile struct {
+		InfoCache map[string]struct {
+			Name string `json:"name"`
+		} `json:"info_cache"`
+	} `json:"profile"`
+}
+
+// readChromiumProfiles returns the profiles listed in a Chromium "Local State" file, sorted by directory.
+func readChromiumProfiles(path string) ([]browserProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var state chromiumLocalState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+
+	profiles := make([]browserProfile, 0, len(state.Profile.InfoCache))
+	for dir, info := range state.Profile.InfoCache {
+		profiles = append(profiles, browserProfile{Name: info.Name, Dir: dir})
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Dir < profiles[j].Dir })
+	return profiles, nil
+}
+
+// readFirefoxProfiles returns the profiles listed in a Firefox profiles.ini file, in file order.
+func readFirefoxProfiles(path string) ([]browserProfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var profiles []browserProfile
+	var current *browserProfile
+	flush := func() {
+		if current != nil && current.Dir != "" {
+			profiles = append(profiles, *current)
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			flush()
+			// Only [ProfileN] sections describe profiles; [General] and [Install...] don't.
+			if strings.HasPrefix(line, "[Profile") {
+				current = &browserProfile{}
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || current == nil {
+			continue
+		}
+		// Path is relative to profiles.ini unless IsRelative=0, in which case it is absolute.
+		switch key {
+		case "Name":
+			current.Name = value
+		case "Path":
+			current.Dir = filepath.FromSlash(value)
+		}
+	}
+	flush()
+	return profiles, scanner.Err()
+}
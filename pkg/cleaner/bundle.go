@@ -0,0 +1,119 @@
+package cleaner
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ====================================================================================================
+// APPLICATION BUNDLE METADATA
+// ====================================================================================================
+
+// errNoBundleIdentifier is returned by bundleIdentifier when Info.plist has no CFBundleIdentifier.
+var errNoBundleIdentifier = errors.New("Info.plist has no CFBundleIdentifier")
+
+// bundleIdentifier returns the CFBundleIdentifier of an application bundle (e.g.,
+// "com.microsoft.VSCode" for Visual Studio Code.app), read from its Contents/Info.plist.
+func bundleIdentifier(bundlePath string) (string, error) {
+	id, err := readPlistString(filepath.Join(bundlePath, "Contents", "Info.plist"), "CFBundleIdentifier")
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "", errNoBundleIdentifier
+	}
+	return id, nil
+}
+
+// readPlistString returns the string value of key in the top-level dictionary of the property list
+// at plistPath, or an empty string if it has none.
+func readPlistString(plistPath string, key string) (string, error) {
+	data, err := readPlist(plistPath)
+	if err != nil {
+		return "", err
+	}
+	value, err := plistString(data, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", plistPath, err)
+	}
+	return value, nil
+}
+
+// readPlist returns the property list at plistPath in XML form. XML property lists are read
+// directly; binary ones are converted with plutil first.
+func readPlist(plistPath string) ([]byte, error) {
+	data, err := os.ReadFile(plistPath)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte("bplist")) {
+		plutil, err := exec.LookPath("plutil")
+		if err != nil {
+			return nil, fmt.Errorf("%s is a binary property list and plutil is not available", plistPath)
+		}
+		data, err = exec.Command(plutil, "-convert", "xml1", "-o", "-", plistPath).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", plistPath, err)
+		}
+	}
+	return data, nil
+}
+
+// plistString returns the string value of key in the top-level dictionary of an XML property list,
+// or an empty string if the key is missing or not a string.
+func plistString(data []byte, key string) (string, error) {
+	return plistValue(data, key, "string")
+}
+
+// plistValue returns the text of the value of key in the top-level dictionary of an XML property
+// list if it is a kind element (e.g., "string" or "date"), or an empty string otherwise.
+func plistValue(data []byte, key string, kind string) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0         // Nesting level of the current element; entries of the top-level dict are at 3
+	expecting := false // Whether the next value of the top-level dict belongs to key
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth != 3 {
+				continue
+			}
+			switch t.Name.Local {
+			case "key":
+				var name string
+				if err := decoder.DecodeElement(&name, &t); err != nil {
+					return "", err
+				}
+				depth-- // DecodeElement consumed the end element
+				expecting = strings.TrimSpace(name) == key
+			case kind:
+				var value string
+				if err := decoder.DecodeElement(&value, &t); err != nil {
+					return "", err
+				}
+				depth--
+				if expecting {
+					return strings.TrimSpace(value), nil
+				}
+			default:
+				expecting = false
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
@@ -2,11 +2,18 @@ package cleaner
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/i18n"
 	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/quarantine"
 	"github.com/kodelint/wiper/pkg/reclaimer"
 	"github.com/kodelint/wiper/pkg/utils"
 )
@@ -19,11 +26,49 @@ import (
 // This struct holds all the necessary information for the cleanup process.
 type cleanupItem struct {
 	Path       string // The aggregated category or display path for the dry run table
-	Size       int64
+	Size       int64  // The bytes removing the item frees
+	Cloned     int64  // The bytes the item shares with APFS clones, which removing it doesn't free
 	Category   string // The actual category for the summary table
 	ActualPath string // The actual file/directory path to delete
+	Root       string // The directory the item must stay within when deleted; empty means the item itself
+	MoveTo     string // When set, the item is moved into this directory instead of being deleted
+	TargetID   string // The ID of the cleanup target that found the item; empty for large files and apps
+
+	// Scanned is what the scan saw of ActualPath; the item is left alone if it changed since.
+	Scanned utils.Fingerprint
+	// Kept is the copy that is kept in place of the item (e.g., of a duplicate), and KeptScanned
+	// what the scan saw of it; the item is left alone if the kept copy changed or is gone.
+	Kept        string
+	KeptScanned utils.Fingerprint
+
+	// Remove, when set, removes the item instead of deleting it by path (see CleanupTarget.Remove).
+	Remove func(path string) error
 }
 
+// confirmMode selects how processCleanupItems asks before deleting.
+type confirmMode int
+
+const (
+	// confirmOnce asks a single question for all items (the default for system cleanup).
+	confirmOnce confirmMode = iota
+	// confirmEachItem asks for every item (--interactive for large files).
+	confirmEachItem
+	// confirmEachCategory asks once per category (--interactive for system cleanup).
+	confirmEachCategory
+	// confirmNone deletes without asking, because the caller already confirmed (e.g., app uninstall).
+	confirmNone
+)
+
+// categoryChoice is an answer to a per-category prompt.
+type categoryChoice int
+
+const (
+	choiceNo categoryChoice = iota
+	choiceYes
+	choiceAll
+	choiceQuit
+)
+
 // dryRunItem represents a folder and its size that would be removed in a dry run.
 // This is specifically for the aggregated table display to show total sizes by category.
 type dryRunItem struct {
@@ -35,23 +80,93 @@ type dryRunItem struct {
 // UTILITY FUNCTIONS
 // ====================================================================================================
 
+// stdinReader is shared by all prompts, so answers typed (or piped) ahead aren't lost in the
+// buffer of a previous prompt's reader.
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// readAnswer reads one line from standard input. It returns ctx's error as soon as ctx is
+// cancelled (e.g., by Ctrl+C), instead of waiting for the user to press Enter.
+func readAnswer(ctx context.Context) (string, error) {
+	answer := make(chan string, 1)
+	go func() {
+		line, _ := stdinReader.ReadString('\n')
+		answer <- line
+	}()
+	select {
+	case line := <-answer:
+		return strings.ToLower(strings.TrimSpace(line)), nil
+	case <-ctx.Done():
+		println("")
+		return "", ctx.Err()
+	}
+}
+
+// assumeYes answers every confirmation with yes (--yes), so wiper can run unattended.
+var assumeYes bool
+
+// SetAssumeYes controls whether ConfirmAction asks the user or answers yes by itself. Prompts that
+// offer a choice (per-category cleanups, the selection list, similar app names) can't be answered
+// that way; callers refuse to combine those modes with it.
+func SetAssumeYes(enabled bool) {
+	assumeYes = enabled
+}
+
 // ConfirmAction asks the user for a yes/no confirmation.
 // This function is now shared by all cleanup processes that require user interaction.
-func ConfirmAction(prompt string) bool {
-	reader := bufio.NewReader(os.Stdin)
+// Accepted answers follow the active language (e.g., "j"/"ja" in German), with English always understood.
+// An interrupted prompt counts as No. With SetAssumeYes, the prompt is only printed (unless output
+// is quiet) and accepted.
+func ConfirmAction(ctx context.Context, prompt string) bool {
+	if assumeYes {
+		if logger.Quiet() {
+			return ctx.Err() == nil
+		}
+		fmt.Printf("%s %s: yes (--yes)\n\n", prompt, i18n.T("prompt.confirm_suffix"))
+		return ctx.Err() == nil
+	}
 	for {
-		fmt.Printf("%s (y/N): ", prompt)
-		input, _ := reader.ReadString('\n')
-		input = strings.ToLower(strings.TrimSpace(input))
-		if input == "y" || input == "yes" {
+		fmt.Printf("%s %s: ", prompt, i18n.T("prompt.confirm_suffix"))
+		input, err := readAnswer(ctx)
+		if err != nil {
+			return false
+		}
+		if i18n.IsYes(input) {
 			println("")
 			return true
 		}
-		if input == "n" || input == "no" || input == "" { // Default to No on empty input
+		if i18n.IsNo(input) || input == "" { // Default to No on empty input
 			println("")
 			return false
 		}
-		fmt.Println("Invalid input. Please enter 'y' or 'n'.")
+		fmt.Println(i18n.T("prompt.invalid_input"))
+	}
+}
+
+// confirmCategory asks whether to clean a category, also accepting "all" (clean this and every
+// following category without asking) and "quit" (skip everything that's left).
+// An interrupted prompt counts as quit.
+func confirmCategory(ctx context.Context, prompt string) categoryChoice {
+	for {
+		fmt.Printf("%s %s: ", prompt, i18n.T("prompt.category_suffix"))
+		input, err := readAnswer(ctx)
+		if err != nil {
+			return choiceQuit
+		}
+		choice := choiceNo
+		switch {
+		case i18n.IsYes(input):
+			choice = choiceYes
+		case i18n.IsAll(input):
+			choice = choiceAll
+		case i18n.IsQuit(input):
+			choice = choiceQuit
+		case i18n.IsNo(input) || input == "": // Default to No on empty input
+		default:
+			fmt.Println(i18n.T("prompt.invalid_choice"))
+			continue
+		}
+		println("")
+		return choice
 	}
 }
 
@@ -61,26 +176,28 @@ func ConfirmAction(prompt string) bool {
 
 // processCleanupItems handles the confirmation and removal logic for a list of items.
 // This is a central function that manages different cleanup modes (dry run, interactive, etc.).
+// When ctx is cancelled, the item being removed is finished, the remaining items are recorded as
+// skipped, and ctx's error is returned together with the space reclaimed so far.
 //
 // Parameters:
+//   - ctx: Cancels the cleanup between items.
 //   - items: The slice of cleanupItem structs to process.
 //   - dryRun: A boolean flag for dry-run mode.
-//   - interactive: A boolean flag for interactive mode (per-file confirmation).
+//   - mode: How to confirm the deletion: once, per item, per category, or not at all; see confirmMode.
 //   - summary: A pointer to a SummaryTable to record actual deletions.
 //   - estimatedSummary: A pointer to a SummaryTable to record dry-run estimations.
-//   - tableTitle: The title for the summary table.
-//   - isApp: A boolean flag indicating if the cleanup is for an application uninstallation.
+//   - tableTitle: The title for the summary table and the selection list (--tui).
 //
 // Returns:
 //   - The total space reclaimed in bytes and an error, if any.
 func processCleanupItems(
+	ctx context.Context,
 	items []cleanupItem,
 	dryRun bool,
-	interactive bool,
+	mode confirmMode,
 	summary *reclaimer.SummaryTable,
 	estimatedSummary *reclaimer.SummaryTable,
 	tableTitle string,
-	isApp bool,
 ) (int64, error) {
 	var totalReclaimed int64
 
@@ -98,7 +215,8 @@ func processCleanupItems(
 			displayKey = item.Category
 		}
 		aggregatedForTable[displayKey] += item.Size
-		estimatedSummary.AddEntry(item.ActualPath, item.Size, false, item.Category)
+		estimatedSummary.AddEstimated(item.ActualPath, item.Size, item.Category)
+		estimatedSummary.MarkCloned(item.Cloned)
 	}
 
 	var tableItems []dryRunItem
@@ -107,60 +225,65 @@ func processCleanupItems(
 	}
 
 	// Print the table of detected items by category [Estimated]
-	estimatedSummary.PrintTable(true, "Estimated Reclaimed Summary")
+	// In quiet mode, only the final summary of a real cleanup is printed.
+	if dryRun || !logger.Quiet() {
+		estimatedSummary.PrintTable(true, i18n.T("summary.estimated_title"))
+	}
 
 	// If dry run mode is enabled, we stop here and just return the estimated total.
 	if dryRun {
 		for _, item := range tableItems { // Sum from tableItems for dry run estimate
 			totalReclaimed += item.Size
 		}
+		history.Add(len(items), totalReclaimed, 0)
 		return totalReclaimed, nil
 	}
 
+	// Every way of removing the items below records them in the summary, so the run's history is
+	// updated from what the summary gained.
+	removedBefore := len(summary.ByStatus(reclaimer.StatusRemoved))
+	reclaimedBefore, failedBefore := summary.TotalReclaimedBytes(), summary.FailedCount()
+	defer func() {
+		removed := len(summary.ByStatus(reclaimer.StatusRemoved)) - removedBefore
+		history.Add(removed, summary.TotalReclaimedBytes()-reclaimedBefore, summary.FailedCount()-failedBefore)
+	}()
+
 	// Step 2: Actual Deletion Logic (Non-Dry Run)
+	// With --tui, the user picks the items from a checkbox list instead of answering prompts.
+	if selectionUI && mode != confirmNone {
+		return selectAndRemove(ctx, items, summary, tableTitle)
+	}
 	var actualRemovedSize int64
 
 	// Case 1: Interactive Mode
 	// The user is prompted to confirm each deletion individually.
-	if interactive {
+	if mode == confirmEachItem {
 		logger.Log.Info("Starting interactive cleanup. You will be prompted for each item.")
-		for _, item := range items { // Loop through actual files for deletion (original `items` list)
-			prompt := fmt.Sprintf("Delete %s (%s, Category: %s)?", item.ActualPath, utils.FormatBytes(item.Size), item.Category)
-			if ConfirmAction(prompt) {
-				reclaimed, err := utils.RemovePath(item.ActualPath, false) // false for not dry run
-				if err != nil {
-					logger.Log.Errorf("Failed to remove %s: %v", item.ActualPath, err)
-					summary.AddEntry(item.ActualPath, item.Size, false, item.Category) // Mark as not removed on error
-				} else {
-					actualRemovedSize += reclaimed
-					summary.AddEntry(item.ActualPath, reclaimed, true, item.Category) // Mark as removed
-					if os.Getenv("WIPER_SHOW_DETAILS") == "true" {                    // Use the same detail env var
-						logger.Log.Infof("Removed %s", item.ActualPath)
-					}
-				}
+		for i, item := range items { // Loop through actual files for deletion (original `items` list)
+			prompt := i18n.T("prompt.delete_item", item.ActualPath, utils.FormatBytes(item.Size), item.Category)
+			confirmed := ConfirmAction(ctx, prompt)
+			if ctx.Err() != nil {
+				skipCancelled(items[i:], summary)
+				break
+			}
+			if confirmed {
+				actualRemovedSize += removeItem(item, summary)
 			} else {
 				logger.Log.Infof("Skipped %s", item.ActualPath)
-				summary.AddEntry(item.ActualPath, item.Size, false, item.Category) // Add to summary but mark as not removed
+				summary.AddSkipped(item.ActualPath, item.Size, item.Category) // Add to summary but mark as skipped
 			}
 		}
-		// Case 2: Application Uninstallation Mode
+		// Case 2: Per-Category Mode
+		// The user is prompted once per category, largest first.
+	} else if mode == confirmEachCategory {
+		actualRemovedSize = processByCategory(ctx, items, summary)
+
+		// Case 3: Application Uninstallation Mode
 		// This mode assumes a single confirmation was already given for the entire application.
 		// It proceeds to delete all files found without further prompts.
-	} else if isApp {
-		for _, item := range items { // Loop through actual files for deletion (original `items` list)
-			reclaimed, err := utils.RemovePath(item.ActualPath, false) // false for not dry run
-			if err != nil {
-				logger.Log.Errorf("Failed to remove %s: %v", item.ActualPath, err)
-				summary.AddEntry(item.ActualPath, item.Size, false, item.Category) // Mark as not removed on error
-			} else {
-				actualRemovedSize += reclaimed
-				summary.AddEntry(item.ActualPath, reclaimed, true, item.Category) // Mark as removed
-				if os.Getenv("WIPER_SHOW_DETAILS") == "true" {                    // Use the same detail env var
-					logger.Log.Infof("Removed %s", item.ActualPath)
-				}
-			}
-		}
-		// Case 3: Single Confirmation Mode (Default for System Cleanup)
+	} else if mode == confirmNone {
+		actualRemovedSize = removeItems(ctx, items, summary)
+		// Case 4: Single Confirmation Mode (Default for System Cleanup)
 		// This mode prompts the user once to confirm the deletion of all items.
 	} else {
 		// Single confirmation mode: ask once for all detected files
@@ -169,23 +292,15 @@ func processCleanupItems(
 			totalPotentialReclaimed += item.Size
 		}
 		println()
-		prompt := fmt.Sprintf("Do you want to clean up these items (Total: %s)?", reclaimer.FormatBytes(totalPotentialReclaimed))
-		if ConfirmAction(prompt) {
-			println(utils.Yellow("  Proceeding with cleanup...🚀"))
-			println(utils.CyanBold("================================"))
-			for _, item := range items { // Loop through actual files for deletion (original `items` list)
-				reclaimed, err := utils.RemovePath(item.ActualPath, false) // false for not dry run
-				if err != nil {
-					logger.Log.Errorf("Failed to remove %s: %v", item.ActualPath, err)
-					summary.AddEntry(item.ActualPath, item.Size, false, item.Category) // Mark as not removed on error
-				} else {
-					actualRemovedSize += reclaimed
-					summary.AddEntry(item.ActualPath, reclaimed, true, item.Category) // Mark as removed
-					if os.Getenv("WIPER_SHOW_DETAILS") == "true" {                    // Use the same detail env var
-						logger.Log.Infof("Removed %s", item.ActualPath)
-					}
-				}
+		prompt := i18n.T("prompt.cleanup_all", reclaimer.FormatBytes(totalPotentialReclaimed))
+		if ConfirmAction(ctx, prompt) {
+			if !logger.Quiet() {
+				println(utils.Yellow("  Proceeding with cleanup...🚀"))
+				println(utils.CyanBold("================================"))
 			}
+			actualRemovedSize = removeItems(ctx, items, summary)
+		} else if ctx.Err() != nil {
+			skipCancelled(items, summary)
 		} else {
 			logger.Log.Info("Cleanup cancelled by user.")
 			return 0, nil // Return 0 reclaimed and no error if cancelled
@@ -193,5 +308,283 @@ func processCleanupItems(
 	}
 
 	totalReclaimed = actualRemovedSize
-	return totalReclaimed, nil
+	return totalReclaimed, ctx.Err()
+}
+
+// removeItems removes items in order until ctx is cancelled, and records the items it didn't get
+// to as skipped.
+//
+// Returns:
+//   - The number of bytes reclaimed.
+func removeItems(ctx context.Context, items []cleanupItem, summary *reclaimer.SummaryTable) int64 {
+	var reclaimed int64
+	for i, item := range items {
+		if ctx.Err() != nil {
+			skipCancelled(items[i:], summary)
+			break
+		}
+		reclaimed += removeItem(item, summary)
+	}
+	return reclaimed
+}
+
+// skipCancelled records items that were left alone because the cleanup was interrupted, so the
+// summary shows exactly what was and wasn't removed.
+func skipCancelled(items []cleanupItem, summary *reclaimer.SummaryTable) {
+	for _, item := range items {
+		summary.AddSkippedReason(item.ActualPath, item.Size, item.Category, reclaimer.SkipReasonCancelled)
+	}
+}
+
+// processByCategory asks once per category, largest first, and removes the items of every
+// accepted category. Items of declined categories, and of all categories after "quit", are
+// recorded as skipped.
+//
+// Returns:
+//   - The number of bytes reclaimed.
+func processByCategory(ctx context.Context, items []cleanupItem, summary *reclaimer.SummaryTable) int64 {
+	var categories []string
+	byCategory := make(map[string][]cleanupItem)
+	sizes := make(map[string]int64)
+	for _, item := range items {
+		if _, ok := byCategory[item.Category]; !ok {
+			categories = append(categories, item.Category)
+		}
+		byCategory[item.Category] = append(byCategory[item.Category], item)
+		sizes[item.Category] += item.Size
+	}
+	sort.SliceStable(categories, func(i, j int) bool { return sizes[categories[i]] > sizes[categories[j]] })
+
+	var reclaimed int64
+	choice := choiceNo
+	for _, category := range categories {
+		if ctx.Err() != nil {
+			skipCancelled(byCategory[category], summary)
+			continue
+		}
+		if choice != choiceAll && choice != choiceQuit {
+			choice = confirmCategory(ctx, i18n.T("prompt.clean_category", category, reclaimer.FormatBytes(sizes[category])))
+		}
+		if ctx.Err() != nil {
+			skipCancelled(byCategory[category], summary)
+			continue
+		}
+		if choice == choiceNo || choice == choiceQuit {
+			logger.Log.Infof("Skipped %s", category)
+			for _, item := range byCategory[category] {
+				summary.AddSkipped(item.ActualPath, item.Size, item.Category)
+			}
+			continue
+		}
+		reclaimed += removeItems(ctx, byCategory[category], summary)
+	}
+	return reclaimed
+}
+
+// removeItem deletes a single cleanup item and records the outcome in the summary and the
+// manifest of the run. When the quarantine is enabled, the item is moved there instead.
+// Failures are logged and recorded with their reason instead of aborting the whole cleanup.
+//
+// Returns:
+//   - The number of bytes reclaimed (0 if the removal failed).
+func removeItem(item cleanupItem, summary *reclaimer.SummaryTable) int64 {
+	start := time.Now()
+	defer func() { summary.Timings.AddDelete(item.Category, time.Since(start)) }()
+
+	if !allowRemoval(item.ActualPath, item.Size, item.Category, summary) {
+		return 0
+	}
+	// Moves, the quarantine and removal tools don't check the scan's fingerprint themselves.
+	if err := utils.VerifyScanned(item.ActualPath, item.Scanned); err != nil {
+		if errors.Is(err, utils.ErrChangedSinceScan) {
+			itemLog(item, item.Size).Infof("Skipped %s: it changed since it was scanned", item.ActualPath)
+			summary.AddSkippedReason(item.ActualPath, item.Size, item.Category, reclaimer.SkipReasonChanged)
+		} else {
+			itemLog(item, item.Size).Errorf("Failed to remove %s: %v", item.ActualPath, err)
+			summary.AddFailed(item.ActualPath, item.Size, item.Category, err)
+		}
+		return 0
+	}
+	if item.Kept != "" {
+		if err := utils.VerifyScanned(item.Kept, item.KeptScanned); err != nil {
+			itemLog(item, item.Size).Infof("Skipped %s: the copy kept in its place, %s, changed since it was scanned", item.ActualPath, item.Kept)
+			summary.AddSkippedReason(item.ActualPath, item.Size, item.Category, reclaimer.SkipReasonChanged)
+			return 0
+		}
+		// Two paths of one file (e.g., through a firmlink or a bind mount) aren't duplicates: removing
+		// either would remove the kept copy too.
+		if sameFile(item.ActualPath, item.Kept) {
+			itemLog(item, item.Size).Infof("Skipped %s: it is the same file as the copy kept in its place, %s", item.ActualPath, item.Kept)
+			summary.AddSkippedReason(item.ActualPath, item.Size, item.Category, reclaimer.SkipReasonSameFile)
+			return 0
+		}
+	}
+	if item.MoveTo != "" {
+		return moveItem(item, summary)
+	}
+
+	// Items owned by a tool (e.g., the read-only Go module cache) are always removed with it, since
+	// deleting, moving or overwriting them directly could break the tool's state.
+	if item.Remove != nil {
+		return removeWithTool(item, summary)
+	}
+
+	root := item.Root
+	if root == "" {
+		root = item.ActualPath
+	}
+	if quarantine.Enabled() {
+		if reclaimed, handled := quarantineItem(item, root, summary); handled {
+			return reclaimed
+		}
+	}
+	reclaimed, err := utils.RemoveScanned(item.ActualPath, root, item.Scanned)
+	if errors.Is(err, utils.ErrChangedSinceScan) {
+		itemLog(item, item.Size).Infof("Skipped %s: it changed since it was scanned", item.ActualPath)
+		summary.AddSkippedReason(item.ActualPath, item.Size, item.Category, reclaimer.SkipReasonChanged)
+		return 0
+	}
+	if err != nil {
+		itemLog(item, item.Size).Errorf("Failed to remove %s: %v", item.ActualPath, err)
+		summary.AddFailed(item.ActualPath, item.Size, item.Category, err)
+		return 0
+	}
+
+	action := quarantine.ActionDeleted
+	if utils.TrashMode() {
+		action = quarantine.ActionTrashed
+	}
+	recordRemoval(item, reclaimed, action, "")
+	summary.AddRemoved(item.ActualPath, reclaimed, item.Category)
+	summary.MarkCloned(item.Cloned)
+	itemLog(item, reclaimed).Detailf("Removed %s", item.ActualPath)
+	return reclaimed
+}
+
+// removeWithTool removes a cleanup item with the remover of its target and records the outcome in
+// the summary. The tool doesn't report what it freed, so the scanned size is reported as reclaimed.
+//
+// Returns:
+//   - The number of bytes reclaimed (0 if the removal failed).
+func removeWithTool(item cleanupItem, summary *reclaimer.SummaryTable) int64 {
+	if err := item.Remove(item.ActualPath); err != nil {
+		itemLog(item, item.Size).Errorf("Failed to remove %s: %v", item.ActualPath, err)
+		summary.AddFailed(item.ActualPath, item.Size, item.Category, err)
+		return 0
+	}
+	recordRemoval(item, item.Size, quarantine.ActionDeleted, "")
+	summary.AddRemoved(item.ActualPath, item.Size, item.Category)
+	summary.MarkCloned(item.Cloned)
+	itemLog(item, item.Size).Detailf("Removed %s", item.ActualPath)
+	return item.Size
+}
+
+// moveItem moves a cleanup item into its archive directory, after the same boundary check a
+// deletion makes, and records the outcome in the summary. Only moves to another volume free space
+// on the source volume, so items archived on the same volume are recorded with 0 bytes reclaimed.
+//
+// Returns:
+//   - The number of bytes reclaimed on the source volume.
+func moveItem(item cleanupItem, summary *reclaimer.SummaryTable) int64 {
+	log := itemLog(item, item.Size)
+	root := item.Root
+	if root == "" {
+		root = item.ActualPath
+	}
+	absPath, err := utils.CheckWithinRoot(item.ActualPath, root)
+	if err != nil {
+		log.Errorf("Failed to move %s: %v", item.ActualPath, err)
+		summary.AddFailed(item.ActualPath, item.Size, item.Category, err)
+		return 0
+	}
+	moved, err := utils.MovePath(absPath, item.MoveTo)
+	if err != nil {
+		log.Errorf("Failed to move %s: %v", item.ActualPath, err)
+		summary.AddFailed(item.ActualPath, item.Size, item.Category, err)
+		return 0
+	}
+	// The item was moved, but let the user know if the copy isn't identical (e.g., unsupported attributes).
+	for _, loss := range moved.LostMetadata {
+		logger.RunWarnings.Add(item.Category, "metadata not preserved", loss.Path, fmt.Errorf("%s: %w", loss.What, loss.Err))
+	}
+	recordRemoval(item, item.Size, quarantine.ActionArchived, moved.Dest)
+
+	var reclaimed int64
+	if moved.CrossedDevice {
+		reclaimed = item.Size
+	} else {
+		log.Debugf("%s was archived on the same volume; no space was freed", item.ActualPath)
+	}
+	summary.AddRemoved(item.ActualPath, reclaimed, item.Category)
+	log.Detailf("Moved %s to %s", item.ActualPath, moved.Dest)
+	return reclaimed
+}
+
+// quarantineItem moves a cleanup item into the quarantine of the run, after the same boundary
+// check a deletion makes. Like the Trash, the quarantine only frees space once it is purged, but
+// the item's size is reported as reclaimed.
+//
+// Returns:
+//   - The number of bytes reclaimed, and false if the item is already inside the quarantine and
+//     must be deleted instead.
+func quarantineItem(item cleanupItem, root string, summary *reclaimer.SummaryTable) (int64, bool) {
+	log := itemLog(item, item.Size)
+	absPath, err := utils.CheckWithinRoot(item.ActualPath, root)
+	if err != nil {
+		log.Errorf("Failed to remove %s: %v", item.ActualPath, err)
+		summary.AddFailed(item.ActualPath, item.Size, item.Category, err)
+		return 0, true
+	}
+	kept, moved, err := quarantine.Keep(absPath, item.Size, item.Category)
+	if !kept {
+		if err != nil {
+			log.Errorf("Failed to quarantine %s: %v", item.ActualPath, err)
+			summary.AddFailed(item.ActualPath, item.Size, item.Category, err)
+			return 0, true
+		}
+		return 0, false
+	}
+	if err != nil {
+		// The item is in the quarantine, but `wiper restore` won't know about it.
+		logger.RunWarnings.Add(item.Category, "not recorded in manifest", item.ActualPath, err)
+	}
+	for _, loss := range moved.LostMetadata {
+		logger.RunWarnings.Add(item.Category, "metadata not preserved", loss.Path, fmt.Errorf("%s: %w", loss.What, loss.Err))
+	}
+
+	summary.AddRemoved(item.ActualPath, item.Size, item.Category)
+	log.Detailf("Quarantined %s", item.ActualPath)
+	return item.Size, true
+}
+
+// itemLog returns a logger that adds the category, path and size (in bytes) of a cleanup item to
+// every record, for structured log formats.
+func itemLog(item cleanupItem, size int64) *logger.Logger {
+	return logger.Log.With("category", item.Category, "path", item.ActualPath, "size", size)
+}
+
+// sameFile reports whether a and b are paths of the same file. Paths that can't be read are
+// treated as the same, so neither is removed.
+func sameFile(a, b string) bool {
+	infoA, errA := os.Lstat(a)
+	infoB, errB := os.Lstat(b)
+	if errA != nil || errB != nil {
+		return true
+	}
+	return os.SameFile(infoA, infoB)
+}
+
+// recordRemoval adds a removed item to the manifest of the run. A manifest that can't be written
+// doesn't stop the cleanup, but is reported with the run's warnings.
+func recordRemoval(item cleanupItem, size int64, action string, location string) {
+	err := quarantine.Record(quarantine.Entry{
+		Path:     item.ActualPath,
+		Size:     size,
+		Category: item.Category,
+		Action:   action,
+		Location: location,
+	})
+	if err != nil {
+		logger.RunWarnings.Add(item.Category, "not recorded in manifest", item.ActualPath, err)
+	}
 }
@@ -0,0 +1,267 @@
+package cleaner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// CLEANER INTERFACE
+// ====================================================================================================
+
+// Item is something a Cleaner found to remove.
+type Item struct {
+	// Path is the absolute path of the file or directory.
+	Path string `json:"path"`
+	// Size is the disk usage of Path in bytes. wiper measures items of registered cleaners
+	// itself before removing them, so it may be left 0.
+	Size int64 `json:"size,omitempty"`
+	// Category groups the item in the summary tables (e.g., "Unity Caches"). Empty means the
+	// name of the cleaner.
+	Category string `json:"category,omitempty"`
+}
+
+// Cleaner finds items to remove. The system, large file and application cleanups are cleaners,
+// and further cleaners can be added with Register (compiled in) or RegisterExternal (executables
+// that print JSON items). The items of registered cleaners are cleaned with the system cleanup.
+type Cleaner interface {
+	// Name identifies the cleaner (e.g., "system"). Names are unique.
+	Name() string
+	// Description says what the cleaner removes, for `wiper cleaners`.
+	Description() string
+	// Scan returns the items to remove. It must not remove anything itself, and should stop
+	// when ctx is cancelled.
+	Scan(ctx context.Context) ([]Item, error)
+}
+
+// builtinCleaner is implemented by the built-in cleaners, whose items carry more than an Item
+// (e.g., how targets remove them), and have already been checked against the ignore list.
+type builtinCleaner interface {
+	Cleaner
+	scanItems(ctx context.Context) ([]cleanupItem, error)
+}
+
+// scanBuiltin implements Cleaner.Scan for a built-in cleaner.
+func scanBuiltin(ctx context.Context, c builtinCleaner) ([]Item, error) {
+	found, err := c.scanItems(ctx)
+	items := make([]Item, 0, len(found))
+	for _, item := range found {
+		items = append(items, Item{Path: item.ActualPath, Size: item.Size, Category: item.Category})
+	}
+	return items, err
+}
+
+// builtinCleanerNames are reserved for the built-in cleaners.
+var builtinCleanerNames = []string{"system", "large-files", "app"}
+
+// ====================================================================================================
+// REGISTRY
+// ====================================================================================================
+
+// cleanersMu guards registeredCleaners, the cleaners added with Register and RegisterExternal.
+var (
+	cleanersMu         sync.Mutex
+	registeredCleaners []Cleaner
+)
+
+// Register adds a cleaner whose items are cleaned with the system cleanup. It is meant to be
+// called from the init function of a cleaner compiled into wiper (e.g., in a file with a build
+// tag), and panics if the cleaner has no name or one that is already taken.
+func Register(c Cleaner) {
+	if err := register(c); err != nil {
+		panic(err)
+	}
+}
+
+// register adds c to the registry, or returns an error if its name is empty or taken.
+func register(c Cleaner) error {
+	name := c.Name()
+	if name == "" {
+		return errors.New("a cleaner needs a name")
+	}
+	cleanersMu.Lock()
+	defer cleanersMu.Unlock()
+	taken := slices.Contains(builtinCleanerNames, name)
+	for _, registered := range registeredCleaners {
+		taken = taken || registered.Name() == name
+	}
+	if taken {
+		return fmt.Errorf("a cleaner named %q is already registered", name)
+	}
+	registeredCleaners = append(registeredCleaners, c)
+	return nil
+}
+
+// Cleaners returns the registered cleaners, sorted by name.
+func Cleaners() []Cleaner {
+	cleanersMu.Lock()
+	defer cleanersMu.Unlock()
+	cleaners := append([]Cleaner(nil), registeredCleaners...)
+	sort.Slice(cleaners, func(i, j int) bool { return cleaners[i].Name() < cleaners[j].Name() })
+	return cleaners
+}
+
+// BuiltinCleaners returns the built-in cleaners with their default settings, for listing them.
+func BuiltinCleaners() []Cleaner {
+	return []Cleaner{
+		&systemCleaner{targets: getCleanupTargets()},
+		&largeFilesCleaner{},
+		&appCleaner{},
+	}
+}
+
+// scanRegistered collects the items of the registered cleaners as cleanupItems. Their items get
+// the same checks as the matches of cleanup targets (see scanPath): ignored, iCloud-synced and
+// tagged paths are skipped, and sizes are measured. A cleaner that fails is reported with the
+// run's warnings and left out.
+//
+// Returns:
+//   - The collected items, and ctx's error if the scan was cancelled.
+func scanRegistered(ctx context.Context, expandedIgnorePaths []string, skipped *reclaimer.SummaryTable) ([]cleanupItem, error) {
+	var itemsToProcess []cleanupItem
+	links := utils.NewLinkSet()
+	for _, c := range Cleaners() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		scanStart := time.Now()
+		logger.Log.Debugf("Scanning with the %s cleaner", c.Name())
+		items, err := c.Scan(ctx)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			logger.RunWarnings.Add(c.Name(), "cleaner failed", c.Name(), err)
+			continue
+		}
+
+		results := make([]scannedPath, len(items))
+		utils.ParallelFor(len(items), func(i int) {
+			if ctx.Err() != nil {
+				return
+			}
+			target := CleanupTarget{ID: "cleaner:" + c.Name(), Category: items[i].Category, CloudSensitive: true, RespectTags: true}
+			if target.Category == "" {
+				target.Category = c.Name()
+			}
+			if !filepath.IsAbs(items[i].Path) {
+				results[i] = scannedPath{reason: "invalid path", err: fmt.Errorf("%q is not an absolute path", items[i].Path)}
+				return
+			}
+			results[i] = scanPath(target, filepath.Clean(items[i].Path), expandedIgnorePaths, links)
+		})
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for i, result := range results {
+			if result.err != nil {
+				logger.RunWarnings.Add(c.Name(), result.reason, items[i].Path, result.err)
+			}
+			if result.reason != "" {
+				skipped.AddSkippedReason(items[i].Path, 0, c.Name(), result.reason)
+			}
+			if result.item != nil {
+				itemsToProcess = append(itemsToProcess, *result.item)
+			}
+		}
+		skipped.Timings.AddScan(c.Name(), time.Since(scanStart))
+	}
+	return itemsToProcess, nil
+}
+
+// ====================================================================================================
+// EXTERNAL CLEANERS
+// ====================================================================================================
+
+// externalCleaner is a cleaner implemented by a command that prints the items to remove as JSON.
+type externalCleaner struct {
+	name        string
+	description string
+	command     string
+}
+
+// RegisterExternal registers a cleaner that runs command with `sh -c` and reads the items to
+// remove from its standard output: JSON objects such as {"path": "/path", "category": "Unity
+// Caches"}, one after the other or in an array. It fails if the name is empty or taken.
+func RegisterExternal(name, description, command string) error {
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("the cleaner %q has no command", name)
+	}
+	if description == "" {
+		description = command
+	}
+	return register(&externalCleaner{name: name, description: description, command: command})
+}
+
+// Name returns the name of the cleaner.
+func (c *externalCleaner) Name() string { return c.name }
+
+// Description returns the description of the cleaner, or its command.
+func (c *externalCleaner) Description() string { return c.description }
+
+// Scan runs the command of the cleaner and parses the items it prints.
+func (c *externalCleaner) Scan(ctx context.Context) ([]Item, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.command)
+	cmd.Env = append(os.Environ(), "WIPER_CLEANER="+c.name)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if out := strings.TrimSpace(stderr.String()); out != "" {
+		logger.Log.Debugf("%s cleaner output: %s", c.name, out)
+		if err != nil {
+			return nil, fmt.Errorf("the %s cleaner failed: %w: %s", c.name, err, out)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("the %s cleaner failed: %w", c.name, err)
+	}
+	items, err := parseItems(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("the %s cleaner printed invalid items: %w", c.name, err)
+	}
+	return items, nil
+}
+
+// parseItems decodes a sequence of JSON items, or of arrays of items.
+func parseItems(data []byte) ([]Item, error) {
+	var items []Item
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err == io.EOF {
+			return items, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if value = bytes.TrimSpace(value); len(value) > 0 && value[0] == '[' {
+			var batch []Item
+			if err := json.Unmarshal(value, &batch); err != nil {
+				return nil, err
+			}
+			items = append(items, batch...)
+			continue
+		}
+		var item Item
+		if err := json.Unmarshal(value, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+}
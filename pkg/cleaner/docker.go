@@ -0,0 +1,356 @@
+package cleaner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/i18n"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/progress"
+	"github.com/kodelint/wiper/pkg/quarantine"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// DOCKER CLEANUP
+// ====================================================================================================
+
+// Catalog keys of the summary table categories of Docker objects (see i18n.T).
+const (
+	dockerContainersKey = "category.docker_containers"
+	dockerImagesKey     = "category.docker_images"
+	dockerBuildCacheKey = "category.docker_build_cache"
+	dockerVolumesKey    = "category.docker_volumes"
+)
+
+// dockerAnonymousVolumeLabel marks volumes Docker created for a container without a name.
+// Like `docker volume prune`, only unused anonymous volumes are removed; named volumes often hold data.
+const dockerAnonymousVolumeLabel = "com.docker.volume.anonymous"
+
+// dockerPrune describes the API call that prunes one kind of Docker object.
+type dockerPrune struct {
+	categoryKey string
+	path        string
+	query       url.Values
+}
+
+// dockerPrunes are run in this order: containers first, since they keep images and volumes in use.
+var dockerPrunes = []dockerPrune{
+	{categoryKey: dockerContainersKey, path: "/containers/prune"},
+	{categoryKey: dockerImagesKey, path: "/images/prune", query: url.Values{"filters": {`{"dangling":["true"]}`}}},
+	{categoryKey: dockerBuildCacheKey, path: "/build/prune"},
+	{categoryKey: dockerVolumesKey, path: "/volumes/prune"},
+}
+
+// dockerUsage is the subset of the `GET /system/df` response used to estimate what pruning frees.
+type dockerUsage struct {
+	Images []struct {
+		ID         string `json:"Id"`
+		RepoTags   []string
+		Size       int64
+		SharedSize int64
+		Containers int64
+	}
+	Containers []struct {
+		ID     string `json:"Id"`
+		Names  []string
+		State  string
+		SizeRw int64
+	}
+	Volumes []struct {
+		Name      string
+		Labels    map[string]string
+		UsageData *struct {
+			Size     int64
+			RefCount int64
+		}
+	}
+	BuildCache []struct {
+		ID    string
+		Size  int64
+		InUse bool
+	}
+}
+
+// dockerPruneReport is the union of the responses of the prune endpoints.
+type dockerPruneReport struct {
+	ContainersDeleted []string
+	ImagesDeleted     []struct{ Deleted string }
+	CachesDeleted     []string
+	VolumesDeleted    []string
+	SpaceReclaimed    int64
+}
+
+// removed returns the number of objects the prune removed.
+func (r dockerPruneReport) removed() int {
+	deletedImages := 0
+	for _, image := range r.ImagesDeleted {
+		if image.Deleted != "" {
+			deletedImages++
+		}
+	}
+	return len(r.ContainersDeleted) + deletedImages + len(r.CachesDeleted) + len(r.VolumesDeleted)
+}
+
+// CleanDocker prunes stopped containers, dangling images, unused build cache and unused anonymous
+// volumes through the API of the running Docker daemon, after a single confirmation. The estimate
+// comes from Docker's own disk usage report; the reclaimed space is what Docker reports freeing.
+// The size of the Docker Desktop disk image is reported as well, since that is where the space lives.
+//
+// Parameters:
+//   - ctx: Cancels the requests, or the cleanup between kinds of objects.
+//   - dryRun: If true, only the estimate is shown.
+//   - summary: A pointer to a SummaryTable to record pruned objects.
+//   - estimatedSummary: A pointer to a SummaryTable to record the estimate.
+//
+// Returns:
+//   - The space reclaimed (or estimated, in dry-run mode) in bytes and an error, if any.
+func CleanDocker(ctx context.Context, dryRun bool, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable) (int64, error) {
+	client, err := newDockerClient()
+	if err != nil {
+		return 0, err
+	}
+
+	scanStart := time.Now()
+	scanProgress := progress.New("Asking Docker for its disk usage", 0)
+	scanProgress.Start()
+	var usage dockerUsage
+	err = client.call(ctx, http.MethodGet, "/system/df", nil, &usage)
+	scanProgress.Stop()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read Docker's disk usage: %w", err)
+	}
+	estimatedSummary.Timings.AddScan("Docker", time.Since(scanStart))
+
+	estimates := usage.estimate(estimatedSummary)
+	if dryRun || !logger.Quiet() {
+		estimatedSummary.PrintTable(true, i18n.T("summary.estimated_title"))
+	}
+	var estimated int64
+	for _, size := range estimates {
+		estimated += size
+	}
+	if len(estimates) == 0 {
+		logger.Log.Info("Docker has nothing to prune.")
+		reportDockerDiskImage()
+		return 0, nil
+	}
+	if dryRun {
+		history.Add(len(estimates), estimated, 0)
+		reportDockerDiskImage()
+		return estimated, nil
+	}
+
+	println()
+	if !ConfirmAction(ctx, i18n.T("prompt.cleanup_all", reclaimer.FormatBytes(estimated))) {
+		if ctx.Err() == nil {
+			logger.Log.Info("Cleanup cancelled by user.")
+		}
+		return 0, ctx.Err()
+	}
+
+	var reclaimed int64
+	pruned, failed := 0, 0
+	defer func() { history.Add(pruned, reclaimed, failed) }()
+	for _, prune := range dockerPrunes {
+		category := i18n.T(prune.categoryKey)
+		if _, ok := estimates[category]; !ok {
+			continue
+		}
+		path := "docker:" + strings.TrimSuffix(prune.path, "/prune")
+		if ctx.Err() != nil {
+			summary.AddSkippedReason(path, estimates[category], category, reclaimer.SkipReasonCancelled)
+			continue
+		}
+		if !allowRemoval(path, estimates[category], category, summary) {
+			continue
+		}
+		start := time.Now()
+		var report dockerPruneReport
+		err := client.call(ctx, http.MethodPost, prune.path, prune.query, &report)
+		summary.Timings.AddDelete(category, time.Since(start))
+		if err != nil {
+			logger.Log.With("category", category, "path", path).Errorf("Failed to prune %s: %v", path, err)
+			summary.AddFailed(path, estimates[category], category, err)
+			failed++
+			continue
+		}
+		logger.Log.With("category", category, "path", path, "size", report.SpaceReclaimed).Infof("Pruned %d objects from %s (%s)", report.removed(), path, reclaimer.FormatBytes(report.SpaceReclaimed))
+		summary.AddRemoved(path, report.SpaceReclaimed, category)
+		recordRemoval(cleanupItem{ActualPath: path, Category: category}, report.SpaceReclaimed, quarantine.ActionDeleted, "")
+		reclaimed += report.SpaceReclaimed
+		pruned++
+	}
+	reportDockerDiskImage()
+	return reclaimed, ctx.Err()
+}
+
+// estimate records every object a prune would remove in estimatedSummary and returns the estimated
+// size per category. Categories without prunable objects are left out.
+func (u dockerUsage) estimate(estimatedSummary *reclaimer.SummaryTable) map[string]int64 {
+	estimates := make(map[string]int64)
+	add := func(path string, size int64, category string) {
+		estimatedSummary.AddEstimated(path, size, category)
+		estimates[category] += size
+	}
+
+	// `docker container prune` removes every container that isn't running or paused.
+	for _, container := range u.Containers {
+		if container.State == "running" || container.State == "paused" || container.State == "restarting" {
+			continue
+		}
+		name := shortDockerID(container.ID)
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+		add("docker:/containers/"+name, container.SizeRw, i18n.T(dockerContainersKey))
+	}
+	for _, image := range u.Images {
+		if image.Containers > 0 || !isDanglingImage(image.RepoTags) {
+			continue
+		}
+		// Layers shared with other images stay on disk.
+		size := image.Size
+		if image.SharedSize > 0 {
+			size -= image.SharedSize
+		}
+		add("docker:/images/"+shortDockerID(image.ID), size, i18n.T(dockerImagesKey))
+	}
+	for _, record := range u.BuildCache {
+		if !record.InUse {
+			add("docker:/build/"+shortDockerID(record.ID), record.Size, i18n.T(dockerBuildCacheKey))
+		}
+	}
+	for _, volume := range u.Volumes {
+		if _, anonymous := volume.Labels[dockerAnonymousVolumeLabel]; !anonymous || volume.UsageData == nil || volume.UsageData.RefCount != 0 {
+			continue
+		}
+		// Docker reports -1 for sizes it hasn't calculated.
+		add("docker:/volumes/"+shortDockerID(volume.Name), max(volume.UsageData.Size, 0), i18n.T(dockerVolumesKey))
+	}
+	return estimates
+}
+
+// isDanglingImage reports whether an image has no tags, which makes it a dangling image.
+func isDanglingImage(repoTags []string) bool {
+	for _, tag := range repoTags {
+		if tag != "<none>:<none>" {
+			return false
+		}
+	}
+	return true
+}
+
+// shortDockerID shortens a Docker object ID the way the docker CLI prints it.
+func shortDockerID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// reportDockerDiskImage logs the size of the Docker Desktop disk image. Docker Desktop keeps images,
+// containers and volumes inside it, and returns freed space to the host in the background.
+func reportDockerDiskImage() {
+	homeDir := utils.ExpandPath("~")
+	for _, path := range []string{
+		filepath.Join(homeDir, "Library", "Containers", "com.docker.docker", "Data", "vms", "0", "data", "Docker.raw"),
+		filepath.Join(homeDir, ".docker", "desktop", "vms", "0", "data", "Docker.raw"),
+	} {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		logger.Log.Infof("Docker Desktop disk image %s uses %s (maximum %s). Space freed inside it is returned to the disk in the background.",
+			path, utils.Yellow(reclaimer.FormatBytes(utils.FileInfoDiskUsage(info))), reclaimer.FormatBytes(info.Size()))
+	}
+}
+
+// ====================================================================================================
+// DOCKER API CLIENT
+// ====================================================================================================
+
+// dockerClient calls the Docker Engine API over the daemon's Unix socket.
+type dockerClient struct {
+	socket string
+	http   *http.Client
+}
+
+// newDockerClient connects to the socket named by DOCKER_HOST, or to the first default socket
+// that exists: Docker Desktop's per-user socket, rootless Docker's, then the system daemon's.
+func newDockerClient() (*dockerClient, error) {
+	var socket string
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		path, ok := strings.CutPrefix(host, "unix://")
+		if !ok {
+			return nil, fmt.Errorf("DOCKER_HOST %s is not supported; only unix:// sockets are", host)
+		}
+		socket = path
+	} else {
+		candidates := []string{utils.ExpandPath("~/.docker/run/docker.sock")}
+		if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+			candidates = append(candidates, filepath.Join(runtimeDir, "docker.sock"))
+		}
+		candidates = append(candidates, "/var/run/docker.sock")
+		for _, candidate := range candidates {
+			if info, err := os.Stat(candidate); err == nil && info.Mode()&os.ModeSocket != 0 {
+				socket = candidate
+				break
+			}
+		}
+		if socket == "" {
+			return nil, fmt.Errorf("Docker is not running (no socket found at %s)", strings.Join(candidates, ", "))
+		}
+	}
+
+	logger.Log.Debugf("Using the Docker socket %s", socket)
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	return &dockerClient{
+		socket: socket,
+		http: &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}},
+	}, nil
+}
+
+// call sends a request to the API and decodes the JSON response into out.
+// Unversioned paths use the API version of the daemon.
+func (c *dockerClient) call(ctx context.Context, method, path string, query url.Values, out any) error {
+	endpoint := url.URL{Scheme: "http", Host: "docker", Path: path, RawQuery: query.Encode()}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Docker at %s: %w", c.socket, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// Errors carry a JSON body with a message, e.g. when a prune is already running.
+		var apiErr struct{ Message string }
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("%s %s: %s", method, path, apiErr.Message)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response to %s %s: %w", method, path, err)
+	}
+	return nil
+}
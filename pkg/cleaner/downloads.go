@@ -0,0 +1,72 @@
+package cleaner
+
+import (
+	"path/filepath" // Imported for filepath.Join
+	"strings"       // Imported for normalizing extensions
+	"time"          // Imported for time.Duration
+
+	"github.com/kodelint/wiper/pkg/i18n" // Imported for localized category names
+)
+
+// ====================================================================================================
+// DOWNLOADS POLICY
+// ====================================================================================================
+
+// DefaultDownloadsMinAge is the age after which downloads are considered old unless configured otherwise.
+const DefaultDownloadsMinAge = 90 * 24 * time.Hour
+
+// DownloadsPolicy controls which files the "Downloads (old)" target picks up and what happens to them.
+// The zero value cleans everything older than DefaultDownloadsMinAge.
+type DownloadsPolicy struct {
+	// MinAge is how long a download must go unmodified before it is cleaned. 0 means DefaultDownloadsMinAge.
+	MinAge time.Duration
+	// Extensions limits the target to files with these extensions (e.g., ".dmg", ".zip"), compared
+	// case-insensitively. Directories are left alone when it is set. Empty means all types.
+	Extensions []string
+	// MinSize skips downloads smaller than this many bytes. 0 means no minimum.
+	MinSize int64
+	// ArchiveDir moves old downloads into this directory (e.g., on an external volume)
+	// instead of deleting them. Empty means delete.
+	ArchiveDir string
+}
+
+// downloadsPolicy is the policy applied to the Downloads target in this run.
+var downloadsPolicy DownloadsPolicy
+
+// SetDownloadsPolicy sets the policy applied to the Downloads target.
+func SetDownloadsPolicy(policy DownloadsPolicy) {
+	for i, ext := range policy.Extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext != "" && !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		policy.Extensions[i] = ext
+	}
+	downloadsPolicy = policy
+}
+
+// oldDownloadsTarget returns the "old_downloads" target for the given Downloads directory,
+// configured according to the current DownloadsPolicy.
+func oldDownloadsTarget(downloadsDir string) CleanupTarget {
+	minAge := downloadsPolicy.MinAge
+	if minAge <= 0 {
+		minAge = DefaultDownloadsMinAge
+	}
+	target := CleanupTarget{
+		ID:                  "old_downloads",
+		Paths:               []string{filepath.Join(downloadsDir, "*")},
+		Category:            i18n.T("category.old_downloads"),
+		MinAge:              minAge,
+		LogAggregationRoots: []string{downloadsDir},
+		Extensions:          downloadsPolicy.Extensions,
+		MinSize:             downloadsPolicy.MinSize,
+		ArchiveDir:          downloadsPolicy.ArchiveDir,
+		CloudSensitive:      true,
+		RespectTags:         true,
+	}
+	// An archive inside the Downloads folder must not be archived into itself on the next run.
+	if downloadsPolicy.ArchiveDir != "" {
+		target.Exclude = []string{filepath.Clean(downloadsPolicy.ArchiveDir)}
+	}
+	return target
+}
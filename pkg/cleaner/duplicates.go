@@ -0,0 +1,338 @@
+package cleaner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/progress"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// DUPLICATE FILE FINDER
+// ====================================================================================================
+
+// duplicatesCategory is the category of removed duplicates in the summary tables.
+const duplicatesCategory = "Duplicate Files"
+
+// DefaultDuplicateMinSize is the size below which files aren't compared (1 MiB): small duplicates
+// free little space and there are usually many of them.
+const DefaultDuplicateMinSize = 1024 * 1024
+
+// duplicatePrefixSize is how much of each file is hashed first, so files of the same size that
+// differ early on aren't read completely.
+const duplicatePrefixSize = 64 * 1024
+
+// Keep rules of DuplicateOptions: which file of a group of duplicates is kept.
+const (
+	KeepNewest = "newest"
+	KeepOldest = "oldest"
+)
+
+// DuplicateOptions configures a duplicate scan.
+type DuplicateOptions struct {
+	// Roots are the directories to scan.
+	Roots []string
+	// IncludeVolumes also scans the external volumes (see ExternalVolumes).
+	IncludeVolumes bool
+	// MinSize is the minimum size in bytes of the files compared. 0 means DefaultDuplicateMinSize.
+	MinSize int64
+	// Keep selects the file of each group that is kept: KeepNewest (the default) or KeepOldest,
+	// by modification time.
+	Keep string
+}
+
+// DuplicateFile is one copy in a group of duplicates.
+type DuplicateFile struct {
+	Path    string
+	ModTime time.Time
+	// Usage is the disk usage of the file; blocks shared with clones are not freed by removing it.
+	Usage utils.Usage
+
+	info os.FileInfo
+}
+
+// DuplicateGroup is a set of files with the same content.
+type DuplicateGroup struct {
+	// Size is the logical size of each file.
+	Size int64
+	// Hash is the SHA-256 of the contents, hex-encoded.
+	Hash string
+	// Files are the copies, the one that is kept first (see DuplicateOptions.Keep).
+	Files []DuplicateFile
+}
+
+// Reclaimable returns the bytes removing every copy but the kept one frees.
+func (g DuplicateGroup) Reclaimable() int64 {
+	var total int64
+	for _, file := range g.Files[1:] {
+		total += file.Usage.Private()
+	}
+	return total
+}
+
+// ValidateKeepRule returns an error if keep is not a known keep rule ("" selects KeepNewest).
+func ValidateKeepRule(keep string) error {
+	switch keep {
+	case "", KeepNewest, KeepOldest:
+		return nil
+	default:
+		return fmt.Errorf("unknown keep rule %q (available: %s, %s)", keep, KeepNewest, KeepOldest)
+	}
+}
+
+// FindDuplicates scans the roots for regular files with the same content: files are grouped by
+// size first, then by the hash of their first 64 KiB, and only then by the hash of their whole
+// contents, so most files are never read. Hard links to the same file are not duplicates of each
+// other (removing one frees nothing) and are reported once.
+//
+// Parameters:
+//   - ctx: Stops the scan when cancelled.
+//   - ignorePaths: Paths to leave out of the scan (see --ignore).
+//   - opts: Roots, minimum size and keep rule; see DuplicateOptions.
+//
+// Returns:
+//   - The groups of duplicates, the largest reclaimable space first, and ctx's error if the scan
+//     was cancelled.
+func FindDuplicates(ctx context.Context, ignorePaths []string, opts DuplicateOptions) ([]DuplicateGroup, error) {
+	if err := ValidateKeepRule(opts.Keep); err != nil {
+		return nil, err
+	}
+	minSize := opts.MinSize
+	if minSize <= 0 {
+		minSize = DefaultDuplicateMinSize
+	}
+	var cleanedIgnorePaths []string
+	for _, p := range ignorePaths {
+		absPath, err := utils.AbsIgnorePath(p)
+		if err != nil {
+			logger.Log.Warnf("Failed to resolve absolute path for ignore entry %s: %v", p, err)
+			continue
+		}
+		cleanedIgnorePaths = append(cleanedIgnorePaths, absPath)
+	}
+
+	roots := opts.Roots
+	if opts.IncludeVolumes {
+		roots = append(append([]string(nil), roots...), ExternalVolumes()...)
+	}
+
+	// Step 1: Group the files of every root by size. Each file (not each link to it) is seen once.
+	scanProgress := progress.New("Scanning for duplicates", 0)
+	scanProgress.SetUnit("files")
+	scanProgress.Start()
+	bySize := make(map[int64][]DuplicateFile)
+	seenPaths := make(map[string]bool)
+	seenFiles := make(map[utils.FileIdentity]bool)
+	for _, root := range roots {
+		err := utils.WalkParallel(root, func(path string, info os.FileInfo, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			scanProgress.Add(1)
+			if err != nil {
+				logger.RunWarnings.Add(duplicatesCategory, reclaimer.SkipReasonForError(err), path, err)
+				return nil
+			}
+			if utils.IsPathIgnored(path, cleanedIgnorePaths) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.IsDir() {
+				scanProgress.SetDetail(path)
+				return nil
+			}
+			if !info.Mode().IsRegular() || info.Size() < minSize || seenPaths[path] {
+				return nil
+			}
+			// Nested roots visit paths twice, and hard links, firmlinks (/System/Volumes/Data/Users
+			// next to /Users) and bind mounts are several paths of one file, even with a single link.
+			seenPaths[path] = true
+			if id, ok := utils.FileInfoIdentity(info); ok {
+				id.Links = 0 // Only the device and inode identify the file.
+				if seenFiles[id] {
+					return nil
+				}
+				seenFiles[id] = true
+			}
+			bySize[info.Size()] = append(bySize[info.Size()], DuplicateFile{Path: path, ModTime: info.ModTime(), info: info})
+			return nil
+		})
+		if err != nil {
+			scanProgress.Stop()
+			return nil, err
+		}
+	}
+	scanProgress.Stop()
+
+	// Step 2: Narrow the groups down by the hash of the first bytes, then of the whole contents.
+	var groups []DuplicateGroup
+	for size, files := range bySize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if len(files) < 2 {
+			continue
+		}
+		for prefixHash, candidates := range groupByHash(files, duplicatePrefixSize) {
+			if size <= duplicatePrefixSize {
+				// The prefix was the whole file.
+				groups = append(groups, newDuplicateGroup(size, prefixHash, candidates, opts.Keep))
+				continue
+			}
+			for hash, same := range groupByHash(candidates, -1) {
+				groups = append(groups, newDuplicateGroup(size, hash, same, opts.Keep))
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if a, b := groups[i].Reclaimable(), groups[j].Reclaimable(); a != b {
+			return a > b
+		}
+		return groups[i].Files[0].Path < groups[j].Files[0].Path
+	})
+	return groups, nil
+}
+
+// groupByHash hashes the first limit bytes of every file (all of it for a negative limit)
+// concurrently, and returns the groups of at least two files with the same hash. Files that
+// can't be read are reported with the run's warnings and left out.
+func groupByHash(files []DuplicateFile, limit int64) map[string][]DuplicateFile {
+	hashes := make([]string, len(files))
+	utils.ParallelFor(len(files), func(i int) {
+		hashes[i] = hashOf(files[i].Path, limit)
+	})
+	byHash := make(map[string][]DuplicateFile)
+	for i, hash := range hashes {
+		if hash != "" {
+			byHash[hash] = append(byHash[hash], files[i])
+		}
+	}
+	for hash, same := range byHash {
+		if len(same) < 2 {
+			delete(byHash, hash)
+		}
+	}
+	return byHash
+}
+
+// hashOf returns the hex-encoded SHA-256 of the first limit bytes of the file at path (all of it
+// for a negative limit), or "" if it can't be read.
+func hashOf(path string, limit int64) string {
+	file, err := os.Open(path)
+	if err != nil {
+		logger.RunWarnings.Add(duplicatesCategory, reclaimer.SkipReasonForError(err), path, err)
+		return ""
+	}
+	defer file.Close()
+	var reader io.Reader = file
+	if limit >= 0 {
+		reader = io.LimitReader(file, limit)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		logger.RunWarnings.Add(duplicatesCategory, reclaimer.SkipReasonForError(err), path, err)
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// newDuplicateGroup builds a group from files with the same content, measuring their disk usage
+// and ordering them by the keep rule: the kept file first, the others by path.
+func newDuplicateGroup(size int64, hash string, files []DuplicateFile, keep string) DuplicateGroup {
+	files = append([]DuplicateFile(nil), files...)
+	for i := range files {
+		files[i].Usage = utils.FileUsage(files[i].Path, files[i].info)
+	}
+	// Files modified at the same time are ordered by path, so the kept one doesn't change between runs.
+	sort.Slice(files, func(i, j int) bool {
+		a, b := files[i].ModTime, files[j].ModTime
+		if !a.Equal(b) {
+			if keep == KeepOldest {
+				return a.Before(b)
+			}
+			return a.After(b)
+		}
+		return files[i].Path < files[j].Path
+	})
+	rest := files[1:]
+	sort.Slice(rest, func(i, j int) bool { return rest[i].Path < rest[j].Path })
+	return DuplicateGroup{Size: size, Hash: hash, Files: files}
+}
+
+// CleanDuplicates removes every copy but the kept one of each group, through the same
+// confirmation, safety checks and summaries as the other cleanups. The copies that would be
+// removed are checked like large files: protected, iCloud-synced and tagged files are skipped.
+//
+// Parameters:
+//   - ctx: Cancels the cleanup between items (see processCleanupItems).
+//   - groups: The duplicates to clean, usually from FindDuplicates.
+//   - dryRun: If true, the copies are only recorded as estimated.
+//   - interactive: If true, every copy is confirmed on its own; the kept file is never offered.
+//   - summary: A pointer to a SummaryTable to record removed copies.
+//   - estimatedSummary: A pointer to a SummaryTable to record estimates and skipped copies.
+//
+// Returns:
+//   - The total space reclaimed in bytes and an error, if any.
+func CleanDuplicates(ctx context.Context, groups []DuplicateGroup, dryRun bool, interactive bool, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable) (int64, error) {
+	var items []cleanupItem
+	for _, group := range groups {
+		// The copies are only duplicates while the kept one is what was hashed.
+		kept := group.Files[0]
+		keptChanged := utils.VerifyScanned(kept.Path, utils.FingerprintOf(kept.info)) != nil
+		if keptChanged {
+			logger.Log.Infof("Keeping the copies of %s: it changed since it was scanned", kept.Path)
+		}
+		for _, file := range group.Files[1:] {
+			reason := ""
+			switch {
+			case keptChanged:
+				reason = reclaimer.SkipReasonChanged
+			case utils.IsProtectedPath(file.Path):
+				reason = reclaimer.SkipReasonProtected
+			case blockedByCloudSync(file.Path, false):
+				reason = reclaimer.SkipReasonCloudSynced
+			case isTagProtected(file.Path):
+				reason = reclaimer.SkipReasonTagged
+			}
+			if reason != "" {
+				estimatedSummary.AddSkippedReason(file.Path, file.Usage.Private(), duplicatesCategory, reason)
+				continue
+			}
+			items = append(items, cleanupItem{
+				Path:        file.Path,
+				Size:        file.Usage.Private(),
+				Cloned:      file.Usage.Cloned,
+				Category:    duplicatesCategory,
+				ActualPath:  file.Path,
+				Scanned:     utils.FingerprintOf(file.info),
+				Kept:        kept.Path,
+				KeptScanned: utils.FingerprintOf(kept.info),
+			})
+		}
+	}
+
+	mode := confirmOnce
+	if interactive {
+		mode = confirmEachItem
+	}
+	reclaimed, err := processCleanupItems(ctx, items, dryRun, mode, summary, estimatedSummary, "Detected Duplicates")
+	if err != nil {
+		return reclaimed, fmt.Errorf("failed to process duplicates cleanup: %w", err)
+	}
+	return reclaimed, nil
+}
@@ -0,0 +1,191 @@
+package cleaner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/reclaimer"
+)
+
+// duplicateTestSize is larger than duplicatePrefixSize, so candidates are hashed in full as well.
+const duplicateTestSize = 2 * duplicatePrefixSize
+
+// writeDuplicateTestFile creates the file name in dir with content, modified at modTime.
+func writeDuplicateTestFile(t *testing.T, dir string, name string, content []byte, modTime time.Time) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// duplicateTestContent returns size bytes of content derived from seed.
+func duplicateTestContent(seed byte, size int) []byte {
+	return bytes.Repeat([]byte{seed, seed + 1, seed + 2}, size/3+1)[:size]
+}
+
+// duplicateTestTree creates files of the same size with the same content, the same first 64 KiB
+// only, or different contents, plus small duplicates, and returns the paths of the copies.
+func duplicateTestTree(t *testing.T, dir string) (older, newer string) {
+	t.Helper()
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	content := duplicateTestContent('a', duplicateTestSize)
+	older = writeDuplicateTestFile(t, dir, "photos/older.jpg", content, base)
+	newer = writeDuplicateTestFile(t, dir, "backup/newer.jpg", content, base.Add(time.Hour))
+
+	// The same size and first 64 KiB, but a different ending: only the full hash tells them apart.
+	samePrefix := append([]byte(nil), content...)
+	samePrefix[len(samePrefix)-1]++
+	writeDuplicateTestFile(t, dir, "photos/edited.jpg", samePrefix, base)
+	// The same size with different contents from the start.
+	writeDuplicateTestFile(t, dir, "photos/other.jpg", duplicateTestContent('x', duplicateTestSize), base)
+	// Duplicates below the minimum size.
+	writeDuplicateTestFile(t, dir, "small/a.txt", []byte("small"), base)
+	writeDuplicateTestFile(t, dir, "small/b.txt", []byte("small"), base)
+	return older, newer
+}
+
+// duplicateGroupPaths returns the paths of the files of group, the kept one first.
+func duplicateGroupPaths(group DuplicateGroup) []string {
+	var paths []string
+	for _, file := range group.Files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+func TestFindDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		keep     string
+		wantKept func(older, newer string) string
+	}{
+		{"keep newest by default", "", func(_, newer string) string { return newer }},
+		{"keep newest", KeepNewest, func(_, newer string) string { return newer }},
+		{"keep oldest", KeepOldest, func(older, _ string) string { return older }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			older, newer := duplicateTestTree(t, dir)
+
+			groups, err := FindDuplicates(context.Background(), nil, DuplicateOptions{Roots: []string{dir}, MinSize: 1024, Keep: tt.keep})
+			if err != nil {
+				t.Fatalf("FindDuplicates() error = %v", err)
+			}
+			if len(groups) != 1 {
+				t.Fatalf("FindDuplicates() found %d groups, want 1: %v", len(groups), groups)
+			}
+			group := groups[0]
+			if group.Size != duplicateTestSize || len(group.Files) != 2 {
+				t.Fatalf("FindDuplicates() group = %v, want 2 files of %d bytes", duplicateGroupPaths(group), duplicateTestSize)
+			}
+			wantKept := tt.wantKept(older, newer)
+			if group.Files[0].Path != wantKept {
+				t.Errorf("FindDuplicates() kept %s, want %s", group.Files[0].Path, wantKept)
+			}
+		})
+	}
+}
+
+func TestFindDuplicatesGroupsSmallFilesByPrefix(t *testing.T) {
+	dir := t.TempDir()
+	duplicateTestTree(t, dir)
+
+	// Files up to 64 KiB are grouped by the hash of their first bytes alone, which is all of them.
+	groups, err := FindDuplicates(context.Background(), nil, DuplicateOptions{Roots: []string{dir}, MinSize: 1})
+	if err != nil {
+		t.Fatalf("FindDuplicates() error = %v", err)
+	}
+	var small []string
+	for _, group := range groups {
+		if group.Size == int64(len("small")) {
+			small = duplicateGroupPaths(group)
+		}
+	}
+	sort.Strings(small)
+	want := []string{filepath.Join(dir, "small/a.txt"), filepath.Join(dir, "small/b.txt")}
+	if len(groups) != 2 || len(small) != 2 || small[0] != want[0] || small[1] != want[1] {
+		t.Errorf("FindDuplicates() found %d groups with small files %v, want 2 groups with %v", len(groups), small, want)
+	}
+}
+
+func TestFindDuplicatesReportsHardLinksOnce(t *testing.T) {
+	dir := t.TempDir()
+	older, _ := duplicateTestTree(t, dir)
+	if err := os.Link(older, filepath.Join(dir, "photos/link.jpg")); err != nil {
+		t.Skipf("hard links are not available: %v", err)
+	}
+
+	groups, err := FindDuplicates(context.Background(), nil, DuplicateOptions{Roots: []string{dir}, MinSize: 1024})
+	if err != nil {
+		t.Fatalf("FindDuplicates() error = %v", err)
+	}
+	// One name of the hard-linked file and its copy: removing the other name would free nothing.
+	if len(groups) != 1 || len(groups[0].Files) != 2 {
+		t.Fatalf("FindDuplicates() = %v, want one group of 2 files", groups)
+	}
+}
+
+func TestCleanDuplicatesDryRun(t *testing.T) {
+	tests := []struct {
+		name string
+		// change modifies the kept copy after the scan.
+		change     func(t *testing.T, kept string)
+		wantStatus reclaimer.EntryStatus
+		wantReason string
+	}{
+		{name: "unchanged", wantStatus: reclaimer.StatusDryRun},
+		{name: "kept copy changed", change: func(t *testing.T, kept string) {
+			if err := os.WriteFile(kept, []byte("rewritten"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}, wantStatus: reclaimer.StatusSkipped, wantReason: reclaimer.SkipReasonChanged},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			older, newer := duplicateTestTree(t, dir)
+			groups, err := FindDuplicates(context.Background(), nil, DuplicateOptions{Roots: []string{dir}, MinSize: 1024})
+			if err != nil || len(groups) != 1 {
+				t.Fatalf("FindDuplicates() = %v, %v, want one group", groups, err)
+			}
+			if tt.change != nil {
+				tt.change(t, newer)
+			}
+
+			summary := reclaimer.NewSummaryTable()
+			estimatedSummary := reclaimer.NewSummaryTable()
+			if _, err := CleanDuplicates(context.Background(), groups, true, false, summary, estimatedSummary); err != nil {
+				t.Fatalf("CleanDuplicates() error = %v", err)
+			}
+			entries := estimatedSummary.All()
+			if len(entries) != 1 {
+				t.Fatalf("CleanDuplicates() recorded %v, want one entry", entries)
+			}
+			if got := entries[0]; got.Path != older || got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("CleanDuplicates() recorded %s as %v (%q), want %s as %v (%q)", got.Path, got.Status, got.Reason, older, tt.wantStatus, tt.wantReason)
+			}
+			if summary.Len() != 0 {
+				t.Errorf("CleanDuplicates() dry run recorded removals: %v", summary.All())
+			}
+			for _, path := range []string{older, newer} {
+				if _, err := os.Stat(path); err != nil {
+					t.Errorf("CleanDuplicates() dry run removed %s: %v", path, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cleaner
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ====================================================================================================
+// FILE TYPE GROUPS
+// ====================================================================================================
+
+// fileTypes maps the names accepted by --type to the lower-case extensions of the group. The
+// groups follow the uniform type identifiers the extensions conform to (public.movie, public.audio,
+// public.image, public.archive, public.disk-image), so they match what Finder calls a movie or an archive.
+var fileTypes = map[string][]string{
+	"video":     {".mp4", ".m4v", ".mov", ".avi", ".mkv", ".wmv", ".flv", ".webm", ".mpg", ".mpeg", ".mts", ".m2ts", ".3gp"},
+	"audio":     {".mp3", ".m4a", ".aac", ".wav", ".aif", ".aiff", ".flac", ".ogg", ".opus", ".caf"},
+	"image":     {".jpg", ".jpeg", ".png", ".gif", ".heic", ".heif", ".tif", ".tiff", ".bmp", ".psd", ".cr2", ".cr3", ".nef", ".arw", ".dng", ".webp"},
+	"archive":   {".zip", ".tar", ".gz", ".tgz", ".bz2", ".tbz", ".xz", ".txz", ".zst", ".7z", ".rar", ".xip"},
+	"diskimage": {".dmg", ".iso", ".img", ".sparseimage", ".vmdk", ".vdi", ".qcow2", ".raw"},
+	"installer": {".pkg", ".mpkg", ".dmg", ".exe", ".msi", ".deb", ".rpm"},
+}
+
+// FileTypes returns the names of the file type groups in sorted order.
+func FileTypes() []string {
+	names := make([]string, 0, len(fileTypes))
+	for name := range fileTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FileTypeExtensions returns the extensions of the given file type groups, or an error naming the
+// first unknown group. Names are case-insensitive.
+func FileTypeExtensions(names []string) ([]string, error) {
+	var extensions []string
+	for _, name := range names {
+		group, ok := fileTypes[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown file type %q (available: %s)", name, strings.Join(FileTypes(), ", "))
+		}
+		extensions = append(extensions, group...)
+	}
+	return extensions, nil
+}
+
+// hasExtension reports whether path has one of the lower-case extensions. An empty list matches everything.
+func hasExtension(path string, extensions []string) bool {
+	if len(extensions) == 0 {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, want := range extensions {
+		if ext == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// FREE SPACE GOAL
+// ====================================================================================================
+
+// GoalOptions configures a goal-driven cleanup (`wipe --free`).
+type GoalOptions struct {
+	// Goal is the number of bytes to free.
+	Goal int64
+	// System configures the system cleanup targets; see SystemOptions.
+	System SystemOptions
+	// LargeFiles configures the large file scan used as the last resort; see LargeFileOptions.
+	LargeFiles LargeFileOptions
+}
+
+// Tiers of the cleanup plan, safest first. Items of a lower tier are always taken before
+// anything of a higher tier.
+const (
+	tierCaches = iota
+	tierTemporary
+	tierOther
+	tierTrash
+	tierDownloads
+	tierLargeFiles
+)
+
+// goalTier returns the tier of a cleanup item, based on the target that found it.
+func goalTier(item cleanupItem) int {
+	switch id := item.TargetID; {
+	case id == "":
+		return tierLargeFiles
+	case id == "old_downloads":
+		return tierDownloads
+	case id == "trash" || id == "volume_trash":
+		return tierTrash
+	case id == "photos_caches":
+		return tierOther // Rebuilding the previews and the analysis of a library takes hours
+
+	case strings.Contains(id, "cache") || id == "thumbnails" || id == "xcode_derived_data":
+		return tierCaches
+	case strings.Contains(id, "temp") || strings.Contains(id, "log") || id == "journal_archives" || id == "mail_downloads":
+		return tierTemporary
+	default:
+		return tierOther
+	}
+}
+
+// CleanToGoal frees at least opts.Goal bytes with as little impact as possible. It plans greedily
+// from the safest categories first (caches, temporary files, Trash, old downloads, then large files),
+// taking the largest items of each first, and stops as soon as the goal is met. Large files are only
+// scanned when the system cleanup can't reach the goal on its own.
+//
+// Parameters:
+//   - ctx: Cancels the scans, or the cleanup between items (see processCleanupItems).
+//   - dryRun: A boolean flag for dry-run mode (the plan is only shown).
+//   - ignorePaths: A list of paths to explicitly exclude from deletion.
+//   - summary: A pointer to a SummaryTable to record deleted items and their sizes.
+//   - estimatedSummary: A pointer to a SummaryTable to record the planned items.
+//   - opts: The goal and the settings of the underlying cleanups; see GoalOptions.
+//
+// Returns:
+//   - The total space reclaimed in bytes and an error, if any.
+func CleanToGoal(ctx context.Context, dryRun bool, ignorePaths []string, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable, opts GoalOptions) (int64, error) {
+	if opts.Goal <= 0 {
+		return 0, fmt.Errorf("the free space goal must be positive")
+	}
+	logger.Log.Infof("Planning a cleanup to free %s...", reclaimer.FormatBytes(opts.Goal))
+
+	// Scans only record skipped paths, so items left out of the plan don't appear in the estimate.
+	items, err := scanTargets(ctx, systemTargets(opts.System), expandIgnorePaths(ignorePaths), estimatedSummary)
+	if err != nil {
+		return 0, err
+	}
+	if total := totalItemSize(items); total < opts.Goal {
+		logger.Log.Infof("System cleanup frees %s; scanning for large files to reach the goal", reclaimer.FormatBytes(total))
+		// Large files inside items of the system cleanup (e.g., a huge cache file) are already planned.
+		systemPaths := make([]string, 0, len(items))
+		for _, item := range items {
+			systemPaths = append(systemPaths, item.ActualPath)
+		}
+		largeFiles, err := (&largeFilesCleaner{ignorePaths: ignorePaths, skipped: estimatedSummary, opts: opts.LargeFiles}).scanItems(ctx)
+		if err != nil {
+			return 0, err
+		}
+		for _, item := range largeFiles {
+			if !utils.ContainsPath(item.ActualPath, systemPaths) {
+				items = append(items, item)
+			}
+		}
+	}
+
+	plan, planned := planForGoal(items, opts.Goal)
+	if planned < opts.Goal {
+		logger.Log.Warnf(utils.Yellow("Only %s can be freed, less than the goal of %s"),
+			reclaimer.FormatBytes(planned), reclaimer.FormatBytes(opts.Goal))
+	} else {
+		logger.Log.Infof("Freeing %s from %d of %d items meets the goal of %s", reclaimer.FormatBytes(planned),
+			len(plan), len(items), reclaimer.FormatBytes(opts.Goal))
+	}
+
+	reclaimed, err := processCleanupItems(ctx,
+		plan,
+		dryRun,
+		opts.System.confirmMode(),
+		summary,
+		estimatedSummary,
+		"Items planned to reach the goal")
+	if err != nil {
+		return reclaimed, fmt.Errorf("failed to process goal cleanup: %w", err)
+	}
+	return reclaimed, nil
+}
+
+// planForGoal orders items by tier and, within a tier, by size (largest first), and returns the
+// shortest prefix whose total reaches goal, together with that total. If the goal can't be met,
+// every item is returned.
+func planForGoal(items []cleanupItem, goal int64) ([]cleanupItem, int64) {
+	ordered := append([]cleanupItem(nil), items...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ti, tj := goalTier(ordered[i]), goalTier(ordered[j])
+		if ti != tj {
+			return ti < tj
+		}
+		return ordered[i].Size > ordered[j].Size
+	})
+
+	var total int64
+	for i, item := range ordered {
+		total += item.Size
+		if total >= goal {
+			return ordered[:i+1], total
+		}
+	}
+	return ordered, total
+}
+
+// totalItemSize returns the combined size of items.
+func totalItemSize(items []cleanupItem) int64 {
+	var total int64
+	for _, item := range items {
+		total += item.Size
+	}
+	return total
+}
@@ -0,0 +1,141 @@
+package cleaner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+)
+
+// ====================================================================================================
+// RUN HOOKS
+// ====================================================================================================
+
+// Hooks are shell commands run around a cleanup, e.g. to stop Docker or start a backup first. They
+// are run with `sh -c` and WIPER_* environment variables describing the run, and never in dry runs.
+type Hooks struct {
+	// PreClean runs once, right before the first item of the run is removed. If it fails, nothing is
+	// removed.
+	PreClean string
+	// PostClean runs after a cleanup, with its result (WIPER_ITEMS, WIPER_RECLAIMED, WIPER_STATUS, ...).
+	PostClean string
+	// PreDeleteItem runs before each item is removed, with WIPER_ITEM_PATH, WIPER_ITEM_SIZE and
+	// WIPER_ITEM_CATEGORY. If it fails, the item is skipped.
+	PreDeleteItem string
+}
+
+// runHooks are the hooks of this invocation of wiper, and hookCommand the wiper command.
+var (
+	runHooks    Hooks
+	hookCommand string
+)
+
+// preCleanMu guards preCleanRun, the ID of the run the pre_clean hook last ran for (commands like
+// `watch` clean several times), and preCleanErr, its outcome.
+var (
+	preCleanMu  sync.Mutex
+	preCleanRun string
+	preCleanErr error
+)
+
+// SetHooks configures the hooks of command, and registers the post_clean hook to run when a
+// cleanup is recorded in the history.
+func SetHooks(hooks Hooks, command string) {
+	runHooks = hooks
+	hookCommand = command
+	if hooks.PostClean != "" {
+		history.OnFinish(runPostCleanHook)
+	}
+}
+
+// runPreCleanHook runs the pre_clean hook before the first removal of a run, and returns the
+// error it failed with every time it is called afterwards in the same run.
+func runPreCleanHook() error {
+	if runHooks.PreClean == "" {
+		return nil
+	}
+	preCleanMu.Lock()
+	defer preCleanMu.Unlock()
+	if runID := history.CurrentID(); preCleanRun != runID || runID == "" {
+		preCleanRun = runID
+		logger.Log.Infof("Running the pre_clean hook: %s", runHooks.PreClean)
+		if preCleanErr = runHook("pre_clean", runHooks.PreClean, nil); preCleanErr != nil {
+			logger.Log.Errorf("%v; nothing is removed", preCleanErr)
+		}
+	}
+	return preCleanErr
+}
+
+// allowRemoval runs the pre_clean hook (once) and the pre_delete_item hook for an item about to
+// be removed. If either fails, the item is recorded as skipped and false is returned.
+func allowRemoval(path string, size int64, category string, summary *reclaimer.SummaryTable) bool {
+	if err := runPreCleanHook(); err != nil {
+		summary.AddSkippedReason(path, size, category, reclaimer.SkipReasonHook)
+		return false
+	}
+	if runHooks.PreDeleteItem == "" {
+		return true
+	}
+	err := runHook("pre_delete_item", runHooks.PreDeleteItem, []string{
+		"WIPER_ITEM_PATH=" + path,
+		"WIPER_ITEM_SIZE=" + strconv.FormatInt(size, 10),
+		"WIPER_ITEM_CATEGORY=" + category,
+	})
+	if err != nil {
+		logger.Log.With("category", category, "path", path).Infof("Skipped %s: %v", path, err)
+		summary.AddSkippedReason(path, size, category, reclaimer.SkipReasonHook)
+		return false
+	}
+	return true
+}
+
+// runPostCleanHook runs the post_clean hook with the result of a cleanup. Dry runs are left alone.
+func runPostCleanHook(run history.Run) {
+	if run.DryRun {
+		return
+	}
+	logger.Log.Infof("Running the post_clean hook: %s", runHooks.PostClean)
+	err := runHook("post_clean", runHooks.PostClean, []string{
+		"WIPER_RUN_ID=" + run.ID,
+		"WIPER_MODE=" + run.Mode,
+		"WIPER_ITEMS=" + strconv.Itoa(run.Items),
+		"WIPER_RECLAIMED=" + strconv.FormatInt(run.Reclaimed, 10),
+		"WIPER_FAILED=" + strconv.Itoa(run.Failed),
+		"WIPER_STATUS=" + run.Status(),
+		"WIPER_ERROR=" + run.Error,
+	})
+	if err != nil {
+		logger.Log.Warn(err)
+	}
+}
+
+// runHook runs a hook command with `sh -c`, the run's variables and env, and logs its output.
+func runHook(name, command string, env []string) error {
+	var output bytes.Buffer
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(), "WIPER_HOOK="+name, "WIPER_COMMAND="+hookCommand)
+	if runID := history.CurrentID(); runID != "" {
+		cmd.Env = append(cmd.Env, "WIPER_RUN_ID="+runID)
+	}
+	cmd.Env = append(cmd.Env, env...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+	if out := strings.TrimSpace(output.String()); out != "" {
+		logger.Log.Debugf("%s hook output: %s", name, out)
+		if err != nil {
+			return fmt.Errorf("the %s hook failed: %w: %s", name, err, out)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("the %s hook failed: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package cleaner
+
+import (
+	"sync" // Imported for sync.Once
+
+	"github.com/kodelint/wiper/pkg/logger" // Imported for the one-time warning
+	"github.com/kodelint/wiper/pkg/utils"  // Imported for utils.ContainsPath
+)
+
+// ====================================================================================================
+// CLOUD SYNC CAUTION MODE
+// ====================================================================================================
+
+// allowCloudSynced lets cleanups delete items in cloud-synced locations (--allow-icloud).
+var allowCloudSynced bool
+
+// cloudSyncWarning makes sure the caution message is printed only once per run.
+var cloudSyncWarning sync.Once
+
+// SetAllowCloudSynced controls whether items in cloud-synced locations may be deleted.
+// By default they are skipped while Desktop & Documents are synced with iCloud, because
+// deleting them also deletes them from iCloud and every other device signed in to it.
+func SetAllowCloudSynced(allow bool) {
+	allowCloudSynced = allow
+}
+
+// blockedByCloudSync reports whether path must be left alone because of cloud sync.
+// Items of cloud-sensitive targets (e.g., old downloads) are blocked as a whole while sync is
+// active; items of other cleanups only when they are inside a synced directory.
+func blockedByCloudSync(path string, sensitive bool) bool {
+	if allowCloudSynced {
+		return false
+	}
+	synced := CurrentPlatform().CloudSyncedDirs()
+	if len(synced) == 0 {
+		return false
+	}
+	if !sensitive && !utils.ContainsPath(path, synced) {
+		return false
+	}
+	cloudSyncWarning.Do(func() {
+		logger.Log.Warn(utils.Yellow("Desktop & Documents are synced with iCloud: deleting files there also removes them " +
+			"from iCloud and your other devices. Affected items are skipped; re-run with --allow-icloud to include them."))
+	})
+	return true
+}
@@ -0,0 +1,250 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// APPLICATION INVENTORY
+// ====================================================================================================
+
+// appInventoryCategory is used for warnings raised while sizing applications.
+const appInventoryCategory = "Applications"
+
+// InstalledApp describes an application bundle found in the platform's install locations.
+type InstalledApp struct {
+	// Name is the bundle name without the ".app" suffix (e.g., "Xcode").
+	Name string
+	// Path is the location of the bundle.
+	Path string
+	// Size is the disk usage of the bundle in bytes.
+	Size int64
+	// DataSize is the disk usage of the app's data outside the bundle (support files, caches,
+	// preferences, containers) in bytes, as found by the uninstall leftover search.
+	DataSize int64
+	// BundleID is the app's CFBundleIdentifier (e.g., "com.apple.dt.Xcode"), if known.
+	BundleID string
+	// LastUsed is when the app was last opened, or the zero time if unknown.
+	LastUsed time.Time
+	// AppStore is true for apps installed from the Mac App Store.
+	AppStore bool
+	// AppStoreID is the app's App Store identifier, known when the mas CLI is installed.
+	AppStoreID string
+	// Version is the installed version as reported by mas, if known.
+	Version string
+	// Cask is the token of the Homebrew cask that installed the app (e.g., "google-chrome"), if any.
+	Cask string
+}
+
+// ListApplications returns the application bundles in the platform's install locations, largest first.
+// Apps from the Mac App Store are recognized by their receipt and, when the `mas` CLI is installed,
+// tagged with their App Store ID and version. Apps installed by a Homebrew cask are tagged with its token.
+func ListApplications() ([]InstalledApp, error) {
+	platform := CurrentPlatform()
+	masApps, err := masInventory()
+	if err != nil {
+		logger.Log.Debugf("App Store details unavailable: %v", err)
+	}
+	caskApps, err := brewCaskApps()
+	if err != nil {
+		logger.Log.Debugf("Homebrew cask details unavailable: %v", err)
+	}
+
+	var bundlePaths []string
+	for _, dir := range platform.AppInstallPaths() {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.app"))
+		bundlePaths = append(bundlePaths, matches...)
+	}
+
+	var apps []InstalledApp
+	for _, bundlePath := range bundlePaths {
+		size, err := utils.GetFileSizeInBytes(bundlePath)
+		if err != nil {
+			logger.RunWarnings.Add(appInventoryCategory, reclaimer.SkipReasonForError(err), bundlePath, err)
+			continue
+		}
+
+		app := InstalledApp{
+			Name:     strings.TrimSuffix(filepath.Base(bundlePath), ".app"),
+			Path:     bundlePath,
+			Size:     size,
+			LastUsed: appLastUsed(bundlePath),
+		}
+		if _, err := os.Stat(filepath.Join(bundlePath, "Contents", "_MASReceipt", "receipt")); err == nil {
+			app.AppStore = true
+		}
+		if mas, ok := masApps[strings.ToLower(app.Name)]; ok {
+			app.AppStore = true
+			app.AppStoreID = mas.ID
+			app.Version = mas.Version
+		}
+		app.Cask = caskApps[filepath.Base(bundlePath)]
+		if id, err := bundleIdentifier(bundlePath); err == nil {
+			app.BundleID = id
+		}
+		app.DataSize = appDataSize(platform, app)
+		apps = append(apps, app)
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].Size != apps[j].Size {
+			return apps[i].Size > apps[j].Size
+		}
+		return apps[i].Name < apps[j].Name
+	})
+	return apps, nil
+}
+
+// appDataSize adds up the sizes of the files the uninstall leftover search finds for app.
+func appDataSize(platform Platform, app InstalledApp) int64 {
+	var bundleIDs []string
+	if app.BundleID != "" {
+		bundleIDs = []string{app.BundleID}
+	}
+	seen := make(map[string]bool)
+	var total int64
+	for _, pattern := range platform.AppLeftoverPatterns(app.Name, bundleIDs) {
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			if seen[match] || withinAny(match, []string{app.Path}) {
+				continue
+			}
+			seen[match] = true
+			if size, err := utils.GetFileSizeInBytes(match); err == nil {
+				total += size
+			}
+		}
+	}
+	return total
+}
+
+// appLastUsed returns when the app was last opened. It asks Spotlight for kMDItemLastUsedDate
+// where available and falls back to the access time of the bundle's executables.
+func appLastUsed(bundlePath string) time.Time {
+	if mdls, err := exec.LookPath("mdls"); err == nil {
+		out, err := exec.Command(mdls, "-raw", "-name", "kMDItemLastUsedDate", bundlePath).Output()
+		if err == nil {
+			if lastUsed, err := time.Parse("2006-01-02 15:04:05 -0700", strings.TrimSpace(string(out))); err == nil {
+				return lastUsed
+			}
+		}
+	}
+	return lastUsed("", filepath.Join(bundlePath, "Contents", "MacOS", "*"))
+}
+
+// ====================================================================================================
+// MAC APP STORE (mas)
+// ====================================================================================================
+
+// masApp is an App Store app as listed by `mas list`.
+type masApp struct {
+	ID      string
+	Name    string
+	Version string
+}
+
+// masInventory returns the apps the `mas` CLI reports as installed from the App Store,
+// keyed by lower-case name. It returns an error if mas is not installed.
+func masInventory() (map[string]masApp, error) {
+	mas, err := exec.LookPath("mas")
+	if err != nil {
+		return nil, err
+	}
+	out, err := exec.Command(mas, "list").Output()
+	if err != nil {
+		return nil, err
+	}
+	apps := make(map[string]masApp)
+	for _, line := range strings.Split(string(out), "\n") {
+		if app, ok := parseMasLine(line); ok {
+			apps[strings.ToLower(app.Name)] = app
+		}
+	}
+	return apps, nil
+}
+
+// parseMasLine parses a line of `mas list` output, such as "497799835  Xcode  (15.0)".
+// Names may contain spaces; the version in parentheses is optional.
+func parseMasLine(line string) (masApp, bool) {
+	id, rest, ok := strings.Cut(strings.TrimSpace(line), " ")
+	if !ok || id == "" {
+		return masApp{}, false
+	}
+	rest = strings.TrimSpace(rest)
+	app := masApp{ID: id, Name: rest}
+	if open := strings.LastIndex(rest, "("); open > 0 && strings.HasSuffix(rest, ")") {
+		app.Name = strings.TrimSpace(rest[:open])
+		app.Version = rest[open+1 : len(rest)-1]
+	}
+	return app, app.Name != ""
+}
+
+// ====================================================================================================
+// HOMEBREW CASKS
+// ====================================================================================================
+
+// brewCaskInfo is the part of `brew info --cask --json=v2` output that names the installed apps.
+type brewCaskInfo struct {
+	Casks []struct {
+		Token string `json:"token"`
+		// Artifacts is a list of objects with a single key, such as {"app": ["Firefox.app"]}.
+		Artifacts []map[string]json.RawMessage `json:"artifacts"`
+	} `json:"casks"`
+}
+
+// brewCaskApps returns the tokens of the installed Homebrew casks, keyed by the bundle names of the
+// apps they installed (e.g., "Google Chrome.app": "google-chrome"). It returns an error if
+// Homebrew is not installed.
+func brewCaskApps() (map[string]string, error) {
+	out, err := brewOutput("info", "--cask", "--json=v2", "--installed")
+	if err != nil {
+		return nil, err
+	}
+	var info brewCaskInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse brew info: %w", err)
+	}
+
+	apps := make(map[string]string)
+	for _, cask := range info.Casks {
+		for _, artifact := range cask.Artifacts {
+			raw, ok := artifact["app"]
+			if !ok {
+				continue
+			}
+			// Entries are bundle names, optionally followed by {"target": "New Name.app"} to rename the last one.
+			var entries []json.RawMessage
+			if err := json.Unmarshal(raw, &entries); err != nil {
+				continue
+			}
+			var last string
+			for _, entry := range entries {
+				var name string
+				var target struct {
+					Target string `json:"target"`
+				}
+				switch {
+				case json.Unmarshal(entry, &name) == nil:
+					last = filepath.Base(name)
+				case json.Unmarshal(entry, &target) == nil && target.Target != "" && last != "":
+					last = filepath.Base(target.Target)
+				default:
+					continue
+				}
+				apps[last] = cask.Token
+			}
+		}
+	}
+	return apps, nil
+}
@@ -1,13 +1,17 @@
 package cleaner
 
 import (
+	"container/heap"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-	"syscall"
+	"time"
 
 	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/progress"
 	"github.com/kodelint/wiper/pkg/reclaimer"
 	"github.com/kodelint/wiper/pkg/utils"
 )
@@ -16,41 +20,179 @@ import (
 // LARGE FILES CLEANUP FUNCTION
 // ====================================================================================================
 
+// largeFilesSkipCategory is the category under which excluded paths of the large file scan are reported.
+const largeFilesSkipCategory = "Large Files"
+
+// DefaultLargeFileThreshold is the size at which a file is considered "large" (100 MiB).
+const DefaultLargeFileThreshold = 100 * 1024 * 1024
+
+// topFileMinSize is the size below which files aren't ranked by a Top scan unless a threshold is
+// given (1 MiB): smaller files only fill the ranking on nearly empty disks.
+const topFileMinSize = 1024 * 1024
+
+// LargeFileOptions configures a large file scan.
+// The zero value scans the default locations with the default threshold.
+type LargeFileOptions struct {
+	// ScanRoots are directories to scan instead of the default locations (e.g., a specific volume).
+	ScanRoots []string
+	// Threshold is the minimum size in bytes for a file to be reported. 0 means DefaultLargeFileThreshold.
+	Threshold int64
+	// UseSpotlight finds large files through the Spotlight index instead of walking the filesystem.
+	// Roots that aren't indexed are still walked.
+	UseSpotlight bool
+	// OlderThan only reports files that weren't modified within this window. 0 reports them all.
+	OlderThan time.Duration
+	// ByAccess makes OlderThan look at the last access time instead of the modification time.
+	ByAccess bool
+	// Types limits the scan to files of these groups (see FileTypes), e.g. "video" or "diskimage".
+	// Empty reports files of every type.
+	Types []string
+	// IncludeVolumes also scans the external volumes (see ExternalVolumes), in addition to the
+	// default locations or ScanRoots.
+	IncludeVolumes bool
+	// Top reports the Top largest files instead of every file above the threshold, largest first.
+	// The default threshold doesn't apply then, only an explicit Threshold. 0 reports them all.
+	Top int
+}
+
+// tooRecent tells whether a file was modified (or accessed, with ByAccess) within OlderThan.
+func (opts LargeFileOptions) tooRecent(info os.FileInfo) bool {
+	if opts.OlderThan <= 0 {
+		return false
+	}
+	last := info.ModTime()
+	if opts.ByAccess {
+		last = utils.AccessTime(info)
+	}
+	return time.Since(last) < opts.OlderThan
+}
+
 // CleanLargeFiles identifies and optionally removes large files based on a size threshold.
 //
 // Parameters:
+//   - ctx: Cancels the scan, or the cleanup between items (see processCleanupItems).
 //   - dryRun: A boolean flag for dry-run mode.
 //   - ignorePaths: A slice of paths to be ignored during the scan.
 //   - summary: A pointer to a SummaryTable to record deleted items.
 //   - estimatedSummary: A pointer to a SummaryTable to record dry-run estimations.
 //   - interactive: A boolean flag for interactive mode (prompts for each file).
+//   - opts: Scan roots and size threshold; see LargeFileOptions.
 //
 // Returns:
 //   - The total space reclaimed in bytes and an error, if any.
-func CleanLargeFiles(dryRun bool, ignorePaths []string, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable, interactive bool) (int64, error) {
+func CleanLargeFiles(ctx context.Context, dryRun bool, ignorePaths []string, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable, interactive bool, opts LargeFileOptions) (int64, error) {
 	logger.Log.Infof("Initiating large file scan (dryRun: %t, interactive: %t)", dryRun, interactive)
+	itemsToProcess, err := (&largeFilesCleaner{ignorePaths: ignorePaths, skipped: estimatedSummary, opts: opts}).scanItems(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// A Top scan is about the ranking, which the summary by category doesn't show.
+	if opts.Top > 0 && len(itemsToProcess) > 0 && (dryRun || !logger.Quiet()) {
+		printRankedFiles(itemsToProcess)
+	}
+
+	// Pass the collected items to the generic processing function.
+	// Interactive mode asks for every file; otherwise there is one prompt for all of them.
+	mode := confirmOnce
+	if interactive {
+		mode = confirmEachItem
+	}
+	reclaimed, err := processCleanupItems(ctx,
+		itemsToProcess,
+		dryRun,
+		mode,
+		summary,
+		estimatedSummary,
+		"Detected Large Files")
+	if err != nil {
+		return reclaimed, fmt.Errorf("failed to process large files cleanup: %w", err)
+	}
+
+	return reclaimed, nil
+}
+
+// EstimateLargeFiles scans for large files without deleting anything or prompting.
+// It returns a SummaryTable with one (not removed) entry per large file a cleanup would offer,
+// along with the paths that were skipped and why.
+//
+// Parameters:
+//   - ctx: Stops the scan when cancelled.
+//   - ignorePaths: A slice of paths to be ignored during the scan.
+//   - opts: Scan roots and size threshold; see LargeFileOptions.
+//
+// Returns:
+//   - The estimated summary and an error, if any.
+func EstimateLargeFiles(ctx context.Context, ignorePaths []string, opts LargeFileOptions) (*reclaimer.SummaryTable, error) {
+	estimate := reclaimer.NewSummaryTable()
+	items, err := (&largeFilesCleaner{ignorePaths: ignorePaths, skipped: estimate, opts: opts}).scanItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		estimate.AddEstimated(item.ActualPath, item.Size, item.Category)
+		estimate.MarkCloned(item.Cloned)
+	}
+	return estimate, nil
+}
 
-	// Define the threshold for a file to be considered "large" (100 MB).
-	const largeFileThreshold = 100 * 1024 * 1024 // 100 MB
+// largeFilesCleaner is the Cleaner of the large file cleanup: it finds files above a size threshold.
+type largeFilesCleaner struct {
+	ignorePaths []string
+	// skipped receives the excluded paths; nil discards them.
+	skipped *reclaimer.SummaryTable
+	opts    LargeFileOptions
+}
+
+// Name returns "large-files".
+func (c *largeFilesCleaner) Name() string { return "large-files" }
 
-	// Directories to scan for large files.
-	// We use utils.ExpandPath to handle environment variables like $HOME and user-friendly paths like `~`.
-	dirsToScan := []string{
-		utils.ExpandPath("/Users"),
-		utils.ExpandPath("/private/var/folders"),
-		utils.ExpandPath("/private/tmp"),
-		utils.ExpandPath("$HOME/Downloads"),
-		utils.ExpandPath("$HOME/Documents"),
+// Description says what the large file cleanup finds.
+func (c *largeFilesCleaner) Description() string {
+	threshold := c.opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultLargeFileThreshold
 	}
+	return fmt.Sprintf("Files of %s or more (with --large-files)", reclaimer.FormatBytes(threshold))
+}
+
+// Scan returns the large files below the scan roots.
+func (c *largeFilesCleaner) Scan(ctx context.Context) ([]Item, error) { return scanBuiltin(ctx, c) }
 
-	// Prepare a cleaned list of absolute paths to ignore.
-	var cleanedIgnorePaths = []string{
-		// We automatically ignore the Applications folder to avoid scanning inside app bundles.
-		utils.ExpandPath("$HOME/Applications/"),
+// scanItems collects the large files below the scan roots as cleanupItems. Excluded paths
+// are recorded as skipped, and scan problems are added to logger.RunWarnings.
+// It returns ctx's error if the scan was cancelled.
+func (c *largeFilesCleaner) scanItems(ctx context.Context) ([]cleanupItem, error) {
+	ignorePaths, estimatedSummary, opts := c.ignorePaths, c.skipped, c.opts
+	if estimatedSummary == nil {
+		estimatedSummary = reclaimer.NewSummaryTable()
 	}
+
+	// Define the threshold for a file to be considered "large" (100 MiB unless configured).
+	largeFileThreshold := opts.Threshold
+	if largeFileThreshold <= 0 {
+		largeFileThreshold = DefaultLargeFileThreshold
+		if opts.Top > 0 {
+			largeFileThreshold = topFileMinSize
+		}
+	}
+	logger.Log.Debugf("Large file threshold: %s", reclaimer.FormatBytes(largeFileThreshold))
+
+	// Directories to scan for large files, as defined by the current platform.
+	platform := CurrentPlatform()
+	dirsToScan := platform.LargeFileScanRoots()
+	if len(opts.ScanRoots) > 0 {
+		dirsToScan = opts.ScanRoots
+	}
+	if opts.IncludeVolumes {
+		dirsToScan = append(append([]string(nil), dirsToScan...), ExternalVolumes()...)
+	}
+
+	// Prepare a cleaned list of absolute paths to ignore, starting with the platform's (e.g., app bundles).
+	cleanedIgnorePaths := platform.LargeFileIgnorePaths()
 	for _, p := range ignorePaths {
 		// Resolve user-provided ignore paths to absolute paths for reliable comparison.
-		absPath, err := filepath.Abs(utils.ExpandPath(p))
+		absPath, err := utils.AbsIgnorePath(p)
 		if err != nil {
 			logger.Log.Warnf("Failed to resolve absolute path for ignore entry %s: %v", p, err)
 			continue
@@ -58,22 +200,134 @@ func CleanLargeFiles(dryRun bool, ignorePaths []string, summary *reclaimer.Summa
 		cleanedIgnorePaths = append(cleanedIgnorePaths, absPath)
 	}
 
-	showWarnings := os.Getenv("WIPER_SHOW_WARNINGS") == "true"
-	showDetails := os.Getenv("WIPER_SHOW_DETAILS") == "true"
-	var suppressedWarnings bool // To track if any warnings were suppressed
+	// File type groups are resolved once; an unknown group fails before the scan starts.
+	extensions, err := FileTypeExtensions(opts.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	showDetails := logger.ShowDetails()
 
 	// Collect all large files as cleanupItems before processing.
 	var itemsToProcess []cleanupItem
 
+	// Scanning whole home directories takes a while, so show a spinner with the number of files seen,
+	// the space found so far, and the directory being read.
+	scanProgress := progress.New("Scanning for large files", 0)
+	scanProgress.SetUnit("files")
+	scanProgress.Start()
+	defer scanProgress.Stop()
+	var scanRoot string
+	var foundBytes int64
+	// Other names of a hard-linked file that was already found add nothing to the totals.
+	links := utils.NewLinkSet()
+	// A Top scan only keeps the largest files seen so far; anything smaller than all of them is passed over.
+	top := &largestFiles{}
+	// addIfLarge records path as a large file if it meets the threshold and isn't protected.
+	addIfLarge := func(path string, info os.FileInfo) {
+		if !hasExtension(path, extensions) {
+			return
+		}
+		// Calculate the actual disk usage of the file.
+		// This is more accurate for sparse files or files on HFS+ and APFS.
+		actualSize := utils.FileInfoDiskUsage(info)
+
+		// Check if the file meets the large file size threshold.
+		if actualSize < largeFileThreshold || (opts.Top > 0 && top.Len() == opts.Top && actualSize < top.smallest()) {
+			return
+		}
+		if showDetails {
+			logger.Log.With("path", path, "size", actualSize).Infof("Found large file: %s (Actual Size: %s, Logical Size: %s)",
+				path, reclaimer.FormatBytes(actualSize), reclaimer.FormatBytes(info.Size()))
+		}
+
+		// Assign a generic category to the file based on its path.
+		category := platform.LargeFileCategory(path)
+		// Files still in use aren't the stale disk hogs an age filter looks for.
+		if opts.tooRecent(info) {
+			estimatedSummary.AddSkippedReason(path, actualSize, category, reclaimer.SkipReasonTooNew)
+			return
+		}
+		if blockedByCloudSync(path, false) {
+			estimatedSummary.AddSkippedReason(path, actualSize, category, reclaimer.SkipReasonCloudSynced)
+			return
+		}
+		if isTagProtected(path) {
+			estimatedSummary.AddSkippedReason(path, actualSize, category, reclaimer.SkipReasonTagged)
+			return
+		}
+		if utils.IsProtectedPath(path) {
+			estimatedSummary.AddSkippedReason(path, actualSize, category, reclaimer.SkipReasonProtected)
+			return
+		}
+		// Clones of the file elsewhere keep its shared blocks, so only its private bytes are freed.
+		usage := links.FileUsage(path, info)
+		item := cleanupItem{
+			Path:       path, // For large files, Path is the actual file path for display in the table
+			Size:       usage.Private(),
+			Cloned:     usage.Cloned,
+			Category:   category, // This is the aggregated category for the summary table
+			ActualPath: path,     // Store the actual file path here
+			Scanned:    utils.FingerprintOf(info),
+		}
+		foundBytes += item.Size
+		if opts.Top > 0 {
+			heap.Push(top, item)
+			if top.Len() > opts.Top {
+				foundBytes -= heap.Pop(top).(cleanupItem).Size
+			}
+		} else {
+			itemsToProcess = append(itemsToProcess, item)
+		}
+		scanProgress.SetLabel(fmt.Sprintf("Scanning %s, %s found", scanRoot, reclaimer.FormatBytes(foundBytes)))
+	}
+
 	for _, dir := range dirsToScan {
-		// filepath.Walk traverses the file tree rooted at 'dir'.
-		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				if showWarnings {
-					logger.Log.Warnf("Error accessing path %s: %v", path, err)
-				} else {
-					suppressedWarnings = true
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		scanRoot = dir
+		scanProgress.SetLabel(fmt.Sprintf("Scanning %s, %s found", scanRoot, reclaimer.FormatBytes(foundBytes)))
+		scanStart := time.Now()
+
+		// The Spotlight index answers in seconds; roots it doesn't cover are walked instead.
+		if opts.UseSpotlight {
+			if paths, ok := spotlightLargeFiles(dir, largeFileThreshold); ok {
+				logger.Log.Debugf("Found %d large file candidates in %s using Spotlight", len(paths), dir)
+				for _, path := range paths {
+					scanProgress.Add(1)
+					info, err := os.Lstat(path)
+					if err != nil || !info.Mode().IsRegular() {
+						// The index can be slightly behind; files deleted since are simply gone.
+						continue
+					}
+					if reason := excludedLargeFileAncestor(platform, dir, path, cleanedIgnorePaths); reason != "" {
+						if info.Size() >= largeFileThreshold {
+							estimatedSummary.AddSkippedReason(path, info.Size(), largeFilesSkipCategory, reason)
+						}
+						continue
+					}
+					addIfLarge(path, info)
 				}
+				estimatedSummary.Timings.AddScan(fmt.Sprintf("%s: %s", largeFilesSkipCategory, dir), time.Since(scanStart))
+				continue
+			}
+			logger.Log.Debugf("Spotlight index unavailable for %s; scanning the filesystem", dir)
+		}
+
+		// WalkParallel traverses the file tree rooted at 'dir' with the shared worker pool (see --jobs).
+		// The callback is never run concurrently, so it can record results without locking.
+		firstItem := len(itemsToProcess)
+		err := utils.WalkParallel(dir, func(path string, info os.FileInfo, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			scanProgress.Add(1)
+			if err != nil {
+				reason := reclaimer.SkipReasonForError(err)
+				// Collapsed per directory and reported after the summary tables (see logger.RunWarnings).
+				logger.RunWarnings.Add(largeFilesSkipCategory, reason, path, err)
+				estimatedSummary.AddSkippedReason(path, 0, largeFilesSkipCategory, reason)
 				// Continue walking the rest of the tree despite the error on this path.
 				return nil
 			}
@@ -82,139 +336,125 @@ func CleanLargeFiles(dryRun bool, ignorePaths []string, summary *reclaimer.Summa
 			if utils.IsPathIgnored(path, cleanedIgnorePaths) {
 				if info.IsDir() {
 					// If the ignored path is a directory, skip the entire directory tree.
+					estimatedSummary.AddSkippedReason(path, 0, largeFilesSkipCategory, reclaimer.SkipReasonIgnored)
 					return filepath.SkipDir
 				}
+				if info.Size() >= largeFileThreshold {
+					estimatedSummary.AddSkippedReason(path, info.Size(), largeFilesSkipCategory, reclaimer.SkipReasonIgnored)
+				}
 				return nil
 			}
 
 			// If it's a directory, check for system paths that should be skipped.
 			if info.IsDir() {
-				if path == "/System" || path == "/Library" || path == "/usr" || path == "/Applications" || strings.HasPrefix(path, "/Developer") {
+				scanProgress.SetDetail(path)
+				if isProtectedDir(platform, path) {
+					estimatedSummary.AddSkippedReason(path, 0, largeFilesSkipCategory, reclaimer.SkipReasonProtected)
+					return filepath.SkipDir
+				}
+				// A tagged folder protects everything inside it.
+				if isTagProtected(path) {
+					estimatedSummary.AddSkippedReason(path, 0, largeFilesSkipCategory, reclaimer.SkipReasonTagged)
 					return filepath.SkipDir
 				}
 				return nil
 			}
 
-			// Calculate the actual disk usage of the file using a system call.
-			// This is more accurate for sparse files or files on HFS+ and APFS.
-			var actualSize int64
-			if stat, ok := info.Sys().(*syscall.Stat_t); ok {
-				actualSize = stat.Blocks * 512
-			} else {
-				actualSize = info.Size()
-				logger.Log.Debugf("Could not get actual disk usage for %s, falling back to logical size.", path)
-			}
-
-			// Check if the file meets the large file size threshold.
-			if actualSize >= largeFileThreshold {
-				if showDetails {
-					logger.Log.Infof("Found large file: %s (Actual Size: %s, Logical Size: %s)",
-						path, reclaimer.FormatBytes(actualSize), reclaimer.FormatBytes(info.Size()))
-				}
-
-				// Assign a generic category to the file based on its path.
-				category := categorizeLargeFilePath(path)
-				itemsToProcess = append(itemsToProcess, cleanupItem{
-					Path:       path, // For large files, Path is the actual file path for display in the table
-					Size:       actualSize,
-					Category:   category, // This is the aggregated category for the summary table
-					ActualPath: path,     // Store the actual file path here
-				})
-			}
+			addIfLarge(path, info)
 			return nil
 		})
+		// Directories are read concurrently, so sort the files of this root for a stable order.
+		found := itemsToProcess[firstItem:]
+		sort.Slice(found, func(i, j int) bool { return found[i].ActualPath < found[j].ActualPath })
+
+		// Large files are only categorized per file, so the scan time is reported per scanned root.
+		estimatedSummary.Timings.AddScan(fmt.Sprintf("%s: %s", largeFilesSkipCategory, dir), time.Since(scanStart))
 
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if err != nil {
-			if showWarnings {
-				logger.Log.Errorf("Error walking directory %s: %v", dir, err)
-			} else {
-				suppressedWarnings = true
-			}
+			logger.RunWarnings.Add(largeFilesSkipCategory, reclaimer.SkipReasonForError(err), dir, err)
 		}
 	}
 
-	if suppressedWarnings {
-		logger.Log.Warn("Some warnings were suppressed. Set WIPER_SHOW_WARNINGS=true to see full warning details.")
+	if opts.Top > 0 {
+		return top.ranked(), nil
 	}
-	// Pass the collected items to the generic processing function.
-	// The `isApp` flag is set to `false` as this is not an application uninstall.
-	reclaimed, err := processCleanupItems(itemsToProcess,
-		dryRun,
-		interactive,
-		summary,
-		estimatedSummary,
-		"Detected Large Files",
-		false)
-	if err != nil {
-		return 0, fmt.Errorf("failed to process large files cleanup: %w", err)
-	}
-
-	return reclaimed, nil
+	return itemsToProcess, nil
 }
 
-// ====================================================================================================
-// PATH CATEGORIZATION FUNCTION
-// ====================================================================================================
+// largestFiles is a min-heap of cleanupItems by size (see container/heap), so the smallest of the
+// largest files found so far is the one replaced by a larger file.
+type largestFiles []cleanupItem
 
-// categorizeLargeFilePath determines a higher-level, generic category for a given large file path.
-// This helps in creating a clean summary table for the user.
-func categorizeLargeFilePath(path string) string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		if strings.HasPrefix(path, "/private/var") || strings.HasPrefix(path, "/tmp") {
-			return "System Temporary Files"
-		}
-		return "Other Large Files"
+func (h largestFiles) Len() int { return len(h) }
+func (h largestFiles) Less(i, j int) bool {
+	if h[i].Size != h[j].Size {
+		return h[i].Size < h[j].Size
 	}
+	// Files of the same size are ranked by path, so the same files make the cut on every run.
+	return h[i].ActualPath > h[j].ActualPath
+}
+func (h largestFiles) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *largestFiles) Push(x interface{}) { *h = append(*h, x.(cleanupItem)) }
+func (h *largestFiles) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
 
-	normalizedPath := path
-	if strings.HasPrefix(path, homeDir) {
-		normalizedPath = strings.Replace(path, homeDir, "~", 1)
-	}
+// smallest returns the size of the smallest file in the heap, which must not be empty.
+func (h largestFiles) smallest() int64 { return h[0].Size }
 
-	if strings.Contains(normalizedPath, "~/Library/Application Support/Google/Chrome") ||
-		strings.Contains(normalizedPath, "~/Library/Caches/Google/Chrome") ||
-		strings.Contains(normalizedPath, "~/Library/Application Support/BraveSoftware/Brave-Browser") ||
-		strings.Contains(normalizedPath, "~/Library/Caches/com.apple.Safari") ||
-		strings.Contains(normalizedPath, "~/Library/Application Support/Firefox") {
-		return "Browser Caches"
+// ranked empties the heap and returns its files, largest first.
+func (h *largestFiles) ranked() []cleanupItem {
+	items := make([]cleanupItem, h.Len())
+	for i := len(items) - 1; i >= 0; i-- {
+		items[i] = heap.Pop(h).(cleanupItem)
 	}
+	return items
+}
 
-	if strings.HasPrefix(normalizedPath, "~/Downloads") {
-		return "User Downloads"
-	}
-	if strings.HasPrefix(normalizedPath, "~/Documents") {
-		return "User Documents"
+// printRankedFiles lists the files of a Top scan with their rank, largest first.
+func printRankedFiles(items []cleanupItem) {
+	rows := make([][]interface{}, len(items))
+	var total int64
+	for i, item := range items {
+		rows[i] = []interface{}{i + 1, item.ActualPath, item.Category, utils.Yellow(reclaimer.FormatBytes(item.Size))}
+		total += item.Size
 	}
+	reclaimer.PrintListTable(fmt.Sprintf("The %d Largest Files", len(items)), []string{"#", "PATH", "CATEGORY", "SIZE"}, rows,
+		[]interface{}{"", utils.Blue("TOTAL"), "", utils.Blue(reclaimer.FormatBytes(total))})
+}
 
-	if strings.HasPrefix(normalizedPath, "~/Library/Application Support") {
-		return "Application Support Files"
-	}
-	if strings.HasPrefix(normalizedPath, "~/Library/Caches") {
-		return "User Caches"
-	}
-	if strings.HasPrefix(normalizedPath, "~/Library/Containers") {
-		return "User Container Data"
-	}
-	if strings.HasPrefix(normalizedPath, "~/Library/Group Containers") {
-		return "User Group Container Data"
-	}
-	if strings.HasPrefix(normalizedPath, "~/Library/Messages/Attachments") {
-		return "Messages Attachments"
-	}
-	if strings.HasPrefix(normalizedPath, "~/Library/Metadata/CoreSpotlight") {
-		return "Spotlight Metadata"
-	}
-	if strings.HasPrefix(normalizedPath, "~/Library") {
-		return "Other User Library Files"
-	}
+// ====================================================================================================
+// PATH CATEGORIZATION FUNCTIONS
+// ====================================================================================================
 
-	if strings.HasPrefix(path, "/private/var/folders") ||
-		strings.HasPrefix(path, "/private/var/tmp") ||
-		strings.HasPrefix(path, "/tmp") {
-		return "System Temporary Files"
+// tildePath returns path with the user's home directory replaced by "~", so platforms can
+// categorize home paths with simple prefixes. The second result is false if the home directory
+// is unknown, in which case path is returned unchanged.
+func tildePath(path string) (string, bool) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path, false
 	}
+	if strings.HasPrefix(path, homeDir) {
+		return strings.Replace(path, homeDir, "~", 1), true
+	}
+	return path, true
+}
 
+// commonLargeFileCategory categorizes a large file by the locations shared by all platforms:
+// developer tool data, the rest of the home directory, and system files. Platforms call it for
+// paths that none of their own locations match.
+//
+// Parameters:
+//   - path: The absolute path of the file.
+//   - normalizedPath: The path as returned by tildePath.
+func commonLargeFileCategory(path string, normalizedPath string) string {
 	if strings.Contains(normalizedPath, ".rustup") ||
 		strings.Contains(normalizedPath, ".npm") ||
 		strings.Contains(normalizedPath, ".gradle") ||
@@ -228,6 +468,10 @@ func categorizeLargeFilePath(path string) string {
 		return "User Home Files"
 	}
 
+	if utils.ContainsPath(path, CurrentPlatform().ExternalVolumeDirs()) {
+		return "External Volumes"
+	}
+
 	if strings.HasPrefix(path, "/var") || strings.HasPrefix(path, "/usr") || strings.HasPrefix(path, "/opt") {
 		return "System Files"
 	}
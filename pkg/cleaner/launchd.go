@@ -0,0 +1,69 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// LAUNCHD JOBS
+// ====================================================================================================
+
+// launchJobDomain returns the launchd domain a job definition at path is loaded into: the user's
+// GUI session for launch agents and the system domain for launch daemons. It returns false for
+// paths that aren't job definitions.
+func launchJobDomain(path string) (string, bool) {
+	if filepath.Ext(path) != ".plist" {
+		return "", false
+	}
+	switch filepath.Base(filepath.Dir(path)) {
+	case "LaunchAgents":
+		return fmt.Sprintf("gui/%d", os.Getuid()), true
+	case "LaunchDaemons":
+		return "system", true
+	default:
+		return "", false
+	}
+}
+
+// unloadLaunchJobs stops the launchd jobs defined by the launch agent and daemon plists among items,
+// so they don't keep running (or get restarted) once their definitions are removed. Jobs that
+// aren't loaded are left alone. A job that can't be unloaded (unloading daemons needs root) is
+// reported with the run's warnings; its plist is still removed.
+func unloadLaunchJobs(items []cleanupItem) {
+	launchctl, err := exec.LookPath("launchctl")
+	if err != nil {
+		return
+	}
+	for _, item := range items {
+		domain, ok := launchJobDomain(item.ActualPath)
+		if !ok {
+			continue
+		}
+		label, err := readPlistString(item.ActualPath, "Label")
+		if err != nil || label == "" {
+			logger.Log.Debugf("Could not read the label of %s: %v", item.ActualPath, err)
+			continue
+		}
+		// `launchctl print` fails for jobs that aren't loaded.
+		if err := exec.Command(launchctl, "print", domain+"/"+label).Run(); err != nil {
+			logger.Log.Debugf("Launch job %s is not loaded in %s", label, domain)
+			continue
+		}
+		out, err := exec.Command(launchctl, "bootout", domain, item.ActualPath).CombinedOutput()
+		if err != nil {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				err = fmt.Errorf("%w: %s", err, msg)
+			}
+			logger.RunWarnings.Add(item.Category, "launch job not unloaded", item.ActualPath, err)
+			continue
+		}
+		logger.Log.Infof(utils.Cyan("Unloaded launch job %s"), label)
+	}
+}
@@ -0,0 +1,172 @@
+package cleaner
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/quarantine"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// LOGIN ITEMS
+// ====================================================================================================
+
+// loginItemsCategory is the summary table category of removed login items.
+const loginItemsCategory = "Login Items"
+
+// loginItem is an app or document that is opened when the user logs in.
+type loginItem struct {
+	Name string
+	Path string
+}
+
+// listLoginItemsScript prints the name and path of every login item, separated by a tab.
+const listLoginItemsScript = `set output to ""
+tell application "System Events"
+	repeat with loginItem in login items
+		set output to output & (name of loginItem) & tab & (path of loginItem) & linefeed
+	end repeat
+end tell
+return output`
+
+// removeLoginItems removes the login items that open one of an application's bundles or carry its
+// name, so the app isn't launched (and reported missing) at the next login. They take no disk
+// space, so they are recorded with a size of 0, and in the manifest of the run. Login items are
+// managed through System Events, which macOS may ask the user to allow once. Nothing happens where
+// osascript isn't available.
+//
+// Background items that apps register with macOS themselves (SMAppService, shown under "Allow in
+// the Background" in System Settings) can't be removed this way; those of the app are reported
+// with the run's warnings (see reportBackgroundItems).
+//
+// Parameters:
+//   - appName: The name of the application without the ".app" suffix.
+//   - bundlePaths: The bundles of the application that were found.
+//   - bundleIDs: The bundle identifiers of the application.
+//   - dryRun: If true, the login items are only recorded as estimated.
+//   - summary: A pointer to a SummaryTable to record removed login items.
+//   - estimatedSummary: A pointer to a SummaryTable to record login items found during a dry run.
+func removeLoginItems(appName string, bundlePaths []string, bundleIDs []string, dryRun bool, summary, estimatedSummary *reclaimer.SummaryTable) {
+	if _, err := exec.LookPath("osascript"); err != nil {
+		return
+	}
+	reportBackgroundItems(appName, bundlePaths, bundleIDs)
+	out, err := osascriptOutput(listLoginItemsScript)
+	if err != nil {
+		logger.Log.Debugf("Could not list login items: %v", err)
+		return
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		name, path, _ := strings.Cut(strings.TrimSpace(line), "\t")
+		item := loginItem{Name: name, Path: strings.TrimSuffix(path, "/")}
+		if item.Name == "" || (item.Name != appName && !withinAny(item.Path, bundlePaths)) {
+			continue
+		}
+
+		entryPath := "login-item:" + item.Name
+		if dryRun {
+			estimatedSummary.AddEstimated(entryPath, 0, loginItemsCategory)
+			continue
+		}
+		script := fmt.Sprintf(`tell application "System Events" to delete login item %s`, appleScriptString(item.Name))
+		if _, err := osascriptOutput(script); err != nil {
+			logger.Log.With("category", loginItemsCategory).Errorf("Failed to remove login item %s: %v", item.Name, err)
+			summary.AddFailed(entryPath, 0, loginItemsCategory, err)
+			continue
+		}
+		logger.Log.Infof(utils.Cyan("Removed login item %s"), item.Name)
+		summary.AddRemoved(entryPath, 0, loginItemsCategory)
+		recordRemoval(cleanupItem{ActualPath: entryPath, Category: loginItemsCategory}, 0, quarantine.ActionDeleted, "")
+	}
+}
+
+// backgroundItem is a record of macOS's Background Task Management database, as printed by
+// `sfltool dumpbtm`: a login item or launch agent an app registered, or the app that owns them.
+type backgroundItem struct {
+	Name     string
+	URL      string
+	BundleID string
+}
+
+// reportBackgroundItems warns about the background items of Background Task Management that
+// belong to the app: they are registered by the app itself and can only be removed in System
+// Settings > General > Login Items. Reading the database needs root; without it, or without
+// sfltool (before macOS 13), nothing is reported.
+func reportBackgroundItems(appName string, bundlePaths []string, bundleIDs []string) {
+	if _, err := exec.LookPath("sfltool"); err != nil {
+		return
+	}
+	out, err := exec.Command("sfltool", "dumpbtm").Output()
+	if err != nil {
+		logger.Log.Debugf("Could not read the background items (sfltool dumpbtm needs root): %v", err)
+		return
+	}
+	for _, item := range parseBackgroundItems(string(out)) {
+		// Bundle URLs are percent-encoded (e.g., "file:///Applications/Google%20Chrome.app/").
+		path := ""
+		if u, err := url.Parse(item.URL); err == nil && u.Scheme == "file" {
+			path = strings.TrimSuffix(u.Path, "/")
+		}
+		if item.Name != appName && !slices.Contains(bundleIDs, item.BundleID) && (path == "" || !withinAny(path, bundlePaths)) {
+			continue
+		}
+		logger.RunWarnings.Add(loginItemsCategory, "background item not removed", item.Name,
+			fmt.Errorf("remove it in System Settings > General > Login Items"))
+	}
+}
+
+// parseBackgroundItems reads the items of `sfltool dumpbtm` output, which lists each one as a block
+// of "Key: value" lines starting with a "#<n>:" line.
+func parseBackgroundItems(out string) []backgroundItem {
+	var items []backgroundItem
+	var current *backgroundItem
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") && strings.HasSuffix(line, ":") {
+			items = append(items, backgroundItem{})
+			current = &items[len(items)-1]
+			continue
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok || current == nil {
+			continue
+		}
+		switch key {
+		case "Name":
+			current.Name = value
+		case "URL":
+			current.URL = value
+		case "Bundle Identifier":
+			current.BundleID = value
+		}
+	}
+	return items
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// osascriptOutput runs an AppleScript and returns its result.
+func osascriptOutput(script string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("osascript", "-e", script)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("osascript: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("osascript: %w", err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,213 @@
+package cleaner
+
+import (
+	"fmt"           // Imported for fmt.Errorf
+	"path/filepath" // Imported for filepath.Match
+	"runtime"       // Imported for runtime.GOOS
+	"slices"        // Imported for slices.Contains
+	"sort"          // Imported for sort.Strings
+	"strings"       // Imported for strings.Join
+	"sync"          // Imported for sync.Mutex
+
+	"github.com/kodelint/wiper/pkg/utils" // Imported for utils.ExpandPath
+)
+
+// ====================================================================================================
+// PLATFORM REGISTRY
+// ====================================================================================================
+
+// Platform describes where an operating system keeps the data wiper cleans. Each supported OS
+// implements it in a build-tagged file (platform_darwin.go, platform_linux.go) and installs it
+// from an init function, so platform-specific paths never leak into other builds.
+type Platform interface {
+	// Name returns the platform's name as reported by runtime.GOOS (e.g., "darwin").
+	Name() string
+	// CleanupTargets returns the system cleanup targets. It is called on every lookup
+	// because category names depend on the active language.
+	CleanupTargets() []CleanupTarget
+	// AppInstallPaths returns the directories searched for application bundles. Platforms without
+	// bundles return none, and only the leftovers of an application are removed.
+	AppInstallPaths() []string
+	// AppLeftoverPatterns returns glob patterns for the data an application leaves behind
+	// (support files, caches, preferences), given its name without a bundle extension and the
+	// identifiers of its installed bundles (e.g., "com.microsoft.VSCode"), if any were found.
+	AppLeftoverPatterns(appName string, bundleIDs []string) []string
+	// AppSettingsDirs returns the directories where applications keep their settings and user
+	// data, which an uninstall with UninstallOptions.KeepPreferences leaves in place.
+	AppSettingsDirs() []string
+	// LargeFileScanRoots returns the directories scanned for large files by default.
+	LargeFileScanRoots() []string
+	// LargeFileIgnorePaths returns paths the large file scan always ignores (e.g., app bundles).
+	LargeFileIgnorePaths() []string
+	// LargeFileCategory returns the summary category of a large file (e.g., "User Downloads").
+	LargeFileCategory(path string) string
+	// ProtectedDirs returns glob patterns for system directories the large file scan never enters.
+	ProtectedDirs() []string
+	// CloudSyncedDirs returns the directories currently synced with a cloud service, where
+	// deletions propagate to the user's other devices (e.g., iCloud Desktop & Documents).
+	CloudSyncedDirs() []string
+	// ExternalVolumeDirs returns the directories external and removable volumes are mounted in
+	// (e.g., /Volumes), which scans only cover with --include-volumes (see ExternalVolumes).
+	ExternalVolumeDirs() []string
+}
+
+// TargetProvider contributes cleanup targets in addition to those of the platform,
+// e.g. from a plugin or an optional feature.
+type TargetProvider interface {
+	CleanupTargets() []CleanupTarget
+}
+
+// TargetProviderFunc adapts an ordinary function to the TargetProvider interface.
+type TargetProviderFunc func() []CleanupTarget
+
+// CleanupTargets calls f.
+func (f TargetProviderFunc) CleanupTargets() []CleanupTarget {
+	return f()
+}
+
+var (
+	// registryMu guards currentPlatform and targetProviders.
+	registryMu sync.Mutex
+	// currentPlatform is the platform wiper is running on. Builds without a platform
+	// implementation fall back to genericPlatform, which only scans the home directory.
+	currentPlatform Platform = genericPlatform{}
+	// targetProviders are the additional target providers, in registration order.
+	targetProviders []TargetProvider
+)
+
+// SetPlatform installs the platform implementation. Platform files call it from init;
+// it can also be used to run wiper against a custom layout.
+func SetPlatform(p Platform) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	currentPlatform = p
+}
+
+// CurrentPlatform returns the installed platform implementation.
+func CurrentPlatform() Platform {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return currentPlatform
+}
+
+// RegisterTargetProvider adds a provider whose targets are cleaned alongside the platform's.
+func RegisterTargetProvider(provider TargetProvider) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	targetProviders = append(targetProviders, provider)
+}
+
+// getCleanupTargets returns the cleanup targets of the current platform followed by those of
+// every registered provider, without the opt-in targets that weren't enabled. Category names are
+// resolved through the i18n catalog, so it must be called after the language is set.
+func getCleanupTargets() []CleanupTarget {
+	var targets []CleanupTarget
+	for _, target := range allCleanupTargets() {
+		if !target.OptIn || enabledTargets[target.ID] {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// allCleanupTargets is getCleanupTargets, including the opt-in targets.
+func allCleanupTargets() []CleanupTarget {
+	registryMu.Lock()
+	platform := currentPlatform
+	providers := append([]TargetProvider(nil), targetProviders...)
+	registryMu.Unlock()
+
+	targets := platform.CleanupTargets()
+	for _, provider := range providers {
+		targets = append(targets, provider.CleanupTargets()...)
+	}
+	return targets
+}
+
+// ====================================================================================================
+// OPT-IN TARGETS
+// ====================================================================================================
+
+// enabledTargets are the IDs of the opt-in targets that are used (see SetEnabledTargets).
+var enabledTargets map[string]bool
+
+// OptInTargets returns the IDs of the opt-in targets of the current platform in sorted order.
+func OptInTargets() []string {
+	var ids []string
+	for _, target := range allCleanupTargets() {
+		if target.OptIn {
+			ids = append(ids, target.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ValidateOptInTargets returns an error naming the first ID that isn't an opt-in target.
+func ValidateOptInTargets(ids []string) error {
+	available := OptInTargets()
+	for _, id := range ids {
+		if !slices.Contains(available, id) {
+			if len(available) == 0 {
+				return fmt.Errorf("unknown opt-in target %q (none on this platform)", id)
+			}
+			return fmt.Errorf("unknown opt-in target %q (available: %s)", id, strings.Join(available, ", "))
+		}
+	}
+	return nil
+}
+
+// SetEnabledTargets adds the opt-in targets with these IDs to the cleanups, and lifts the built-in
+// path protection from their matches (see utils.SetUnprotectedPaths). Like the other category
+// names, theirs are resolved through the i18n catalog, so it must be called after the language is set.
+func SetEnabledTargets(ids []string) {
+	enabledTargets = make(map[string]bool, len(ids))
+	for _, id := range ids {
+		enabledTargets[id] = true
+	}
+	var unprotected []string
+	for _, target := range allCleanupTargets() {
+		if target.OptIn && enabledTargets[target.ID] {
+			unprotected = append(unprotected, target.Paths...)
+		}
+	}
+	utils.SetUnprotectedPaths(unprotected...)
+}
+
+// isProtectedDir reports whether path matches one of the platform's protected directory patterns.
+func isProtectedDir(platform Platform, path string) bool {
+	for _, pattern := range platform.ProtectedDirs() {
+		if matched, err := filepath.Match(pattern, path); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ====================================================================================================
+// GENERIC PLATFORM
+// ====================================================================================================
+
+// genericPlatform is used on operating systems without a dedicated implementation.
+// It has no system cleanup targets or application locations; only the large file scan works.
+type genericPlatform struct{}
+
+func (genericPlatform) Name() string                    { return runtime.GOOS }
+func (genericPlatform) CleanupTargets() []CleanupTarget { return nil }
+func (genericPlatform) AppInstallPaths() []string       { return nil }
+func (genericPlatform) LargeFileIgnorePaths() []string  { return nil }
+func (genericPlatform) ProtectedDirs() []string         { return nil }
+func (genericPlatform) CloudSyncedDirs() []string       { return nil }
+func (genericPlatform) AppSettingsDirs() []string       { return nil }
+func (genericPlatform) ExternalVolumeDirs() []string    { return nil }
+
+func (genericPlatform) AppLeftoverPatterns(appName string, bundleIDs []string) []string { return nil }
+
+func (genericPlatform) LargeFileCategory(path string) string {
+	normalizedPath, _ := tildePath(path)
+	return commonLargeFileCategory(path, normalizedPath)
+}
+
+func (genericPlatform) LargeFileScanRoots() []string {
+	return []string{utils.ExpandPath("~")}
+}
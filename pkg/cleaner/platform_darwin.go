@@ -0,0 +1,194 @@
+//go:build darwin
+
+package cleaner
+
+import (
+	"os"            // Imported for os.Stat
+	"path/filepath" // Imported for filepath.Join
+	"strings"       // Imported for path prefix checks
+
+	"github.com/kodelint/wiper/pkg/utils" // Imported for utils.ExpandPath
+)
+
+// ====================================================================================================
+// PLATFORM (macOS)
+// ====================================================================================================
+
+// init installs the macOS platform.
+func init() {
+	SetPlatform(darwinPlatform{})
+}
+
+// darwinPlatform locates cleanable data on macOS.
+type darwinPlatform struct{}
+
+// Name returns "darwin".
+func (darwinPlatform) Name() string { return "darwin" }
+
+// CleanupTargets returns the macOS cleanup targets (see targets_darwin.go).
+func (darwinPlatform) CleanupTargets() []CleanupTarget { return darwinCleanupTargets() }
+
+// AppInstallPaths returns the common directories where macOS applications are installed.
+func (darwinPlatform) AppInstallPaths() []string {
+	return []string{
+		"/Applications",
+		utils.ExpandPath("$HOME/Applications"),
+	}
+}
+
+// LargeFileScanRoots returns the user and temporary directories scanned for large files.
+func (darwinPlatform) LargeFileScanRoots() []string {
+	return []string{
+		"/Users",
+		"/private/var/folders",
+		"/private/tmp",
+		utils.ExpandPath("$HOME/Downloads"),
+		utils.ExpandPath("$HOME/Documents"),
+	}
+}
+
+// LargeFileIgnorePaths ignores the user's Applications folder to avoid scanning inside app bundles.
+func (darwinPlatform) LargeFileIgnorePaths() []string {
+	return []string{utils.ExpandPath("$HOME/Applications/")}
+}
+
+// ExternalVolumeDirs returns /Volumes, where macOS mounts every volume but the startup disk.
+func (darwinPlatform) ExternalVolumeDirs() []string { return []string{"/Volumes"} }
+
+// ProtectedDirs returns the macOS system directories.
+func (darwinPlatform) ProtectedDirs() []string {
+	return []string{"/System", "/Library", "/usr", "/Applications", "/Developer*"}
+}
+
+// CloudSyncedDirs returns Desktop and Documents when "Desktop & Documents Folders" is enabled in
+// the iCloud Drive settings. macOS then keeps their contents in iCloud Drive, where the folders
+// show up as ~/Library/Mobile Documents/com~apple~CloudDocs/Desktop and .../Documents.
+func (darwinPlatform) CloudSyncedDirs() []string {
+	cloudDocs := utils.ExpandPath("$HOME/Library/Mobile Documents/com~apple~CloudDocs")
+	var synced []string
+	for _, name := range []string{"Desktop", "Documents"} {
+		cloudDir := filepath.Join(cloudDocs, name)
+		if info, err := os.Stat(cloudDir); err == nil && info.IsDir() {
+			synced = append(synced, utils.ExpandPath("$HOME/"+name), cloudDir)
+		}
+	}
+	return synced
+}
+
+// AppLeftoverPatterns returns the Library locations where macOS applications keep their support
+// files, caches, preferences, saved state, and sandbox containers.
+//
+// Preferences, saved state, caches and containers are named after the bundle identifier
+// (e.g., com.microsoft.VSCode.plist for Visual Studio Code). Without an identifier from the
+// bundle's Info.plist (e.g., when the bundle was already deleted), it is guessed from the name.
+func (darwinPlatform) AppLeftoverPatterns(appName string, bundleIDs []string) []string {
+	homeDir := os.Getenv("HOME")
+	patterns := []string{
+		// Common paths for application support, caches, and containers.
+		filepath.Join(homeDir, "Library", "Application Support", appName),
+		filepath.Join(homeDir, "Library", "Caches", appName),
+		filepath.Join(homeDir, "Library", "Containers", "*"+appName+"*"),
+		filepath.Join(homeDir, "Library", "Group Containers", "*"+appName+"*"),
+		// System-wide library paths.
+		filepath.Join("/Library", "Application Support", appName),
+		filepath.Join("/Library", "Caches", appName),
+	}
+
+	if len(bundleIDs) == 0 {
+		// Preferences files often follow a reverse-domain-name convention (e.g., com.google.chrome.plist).
+		bundleIDPrefix := "com." + strings.ToLower(strings.ReplaceAll(appName, " ", "")) + ".*"
+		return append(patterns,
+			filepath.Join(homeDir, "Library", "Preferences", bundleIDPrefix),
+			filepath.Join(homeDir, "Library", "Saved Application State", bundleIDPrefix),
+			filepath.Join("/Library", "Preferences", bundleIDPrefix),
+		)
+	}
+	for _, id := range bundleIDs {
+		patterns = append(patterns,
+			// Preference files (com.microsoft.VSCode.plist) and saved state (com.microsoft.VSCode.savedState).
+			filepath.Join(homeDir, "Library", "Preferences", id+".*"),
+			filepath.Join(homeDir, "Library", "Saved Application State", id+".*"),
+			// Caches of the app and of its helpers (e.g., com.microsoft.VSCode.ShipIt).
+			filepath.Join(homeDir, "Library", "Caches", id),
+			filepath.Join(homeDir, "Library", "Caches", id+".*"),
+			filepath.Join(homeDir, "Library", "Containers", id),
+			filepath.Join("/Library", "Preferences", id+".*"),
+			// Launch agents and daemons of the app and its helpers (unloaded before removal).
+			filepath.Join(homeDir, "Library", "LaunchAgents", id+".plist"),
+			filepath.Join(homeDir, "Library", "LaunchAgents", id+".*.plist"),
+			filepath.Join("/Library", "LaunchAgents", id+".plist"),
+			filepath.Join("/Library", "LaunchAgents", id+".*.plist"),
+			filepath.Join("/Library", "LaunchDaemons", id+".plist"),
+			filepath.Join("/Library", "LaunchDaemons", id+".*.plist"),
+		)
+	}
+	return patterns
+}
+
+// AppSettingsDirs returns the Preferences and Application Support folders of the user and of the system.
+func (darwinPlatform) AppSettingsDirs() []string {
+	homeDir := os.Getenv("HOME")
+	return []string{
+		filepath.Join(homeDir, "Library", "Preferences"),
+		filepath.Join(homeDir, "Library", "Application Support"),
+		filepath.Join("/Library", "Preferences"),
+		filepath.Join("/Library", "Application Support"),
+	}
+}
+
+// LargeFileCategory determines a higher-level, generic category for a given large file path.
+// This helps in creating a clean summary table for the user.
+func (darwinPlatform) LargeFileCategory(path string) string {
+	normalizedPath, ok := tildePath(path)
+	if !ok {
+		if strings.HasPrefix(path, "/private/var") || strings.HasPrefix(path, "/tmp") {
+			return "System Temporary Files"
+		}
+		return "Other Large Files"
+	}
+
+	if strings.Contains(normalizedPath, "~/Library/Application Support/Google/Chrome") ||
+		strings.Contains(normalizedPath, "~/Library/Caches/Google/Chrome") ||
+		strings.Contains(normalizedPath, "~/Library/Application Support/BraveSoftware/Brave-Browser") ||
+		strings.Contains(normalizedPath, "~/Library/Caches/com.apple.Safari") ||
+		strings.Contains(normalizedPath, "~/Library/Application Support/Firefox") {
+		return "Browser Caches"
+	}
+
+	if strings.HasPrefix(normalizedPath, "~/Downloads") {
+		return "User Downloads"
+	}
+	if strings.HasPrefix(normalizedPath, "~/Documents") {
+		return "User Documents"
+	}
+
+	if strings.HasPrefix(normalizedPath, "~/Library/Application Support") {
+		return "Application Support Files"
+	}
+	if strings.HasPrefix(normalizedPath, "~/Library/Caches") {
+		return "User Caches"
+	}
+	if strings.HasPrefix(normalizedPath, "~/Library/Containers") {
+		return "User Container Data"
+	}
+	if strings.HasPrefix(normalizedPath, "~/Library/Group Containers") {
+		return "User Group Container Data"
+	}
+	if strings.HasPrefix(normalizedPath, "~/Library/Messages/Attachments") {
+		return "Messages Attachments"
+	}
+	if strings.HasPrefix(normalizedPath, "~/Library/Metadata/CoreSpotlight") {
+		return "Spotlight Metadata"
+	}
+	if strings.HasPrefix(normalizedPath, "~/Library") {
+		return "Other User Library Files"
+	}
+
+	if strings.HasPrefix(path, "/private/var/folders") ||
+		strings.HasPrefix(path, "/private/var/tmp") ||
+		strings.HasPrefix(path, "/tmp") {
+		return "System Temporary Files"
+	}
+
+	return commonLargeFileCategory(path, normalizedPath)
+}
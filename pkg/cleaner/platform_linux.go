@@ -0,0 +1,151 @@
+//go:build linux
+
+package cleaner
+
+import (
+	"path/filepath" // Imported for filepath.Join
+	"slices"        // Imported for slices.Contains
+	"strings"       // Imported for name variants and path prefix checks
+
+	"github.com/kodelint/wiper/pkg/utils" // Imported for utils.ExpandPath
+)
+
+// ====================================================================================================
+// PLATFORM (Linux)
+// ====================================================================================================
+
+// init installs the Linux platform.
+func init() {
+	SetPlatform(linuxPlatform{})
+}
+
+// linuxPlatform locates cleanable data on Linux.
+type linuxPlatform struct{}
+
+// Name returns "linux".
+func (linuxPlatform) Name() string { return "linux" }
+
+// CleanupTargets returns the Linux cleanup targets (see targets_linux.go).
+func (linuxPlatform) CleanupTargets() []CleanupTarget { return linuxCleanupTargets() }
+
+// AppInstallPaths returns no directories: Linux applications are managed by package managers
+// rather than installed as self-contained bundles.
+func (linuxPlatform) AppInstallPaths() []string { return nil }
+
+// LargeFileScanRoots returns the user's home and the temporary directories scanned for large files.
+func (linuxPlatform) LargeFileScanRoots() []string {
+	return []string{
+		utils.ExpandPath("~"),
+		"/tmp",
+		"/var/tmp",
+	}
+}
+
+// LargeFileIgnorePaths returns no paths; Linux has no application bundles to skip.
+func (linuxPlatform) LargeFileIgnorePaths() []string { return nil }
+
+// CloudSyncedDirs returns no directories; sync clients on Linux don't take over the standard folders.
+func (linuxPlatform) CloudSyncedDirs() []string { return nil }
+
+// ExternalVolumeDirs returns where desktops (udisks) and administrators mount external drives.
+func (linuxPlatform) ExternalVolumeDirs() []string {
+	return []string{"/media", "/run/media", "/mnt"}
+}
+
+// ProtectedDirs returns virtual file systems and directories owned by the package manager.
+func (linuxPlatform) ProtectedDirs() []string {
+	return []string{"/proc", "/sys", "/dev", "/run", "/boot", "/usr", "/snap"}
+}
+
+// AppLeftoverPatterns returns the XDG locations where applications keep their configuration,
+// caches, data, and state, plus Flatpak data and the user's desktop launchers. Directories are
+// tried with the name as given and in the lowercase, dash-separated form most programs use
+// (e.g., "Visual Studio Code" and "visual-studio-code"). Linux applications have no bundle
+// identifiers, so bundleIDs is not used.
+func (linuxPlatform) AppLeftoverPatterns(appName string, bundleIDs []string) []string {
+	homeDir := utils.ExpandPath("~")
+	configDir := xdgDir("XDG_CONFIG_HOME", filepath.Join(homeDir, ".config"))
+	cacheDir := xdgDir("XDG_CACHE_HOME", filepath.Join(homeDir, ".cache"))
+	dataDir := xdgDir("XDG_DATA_HOME", filepath.Join(homeDir, ".local", "share"))
+	stateDir := xdgDir("XDG_STATE_HOME", filepath.Join(homeDir, ".local", "state"))
+
+	var patterns []string
+	for _, name := range linuxAppNames(appName) {
+		patterns = append(patterns,
+			filepath.Join(configDir, name),
+			filepath.Join(cacheDir, name),
+			filepath.Join(dataDir, name),
+			filepath.Join(stateDir, name),
+			// Flatpak keeps each application's data under its ID (e.g., ~/.var/app/com.spotify.Client).
+			filepath.Join(homeDir, ".var", "app", "*."+name),
+			filepath.Join(dataDir, "applications", name+".desktop"),
+		)
+	}
+	return patterns
+}
+
+// AppSettingsDirs returns the XDG configuration and data directories.
+func (linuxPlatform) AppSettingsDirs() []string {
+	homeDir := utils.ExpandPath("~")
+	return []string{
+		xdgDir("XDG_CONFIG_HOME", filepath.Join(homeDir, ".config")),
+		xdgDir("XDG_DATA_HOME", filepath.Join(homeDir, ".local", "share")),
+	}
+}
+
+// linuxAppNames returns the distinct spellings under which an application's directories are
+// looked up: the name as given, lowercase, and lowercase with spaces replaced by dashes.
+func linuxAppNames(appName string) []string {
+	lower := strings.ToLower(appName)
+	names := []string{appName}
+	for _, variant := range []string{lower, strings.ReplaceAll(lower, " ", "-")} {
+		if !slices.Contains(names, variant) {
+			names = append(names, variant)
+		}
+	}
+	return names
+}
+
+// LargeFileCategory categorizes a large file by the XDG base directories and the common
+// locations of a Linux home, falling back to the categories shared by all platforms.
+func (linuxPlatform) LargeFileCategory(path string) string {
+	homeDir := utils.ExpandPath("~")
+	cacheDir := xdgDir("XDG_CACHE_HOME", filepath.Join(homeDir, ".cache"))
+	configDir := xdgDir("XDG_CONFIG_HOME", filepath.Join(homeDir, ".config"))
+	dataDir := xdgDir("XDG_DATA_HOME", filepath.Join(homeDir, ".local", "share"))
+	normalizedPath, _ := tildePath(path)
+
+	for _, browserDir := range []string{
+		filepath.Join(cacheDir, "google-chrome"),
+		filepath.Join(cacheDir, "chromium"),
+		filepath.Join(cacheDir, "mozilla"),
+		filepath.Join(cacheDir, "BraveSoftware"),
+		filepath.Join(homeDir, ".mozilla"),
+		filepath.Join(configDir, "google-chrome"),
+		filepath.Join(configDir, "chromium"),
+	} {
+		if utils.ContainsPath(path, []string{browserDir}) {
+			return "Browser Caches"
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(normalizedPath, "~/Downloads"):
+		return "User Downloads"
+	case strings.HasPrefix(normalizedPath, "~/Documents"):
+		return "User Documents"
+	case utils.ContainsPath(path, []string{cacheDir}):
+		return "User Caches"
+	case utils.ContainsPath(path, []string{filepath.Join(dataDir, "Trash")}):
+		return "Trash"
+	case utils.ContainsPath(path, []string{filepath.Join(homeDir, ".var", "app")}):
+		return "Flatpak Application Data"
+	case utils.ContainsPath(path, []string{dataDir}):
+		return "Application Data"
+	case utils.ContainsPath(path, []string{configDir}):
+		return "Application Configuration"
+	case strings.HasPrefix(path, "/tmp/") || strings.HasPrefix(path, "/var/tmp/"):
+		return "System Temporary Files"
+	}
+	return commonLargeFileCategory(path, normalizedPath)
+}
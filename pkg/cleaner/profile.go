@@ -0,0 +1,132 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// CLEANUP PROFILES
+// ====================================================================================================
+
+// cleanupProfiles maps a profile name to the IDs of the cleanup targets it covers.
+// Profiles allow non-interactive frontends (like the web dashboard) to trigger a well-defined,
+// conservative subset of the system cleanup without prompting on the terminal.
+var cleanupProfiles = map[string][]string{
+	// safe only covers data that applications and macOS regenerate on demand.
+	"safe": {"user_temp", "system_temp", "user_caches", "browser_caches"},
+	// full covers every system cleanup target registered for the platform, including Trash and old downloads.
+	"full": nil,
+}
+
+// Profiles returns the names of all available cleanup profiles in sorted order.
+func Profiles() []string {
+	names := make([]string, 0, len(cleanupProfiles))
+	for name := range cleanupProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ====================================================================================================
+// PLANNING AND EXECUTION
+// ====================================================================================================
+
+// EstimateSystem scans all system cleanup targets and registered cleaners without deleting or printing anything.
+// It returns a SummaryTable with one (not removed) entry per item that a cleanup would remove.
+//
+// Parameters:
+//   - ctx: Stops the scan when cancelled.
+//   - ignorePaths: A list of paths to explicitly exclude from the estimate.
+//
+// Returns:
+//   - The estimated summary and an error, if any.
+func EstimateSystem(ctx context.Context, ignorePaths []string) (*reclaimer.SummaryTable, error) {
+	estimate := reclaimer.NewSummaryTable()
+	expandedIgnorePaths := expandIgnorePaths(ignorePaths)
+	system := &systemCleaner{targets: getCleanupTargets(), ignorePaths: expandedIgnorePaths, skipped: estimate}
+	items, err := system.scanItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registered, err := scanRegistered(ctx, expandedIgnorePaths, estimate)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, registered...)
+	for _, item := range items {
+		estimate.AddEstimated(item.ActualPath, item.Size, item.Category)
+	}
+	return estimate, nil
+}
+
+// CleanProfile removes every item matched by the targets of the named profile without prompting.
+// Callers are responsible for obtaining the user's consent before invoking it.
+//
+// Parameters:
+//   - ctx: Cancels the scan, or the cleanup between items.
+//   - profile: The name of the profile to run (see Profiles).
+//   - dryRun: A boolean flag for dry-run mode (no files are actually deleted).
+//   - ignorePaths: A list of paths to explicitly exclude from deletion.
+//   - summary: A pointer to a SummaryTable to record processed items and their sizes.
+//
+// Returns:
+//   - The total space reclaimed (or estimated, in dry-run mode) in bytes and an error, if any.
+func CleanProfile(ctx context.Context, profile string, dryRun bool, ignorePaths []string, summary *reclaimer.SummaryTable) (int64, error) {
+	targetIDs, ok := cleanupProfiles[profile]
+	if !ok {
+		return 0, fmt.Errorf("unknown cleanup profile %q", profile)
+	}
+
+	wanted := make(map[string]bool, len(targetIDs))
+	for _, id := range targetIDs {
+		wanted[id] = true
+	}
+	var profileTargets []CleanupTarget
+	for _, target := range getCleanupTargets() {
+		// A profile without target IDs covers every target.
+		if targetIDs == nil || wanted[target.ID] {
+			profileTargets = append(profileTargets, target)
+		}
+	}
+
+	logger.Log.Infof("Running cleanup profile '%s' (dryRun: %t)", profile, dryRun)
+	items, err := scanTargets(ctx, profileTargets, expandIgnorePaths(ignorePaths), summary)
+	if err != nil {
+		return 0, err
+	}
+
+	if dryRun {
+		var estimated int64
+		for _, item := range items {
+			estimated += item.Size
+			summary.AddEstimated(item.ActualPath, item.Size, item.Category)
+		}
+		history.Add(len(items), estimated, 0)
+		return estimated, nil
+	}
+	removedBefore, failedBefore := len(summary.ByStatus(reclaimer.StatusRemoved)), summary.FailedCount()
+	reclaimed := removeItems(ctx, items, summary)
+	history.Add(len(summary.ByStatus(reclaimer.StatusRemoved))-removedBefore, reclaimed, summary.FailedCount()-failedBefore)
+	return reclaimed, ctx.Err()
+}
+
+// ====================================================================================================
+// HELPER FUNCTIONS
+// ====================================================================================================
+
+// expandIgnorePaths expands `~` and environment variables in every ignore path once upfront.
+func expandIgnorePaths(ignorePaths []string) []string {
+	var expandedIgnorePaths []string
+	for _, p := range ignorePaths {
+		expandedIgnorePaths = append(expandedIgnorePaths, utils.ExpandIgnorePath(p))
+	}
+	return expandedIgnorePaths
+}
@@ -0,0 +1,239 @@
+package cleaner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// INSTALLER PACKAGE RECEIPTS
+// ====================================================================================================
+
+// packageFilesCategory is the summary table category of files installed by an installer package.
+const packageFilesCategory = "Installer Package Files"
+
+// packageReceipt is an installer package (.pkg) that macOS keeps a receipt for, with the files it
+// installed that are still on disk.
+type packageReceipt struct {
+	// ID is the package identifier (e.g., "com.docker.docker").
+	ID string
+	// Files are the absolute paths of the installed files that still exist.
+	Files []string
+}
+
+// systemPathPrefixes are locations whose files are never removed on behalf of a package receipt,
+// even if a third-party package claims to have installed them.
+var systemPathPrefixes = []string{"/System/", "/bin/", "/sbin/", "/usr/bin/", "/usr/lib/", "/usr/libexec/", "/usr/sbin/", "/usr/share/"}
+
+// findPackageReceipts returns the receipts of the installer packages that installed an application:
+// those that installed one of its bundles (`pkgutil --file-info`), and those whose identifier starts
+// with one of its bundle identifiers. Apple's own packages are never returned. Files inside the
+// bundles are left out, since the bundles are removed as a whole, and so are files inside other
+// bundles and system locations, and files that another package installed too.
+//
+// Returns:
+//   - The receipts, or none if pkgutil is not available (e.g., on Linux).
+func findPackageReceipts(bundlePaths []string, bundleIDs []string) []packageReceipt {
+	if _, err := exec.LookPath("pkgutil"); err != nil {
+		return nil
+	}
+
+	var ids []string
+	add := func(id string) {
+		if id != "" && !strings.HasPrefix(id, "com.apple.pkg.") && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	for _, bundlePath := range bundlePaths {
+		out, err := pkgutilOutput("--file-info", bundlePath)
+		if err != nil {
+			logger.Log.Debugf("pkgutil --file-info %s: %v", bundlePath, err)
+			continue
+		}
+		for _, id := range pkgutilFields(out, "pkgid") {
+			add(id)
+		}
+	}
+	if len(bundleIDs) > 0 {
+		out, err := pkgutilOutput("--pkgs")
+		if err != nil {
+			logger.Log.Debugf("pkgutil --pkgs: %v", err)
+		}
+		for _, id := range strings.Fields(out) {
+			for _, bundleID := range bundleIDs {
+				if id == bundleID || strings.HasPrefix(id, bundleID+".") {
+					add(id)
+				}
+			}
+		}
+	}
+
+	var receipts []packageReceipt
+	for _, id := range ids {
+		files, err := packageFiles(id)
+		if err != nil {
+			logger.Log.Debugf("Could not list the files of package %s: %v", id, err)
+			continue
+		}
+		var remaining []string
+		for _, file := range files {
+			// Packages that install several apps (e.g., an office suite) keep the other apps.
+			if withinAny(file, bundlePaths) || isSystemPath(file) || strings.Contains(file, ".app/") {
+				continue
+			}
+			if _, err := os.Lstat(file); err != nil {
+				continue
+			}
+			// Files of shared frameworks and helpers are often installed by several packages.
+			if owner, err := otherPackageOwner(file, ids); err != nil || owner != "" {
+				if err != nil {
+					logger.Log.Debugf("Keeping %s; its packages are unknown: %v", file, err)
+				} else {
+					logger.Log.Debugf("Keeping %s; it was also installed by package %s", file, owner)
+				}
+				continue
+			}
+			remaining = append(remaining, file)
+		}
+		logger.Log.Debugf("Package %s has %d of %d files left outside the app bundle", id, len(remaining), len(files))
+		receipts = append(receipts, packageReceipt{ID: id, Files: remaining})
+	}
+	return receipts
+}
+
+// packageReceiptItems turns the files of receipts into cleanup items. Files that were already
+// collected (in seen) are left out, and ignored files are recorded as skipped.
+func packageReceiptItems(receipts []packageReceipt, seen map[string]bool, ignorePaths []string, estimatedSummary *reclaimer.SummaryTable) []cleanupItem {
+	var items []cleanupItem
+	for _, receipt := range receipts {
+		for _, file := range receipt.Files {
+			if seen[file] {
+				continue
+			}
+			seen[file] = true
+			if utils.IsPathIgnored(file, ignorePaths) {
+				logger.Log.Debugf(utils.Yellow("Skipping ignored package file: %s"), file)
+				estimatedSummary.AddSkippedReason(file, 0, packageFilesCategory, reclaimer.SkipReasonIgnored)
+				continue
+			}
+			size, err := utils.GetFileSizeInBytes(file)
+			if err != nil {
+				logger.RunWarnings.Add(packageFilesCategory, reclaimer.SkipReasonForError(err), file, err)
+				continue
+			}
+			items = append(items, cleanupItem{
+				Path:       file,
+				Size:       size,
+				Category:   packageFilesCategory,
+				ActualPath: file,
+			})
+		}
+	}
+	return items
+}
+
+// packageFiles returns the absolute paths of the files (not directories) a package installed.
+// Paths in the receipt are relative to the volume and install location of the package.
+func packageFiles(id string) ([]string, error) {
+	info, err := pkgutilOutput("--pkg-info", id)
+	if err != nil {
+		return nil, err
+	}
+	base := "/"
+	if volume := pkgutilFields(info, "volume"); len(volume) > 0 {
+		base = volume[0]
+	}
+	if location := pkgutilFields(info, "location"); len(location) > 0 {
+		base = filepath.Join(base, location[0])
+	}
+
+	out, err := pkgutilOutput("--files", id, "--only-files")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, rel := range strings.Split(out, "\n") {
+		if rel = strings.TrimSpace(rel); rel != "" {
+			files = append(files, filepath.Join(base, rel))
+		}
+	}
+	return files, nil
+}
+
+// otherPackageOwner returns a package that installed file besides the packages in ids, according to
+// `pkgutil --file-info`, or "" if there is none.
+func otherPackageOwner(file string, ids []string) (string, error) {
+	out, err := pkgutilOutput("--file-info", file)
+	if err != nil {
+		return "", err
+	}
+	for _, id := range pkgutilFields(out, "pkgid") {
+		if !slices.Contains(ids, id) {
+			return id, nil
+		}
+	}
+	return "", nil
+}
+
+// forgetPackageReceipts removes the receipts whose files are all gone, so `pkgutil --pkgs` no longer
+// lists the packages. Forgetting a receipt needs root; failures are reported with the run's warnings.
+func forgetPackageReceipts(receipts []packageReceipt) {
+	for _, receipt := range receipts {
+		if slices.ContainsFunc(receipt.Files, func(file string) bool { _, err := os.Lstat(file); return err == nil }) {
+			logger.Log.Debugf("Keeping the receipt of %s; some of its files are still installed", receipt.ID)
+			continue
+		}
+		if _, err := pkgutilOutput("--forget", receipt.ID); err != nil {
+			logger.RunWarnings.Add(packageFilesCategory, "receipt not forgotten", receipt.ID, err)
+			continue
+		}
+		logger.Log.Infof(utils.Cyan("Forgot the installer package receipt %s"), receipt.ID)
+	}
+}
+
+// isSystemPath reports whether path is inside one of the systemPathPrefixes.
+func isSystemPath(path string) bool {
+	for _, prefix := range systemPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// pkgutilFields returns the values of the "name: value" lines with the given name in pkgutil output.
+func pkgutilFields(out string, name string) []string {
+	var values []string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), name+":"); ok {
+			values = append(values, strings.TrimSpace(value))
+		}
+	}
+	return values
+}
+
+// pkgutilOutput runs pkgutil with the given arguments and returns its standard output.
+func pkgutilOutput(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("pkgutil", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("pkgutil %s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("pkgutil %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
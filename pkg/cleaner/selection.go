@@ -0,0 +1,65 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/tui"
+)
+
+// ====================================================================================================
+// SELECTION LIST MODE
+// ====================================================================================================
+
+// selectionUI lets the user pick the items to remove from a checkbox list (--tui), instead of
+// answering yes/no prompts.
+var selectionUI bool
+
+// SetSelectionUI controls whether cleanups show detected items in a checkbox list to pick from.
+// It replaces the confirmation prompts of the system, large files, goal and volume Trash cleanups;
+// application uninstalls, which are confirmed upfront, are not affected.
+func SetSelectionUI(enabled bool) {
+	selectionUI = enabled
+}
+
+// selectAndRemove shows items in the selection list and removes the ones the user kept selected.
+// Deselected items are recorded as skipped.
+//
+// Returns:
+//   - The number of bytes reclaimed and an error if the list couldn't be shown or ctx was cancelled.
+func selectAndRemove(ctx context.Context, items []cleanupItem, summary *reclaimer.SummaryTable, title string) (int64, error) {
+	listItems := make([]tui.Item, len(items))
+	for i, item := range items {
+		listItems[i] = tui.Item{Label: item.ActualPath, Group: item.Category, Size: item.Size}
+	}
+	selected, err := tui.Select(ctx, title, listItems)
+	if ctx.Err() != nil {
+		skipCancelled(items, summary)
+		return 0, ctx.Err()
+	}
+	if errors.Is(err, tui.ErrAborted) {
+		logger.Log.Info("Cleanup cancelled by user.")
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to show the selection list: %w", err)
+	}
+
+	var chosen []cleanupItem
+	for i, item := range items {
+		if selected[i] {
+			chosen = append(chosen, item)
+		} else {
+			summary.AddSkipped(item.ActualPath, item.Size, item.Category)
+		}
+	}
+	if len(chosen) == 0 {
+		logger.Log.Info("No items were selected.")
+		return 0, nil
+	}
+	logger.Log.Infof("Removing %d of %d items...", len(chosen), len(items))
+	return removeItems(ctx, chosen, summary), ctx.Err()
+}
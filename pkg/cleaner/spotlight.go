@@ -0,0 +1,166 @@
+package cleaner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// SPOTLIGHT LARGE FILE BACKEND
+// ====================================================================================================
+
+// spotlightLargeFiles asks the Spotlight index for the files below root whose size is at least
+// threshold bytes. It returns false when the index can't be trusted for root: mdfind is not
+// available, indexing is disabled for the volume, or root has entries but none of them are indexed
+// (e.g., temporary directories that Spotlight excludes). The caller then walks the tree instead.
+func spotlightLargeFiles(root string, threshold int64) ([]string, bool) {
+	mdfind, err := exec.LookPath("mdfind")
+	if err != nil {
+		return nil, false
+	}
+	if mdutil, err := exec.LookPath("mdutil"); err == nil {
+		out, err := exec.Command(mdutil, "-s", root).CombinedOutput()
+		if err != nil || bytes.Contains(bytes.ToLower(out), []byte("disabled")) {
+			logger.Log.Debugf("Spotlight indexing is not enabled for %s: %s", root, strings.TrimSpace(string(out)))
+			return nil, false
+		}
+	}
+
+	// An empty result is only meaningful when the index knows about root's contents at all.
+	out, err := exec.Command(mdfind, "-onlyin", root, "-count", "kMDItemFSName == '*'").Output()
+	if err != nil {
+		return nil, false
+	}
+	indexed, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return nil, false
+	}
+	if indexed == 0 {
+		if entries, err := os.ReadDir(root); err != nil || len(entries) > 0 {
+			return nil, false
+		}
+	}
+
+	query := fmt.Sprintf("kMDItemFSSize >= %d", threshold)
+	out, err = exec.Command(mdfind, "-0", "-onlyin", root, query).Output()
+	if err != nil {
+		logger.Log.Debugf("Spotlight query %q failed for %s: %v", query, root, err)
+		return nil, false
+	}
+	var paths []string
+	for _, path := range strings.Split(string(out), "\x00") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, true
+}
+
+// excludedLargeFileAncestor applies the checks the filesystem walk makes on the way down to a file
+// found through Spotlight: ignored paths, and protected or tagged directories between root and the
+// file. It returns the skip reason, or an empty string if the file may be cleaned.
+func excludedLargeFileAncestor(platform Platform, root, path string, ignorePaths []string) string {
+	if utils.IsPathIgnored(path, ignorePaths) {
+		return reclaimer.SkipReasonIgnored
+	}
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if isProtectedDir(platform, dir) {
+			return reclaimer.SkipReasonProtected
+		}
+		if isTagProtected(dir) {
+			return reclaimer.SkipReasonTagged
+		}
+		if dir == root || dir == filepath.Dir(dir) {
+			return ""
+		}
+	}
+}
+
+// ====================================================================================================
+// SPOTLIGHT LEFTOVER DISCOVERY
+// ====================================================================================================
+
+// spotlightLeftovers asks the Spotlight index for files anywhere on disk that belong to the
+// applications with the given bundle identifiers: copies of the app (kMDItemCFBundleIdentifier),
+// and files and folders named after an identifier (e.g., com.microsoft.VSCode.plist or
+// com.microsoft.VSCode.ShipIt). Items inside exclude (the bundles being removed) or below a protected
+// directory (the well-known Library locations are covered by the leftover patterns) are left out,
+// as are items inside another result.
+//
+// Returns:
+//   - The paths found, sorted, and false if mdfind is not available.
+func spotlightLeftovers(platform Platform, bundleIDs []string, exclude []string) ([]string, bool) {
+	mdfind, err := exec.LookPath("mdfind")
+	if err != nil {
+		return nil, false
+	}
+
+	var found []string
+	for _, id := range bundleIDs {
+		queries := []string{
+			fmt.Sprintf("kMDItemCFBundleIdentifier == %q", id),
+			// The name is the identifier itself or starts with it and a dot, so longer identifiers
+			// (com.example.AppHelper for com.example.App) aren't matched.
+			fmt.Sprintf("kMDItemFSName == %q || kMDItemFSName == %q", id, id+".*"),
+		}
+		for _, query := range queries {
+			out, err := exec.Command(mdfind, "-0", query).Output()
+			if err != nil {
+				logger.Log.Debugf("Spotlight query %q failed: %v", query, err)
+				continue
+			}
+			for _, path := range strings.Split(string(out), "\x00") {
+				if path = strings.TrimSpace(path); path == "" {
+					continue
+				}
+				// The name is checked again, independently of how mdfind interprets the query.
+				name := filepath.Base(path)
+				if strings.HasPrefix(query, "kMDItemFSName") && name != id && !strings.HasPrefix(name, id+".") {
+					continue
+				}
+				found = append(found, path)
+			}
+		}
+	}
+
+	sort.Strings(found)
+	var leftovers []string
+	for _, path := range slices.Compact(found) {
+		if withinAny(path, exclude) || withinAny(path, leftovers) || belowProtectedDir(platform, path) {
+			continue
+		}
+		leftovers = append(leftovers, path)
+	}
+	return leftovers, true
+}
+
+// belowProtectedDir reports whether one of the parent directories of path is protected.
+func belowProtectedDir(platform Platform, path string) bool {
+	for dir := filepath.Dir(path); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if isProtectedDir(platform, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// withinAny reports whether path is one of dirs or inside one of them.
+func withinAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
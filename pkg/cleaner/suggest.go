@@ -0,0 +1,148 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kodelint/wiper/pkg/i18n"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// APPLICATION NAME SUGGESTIONS
+// ====================================================================================================
+
+// maxSuggestions is the number of similar application names offered when a name matches nothing.
+const maxSuggestions = 5
+
+// suggestApplications returns the installed applications whose names are similar to name, best
+// match first. Names match case-insensitively when they contain name, or when name is only a few
+// typos away from the whole name, its start, or one of its words (e.g., "Chorme" for "Google Chrome").
+//
+// Returns:
+//   - The bundle names including the ".app" suffix (e.g., "Google Chrome.app").
+func suggestApplications(installPaths []string, name string) []string {
+	query := strings.ToLower(strings.TrimSuffix(name, ".app"))
+	if query == "" {
+		return nil
+	}
+	// Allow roughly one typo per three characters.
+	maxDistance := max(1, len([]rune(query))/3)
+
+	scores := make(map[string]int)
+	for _, dir := range installPaths {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.app"))
+		for _, match := range matches {
+			bundle := filepath.Base(match)
+			appName := strings.ToLower(strings.TrimSuffix(bundle, ".app"))
+			score := editDistance(query, appName)
+			if strings.Contains(appName, query) {
+				score = 0
+			}
+			// The start of the name catches typos in a name that was typed only partly ("Visual Studo").
+			if runes := []rune(appName); len(runes) > len([]rune(query)) {
+				score = min(score, editDistance(query, string(runes[:len([]rune(query))])))
+			}
+			for _, word := range strings.Fields(appName) {
+				score = min(score, editDistance(query, word))
+			}
+			if score > maxDistance {
+				continue
+			}
+			if previous, ok := scores[bundle]; !ok || score < previous {
+				scores[bundle] = score
+			}
+		}
+	}
+
+	suggestions := make([]string, 0, len(scores))
+	for bundle := range scores {
+		suggestions = append(suggestions, bundle)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if scores[suggestions[i]] != scores[suggestions[j]] {
+			return scores[suggestions[i]] < scores[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+// pickSuggestion offers the applications similar to name when it matched nothing: a yes/no question
+// for a single suggestion, or a numbered list to pick from. Without a terminal to answer on, the
+// suggestions are only listed.
+//
+// Returns:
+//   - The picked bundle name (e.g., "Google Chrome.app"), or an empty string if none was picked.
+func pickSuggestion(ctx context.Context, installPaths []string, name string) string {
+	suggestions := suggestApplications(installPaths, name)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	// An unattended run (--yes) must not uninstall an app the user didn't name.
+	if !utils.IsTerminal(os.Stdin) || assumeYes {
+		logger.Log.Infof(utils.Yellow("Did you mean: %s?"), strings.Join(suggestions, ", "))
+		return ""
+	}
+	if len(suggestions) == 1 {
+		if ConfirmAction(ctx, i18n.T("prompt.did_you_mean", suggestions[0])) {
+			return suggestions[0]
+		}
+		return ""
+	}
+
+	fmt.Println(i18n.T("prompt.did_you_mean_one_of"))
+	for i, suggestion := range suggestions {
+		fmt.Printf("  %d) %s\n", i+1, suggestion)
+	}
+	for {
+		fmt.Printf("%s ", i18n.T("prompt.pick_app", len(suggestions)))
+		input, err := readAnswer(ctx)
+		if err != nil || input == "" {
+			println("")
+			return ""
+		}
+		if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(suggestions) {
+			println("")
+			return suggestions[n-1]
+		}
+		fmt.Println(i18n.T("prompt.invalid_pick", len(suggestions)))
+	}
+}
+
+// editDistance returns the number of single-character insertions, deletions, substitutions and
+// transpositions of adjacent characters needed to turn a into b.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// rows[i][j] is the distance between the first i runes of s and the first j runes of t.
+	rows := make([][]int, len(s)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(t)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(s)][len(t)]
+}
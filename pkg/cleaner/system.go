@@ -1,7 +1,9 @@
 package cleaner
 
 import (
+	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -16,116 +18,332 @@ import (
 // SYSTEM CLEANUP FUNCTION
 // ====================================================================================================
 
-// CleanSystem performs a comprehensive system cleanup on macOS.
+// SystemOptions configures a system cleanup.
+// The zero value uses every target with its built-in settings.
+type SystemOptions struct {
+	// MinAge raises the minimum age of every target to at least this value,
+	// so only items older than MinAge are cleaned. 0 keeps the per-target defaults.
+	MinAge time.Duration
+	// PerCategory asks for confirmation once per category instead of once for everything.
+	PerCategory bool
+}
+
+// confirmMode returns how the system cleanup asks before deleting.
+func (opts SystemOptions) confirmMode() confirmMode {
+	if opts.PerCategory {
+		return confirmEachCategory
+	}
+	return confirmOnce
+}
+
+// CleanSystem performs a comprehensive system cleanup using the targets registered for the current platform.
 // It removes temporary files, caches, and other junk files based on predefined targets.
 //
 // Parameters:
+//   - ctx: Cancels the scan, or the cleanup between items (see processCleanupItems).
 //   - dryRun: A boolean flag for dry-run mode (no files are actually deleted).
 //   - ignorePaths: A list of paths to explicitly exclude from deletion.
 //   - summary: A pointer to a SummaryTable to record deleted items and their sizes.
 //   - estimatedSummary: A pointer to a SummaryTable to record items found during a dry run.
+//   - opts: Additional settings such as a minimum age override; see SystemOptions.
 //
 // Returns:
-//   - The total space reclaimed in bytes and an error, if any.
-func CleanSystem(dryRun bool, ignorePaths []string, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable) (int64, error) {
-	logger.Log.Debugf(utils.Cyan("Starting system cleanup..."))
-	// getCleanupTargets() is assumed to be defined elsewhere and returns a slice of CleanupTarget structs.
-	cleanupTargets := getCleanupTargets() // Get cleanup targets from the dedicated function
+//   - The total space reclaimed in bytes and an error, if any. An interrupted cleanup returns
+//     the space reclaimed before it stopped together with ctx's error.
+func CleanSystem(ctx context.Context, dryRun bool, ignorePaths []string, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable, opts SystemOptions) (int64, error) {
+	logger.Log.Debug(utils.Cyan("Starting system cleanup..."))
 
 	// Pre-process ignorePaths to expand environment variables like ~ and $HOME once upfront.
-	var expandedIgnorePaths []string
-	for _, p := range ignorePaths {
-		expandedIgnorePaths = append(expandedIgnorePaths, utils.ExpandPath(p))
+	expandedIgnorePaths := expandIgnorePaths(ignorePaths)
+
+	// Collect all potential items to process as cleanupItems, followed by those of the registered cleaners.
+	// Scan warnings are collected in logger.RunWarnings and reported after the summary tables.
+	system := &systemCleaner{targets: systemTargets(opts), ignorePaths: expandedIgnorePaths, skipped: estimatedSummary}
+	itemsToProcess, err := system.scanItems(ctx)
+	if err != nil {
+		return 0, err
+	}
+	registered, err := scanRegistered(ctx, expandedIgnorePaths, estimatedSummary)
+	if err != nil {
+		return 0, err
+	}
+	itemsToProcess = append(itemsToProcess, registered...)
+
+	// Call the generic processCleanupItems function to handle the deletion logic.
+	// System cleanup is not interactive by default.
+	reclaimed, err := processCleanupItems(ctx,
+		itemsToProcess,
+		dryRun,
+		opts.confirmMode(),
+		summary,
+		estimatedSummary,
+		"Folders that would be cleaned")
+	if err != nil {
+		return reclaimed, fmt.Errorf("failed to process system cleanup: %w", err)
+	}
+
+	return reclaimed, nil
+}
+
+// systemTargets returns the cleanup targets of the current platform with opts applied.
+func systemTargets(opts SystemOptions) []CleanupTarget {
+	cleanupTargets := getCleanupTargets()
+
+	// Apply the minimum age override as a floor, so targets with a longer default keep it.
+	if opts.MinAge > 0 {
+		logger.Log.Debugf("Minimum age override: %s", opts.MinAge)
+		for i := range cleanupTargets {
+			if cleanupTargets[i].MinAge < opts.MinAge {
+				cleanupTargets[i].MinAge = opts.MinAge
+			}
+		}
 	}
+	return cleanupTargets
+}
+
+// ====================================================================================================
+// TARGET SCANNING
+// ====================================================================================================
 
-	showWarnings := os.Getenv("WIPER_SHOW_WARNINGS") == "true"
-	var suppressedWarnings bool // To track if any warnings were suppressed
+// systemCleaner is the Cleaner of the system cleanup: it finds the matches of cleanup targets.
+type systemCleaner struct {
+	targets []CleanupTarget
+	// ignorePaths have already been expanded with utils.ExpandPath.
+	ignorePaths []string
+	// skipped receives the excluded paths; nil discards them.
+	skipped *reclaimer.SummaryTable
+}
+
+// Name returns "system".
+func (c *systemCleaner) Name() string { return "system" }
+
+// Description says what the system cleanup removes.
+func (c *systemCleaner) Description() string {
+	return fmt.Sprintf("Temporary files, caches, logs and other junk (%d targets)", len(c.targets))
+}
+
+// Scan returns the matches of the cleanup targets.
+func (c *systemCleaner) Scan(ctx context.Context) ([]Item, error) { return scanBuiltin(ctx, c) }
+
+// scanItems collects the matches of the cleanup targets as cleanupItems (see scanTargets).
+func (c *systemCleaner) scanItems(ctx context.Context) ([]cleanupItem, error) {
+	skipped := c.skipped
+	if skipped == nil {
+		skipped = reclaimer.NewSummaryTable()
+	}
+	return scanTargets(ctx, c.targets, c.ignorePaths, skipped)
+}
+
+// scanTargets expands the glob patterns of every cleanup target and collects the matching
+// paths as cleanupItems, honoring the ignore list and each target's minimum age.
+//
+// Parameters:
+//   - ctx: Stops the scan when cancelled.
+//   - cleanupTargets: The targets to scan.
+//   - expandedIgnorePaths: Ignore paths that have already been expanded with utils.ExpandPath.
+//   - skipped: A SummaryTable that receives every excluded path together with the reason.
+//
+// Returns:
+//   - The collected items. Problems along the way are added to logger.RunWarnings.
+//   - ctx's error if the scan was cancelled.
+func scanTargets(ctx context.Context, cleanupTargets []CleanupTarget, expandedIgnorePaths []string, skipped *reclaimer.SummaryTable) ([]cleanupItem, error) {
 
-	// Collect all potential items to process as cleanupItems
 	var itemsToProcess []cleanupItem
+	// A file hard-linked from several matches is only counted for the first one sized.
+	links := utils.NewLinkSet()
 
 	for _, target := range cleanupTargets {
-		logger.Log.Debugf("Scanning for %s using patterns: %v", target.Category, target.Paths)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		scanStart := time.Now()
+		log := logger.Log.With("category", target.Category)
+		log.Debugf("Scanning for %s using patterns: %v", target.Category, target.Paths)
+		var matches []string
 		for _, pattern := range target.Paths {
 			// filepath.Glob finds all file paths matching a pattern.
-			matches, err := filepath.Glob(pattern)
+			patternMatches, err := filepath.Glob(pattern)
 			if err != nil {
-				if showWarnings {
-					logger.Log.Warnf("Error globbing pattern %s: %v", pattern, err)
-				} else {
-					suppressedWarnings = true
-				}
+				logger.RunWarnings.Add(target.Category, "invalid pattern", pattern, err)
 				continue
 			}
+			matches = append(matches, patternMatches...)
+		}
+		// Some targets compute their paths instead of (or in addition to) globbing for them.
+		if target.Find != nil {
+			found, err := target.Find()
+			if err != nil {
+				logger.RunWarnings.Add(target.Category, reclaimer.SkipReasonForError(err), target.ID, err)
+			}
+			matches = append(matches, found...)
+		}
 
-			for _, path := range matches {
-				// Check if the path is in the list of paths to ignore.
-				if utils.ContainsPath(path, expandedIgnorePaths) {
-					logger.Log.Debugf(utils.Yellow("Skipping ignored path: %s"), path)
-					continue
-				}
-
-				fileInfo, err := os.Stat(path)
-				if err != nil {
-					if showWarnings {
-						logger.Log.Debugf("Error stating path %s: %v", path, err)
-					} else {
-						suppressedWarnings = true
-					}
-					continue
-				}
-				// Check if the file's modification time is recent, if a minimum age is specified.
-				if target.MinAge > 0 && time.Since(fileInfo.ModTime()) < target.MinAge {
-					logger.Log.Debugf("Skipping recent file/directory: %s (Modified: %s)", path, fileInfo.ModTime().Format("2006-01-02"))
-					continue
-				}
-				// Get the size of the file to be able to calculate the total reclaimed space.
-				size, err := utils.GetFileSizeInBytes(path)
-				if err != nil {
-					if showWarnings {
-						logger.Log.Debugf("Could not get size of %s for aggregation: %v", path, err)
-					} else {
-						suppressedWarnings = true
-					}
-					continue
-				}
-
-				// The 'Path' field in cleanupItem is used for display. We aggregate files
-				// by their cleanup target root for a cleaner-looking summary table.
-				displayPath := path // Default to individual path
-				for _, root := range target.LogAggregationRoots {
-					if strings.HasPrefix(path, root) {
-						displayPath = root
-						break
-					}
-				}
-
-				itemsToProcess = append(itemsToProcess, cleanupItem{
-					Path:       displayPath,     // This is the aggregated path for display in the table
-					Size:       size,            // Size of the file.
-					Category:   target.Category, // This is the higher-level category for the summary table
-					ActualPath: path,            // This is the actual path to delete
-				})
+		// Matches are checked and sized concurrently (see --jobs), then recorded in their original order.
+		results := make([]scannedPath, len(matches))
+		utils.ParallelFor(len(matches), func(i int) {
+			if ctx.Err() == nil {
+				results[i] = scanPath(target, matches[i], expandedIgnorePaths, links)
+			}
+		})
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for i, result := range results {
+			if result.err != nil {
+				logger.RunWarnings.Add(target.Category, result.reason, matches[i], result.err)
+			}
+			if result.reason != "" {
+				skipped.AddSkippedReason(matches[i], 0, target.Category, result.reason)
+			}
+			if result.item != nil {
+				itemsToProcess = append(itemsToProcess, *result.item)
 			}
 		}
+		skipped.Timings.AddScan(target.Category, time.Since(scanStart))
 	}
 
-	if suppressedWarnings {
-		logger.Log.Warn("Some warnings were suppressed. Set WIPER_SHOW_WARNINGS=true to see full warning details.")
+	return itemsToProcess, nil
+}
+
+// scannedPath is the outcome of checking one match of a cleanup target.
+type scannedPath struct {
+	// item is the item to clean, or nil if the path is left alone.
+	item *cleanupItem
+	// reason is why the path was skipped, if it is recorded in the skipped table.
+	reason string
+	// err is a problem to report with the run's warnings, under reason.
+	err error
+}
+
+// scanPath checks a path matched by target against the ignore list, protected paths, iCloud and tag protection,
+// and the target's age, type, and size limits, and sizes it. Hard-linked files already counted
+// in links are left out of the size. It only reads shared state (links is safe for concurrent
+// use), so scanTargets runs it concurrently.
+func scanPath(target CleanupTarget, path string, expandedIgnorePaths []string, links *utils.LinkSet) scannedPath {
+	log := logger.Log.With("category", target.Category)
+
+	// Paths that belong to a more specific target are counted there.
+	if target.isExcluded(path) {
+		return scannedPath{}
 	}
 
-	// Call the generic processCleanupItems function to handle the deletion logic.
-	// System cleanup is not interactive by default.
-	reclaimed, err := processCleanupItems(itemsToProcess,
-		dryRun,
-		false,
-		summary,
-		estimatedSummary,
-		"Folders that would be cleaned",
-		false)
+	// Check if the path is in the list of paths to ignore.
+	if utils.ContainsPath(path, expandedIgnorePaths) {
+		log.Debugf(utils.Yellow("Skipping ignored path: %s"), path)
+		return scannedPath{reason: reclaimer.SkipReasonIgnored}
+	}
+
+	// Keychains, mail, photo libraries and the system are never removed, whatever the target matched.
+	if utils.IsProtectedPath(path) {
+		log.Debugf(utils.Yellow("Skipping protected path: %s"), path)
+		return scannedPath{reason: reclaimer.SkipReasonProtected}
+	}
+
+	// Deletions in iCloud-synced locations propagate to other devices, so they need --allow-icloud.
+	if blockedByCloudSync(path, target.CloudSensitive) {
+		return scannedPath{reason: reclaimer.SkipReasonCloudSynced}
+	}
+
+	// Items tagged in Finder with the protect tag are kept on purpose.
+	if target.RespectTags && isTagProtected(path) {
+		log.Debugf(utils.Yellow("Skipping tagged path: %s"), path)
+		return scannedPath{reason: reclaimer.SkipReasonTagged}
+	}
+
+	fileInfo, err := os.Stat(path)
 	if err != nil {
-		return 0, fmt.Errorf("failed to process system cleanup: %w", err)
+		return scannedPath{reason: reclaimer.SkipReasonForError(err), err: err}
+	}
+	// Check if the file's modification time is recent, if a minimum age is specified.
+	if target.MinAge > 0 && time.Since(fileInfo.ModTime()) < target.MinAge {
+		log.Debugf("Skipping recent file/directory: %s (Modified: %s)", path, fileInfo.ModTime().Format("2006-01-02"))
+		return scannedPath{reason: reclaimer.SkipReasonTooNew}
+	}
+	if target.MinAge > 0 && target.AgeByContents && fileInfo.IsDir() {
+		recent, err := modifiedSince(path, time.Now().Add(-target.MinAge))
+		if err != nil {
+			// Without seeing all of the contents, the directory can't be shown to be unused.
+			return scannedPath{reason: reclaimer.SkipReasonForError(err), err: err}
+		}
+		if recent != "" {
+			log.Debugf("Skipping directory with recent contents: %s (%s was modified recently)", path, recent)
+			return scannedPath{reason: reclaimer.SkipReasonTooNew}
+		}
+	}
+	// Targets limited to certain file types (e.g., installers in Downloads) skip everything else.
+	if !target.matchesExtension(path, fileInfo.IsDir()) {
+		return scannedPath{}
+	}
+	// Directories are removed as a whole, with whatever ignored paths are inside them.
+	if fileInfo.IsDir() && len(expandedIgnorePaths) > 0 {
+		ignored, err := utils.IgnoredBelow(path, expandedIgnorePaths)
+		if err != nil {
+			return scannedPath{reason: reclaimer.SkipReasonForError(err), err: err}
+		}
+		if ignored != "" {
+			log.Debugf(utils.Yellow("Skipping %s: it contains the ignored path %s"), path, ignored)
+			return scannedPath{reason: reclaimer.SkipReasonIgnored}
+		}
+	}
+	// Get the size of the file to be able to calculate the total reclaimed space, and remember what
+	// the item looked like, so it is left alone if it is replaced before it is removed.
+	scanned := utils.FingerprintPath(path)
+	usage, err := links.Usage(path)
+	if err != nil {
+		return scannedPath{reason: reclaimer.SkipReasonForError(err), err: err}
+	}
+	if usage.Bytes < target.MinSize {
+		log.Debugf("Skipping small file/directory: %s (%s)", path, reclaimer.FormatBytes(usage.Bytes))
+		return scannedPath{}
 	}
 
-	return reclaimed, nil
+	// The 'Path' field in cleanupItem is used for display. We aggregate files
+	// by their cleanup target root for a cleaner-looking summary table.
+	displayPath := path // Default to individual path
+	removalRoot := ""
+	for _, root := range target.LogAggregationRoots {
+		if strings.HasPrefix(path, root) {
+			displayPath = root
+			removalRoot = root // Symlinks must not lead the removal out of this root
+			break
+		}
+	}
+
+	return scannedPath{item: &cleanupItem{
+		Path:       displayPath,     // This is the aggregated path for display in the table
+		Size:       usage.Private(), // The bytes removing the item frees.
+		Cloned:     usage.Cloned,    // The bytes shared with clones, which stay allocated.
+		Category:   target.Category, // This is the higher-level category for the summary table
+		ActualPath: path,            // This is the actual path to delete
+		Root:       removalRoot,
+		MoveTo:     target.ArchiveDir,
+		TargetID:   target.ID,
+		Remove:     target.Remove,
+		Scanned:    scanned,
+	}}
+}
+
+// modifiedSince walks dir until it finds an entry modified after cutoff, for targets with
+// AgeByContents set.
+//
+// Returns:
+//   - The first entry found that was modified after cutoff, or "" if there is none, and an error if
+//     part of the directory can't be read.
+func modifiedSince(dir string, cutoff time.Time) (string, error) {
+	var recent string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(cutoff) {
+			recent = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return recent, err
 }
@@ -0,0 +1,32 @@
+package cleaner
+
+import (
+	"strings" // Imported for trimming the configured tag
+
+	"github.com/kodelint/wiper/pkg/utils" // Imported for utils.HasTag
+)
+
+// ====================================================================================================
+// TAG-BASED PROTECTION
+// ====================================================================================================
+
+// DefaultProtectTag is the Finder tag that keeps files and folders out of the Downloads and
+// large file cleanups unless another tag is configured.
+const DefaultProtectTag = "Keep"
+
+// protectTag is the tag in effect for this run.
+var protectTag = DefaultProtectTag
+
+// SetProtectTag sets the tag that protects items from cleanup. An empty tag restores DefaultProtectTag.
+func SetProtectTag(tag string) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		tag = DefaultProtectTag
+	}
+	protectTag = tag
+}
+
+// isTagProtected reports whether path carries the protect tag.
+func isTagProtected(path string) bool {
+	return utils.HasTag(path, protectTag)
+}
@@ -1,19 +1,20 @@
 package cleaner
 
 import (
-	"path/filepath" // Imported for filepath.Join and other path manipulations
+	"path/filepath" // Imported for filepath.Match
 	"time"          // Imported for time.Duration
-
-	"github.com/kodelint/wiper/pkg/utils" // Imported for utils.ExpandPath
 )
 
 // ====================================================================================================
 // DATA STRUCTURES
 // ====================================================================================================
 
-// cleanupTarget defines a category of files to be cleaned. It provides all the necessary
+// CleanupTarget defines a category of files to be cleaned. It provides all the necessary
 // information for the system cleanup function to know what to look for and how to handle it.
-type cleanupTarget struct {
+type CleanupTarget struct {
+	// ID is a stable, language-independent identifier for the target (e.g., "user_caches").
+	// It is used to reference targets from profiles and configuration.
+	ID string
 	// Paths is a slice of glob patterns to find files and directories for this target.
 	Paths []string
 	// Category is a user-friendly name for the type of files being cleaned (e.g., "User Caches").
@@ -21,83 +22,57 @@ type cleanupTarget struct {
 	// MinAge is the minimum age a file must have to be considered for deletion.
 	// A value of 0 means all files matching the pattern will be considered.
 	MinAge time.Duration
+	// AgeByContents judges MinAge by the newest modification time inside a matched directory
+	// instead of the directory's own, which doesn't change when files deeper inside are added or
+	// rewritten (e.g., a bucket of a content-addressed cache, or the cache of a Gradle version).
+	AgeByContents bool
 	// LogAggregationRoots is a list of root paths used to group found items
 	// in the log output for a cleaner, more readable summary table.
 	LogAggregationRoots []string
+	// Exclude is a list of glob patterns for matches that belong to another target
+	// (e.g., browser caches inside ~/.cache) and must not be counted twice.
+	Exclude []string
+	// Find optionally computes additional paths that can't be expressed as a glob
+	// (e.g., disabled snap revisions). It is called once per scan.
+	Find func() ([]string, error)
+	// Extensions limits the target to files with one of these lower-case extensions (e.g., ".dmg").
+	// Directories never match when it is set. Empty means every match is considered.
+	Extensions []string
+	// MinSize is the minimum size in bytes an item must have to be cleaned. 0 means no minimum.
+	MinSize int64
+	// ArchiveDir, when set, moves matching items into this directory instead of deleting them.
+	ArchiveDir string
+	// CloudSensitive marks targets next to cloud-synced folders (e.g., Downloads beside an iCloud-synced
+	// Desktop and Documents). While sync is active, their items are skipped unless --allow-icloud is given.
+	CloudSensitive bool
+	// RespectTags skips items carrying the protect tag (see SetProtectTag), so users can keep
+	// individual files by tagging them in Finder.
+	RespectTags bool
+	// OptIn leaves the target out of every cleanup unless it is enabled with SetEnabledTargets
+	// (--enable-targets), because what it removes is slow or costly to regenerate. Its paths may
+	// reach into a protected tree, which is lifted for them only while the target is enabled.
+	OptIn bool
+	// Remove optionally removes a matched item with the tool that owns it (e.g., `go clean`) instead
+	// of deleting it by path. It is used even with --trash, --quarantine or --secure, whose
+	// handling only applies to items deleted by path.
+	Remove func(path string) error
 }
 
-// ====================================================================================================
-// CLEANUP TARGETS CONFIGURATION
-// ====================================================================================================
+// matchesExtension reports whether path has one of the target's extensions.
+// Targets without extensions match everything.
+func (t CleanupTarget) matchesExtension(path string, isDir bool) bool {
+	if len(t.Extensions) == 0 {
+		return true
+	}
+	return !isDir && hasExtension(path, t.Extensions)
+}
 
-// getCleanupTargets initializes and returns the slice of cleanup targets.
-// This function acts as the central configuration for the system cleanup feature, defining
-// the specific files and directories that the tool will target for removal.
-func getCleanupTargets() []cleanupTarget {
-	homeDir := utils.ExpandPath("~") // Ensure homeDir is expanded once
-	return []cleanupTarget{
-		{
-			Paths:               []string{filepath.Join(homeDir, "Library", "Caches", "TemporaryItems", "*"), "/private/var/folders/*/*/T/*"},
-			Category:            "User Temporary Files",
-			MinAge:              24 * time.Hour,
-			LogAggregationRoots: []string{filepath.Join(homeDir, "Library", "Caches", "TemporaryItems"), "/private/var/folders"},
-		},
-		{
-			Paths:               []string{"/private/var/tmp/*", "/tmp/*"},
-			Category:            "System Temporary Files",
-			MinAge:              24 * time.Hour,
-			LogAggregationRoots: []string{"/private/var/tmp", "/tmp"},
-		},
-		{
-			Paths:               []string{filepath.Join(homeDir, "Library", "Caches", "*")},
-			Category:            "User Caches",
-			MinAge:              0,
-			LogAggregationRoots: []string{filepath.Join(homeDir, "Library", "Caches")},
-		},
-		{
-			Paths:               []string{"/Library/Caches/*"},
-			Category:            "System Caches",
-			MinAge:              0,
-			LogAggregationRoots: []string{"/Library/Caches"},
-		},
-		{
-			Paths:               []string{filepath.Join(homeDir, "Library", "Logs", "*")},
-			Category:            "User Logs",
-			MinAge:              30 * 24 * time.Hour,
-			LogAggregationRoots: []string{filepath.Join(homeDir, "Library", "Logs")},
-		},
-		{
-			Paths: []string{
-				filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome", "Default", "Cache", "*"),
-				filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome", "Default", "Service Worker", "CacheStorage", "*"),
-				filepath.Join(homeDir, "Library", "Caches", "Google", "Chrome", "*"),
-				filepath.Join(homeDir, "Library", "Caches", "com.apple.Safari", "*"),
-				filepath.Join(homeDir, "Library", "Application Support", "Firefox", "Profiles", "*", "cache2", "entries", "*"),
-				filepath.Join(homeDir, "Library", "Application Support", "BraveSoftware", "Brave-Browser", "Default", "Cache", "*"),
-				filepath.Join(homeDir, "Library", "Caches", "BraveSoftware", "Brave-Browser", "*"),
-			},
-			Category: "Browser Caches",
-			MinAge:   0,
-			LogAggregationRoots: []string{
-				filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome"),
-				filepath.Join(homeDir, "Library", "Caches", "Google", "Chrome"),
-				filepath.Join(homeDir, "Library", "Caches", "com.apple.Safari"),
-				filepath.Join(homeDir, "Library", "Application Support", "Firefox"),
-				filepath.Join(homeDir, "Library", "Application Support", "BraveSoftware", "Brave-Browser"),
-				filepath.Join(homeDir, "Library", "Caches", "BraveSoftware", "Brave-Browser"),
-			},
-		},
-		{
-			Paths:               []string{filepath.Join(homeDir, ".Trash", "*")},
-			Category:            "Trash Bin",
-			MinAge:              0,
-			LogAggregationRoots: []string{filepath.Join(homeDir, ".Trash")},
-		},
-		{
-			Paths:               []string{filepath.Join(homeDir, "Downloads", "*")},
-			Category:            "Downloads (old)",
-			MinAge:              90 * 24 * time.Hour,
-			LogAggregationRoots: []string{filepath.Join(homeDir, "Downloads")},
-		},
+// isExcluded reports whether path matches one of the target's exclude patterns.
+func (t CleanupTarget) isExcluded(path string) bool {
+	for _, pattern := range t.Exclude {
+		if matched, err := filepath.Match(pattern, path); err == nil && matched {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,281 @@
+//go:build darwin
+
+package cleaner
+
+import (
+	"path/filepath" // Imported for filepath.Join and other path manipulations
+	"time"          // Imported for time.Duration
+
+	"github.com/kodelint/wiper/pkg/i18n"   // Imported for localized category names
+	"github.com/kodelint/wiper/pkg/logger" // Imported for debug messages
+	"github.com/kodelint/wiper/pkg/utils"  // Imported for utils.ExpandPath
+)
+
+// ====================================================================================================
+// CLEANUP TARGETS CONFIGURATION (macOS)
+// ====================================================================================================
+
+// darwinCleanupTargets initializes and returns the slice of macOS cleanup targets.
+// This function acts as the central configuration for the system cleanup feature, defining
+// the specific files and directories that the tool will target for removal.
+func darwinCleanupTargets() []CleanupTarget {
+	homeDir := utils.ExpandPath("~") // Ensure homeDir is expanded once
+	developerDir := filepath.Join(homeDir, "Library", "Developer")
+	// The caches of developer tools live inside ~/Library/Caches but have their own targets.
+	cachesDir := filepath.Join(homeDir, "Library", "Caches")
+	yarnCacheDir := filepath.Join(cachesDir, "Yarn")
+	cocoaPodsCacheDir := filepath.Join(cachesDir, "CocoaPods")
+	carthageCacheDir := filepath.Join(cachesDir, "org.carthage.CarthageKit")
+	mailContainerDir := filepath.Join(homeDir, "Library", "Containers", "com.apple.mail", "Data", "Library")
+
+	targets := []CleanupTarget{
+		{
+			ID:                  "user_temp",
+			Paths:               []string{filepath.Join(homeDir, "Library", "Caches", "TemporaryItems", "*"), "/private/var/folders/*/*/T/*"},
+			Category:            i18n.T("category.user_temp"),
+			MinAge:              24 * time.Hour,
+			LogAggregationRoots: []string{filepath.Join(homeDir, "Library", "Caches", "TemporaryItems"), "/private/var/folders"},
+		},
+		{
+			ID:                  "system_temp",
+			Paths:               []string{"/private/var/tmp/*", "/tmp/*"},
+			Category:            i18n.T("category.system_temp"),
+			MinAge:              24 * time.Hour,
+			LogAggregationRoots: []string{"/private/var/tmp", "/tmp"},
+		},
+		{
+			ID:                  "user_caches",
+			Paths:               []string{filepath.Join(homeDir, "Library", "Caches", "*")},
+			Category:            i18n.T("category.user_caches"),
+			MinAge:              0,
+			LogAggregationRoots: []string{filepath.Join(homeDir, "Library", "Caches")},
+			Exclude:             append([]string{yarnCacheDir, cocoaPodsCacheDir, carthageCacheDir, photoAnalysisCacheDir(cachesDir)}, devCacheExcludes(cachesDir)...),
+		},
+		{
+			ID:                  "system_caches",
+			Paths:               []string{"/Library/Caches/*"},
+			Category:            i18n.T("category.system_caches"),
+			MinAge:              0,
+			LogAggregationRoots: []string{"/Library/Caches"},
+		},
+		{
+			ID:                  "user_logs",
+			Paths:               []string{filepath.Join(homeDir, "Library", "Logs", "*")},
+			Category:            i18n.T("category.user_logs"),
+			MinAge:              30 * 24 * time.Hour,
+			LogAggregationRoots: []string{filepath.Join(homeDir, "Library", "Logs")},
+		},
+		{
+			ID: "browser_caches",
+			// Chromium and Firefox caches are found per profile, so --browser-profiles can select them.
+			Paths: append(browserCachePaths(darwinBrowsers(homeDir)),
+				filepath.Join(homeDir, "Library", "Caches", "com.apple.Safari", "*"),
+			),
+			Category: i18n.T("category.browser_caches"),
+			MinAge:   0,
+			LogAggregationRoots: []string{
+				filepath.Join(homeDir, "Library", "Application Support", "Google", "Chrome"),
+				filepath.Join(homeDir, "Library", "Caches", "Google", "Chrome"),
+				filepath.Join(homeDir, "Library", "Caches", "com.apple.Safari"),
+				filepath.Join(homeDir, "Library", "Application Support", "Firefox"),
+				filepath.Join(homeDir, "Library", "Caches", "Firefox"),
+				filepath.Join(homeDir, "Library", "Application Support", "BraveSoftware", "Brave-Browser"),
+				filepath.Join(homeDir, "Library", "Caches", "BraveSoftware", "Brave-Browser"),
+			},
+		},
+		{
+			// Build products and indexes; Xcode rebuilds them on the next build of each project.
+			ID:                  "xcode_derived_data",
+			Paths:               []string{filepath.Join(developerDir, "Xcode", "DerivedData", "*")},
+			Category:            i18n.T("category.xcode_derived_data"),
+			MinAge:              0,
+			LogAggregationRoots: []string{filepath.Join(developerDir, "Xcode", "DerivedData")},
+		},
+		{
+			// Archives hold the dSYMs needed to symbolicate crash reports of the builds they
+			// distributed, which stay in users' hands for years, so only old ones are cleaned, and only
+			// when enabled with --enable-targets.
+			ID:                  "xcode_archives",
+			Paths:               []string{filepath.Join(developerDir, "Xcode", "Archives", "*", "*.xcarchive")},
+			Category:            i18n.T("category.xcode_archives"),
+			MinAge:              180 * 24 * time.Hour,
+			LogAggregationRoots: []string{filepath.Join(developerDir, "Xcode", "Archives")},
+			OptIn:               true,
+		},
+		{
+			// Symbols copied from each OS version of a connected device; they are copied again when
+			// a device with that version is connected.
+			ID: "xcode_device_support",
+			Paths: []string{
+				filepath.Join(developerDir, "Xcode", "iOS DeviceSupport", "*"),
+				filepath.Join(developerDir, "Xcode", "watchOS DeviceSupport", "*"),
+				filepath.Join(developerDir, "Xcode", "tvOS DeviceSupport", "*"),
+				filepath.Join(developerDir, "Xcode", "visionOS DeviceSupport", "*"),
+			},
+			Category:            i18n.T("category.xcode_device_support"),
+			MinAge:              90 * 24 * time.Hour,
+			LogAggregationRoots: []string{filepath.Join(developerDir, "Xcode")},
+		},
+		{
+			ID:                  "simulator_caches",
+			Paths:               []string{filepath.Join(developerDir, "CoreSimulator", "Caches", "*")},
+			Category:            i18n.T("category.simulator_caches"),
+			MinAge:              30 * 24 * time.Hour,
+			LogAggregationRoots: []string{filepath.Join(developerDir, "CoreSimulator", "Caches")},
+		},
+		{
+			ID:                  "cocoapods_cache",
+			Paths:               []string{filepath.Join(cocoaPodsCacheDir, "*")},
+			Category:            i18n.T("category.cocoapods_cache"),
+			MinAge:              0,
+			LogAggregationRoots: []string{cocoaPodsCacheDir},
+		},
+		{
+			// Spec repositories are cloned again by `pod install` or `pod repo update`, so only those
+			// that haven't been updated for a while are cleaned.
+			ID:                  "cocoapods_repos",
+			Paths:               []string{filepath.Join(homeDir, ".cocoapods", "repos", "*")},
+			Category:            i18n.T("category.cocoapods_repos"),
+			MinAge:              30 * 24 * time.Hour,
+			LogAggregationRoots: []string{filepath.Join(homeDir, ".cocoapods", "repos")},
+		},
+		{
+			ID:                  "carthage_cache",
+			Paths:               []string{filepath.Join(carthageCacheDir, "*")},
+			Category:            i18n.T("category.carthage_cache"),
+			MinAge:              0,
+			LogAggregationRoots: []string{carthageCacheDir},
+		},
+		{
+			ID:                  "trash",
+			Paths:               []string{filepath.Join(homeDir, ".Trash", "*")},
+			Category:            i18n.T("category.trash"),
+			MinAge:              0,
+			LogAggregationRoots: []string{filepath.Join(homeDir, ".Trash")},
+		},
+		volumeTrashTarget(),
+		photosCachesTarget(homeDir, cachesDir),
+		{
+			// Copies of the attachments opened or previewed from Mail; the messages keep the originals.
+			// Before the Mail sandbox (macOS 10.14), they were saved to ~/Library/Mail Downloads.
+			ID: "mail_downloads",
+			Paths: []string{
+				filepath.Join(mailContainerDir, "Mail Downloads", "*"),
+				filepath.Join(homeDir, "Library", "Mail Downloads", "*"),
+			},
+			Category:            i18n.T("category.mail_downloads"),
+			MinAge:              30 * 24 * time.Hour,
+			LogAggregationRoots: []string{filepath.Join(mailContainerDir, "Mail Downloads"), filepath.Join(homeDir, "Library", "Mail Downloads")},
+			RespectTags:         true,
+		},
+		{
+			// Attachment previews and message caches of the sandboxed Mail, which ~/Library/Caches
+			// doesn't cover. Mail rebuilds them from the mailboxes.
+			ID:                  "mail_caches",
+			Paths:               []string{filepath.Join(mailContainerDir, "Caches", "*")},
+			Category:            i18n.T("category.mail_caches"),
+			MinAge:              30 * 24 * time.Hour,
+			LogAggregationRoots: []string{filepath.Join(mailContainerDir, "Caches")},
+		},
+		oldDownloadsTarget(filepath.Join(homeDir, "Downloads")),
+	}
+	targets = append(targets, nodePackageCacheTargets(homeDir, yarnCacheDir, filepath.Join(homeDir, "Library", "pnpm", "store"))...)
+	targets = append(targets, goCacheTargets(homeDir, cachesDir)...)
+	targets = append(targets, pythonCacheTargets(homeDir, cachesDir)...)
+	return append(targets, jvmBuildCacheTargets(homeDir)...)
+}
+
+// photoAnalysisCacheDir returns the cache of photoanalysisd, the daemon that finds faces, scenes
+// and memories in the Photos library. It belongs to the opt-in Photos target, not the user caches,
+// because rebuilding it analyzes the whole library again.
+func photoAnalysisCacheDir(cachesDir string) string {
+	return filepath.Join(cachesDir, "com.apple.photoanalysisd")
+}
+
+// photosCachesTarget returns the opt-in target of the data Photos regenerates from the originals:
+// the thumbnails and previews in resources/derivatives of the libraries in ~/Pictures, the caches
+// of photoanalysisd inside them and in ~/Library/Caches. Only these folders are matched, so the
+// originals (originals/, or Masters/ in older libraries), edits and the database stay protected.
+// With "Optimize Mac Storage", Photos downloads the previews from iCloud again, and until then
+// shows placeholders; quit Photos before cleaning.
+func photosCachesTarget(homeDir, cachesDir string) CleanupTarget {
+	libraries := filepath.Join(homeDir, "Pictures", "*.photoslibrary")
+	target := CleanupTarget{
+		ID: "photos_caches",
+		Paths: []string{
+			filepath.Join(libraries, "resources", "derivatives", "*"),
+			filepath.Join(libraries, "private", "com.apple.photoanalysisd", "caches", "*"),
+			filepath.Join(photoAnalysisCacheDir(cachesDir), "*"),
+		},
+		Category:            i18n.T("category.photos_caches"),
+		MinAge:              0,
+		LogAggregationRoots: []string{photoAnalysisCacheDir(cachesDir)},
+		OptIn:               true,
+	}
+	found, err := filepath.Glob(libraries)
+	if err != nil {
+		logger.Log.Debugf("Failed to look for Photos libraries: %v", err)
+	}
+	for _, library := range found {
+		target.LogAggregationRoots = append(target.LogAggregationRoots,
+			filepath.Join(library, "resources", "derivatives"),
+			filepath.Join(library, "private", "com.apple.photoanalysisd", "caches"))
+	}
+	return target
+}
+
+// volumeTrashTarget returns the target of the Trashes that Finder keeps on every volume other than
+// the home volume, in <volume>/.Trashes/<uid>: files deleted from an external drive keep taking space
+// on it until they are emptied. The root volume's /.Trashes is included, /Volumes/* entries that
+// link back to it (e.g., "Macintosh HD") are not, since only mount points are considered.
+func volumeTrashTarget() CleanupTarget {
+	target := CleanupTarget{
+		ID:       "volume_trash",
+		Category: i18n.T("category.volume_trash"),
+		MinAge:   0,
+	}
+	volumes, err := utils.Volumes()
+	if err != nil {
+		logger.Log.Debugf("Skipping the Trashes of other volumes: %v", err)
+		return target
+	}
+	for _, volume := range volumes {
+		// Even looking into the Trash of a file server can stall the scan.
+		if volume.Network && !utils.ScanNetworkVolumes() {
+			continue
+		}
+		trashDir := utils.VolumeTrashDir(volume.Path)
+		target.Paths = append(target.Paths, filepath.Join(trashDir, "*"))
+		target.LogAggregationRoots = append(target.LogAggregationRoots, trashDir)
+	}
+	return target
+}
+
+// darwinBrowsers returns where the supported browsers keep their profiles on macOS.
+func darwinBrowsers(homeDir string) []browserInstall {
+	appSupport := filepath.Join(homeDir, "Library", "Application Support")
+	caches := filepath.Join(homeDir, "Library", "Caches")
+	return []browserInstall{
+		{
+			Name:         "Chrome",
+			Kind:         chromiumBrowser,
+			ProfileRoot:  filepath.Join(appSupport, "Google", "Chrome"),
+			CacheSubdirs: []string{"Cache", filepath.Join("Service Worker", "CacheStorage")},
+			CacheRoots:   []string{filepath.Join(caches, "Google", "Chrome")},
+		},
+		{
+			Name:         "Firefox",
+			Kind:         firefoxBrowser,
+			ProfileRoot:  filepath.Join(appSupport, "Firefox"),
+			CacheSubdirs: []string{filepath.Join("cache2", "entries")},
+			CacheRoots:   []string{filepath.Join(caches, "Firefox")},
+		},
+		{
+			Name:         "Brave",
+			Kind:         chromiumBrowser,
+			ProfileRoot:  filepath.Join(appSupport, "BraveSoftware", "Brave-Browser"),
+			CacheSubdirs: []string{"Cache"},
+			CacheRoots:   []string{filepath.Join(caches, "BraveSoftware", "Brave-Browser")},
+		},
+	}
+}
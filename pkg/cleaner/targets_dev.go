@@ -0,0 +1,288 @@
+package cleaner
+
+import (
+	"bytes"         // Imported for capturing the output of the go tool
+	"fmt"           // Imported for error messages
+	"io/fs"         // Imported for walking the Maven repository
+	"os"            // Imported for reading GOPATH and friends
+	"os/exec"       // Imported for running the go tool
+	"path/filepath" // Imported for filepath.Join
+	"strings"       // Imported for trimming the output of the go tool
+	"time"          // Imported for time.Duration
+
+	"github.com/kodelint/wiper/pkg/i18n" // Imported for localized category names
+)
+
+// ====================================================================================================
+// DEVELOPER TOOL CACHE TARGETS
+// ====================================================================================================
+
+// devCacheMinAge keeps package manager caches that were used in the last week, so cleaning
+// doesn't force the next install of an active project to download everything again.
+const devCacheMinAge = 7 * 24 * time.Hour
+
+// userCacheDevDirs are the directories in the user cache directory that belong to the developer
+// tool targets below, so the user caches target leaves them alone.
+var userCacheDevDirs = []string{"go-build", "pip", "pipenv"}
+
+// devCacheExcludes returns the paths of userCacheDevDirs inside userCacheDir.
+func devCacheExcludes(userCacheDir string) []string {
+	excludes := make([]string, 0, len(userCacheDevDirs))
+	for _, name := range userCacheDevDirs {
+		excludes = append(excludes, filepath.Join(userCacheDir, name))
+	}
+	return excludes
+}
+
+// nodePackageCacheTargets returns the targets for the caches of the Node.js package managers.
+// Their location differs per platform, so the platform passes the Yarn cache and pnpm store
+// directories; the npm cache is in the home directory everywhere.
+func nodePackageCacheTargets(homeDir, yarnCacheDir, pnpmStoreDir string) []CleanupTarget {
+	npmCacheDir := filepath.Join(homeDir, ".npm", "_cacache")
+	yarnBerryCacheDir := filepath.Join(homeDir, ".yarn", "berry", "cache")
+	legacyPnpmStoreDir := filepath.Join(homeDir, ".pnpm-store")
+	return []CleanupTarget{
+		{
+			// npm verifies cached content on use and downloads whatever is missing again. The cache
+			// is content-addressed, so its hash buckets are removed once nothing in them was written
+			// for a while; the top-level directories change with every install.
+			ID: "npm_cache",
+			Paths: []string{
+				filepath.Join(npmCacheDir, "content-v2", "*", "*"),
+				filepath.Join(npmCacheDir, "index-v5", "*"),
+				filepath.Join(npmCacheDir, "tmp", "*"),
+			},
+			Category:            i18n.T("category.npm_cache"),
+			MinAge:              devCacheMinAge,
+			AgeByContents:       true,
+			LogAggregationRoots: []string{npmCacheDir},
+		},
+		{
+			// Yarn 1 keeps one directory per package version; Yarn 2+ keeps one archive per package.
+			ID:                  "yarn_cache",
+			Paths:               []string{filepath.Join(yarnCacheDir, "*", "*"), filepath.Join(yarnBerryCacheDir, "*")},
+			Category:            i18n.T("category.yarn_cache"),
+			MinAge:              devCacheMinAge,
+			LogAggregationRoots: []string{yarnCacheDir, yarnBerryCacheDir},
+		},
+		{
+			// Projects hard-link or clone their files from the store, so they keep working without it.
+			// As with npm, the hash buckets of each store version (e.g., v3) are removed only once
+			// nothing in them was added for a while.
+			ID: "pnpm_cache",
+			Paths: []string{
+				filepath.Join(pnpmStoreDir, "*", "files", "*"), filepath.Join(pnpmStoreDir, "*", "index", "*"),
+				filepath.Join(legacyPnpmStoreDir, "*", "files", "*"), filepath.Join(legacyPnpmStoreDir, "*", "index", "*"),
+			},
+			Category:            i18n.T("category.pnpm_store"),
+			MinAge:              devCacheMinAge,
+			AgeByContents:       true,
+			LogAggregationRoots: []string{pnpmStoreDir, legacyPnpmStoreDir},
+		},
+	}
+}
+
+// goCacheTargets returns the targets for the Go build cache and module cache.
+//
+// When the go tool is installed, it reports where the caches are and removes them with
+// `go clean -cache` and `go clean -modcache`; the module cache is read-only, so it can't simply
+// be deleted. Otherwise, the caches are found at their default locations below userCacheDir
+// and GOPATH, and only the writable download cache of the module cache is removed.
+// Go trims its build cache on its own, so neither target has a minimum age.
+func goCacheTargets(homeDir, userCacheDir string) []CleanupTarget {
+	buildCache := CleanupTarget{
+		ID:       "go_build_cache",
+		Category: i18n.T("category.go_build_cache"),
+		MinAge:   0,
+	}
+	modCache := CleanupTarget{
+		ID:       "go_mod_cache",
+		Category: i18n.T("category.go_mod_cache"),
+		MinAge:   0,
+	}
+
+	if _, err := exec.LookPath("go"); err == nil {
+		buildCache.Find = func() ([]string, error) { return goEnvPath("GOCACHE") }
+		buildCache.Remove = func(string) error { return goClean("-cache") }
+		modCache.Find = func() ([]string, error) { return goEnvPath("GOMODCACHE") }
+		modCache.Remove = func(string) error { return goClean("-modcache") }
+		return []CleanupTarget{buildCache, modCache}
+	}
+
+	buildCacheDir := os.Getenv("GOCACHE")
+	if buildCacheDir == "" {
+		buildCacheDir = filepath.Join(userCacheDir, "go-build")
+	}
+	if buildCacheDir != "off" {
+		buildCache.Paths = []string{buildCacheDir}
+	}
+	modCacheDir := os.Getenv("GOMODCACHE")
+	if modCacheDir == "" {
+		modCacheDir = filepath.Join(defaultGOPATH(homeDir), "pkg", "mod")
+	}
+	modCache.Paths = []string{filepath.Join(modCacheDir, "cache", "download")}
+	return []CleanupTarget{buildCache, modCache}
+}
+
+// defaultGOPATH returns the first entry of GOPATH, or ~/go if it isn't set.
+func defaultGOPATH(homeDir string) string {
+	if gopath := filepath.SplitList(os.Getenv("GOPATH")); len(gopath) > 0 && gopath[0] != "" {
+		return gopath[0]
+	}
+	return filepath.Join(homeDir, "go")
+}
+
+// goEnvPath returns the directory `go env` reports for name as the only match of a target,
+// or nothing if it is unset or turned off (e.g., GOCACHE=off).
+func goEnvPath(name string) ([]string, error) {
+	out, err := goOutput("env", name)
+	if err != nil {
+		return nil, err
+	}
+	dir := strings.TrimSpace(out)
+	if dir == "" || dir == "off" {
+		return nil, nil
+	}
+	return []string{dir}, nil
+}
+
+// goClean runs `go clean` with the given flag (e.g., "-modcache").
+func goClean(flag string) error {
+	_, err := goOutput("clean", flag)
+	return err
+}
+
+// goOutput runs the go tool with the given arguments and returns its standard output.
+func goOutput(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("go %s: %w: %s", strings.Join(args, " "), err, msg)
+		}
+		return "", fmt.Errorf("go %s: %w", strings.Join(args, " "), err)
+	}
+	return stdout.String(), nil
+}
+
+// pythonCacheTargets returns the targets for the caches of pip, pipenv and conda.
+// pip and pipenv keep their caches in the user cache directory of the platform; conda keeps
+// downloaded and extracted packages in the pkgs directory of each installation.
+func pythonCacheTargets(homeDir, userCacheDir string) []CleanupTarget {
+	pipCacheDir := filepath.Join(userCacheDir, "pip")
+	pipenvCacheDir := filepath.Join(userCacheDir, "pipenv")
+	condaPkgsDirs := condaPackageDirs(homeDir)
+	condaPaths := make([]string, 0, len(condaPkgsDirs))
+	for _, dir := range condaPkgsDirs {
+		condaPaths = append(condaPaths, filepath.Join(dir, "*"))
+	}
+	return []CleanupTarget{
+		{
+			ID:                  "pip_cache",
+			Paths:               []string{filepath.Join(pipCacheDir, "*")},
+			Category:            i18n.T("category.pip_cache"),
+			MinAge:              0,
+			LogAggregationRoots: []string{pipCacheDir},
+		},
+		{
+			ID:                  "pipenv_cache",
+			Paths:               []string{filepath.Join(pipenvCacheDir, "*")},
+			Category:            i18n.T("category.pipenv_cache"),
+			MinAge:              0,
+			LogAggregationRoots: []string{pipenvCacheDir},
+		},
+		{
+			// Environments hard-link their files from here, so removing a package they use only frees
+			// the space once the environment is removed as well; recently extracted packages are kept.
+			ID:                  "conda_pkgs_cache",
+			Paths:               condaPaths,
+			Category:            i18n.T("category.conda_pkgs"),
+			MinAge:              devCacheMinAge,
+			LogAggregationRoots: condaPkgsDirs,
+		},
+	}
+}
+
+// condaPackageDirs returns the pkgs directories of the usual conda installations in the home
+// directory, and those listed in CONDA_PKGS_DIRS.
+func condaPackageDirs(homeDir string) []string {
+	var dirs []string
+	for _, dir := range strings.Split(os.Getenv("CONDA_PKGS_DIRS"), ",") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, filepath.Clean(dir))
+		}
+	}
+	for _, install := range []string{"miniconda3", "anaconda3", "miniforge3", "mambaforge", filepath.Join("opt", "miniconda3"), filepath.Join("opt", "anaconda3"), ".conda"} {
+		dirs = append(dirs, filepath.Join(homeDir, install, "pkgs"))
+	}
+	return dirs
+}
+
+// mavenArtifactMinAge keeps Maven artifacts that were downloaded in the last three months;
+// Maven doesn't record when an artifact was last used, so the download time is all there is.
+const mavenArtifactMinAge = 90 * 24 * time.Hour
+
+// jvmBuildCacheTargets returns the targets for the caches of Gradle and Maven, which are in the
+// home directory on every platform. GRADLE_USER_HOME moves the Gradle directory.
+func jvmBuildCacheTargets(homeDir string) []CleanupTarget {
+	gradleHome := os.Getenv("GRADLE_USER_HOME")
+	if gradleHome == "" {
+		gradleHome = filepath.Join(homeDir, ".gradle")
+	}
+	gradleCacheDir := filepath.Join(gradleHome, "caches")
+	gradleDaemonDir := filepath.Join(gradleHome, "daemon")
+	mavenRepository := filepath.Join(homeDir, ".m2", "repository")
+	return []CleanupTarget{
+		{
+			// The caches of Gradle versions that no build used for a while (e.g., caches/7.6), which
+			// every build of that version writes to, and the entries of the local build cache, which
+			// Gradle touches when it reuses them. The shared dependency and transform caches are
+			// left to Gradle's own cleanup, since their contents don't show when they were last used.
+			ID:                  "gradle_cache",
+			Paths:               []string{filepath.Join(gradleCacheDir, "[0-9]*"), filepath.Join(gradleCacheDir, "build-cache-*", "*")},
+			Category:            i18n.T("category.gradle_cache"),
+			MinAge:              devCacheMinAge,
+			AgeByContents:       true,
+			LogAggregationRoots: []string{gradleCacheDir},
+		},
+		{
+			// Each daemon writes a log per run, one directory per Gradle version.
+			ID:                  "gradle_daemon_logs",
+			Paths:               []string{filepath.Join(gradleDaemonDir, "*", "*")},
+			Category:            i18n.T("category.gradle_daemon_logs"),
+			MinAge:              0,
+			LogAggregationRoots: []string{gradleDaemonDir},
+			Extensions:          []string{".log"},
+		},
+		{
+			ID:                  "maven_cache",
+			Category:            i18n.T("category.maven_repository"),
+			MinAge:              mavenArtifactMinAge,
+			LogAggregationRoots: []string{mavenRepository},
+			Find:                func() ([]string, error) { return mavenArtifactDirs(mavenRepository) },
+		},
+	}
+}
+
+// mavenArtifactDirs returns the version directories of the artifacts in a Maven repository
+// (e.g., repository/org/slf4j/slf4j-api/2.0.9), which are recognized by their .pom file.
+// Removing a whole version keeps the repository consistent; Maven downloads it again when needed.
+func mavenArtifactDirs(repository string) ([]string, error) {
+	if _, err := os.Stat(repository); err != nil {
+		return nil, nil
+	}
+	var dirs []string
+	err := filepath.WalkDir(repository, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".pom" {
+			return nil
+		}
+		dirs = append(dirs, filepath.Dir(path))
+		return fs.SkipDir // The rest of the version directory doesn't need to be walked
+	})
+	return dirs, err
+}
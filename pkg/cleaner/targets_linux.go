@@ -0,0 +1,308 @@
+//go:build linux
+
+package cleaner
+
+import (
+	"fmt"           // Imported for fmt.Errorf
+	"os"            // Imported for os.Getenv and os.Readlink
+	"os/exec"       // Imported for running snap and flatpak
+	"path/filepath" // Imported for filepath.Join and other path manipulations
+	"strings"       // Imported for parsing snap file names
+	"sync"          // Imported for sync.Mutex
+	"time"          // Imported for time.Duration
+
+	"github.com/kodelint/wiper/pkg/i18n"  // Imported for localized category names
+	"github.com/kodelint/wiper/pkg/utils" // Imported for utils.ExpandPath
+)
+
+// ====================================================================================================
+// CLEANUP TARGETS CONFIGURATION (Linux)
+// ====================================================================================================
+
+// linuxCleanupTargets initializes and returns the slice of Linux cleanup targets.
+// User paths follow the XDG base directory specification; system paths cover the package
+// managers of the common distribution families. Paths that don't exist on a given system
+// simply match nothing.
+func linuxCleanupTargets() []CleanupTarget {
+	homeDir := utils.ExpandPath("~") // Ensure homeDir is expanded once
+	cacheDir := xdgDir("XDG_CACHE_HOME", filepath.Join(homeDir, ".cache"))
+	dataDir := xdgDir("XDG_DATA_HOME", filepath.Join(homeDir, ".local", "share"))
+
+	// Browser caches and thumbnails live inside the XDG cache directory but have their own targets.
+	browserCacheDirs := []string{
+		filepath.Join(cacheDir, "google-chrome"),
+		filepath.Join(cacheDir, "chromium"),
+		filepath.Join(cacheDir, "mozilla"),
+		filepath.Join(cacheDir, "BraveSoftware"),
+	}
+	thumbnailDir := filepath.Join(cacheDir, "thumbnails")
+	browsers := linuxBrowsers(homeDir, cacheDir)
+	// So do the caches of developer tools.
+	yarnCacheDir := filepath.Join(cacheDir, "yarn")
+
+	targets := []CleanupTarget{
+		{
+			ID:                  "system_temp",
+			Paths:               []string{"/tmp/*", "/var/tmp/*"},
+			Category:            i18n.T("category.system_temp"),
+			MinAge:              24 * time.Hour,
+			LogAggregationRoots: []string{"/tmp", "/var/tmp"},
+			Exclude:             systemTempExcludes,
+		},
+		{
+			ID:                  "user_caches",
+			Paths:               []string{filepath.Join(cacheDir, "*")},
+			Category:            i18n.T("category.user_caches"),
+			MinAge:              0,
+			LogAggregationRoots: []string{cacheDir},
+			Exclude:             append(append([]string{thumbnailDir, yarnCacheDir}, browserCacheDirs...), devCacheExcludes(cacheDir)...),
+		},
+		{
+			ID:                  "thumbnails",
+			Paths:               []string{filepath.Join(thumbnailDir, "*")},
+			Category:            i18n.T("category.thumbnails"),
+			MinAge:              0,
+			LogAggregationRoots: []string{thumbnailDir},
+		},
+		{
+			// Caches are found per profile, so --browser-profiles can select them.
+			ID:                  "browser_caches",
+			Paths:               browserCachePaths(browsers),
+			Category:            i18n.T("category.browser_caches"),
+			MinAge:              0,
+			LogAggregationRoots: browserAggregationRoots(browsers),
+		},
+		{
+			ID: "package_caches",
+			Paths: []string{
+				"/var/cache/apt/archives/*.deb",
+				"/var/cache/apt/archives/partial/*",
+				"/var/cache/dnf/*",
+				"/var/cache/yum/*",
+				"/var/cache/pacman/pkg/*",
+			},
+			Category:            i18n.T("category.package_caches"),
+			MinAge:              0,
+			LogAggregationRoots: []string{"/var/cache/apt", "/var/cache/dnf", "/var/cache/yum", "/var/cache/pacman"},
+		},
+		{
+			// Only archived journal files carry an "@" in their name, so the active journals never match.
+			// Removing archives older than two weeks is what `journalctl --vacuum-time=2weeks` does.
+			ID:                  "journal_archives",
+			Paths:               []string{"/var/log/journal/*/*@*.journal", "/var/log/journal/*/*@*.journal~"},
+			Category:            i18n.T("category.journal_archives"),
+			MinAge:              14 * 24 * time.Hour,
+			LogAggregationRoots: []string{"/var/log/journal"},
+		},
+		{
+			// Revisions are removed with snap, so snapd forgets them too.
+			ID:                  "snap_revisions",
+			Category:            i18n.T("category.snap_revisions"),
+			MinAge:              0,
+			LogAggregationRoots: []string{"/var/lib/snapd/snaps"},
+			Find:                disabledSnapRevisions,
+			Remove:              removeSnapRevision,
+		},
+		{
+			// Flatpak moves replaced deployments here and only deletes them when no app is using them,
+			// so they are left to flatpak (see removeFlatpakUnused).
+			ID: "flatpak_removed",
+			Paths: []string{
+				"/var/lib/flatpak/.removed/*",
+				filepath.Join(dataDir, "flatpak", ".removed", "*"),
+			},
+			Category:            i18n.T("category.flatpak_removed"),
+			MinAge:              0,
+			LogAggregationRoots: []string{"/var/lib/flatpak/.removed", filepath.Join(dataDir, "flatpak", ".removed")},
+			Remove:              removeFlatpakUnused,
+		},
+		{
+			ID: "trash",
+			Paths: []string{
+				filepath.Join(dataDir, "Trash", "files", "*"),
+				filepath.Join(dataDir, "Trash", "info", "*"),
+			},
+			Category:            i18n.T("category.trash"),
+			MinAge:              0,
+			LogAggregationRoots: []string{filepath.Join(dataDir, "Trash")},
+		},
+		oldDownloadsTarget(filepath.Join(homeDir, "Downloads")),
+	}
+	targets = append(targets, nodePackageCacheTargets(homeDir, yarnCacheDir, filepath.Join(dataDir, "pnpm", "store"))...)
+	targets = append(targets, goCacheTargets(homeDir, cacheDir)...)
+	targets = append(targets, pythonCacheTargets(homeDir, cacheDir)...)
+	return append(targets, jvmBuildCacheTargets(homeDir)...)
+}
+
+// systemTempExcludes are the entries of /tmp and /var/tmp that running sessions and services
+// depend on, however old they are: the X11, ICE and font server socket directories, X display
+// locks, and the private temporary directories systemd and snapd create for services.
+var systemTempExcludes = []string{
+	"/tmp/.X11-unix",
+	"/tmp/.ICE-unix",
+	"/tmp/.XIM-unix",
+	"/tmp/.font-unix",
+	"/tmp/.Test-unix",
+	"/tmp/.X*-lock",
+	"/tmp/systemd-private-*",
+	"/var/tmp/systemd-private-*",
+	"/tmp/snap-private-tmp",
+}
+
+// linuxBrowsers returns where the supported browsers keep their profiles and caches on Linux.
+// Profiles live in the XDG config directory (Firefox: ~/.mozilla), while most cached data is
+// stored in a per-profile directory below the XDG cache directory.
+func linuxBrowsers(homeDir string, cacheDir string) []browserInstall {
+	configDir := xdgDir("XDG_CONFIG_HOME", filepath.Join(homeDir, ".config"))
+	serviceWorkerCache := filepath.Join("Service Worker", "CacheStorage")
+	return []browserInstall{
+		{
+			Name:         "Chrome",
+			Kind:         chromiumBrowser,
+			ProfileRoot:  filepath.Join(configDir, "google-chrome"),
+			CacheSubdirs: []string{serviceWorkerCache},
+			CacheRoots:   []string{filepath.Join(cacheDir, "google-chrome")},
+		},
+		{
+			Name:         "Chromium",
+			Kind:         chromiumBrowser,
+			ProfileRoot:  filepath.Join(configDir, "chromium"),
+			CacheSubdirs: []string{serviceWorkerCache},
+			CacheRoots:   []string{filepath.Join(cacheDir, "chromium")},
+		},
+		{
+			Name:        "Firefox",
+			Kind:        firefoxBrowser,
+			ProfileRoot: filepath.Join(homeDir, ".mozilla", "firefox"),
+			CacheRoots:  []string{filepath.Join(cacheDir, "mozilla", "firefox")},
+		},
+		{
+			Name:         "Brave",
+			Kind:         chromiumBrowser,
+			ProfileRoot:  filepath.Join(configDir, "BraveSoftware", "Brave-Browser"),
+			CacheSubdirs: []string{serviceWorkerCache},
+			CacheRoots:   []string{filepath.Join(cacheDir, "BraveSoftware", "Brave-Browser")},
+		},
+	}
+}
+
+// browserAggregationRoots returns the profile and cache roots of the browsers, for grouping their items.
+func browserAggregationRoots(browsers []browserInstall) []string {
+	var roots []string
+	for _, browser := range browsers {
+		roots = append(roots, browser.CacheRoots...)
+		roots = append(roots, browser.ProfileRoot)
+	}
+	return roots
+}
+
+// xdgDir returns the directory named by an XDG environment variable, or fallback when it is unset.
+// The specification requires the value to be absolute; relative values are ignored.
+func xdgDir(env string, fallback string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return fallback
+}
+
+// disabledSnapRevisions returns the snap packages in /var/lib/snapd/snaps that are not the
+// current revision of their snap. snapd keeps these for rollbacks, but they are often the
+// largest files on Ubuntu systems.
+func disabledSnapRevisions() ([]string, error) {
+	snaps, err := filepath.Glob("/var/lib/snapd/snaps/*.snap")
+	if err != nil {
+		return nil, err
+	}
+
+	var disabled []string
+	for _, snap := range snaps {
+		name, revision, ok := snapRevision(snap)
+		if !ok {
+			continue
+		}
+
+		current, err := os.Readlink(filepath.Join("/snap", name, "current"))
+		if err != nil {
+			// Without a known current revision, keep every revision of the snap.
+			continue
+		}
+		if filepath.Base(current) != revision {
+			disabled = append(disabled, snap)
+		}
+	}
+	return disabled, nil
+}
+
+// snapRevision returns the snap name and revision of a package file in /var/lib/snapd/snaps.
+// Snap packages are named <name>_<revision>.snap; names can't contain underscores, but parallel
+// instances (<name>_<key>) can, so the name ends at the last one.
+func snapRevision(path string) (name string, revision string, ok bool) {
+	base := strings.TrimSuffix(filepath.Base(path), ".snap")
+	sep := strings.LastIndex(base, "_")
+	if sep <= 0 {
+		return "", "", false
+	}
+	return base[:sep], base[sep+1:], true
+}
+
+// removeSnapRevision removes a disabled snap revision with `snap remove --revision`, which also
+// drops it from snapd's state. Deleting the package file alone would leave snapd with a revision
+// it can't mount.
+func removeSnapRevision(path string) error {
+	name, revision, ok := snapRevision(path)
+	if !ok {
+		return fmt.Errorf("%s is not a snap package", path)
+	}
+	snap, err := exec.LookPath("snap")
+	if err != nil {
+		return fmt.Errorf("snap not found in PATH: %w", err)
+	}
+	if out, err := exec.Command(snap, "remove", name, "--revision="+revision).CombinedOutput(); err != nil {
+		return fmt.Errorf("snap remove %s --revision=%s: %w: %s", name, revision, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// flatpakPruned records the installations `flatpak uninstall --unused` already ran for in this run,
+// keyed by its scope flag.
+var (
+	flatpakPrunedMu sync.Mutex
+	flatpakPruned   = make(map[string]error)
+)
+
+// removeFlatpakUnused leaves a replaced deployment to flatpak: it runs `flatpak uninstall --unused`
+// once for the installation the path belongs to (system or user), which removes the runtimes no app
+// uses anymore and lets flatpak delete the replaced deployments no running app holds. A deployment
+// that is still there afterwards is in use and reported as not removed.
+func removeFlatpakUnused(path string) error {
+	scope := "--user"
+	if strings.HasPrefix(path, "/var/lib/flatpak/") {
+		scope = "--system"
+	}
+	flatpakPrunedMu.Lock()
+	err, ran := flatpakPruned[scope]
+	if !ran {
+		err = runFlatpakUninstallUnused(scope)
+		flatpakPruned[scope] = err
+	}
+	flatpakPrunedMu.Unlock()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("the deployment is still in use; flatpak removes it once no app uses it")
+	}
+	return nil
+}
+
+// runFlatpakUninstallUnused runs `flatpak uninstall --unused` without prompting for one installation.
+func runFlatpakUninstallUnused(scope string) error {
+	flatpak, err := exec.LookPath("flatpak")
+	if err != nil {
+		return fmt.Errorf("flatpak not found in PATH: %w", err)
+	}
+	if out, err := exec.Command(flatpak, "uninstall", scope, "--unused", "--noninteractive").CombinedOutput(); err != nil {
+		return fmt.Errorf("flatpak uninstall %s --unused: %w: %s", scope, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kodelint/wiper/pkg/i18n"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// EXTERNAL VOLUMES
+// ====================================================================================================
+
+// ExternalVolumes returns the mount points of the volumes mounted in the platform's
+// ExternalVolumeDirs (e.g., /Volumes/*), for scans with --include-volumes. Read-only mounts, where
+// nothing can be removed, Time Machine backups, which must never be cleaned by path, and network
+// volumes (unless --network-volumes) are left out with a message.
+func ExternalVolumes() []string {
+	volumes, err := utils.Volumes()
+	if err != nil {
+		logger.Log.Warnf("Failed to list the external volumes: %v", err)
+		return nil
+	}
+	dirs := CurrentPlatform().ExternalVolumeDirs()
+	var mounts []string
+	for _, volume := range volumes {
+		if !utils.ContainsPath(volume.Path, dirs) {
+			continue
+		}
+		switch {
+		case volume.ReadOnly:
+			logger.Log.Infof("Skipping read-only volume %s", volume.Path)
+		case volume.Network && !utils.ScanNetworkVolumes():
+			logger.Log.Infof("Skipping network volume %s (use --network-volumes to scan it)", volume.Path)
+		case isBackupVolume(volume.Path):
+			logger.Log.Infof("Skipping Time Machine backup volume %s", volume.Path)
+		default:
+			mounts = append(mounts, volume.Path)
+		}
+	}
+	return mounts
+}
+
+// isBackupVolume recognizes Time Machine destinations: HFS+ backups keep everything in
+// Backups.backupdb, APFS backups keep one <date>.previous (or .inprogress) folder per backup,
+// and the snapshots Time Machine browses are mounted below /Volumes/.timemachine.
+func isBackupVolume(mount string) bool {
+	if strings.HasPrefix(mount, "/Volumes/.timemachine/") {
+		return true
+	}
+	if _, err := os.Stat(filepath.Join(mount, "Backups.backupdb")); err == nil {
+		return true
+	}
+	for _, pattern := range []string{"*.previous", "*.inprogress"} {
+		if matches, _ := filepath.Glob(filepath.Join(mount, pattern)); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ====================================================================================================
+// VOLUME TRASH CLEANUP FUNCTION
+// ====================================================================================================
+
+// CleanVolumeTrash empties the current user's Trash on a specific mounted volume.
+// macOS keeps a separate Trash for every volume under `<volume>/.Trashes/<uid>`, so files
+// deleted from an external drive keep occupying space on that drive until it is emptied.
+//
+// Parameters:
+//   - ctx: Cancels the scan, or the cleanup between items (see processCleanupItems).
+//   - volume: The mount point of the volume (e.g., "/Volumes/External").
+//   - dryRun: A boolean flag for dry-run mode (no files are actually deleted).
+//   - ignorePaths: A list of paths to explicitly exclude from deletion.
+//   - summary: A pointer to a SummaryTable to record deleted items and their sizes.
+//   - estimatedSummary: A pointer to a SummaryTable to record items found during a dry run.
+//
+// Returns:
+//   - The total space reclaimed in bytes and an error, if any.
+func CleanVolumeTrash(ctx context.Context, volume string, dryRun bool, ignorePaths []string, summary *reclaimer.SummaryTable, estimatedSummary *reclaimer.SummaryTable) (int64, error) {
+	logger.Log.Debugf("Starting Trash cleanup for volume %s", volume)
+
+	trashRoot := filepath.Join(volume, ".Trashes", strconv.Itoa(os.Getuid()))
+	volumeTargets := []CleanupTarget{
+		{
+			Paths:               []string{filepath.Join(trashRoot, "*")},
+			ID:                  "volume_trash",
+			Category:            i18n.T("category.volume_trash"),
+			MinAge:              0,
+			LogAggregationRoots: []string{trashRoot},
+		},
+	}
+
+	expandedIgnorePaths := expandIgnorePaths(ignorePaths)
+
+	itemsToProcess, err := scanTargets(ctx, volumeTargets, expandedIgnorePaths, estimatedSummary)
+	if err != nil {
+		return 0, err
+	}
+
+	reclaimed, err := processCleanupItems(ctx,
+		itemsToProcess,
+		dryRun,
+		confirmOnce,
+		summary,
+		estimatedSummary,
+		fmt.Sprintf("Trash on %s", volume))
+	if err != nil {
+		return reclaimed, fmt.Errorf("failed to process trash cleanup for %s: %w", volume, err)
+	}
+
+	return reclaimed, nil
+}
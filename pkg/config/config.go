@@ -0,0 +1,179 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ====================================================================================================
+// DATA STRUCTURES AND GLOBAL VARIABLES
+// ====================================================================================================
+
+// Config holds user preferences loaded from the wiper configuration file.
+// Every field is optional; a zero value means "use the built-in default".
+type Config struct {
+	// Language selects the message catalog used for prompts and summaries (e.g., "en", "de").
+	// When empty, the language is detected from the LANG/LC_* environment variables.
+	Language string `json:"language"`
+	// LogFormat selects the log output: "console" (default, colored), "text" (key=value) or "json".
+	LogFormat string `json:"log_format"`
+	// SystemLog forwards warnings and errors to the macOS unified log (or syslog on other systems).
+	SystemLog bool `json:"system_log"`
+	// LogFile mirrors the logs, including every removed item, to this file like --log-file.
+	// "default" selects ~/Library/Logs/wiper/wiper.log on macOS. Empty disables the log file.
+	LogFile string `json:"log_file"`
+	// LogFileMaxSize is the size at which the log file is rotated (e.g., "10MB"). Default 10 MiB.
+	LogFileMaxSize string `json:"log_file_max_size"`
+	// LogFileBackups is the number of rotated log files kept next to the log file. Default 5.
+	LogFileBackups *int `json:"log_file_backups,omitempty"`
+	// TableStyle selects how summary tables are drawn (e.g., "colored-dark", "rounded", "ascii").
+	// "ascii" renders plain text without Unicode borders or colors, suitable for captured logs.
+	TableStyle string `json:"table_style"`
+	// TableWidth limits the width of summary tables in characters. 0 means unlimited.
+	TableWidth int `json:"table_width"`
+	// TableSort orders the category rows of summary tables: "size" (default), "name" or "count".
+	TableSort string `json:"table_sort"`
+	// TableCounts adds a column with the number of items in each category to summary tables.
+	TableCounts bool `json:"table_counts"`
+	// UpdateCheck controls whether `wiper version` looks up the latest release on GitHub.
+	// It defaults to true; set it to false on machines without GitHub access.
+	// The WIPER_NO_UPDATE_CHECK environment variable disables the check as well.
+	UpdateCheck *bool `json:"update_check,omitempty"`
+	// BrowserProfiles limits browser cache cleaning to these profiles, by name or directory
+	// (e.g., ["Default", "Work"]). Empty means every profile.
+	BrowserProfiles []string `json:"browser_profiles"`
+	// EnableTargets are the opt-in cleanup targets to include (e.g., "photos_caches"), like --enable-targets.
+	EnableTargets []string `json:"enable_targets"`
+	// Downloads configures which files the "Downloads (old)" target cleans and whether they are archived.
+	Downloads DownloadsConfig `json:"downloads"`
+	// ProtectTag is the Finder tag that keeps files and folders out of the Downloads and large file
+	// cleanups. Default "Keep".
+	ProtectTag string `json:"protect_tag"`
+	// Trash moves cleaned items to the Trash instead of deleting them permanently, like --trash.
+	Trash bool `json:"trash"`
+	// Jobs is the number of directories scanned concurrently, like --jobs. 0 means four per CPU.
+	Jobs int `json:"jobs"`
+	// NetworkVolumes makes scans descend into network volumes, like --network-volumes.
+	NetworkVolumes bool `json:"network_volumes"`
+	// Notify posts a desktop notification with the result of every cleanup, like --notify.
+	Notify bool `json:"notify"`
+	// Hooks are shell commands run before and after a cleanup, and before each removed item.
+	Hooks HooksConfig `json:"hooks"`
+	// Webhook posts the summary of every cleanup to a URL, e.g. to collect the results of many Macs.
+	Webhook WebhookConfig `json:"webhook"`
+	// ProtectedPaths are never removed, in addition to the built-in protected paths (keychains, mail,
+	// photo libraries, the system). Entries may start with `~` and end in glob patterns.
+	ProtectedPaths []string `json:"protected_paths"`
+	// Cleaners are external cleaners, whose items are cleaned with the system cleanup.
+	Cleaners []CleanerConfig `json:"cleaners"`
+	// Quarantine keeps cleaned items in wiper's quarantine so `wiper restore` can put them back, like --quarantine.
+	Quarantine bool `json:"quarantine"`
+}
+
+// DownloadsConfig configures the "Downloads (old)" cleanup target.
+// Sizes and ages use the same human-readable forms as the command-line flags.
+type DownloadsConfig struct {
+	// MinAge is how long a download must go unmodified before it is cleaned (e.g., "30d"). Default "90d".
+	MinAge string `json:"min_age"`
+	// Extensions limits the cleanup to these file types (e.g., [".dmg", ".pkg", ".zip"]). Empty means all.
+	Extensions []string `json:"extensions"`
+	// MinSize leaves downloads smaller than this alone (e.g., "50MB"). Empty means no minimum.
+	MinSize string `json:"min_size"`
+	// ArchiveDir moves old downloads into this directory (e.g., "/Volumes/Backup/Downloads")
+	// instead of deleting them.
+	ArchiveDir string `json:"archive_dir"`
+}
+
+// HooksConfig holds the shell commands run around a cleanup (never in dry runs). They are run with
+// `sh -c` and WIPER_* environment variables describing the run.
+type HooksConfig struct {
+	// PreClean runs before the first item is removed (e.g., "docker stop my-db"). If it fails,
+	// nothing is removed.
+	PreClean string `json:"pre_clean"`
+	// PostClean runs after the cleanup, with its result in WIPER_ITEMS, WIPER_RECLAIMED, WIPER_STATUS, ...
+	PostClean string `json:"post_clean"`
+	// PreDeleteItem runs before each item is removed, with WIPER_ITEM_PATH, WIPER_ITEM_SIZE and
+	// WIPER_ITEM_CATEGORY. If it fails, the item is skipped.
+	PreDeleteItem string `json:"pre_delete_item"`
+}
+
+// WebhookConfig configures the webhook that receives the summary of every cleanup.
+type WebhookConfig struct {
+	// URL receives a JSON POST request when a cleanup completes. Empty disables the webhook.
+	URL string `json:"url"`
+	// Template is a Go text/template for the request body (e.g., a Slack message). Empty posts the
+	// run summary as JSON.
+	Template string `json:"template"`
+}
+
+// CleanerConfig declares an external cleaner: a command that prints the items to remove as JSON
+// objects such as {"path": "/path", "category": "Unity Caches"}.
+type CleanerConfig struct {
+	// Name identifies the cleaner in `wiper cleaners` and the run's warnings (e.g., "unity").
+	Name string `json:"name"`
+	// Description says what the cleaner removes. Empty shows the command.
+	Description string `json:"description"`
+	// Command is run with `sh -c` to list the items (e.g., "~/bin/unity-caches --json").
+	Command string `json:"command"`
+}
+
+// Current is the configuration in effect for this run.
+// It starts out empty and is populated by Load during command initialization.
+var Current = &Config{}
+
+// ====================================================================================================
+// PUBLIC FUNCTIONS
+// ====================================================================================================
+
+// Dir returns the directory where wiper keeps its configuration and state files.
+// On macOS this resolves to `~/Library/Application Support/wiper`.
+func Dir() (string, error) {
+	baseDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user config directory: %w", err)
+	}
+	return filepath.Join(baseDir, "wiper"), nil
+}
+
+// DefaultPath returns the location of the configuration file used when --config is not given.
+func DefaultPath() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "config.json")
+}
+
+// Load reads the JSON configuration file at path into Current.
+//
+// Parameters:
+//   - path: The configuration file to read. An empty path means the default location.
+//   - required: When true, a missing file is reported as an error; otherwise it is silently ignored.
+//
+// Returns:
+//   - An error if the file could not be read or parsed.
+func Load(path string, required bool) error {
+	if path == "" {
+		path = DefaultPath()
+	}
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !required {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	Current = cfg
+	return nil
+}
@@ -0,0 +1,471 @@
+package dashboard
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/cleaner"
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/quarantine"
+	"github.com/kodelint/wiper/pkg/reclaimer"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// DATA STRUCTURES
+// ====================================================================================================
+
+// Server is a localhost-only HTTP server that renders the wiper dashboard.
+// It exposes a small JSON API that the page polls, backed by the same cleaner package as the CLI.
+type Server struct {
+	// Addr is the listen address. Only loopback addresses are accepted.
+	Addr string
+	// DryRun forces every cleanup triggered from the dashboard to be simulated.
+	DryRun bool
+	// IgnorePaths are excluded from estimates and cleanups, exactly like --ignore on the CLI.
+	IgnorePaths []string
+
+	// token protects state-changing requests from being forged by other websites.
+	token string
+	// port is the port the server listens on, the only one requests may be addressed to.
+	port string
+	// mu serializes scans and cleanups so two button presses can't run concurrently.
+	mu sync.Mutex
+}
+
+// diskStatus describes the capacity of a volume for the status panel.
+type diskStatus struct {
+	Path  string `json:"path"`
+	Total int64  `json:"total"`
+	Free  int64  `json:"free"`
+	Used  int64  `json:"used"`
+}
+
+// categoryTotal is a single row of the reclaimable estimate.
+type categoryTotal struct {
+	Category string `json:"category"`
+	Bytes    int64  `json:"bytes"`
+	Human    string `json:"human"`
+}
+
+// cleanResult is returned after a profile has been executed.
+type cleanResult struct {
+	Profile   string          `json:"profile"`
+	DryRun    bool            `json:"dryRun"`
+	Reclaimed int64           `json:"reclaimed"`
+	Human     string          `json:"human"`
+	Totals    []categoryTotal `json:"totals"`
+	RunID     string          `json:"runId"`
+}
+
+// historyDay is the space reclaimed on one day, for the history chart.
+type historyDay struct {
+	Date      string `json:"date"`
+	Runs      int    `json:"runs"`
+	Reclaimed int64  `json:"reclaimed"`
+	Human     string `json:"human"`
+}
+
+// historyDays is the number of days the history chart covers.
+const historyDays = 30
+
+// ====================================================================================================
+// CONSTRUCTOR AND METHODS
+// ====================================================================================================
+
+// NewServer creates a dashboard server for the given listen address.
+func NewServer(addr string, dryRun bool, ignorePaths []string) (*Server, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid listen address %s: %w", addr, err)
+	}
+	if !isLoopbackHost(host) {
+		return nil, fmt.Errorf("the dashboard only listens on loopback addresses, got %s", host)
+	}
+
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate dashboard token: %w", err)
+	}
+
+	return &Server{
+		Addr:        addr,
+		DryRun:      dryRun,
+		IgnorePaths: ignorePaths,
+		token:       hex.EncodeToString(tokenBytes),
+	}, nil
+}
+
+// ListenAndServe starts serving the dashboard and blocks until the server fails or ctx is
+// cancelled. Cancelling ctx also stops running cleanups between items.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/api/status", s.handleStatus)
+	mux.HandleFunc("/api/estimate", s.handleEstimate)
+	mux.HandleFunc("/api/history", s.handleHistory)
+	mux.HandleFunc("/api/clean", s.handleClean)
+
+	listener, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	host, _, _ := net.SplitHostPort(s.Addr)
+	_, s.port, _ = net.SplitHostPort(listener.Addr().String())
+
+	server := &http.Server{
+		Handler:     s.checkHost(mux),
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		// Let running requests finish; cleanups among them stop at the next item.
+		server.Shutdown(context.Background())
+	}()
+
+	logger.Log.Infof("Dashboard available at %s", utils.GreenBold("http://"+net.JoinHostPort(host, s.port)+"/"))
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ====================================================================================================
+// HTTP HANDLERS
+// ====================================================================================================
+
+// checkHost rejects requests that aren't addressed to a loopback host on the dashboard's port. A
+// website can point its own host name at 127.0.0.1 (DNS rebinding) and would then read the page,
+// and with it the token, as same-origin; its requests still carry its host name.
+func (s *Server) checkHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, port, err := net.SplitHostPort(r.Host)
+		if err != nil || port != s.port || !isLoopbackHost(host) {
+			http.Error(w, "invalid host", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleIndex renders the single-page dashboard.
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	data := struct {
+		Token    string
+		DryRun   bool
+		Profiles []string
+	}{s.token, s.DryRun, cleaner.Profiles()}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := indexTemplate.Execute(w, data); err != nil {
+		logger.Log.Errorf("Failed to render dashboard: %v", err)
+	}
+}
+
+// handleStatus reports capacity and free space of the root and home volumes.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	paths := []string{"/"}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, home)
+	}
+
+	volumes, err := utils.Volumes()
+	if err != nil {
+		logger.Log.Debugf("Failed to list volumes: %v", err)
+	}
+
+	var disks []diskStatus
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		// The home directory usually lives on the root volume; avoid listing it twice. Without the
+		// list of volumes, each path stands for its own.
+		volume := p
+		if mount, ok := utils.VolumeOf(p, volumes); ok {
+			volume = mount.Path
+		}
+		if seen[volume] {
+			continue
+		}
+		seen[volume] = true
+		free, total, err := utils.VolumeSpace(p)
+		if err != nil {
+			logger.Log.Debugf("Failed to read disk status for %s: %v", p, err)
+			continue
+		}
+		disks = append(disks, diskStatus{Path: volume, Total: total, Free: free, Used: total - free})
+	}
+	writeJSON(w, disks)
+}
+
+// handleEstimate scans the system cleanup targets and returns reclaimable bytes per category.
+func (s *Server) handleEstimate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	estimate, err := cleaner.EstimateSystem(r.Context(), s.IgnorePaths)
+	// The server runs many scans, so report each request's warnings instead of accumulating them.
+	logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, categoryTotals(estimate, false))
+}
+
+// handleHistory returns the space reclaimed per day over the last historyDays days, oldest first,
+// from the runs in the history. Dry runs reclaim nothing and aren't counted.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	runs, err := history.Runs()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	today := time.Now()
+	days := make([]historyDay, historyDays)
+	index := make(map[string]int, historyDays)
+	for i := range days {
+		date := today.AddDate(0, 0, i-historyDays+1).Format("2006-01-02")
+		days[i].Date = date
+		index[date] = i
+	}
+	for _, run := range runs {
+		i, ok := index[run.Time.Local().Format("2006-01-02")]
+		if !ok || run.DryRun {
+			continue
+		}
+		days[i].Runs++
+		days[i].Reclaimed += run.Reclaimed
+	}
+	for i := range days {
+		days[i].Human = utils.FormatBytes(days[i].Reclaimed)
+	}
+	writeJSON(w, days)
+}
+
+// handleClean runs a cleanup profile. It only accepts POST requests carrying the page token.
+func (s *Server) handleClean(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("X-Wiper-Token") != s.token {
+		http.Error(w, "invalid dashboard token", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile := r.URL.Query().Get("profile")
+	summary := reclaimer.NewSummaryTable()
+	// Each button press is a run of its own, with one ID in the logs, its manifest and the history.
+	runID := utils.NewRunID()
+	logger.SetRunID(runID)
+	quarantine.Start(runID, quarantine.Enabled())
+	history.Start(runID, "dashboard", s.DryRun)
+	history.SetMode("profile " + profile)
+	reclaimed, err := cleaner.CleanProfile(r.Context(), profile, s.DryRun, s.IgnorePaths, summary)
+	if histErr := history.Finish(err); histErr != nil {
+		logger.Log.Warnf("Could not record the run in the history: %v", histErr)
+	}
+	logger.RunWarnings.Report(logger.Log, logger.ShowWarnings())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, cleanResult{
+		Profile:   profile,
+		DryRun:    s.DryRun,
+		Reclaimed: reclaimed,
+		Human:     utils.FormatBytes(reclaimed),
+		Totals:    categoryTotals(summary, !s.DryRun),
+		RunID:     runID,
+	})
+}
+
+// ====================================================================================================
+// HELPER FUNCTIONS
+// ====================================================================================================
+
+// categoryTotals aggregates summary entries by category, largest first.
+// When removedOnly is true, only entries that were actually removed are counted;
+// otherwise removed and estimated entries are counted. Skipped and failed entries never count.
+func categoryTotals(summary *reclaimer.SummaryTable, removedOnly bool) []categoryTotal {
+	statuses := []reclaimer.EntryStatus{reclaimer.StatusRemoved}
+	if !removedOnly {
+		statuses = append(statuses, reclaimer.StatusDryRun)
+	}
+
+	grouped := summary.TotalsByCategory(statuses...)
+	totals := make([]categoryTotal, 0, len(grouped))
+	for _, total := range grouped {
+		totals = append(totals, categoryTotal{Category: total.Category, Bytes: total.Bytes, Human: utils.FormatBytes(total.Bytes)})
+	}
+	return totals
+}
+
+// isLoopbackHost reports whether host, without a port, is localhost or a loopback address.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// writeJSON serializes v as the JSON response body.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Log.Errorf("Failed to encode dashboard response: %v", err)
+	}
+}
+
+// indexTemplate is the dashboard page. It is intentionally dependency-free (no external JS or CSS)
+// so it works offline and can't leak anything to third-party hosts.
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>wiper dashboard</title>
+<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; margin: 2rem; background: #1e1e1e; color: #ddd; }
+  h1 { font-weight: 600; }
+  section { margin-bottom: 2rem; }
+  .bar { background: #333; border-radius: 4px; height: 14px; width: 100%; }
+  .fill { background: #4caf50; border-radius: 4px; height: 14px; }
+  table { border-collapse: collapse; width: 100%; max-width: 48rem; }
+  td, th { padding: 0.3rem 0.6rem; text-align: left; border-bottom: 1px solid #333; }
+  button { margin-right: 0.5rem; padding: 0.4rem 0.9rem; }
+  .muted { color: #888; }
+  .chart { display: flex; align-items: flex-end; gap: 2px; height: 120px; max-width: 48rem; border-bottom: 1px solid #333; }
+  .day { flex: 1; background: #4caf50; min-height: 1px; }
+</style>
+</head>
+<body>
+<h1>wiper</h1>
+{{if .DryRun}}<p class="muted">Dry-run mode: cleanups are simulated and nothing is deleted.</p>{{end}}
+
+<section>
+  <h2>Disk status</h2>
+  <div id="disks" class="muted">Loading...</div>
+</section>
+
+<section>
+  <h2>Reclaimable estimate</h2>
+  <button onclick="loadEstimate()">Refresh estimate</button>
+  <table id="estimate"><tr><td class="muted">Scanning...</td></tr></table>
+</section>
+
+<section>
+  <h2>Reclaimed in the last 30 days</h2>
+  <div id="history" class="chart"></div>
+  <p id="history-total" class="muted"></p>
+</section>
+
+<section>
+  <h2>Run a profile</h2>
+  {{range .Profiles}}<button onclick="runProfile('{{.}}')">Clean: {{.}}</button>{{end}}
+  <p id="result" class="muted"></p>
+</section>
+
+<script>
+const token = "{{.Token}}";
+function fmt(b) {
+  const units = ["Bytes", "KB", "MB", "GB", "TB"];
+  let i = 0;
+  while (b >= 1024 && i < units.length - 1) { b /= 1024; i++; }
+  return (i === 0 ? b : b.toFixed(2)) + " " + units[i];
+}
+// el creates an element with the given class and text. Text is never parsed as HTML, since
+// categories and paths come from the file system.
+function el(tag, className, text) {
+  const e = document.createElement(tag);
+  if (className) e.className = className;
+  if (text !== undefined) e.textContent = text;
+  return e;
+}
+function bar(fraction) {
+  const outer = el("div", "bar"), fill = el("div", "fill");
+  fill.style.width = (100 * fraction) + "%";
+  outer.appendChild(fill);
+  return outer;
+}
+function rows(table, totals) {
+  table.replaceChildren();
+  if (!totals || totals.length === 0) {
+    table.appendChild(el("tr")).appendChild(el("td", "muted", "Nothing to reclaim."));
+    return;
+  }
+  const max = totals[0].bytes || 1;
+  for (const t of totals) {
+    const tr = table.appendChild(el("tr"));
+    tr.appendChild(el("td", "", t.category));
+    tr.appendChild(el("td", "", t.human));
+    const td = tr.appendChild(el("td"));
+    td.style.width = "40%";
+    td.appendChild(bar(t.bytes / max));
+  }
+}
+async function loadStatus() {
+  const disks = await (await fetch("/api/status")).json();
+  const div = document.getElementById("disks");
+  div.replaceChildren();
+  div.className = "";
+  for (const d of disks || []) {
+    div.appendChild(el("p", "", d.path + ": " + fmt(d.free) + " free of " + fmt(d.total)));
+    div.appendChild(bar(d.used / d.total));
+  }
+}
+async function loadEstimate() {
+  const table = document.getElementById("estimate");
+  table.replaceChildren();
+  table.appendChild(el("tr")).appendChild(el("td", "muted", "Scanning..."));
+  rows(table, await (await fetch("/api/estimate")).json());
+}
+async function loadHistory() {
+  const days = await (await fetch("/api/history")).json();
+  const chart = document.getElementById("history");
+  chart.replaceChildren();
+  const max = Math.max(1, ...days.map(d => d.reclaimed));
+  let total = 0, runs = 0;
+  for (const d of days) {
+    const day = chart.appendChild(el("div", "day"));
+    day.style.height = (100 * d.reclaimed / max) + "%";
+    day.title = d.date + ": " + d.human + " in " + d.runs + (d.runs === 1 ? " run" : " runs");
+    total += d.reclaimed;
+    runs += d.runs;
+  }
+  document.getElementById("history-total").textContent = fmt(total) + " reclaimed in " + runs + (runs === 1 ? " run." : " runs.");
+}
+async function runProfile(name) {
+  if (!confirm("Run the '" + name + "' cleanup profile?")) return;
+  document.getElementById("result").textContent = "Running " + name + "...";
+  const resp = await fetch("/api/clean?profile=" + encodeURIComponent(name), { method: "POST", headers: { "X-Wiper-Token": token } });
+  if (!resp.ok) { document.getElementById("result").textContent = await resp.text(); return; }
+  const res = await resp.json();
+  document.getElementById("result").textContent = (res.dryRun ? "Would reclaim " : "Reclaimed ") + res.human + " (run " + res.runId + ").";
+  loadStatus(); loadEstimate(); loadHistory();
+}
+loadStatus(); loadEstimate(); loadHistory();
+</script>
+</body>
+</html>
+`))
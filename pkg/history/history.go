@@ -0,0 +1,234 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/config"
+)
+
+// ====================================================================================================
+// DATA STRUCTURES
+// ====================================================================================================
+
+// Run is the history record of one invocation of wiper that cleaned something (or estimated what it
+// would clean, for dry runs).
+type Run struct {
+	// ID is the run ID, as printed in the logs of the run and used by `wiper restore`.
+	ID string `json:"id"`
+	// Time is when the run started.
+	Time time.Time `json:"time"`
+	// Command is the wiper command that was run (e.g., "wipe").
+	Command string `json:"command"`
+	// Mode describes what the command cleaned (e.g., "system cleanup", "uninstall Slack").
+	Mode string `json:"mode,omitempty"`
+	// DryRun is set when nothing was removed and Items and Reclaimed are estimates.
+	DryRun bool `json:"dry_run,omitempty"`
+	// Items is the number of items removed.
+	Items int `json:"items"`
+	// Reclaimed is the number of bytes reclaimed.
+	Reclaimed int64 `json:"reclaimed"`
+	// Failed is the number of items that could not be removed.
+	Failed int `json:"failed,omitempty"`
+	// Duration is how long the run took.
+	Duration time.Duration `json:"duration"`
+	// Error is the error the run ended with, if any (e.g., "cleanup interrupted: context canceled").
+	Error string `json:"error,omitempty"`
+	// Declined is set when the user declined the cleanup at its confirmation prompt.
+	Declined bool `json:"declined,omitempty"`
+}
+
+// Status returns a short description of how the run ended: "dry run", "declined" (at the
+// confirmation prompt), "failed", "partial" (some items could not be removed), or "ok".
+func (r Run) Status() string {
+	switch {
+	case r.DryRun && r.Error == "":
+		return "dry run"
+	case r.Declined:
+		return "declined"
+	case r.Error != "" && r.Failed == 0:
+		return "failed"
+	case r.Failed > 0:
+		return "partial"
+	default:
+		return "ok"
+	}
+}
+
+// fileName is the file, in the configuration directory, that holds the history as JSON lines.
+const fileName = "history.jsonl"
+
+// recorder accumulates the record of the current run.
+type recorder struct {
+	mu      sync.Mutex
+	run     Run
+	cleaned bool // Whether a cleanup ran; runs that never cleaned anything aren't recorded
+}
+
+// current is the recorder of this run. It is nil until Start is called, and nothing is recorded then.
+var current *recorder
+
+// finishHooks are called with every run recorded by Finish (see OnFinish).
+var finishHooks []func(Run)
+
+// ====================================================================================================
+// RECORDING
+// ====================================================================================================
+
+// Start begins the record of run runID of command. Nothing is written until Finish, and only if a
+// cleanup was recorded with Add in between; other commands (e.g., `apps list`) leave no record.
+func Start(runID string, command string, dryRun bool) {
+	current = &recorder{run: Run{ID: runID, Time: time.Now(), Command: command, DryRun: dryRun}}
+}
+
+// CurrentID returns the ID of the current run, or an empty string if no record was started.
+func CurrentID() string {
+	if current == nil {
+		return ""
+	}
+	return current.run.ID
+}
+
+// SetMode describes what the current run cleans (e.g., "large files").
+func SetMode(mode string) {
+	if current == nil {
+		return
+	}
+	current.mu.Lock()
+	current.run.Mode = mode
+	current.mu.Unlock()
+}
+
+// Add adds one batch of processed items to the current run: the number of items removed (or that
+// would be removed, in a dry run), the bytes reclaimed, and the number of failed removals.
+// It does nothing if the record wasn't started.
+func Add(items int, reclaimed int64, failed int) {
+	if current == nil {
+		return
+	}
+	current.mu.Lock()
+	defer current.mu.Unlock()
+	current.cleaned = true
+	current.run.Items += items
+	current.run.Reclaimed += reclaimed
+	current.run.Failed += failed
+}
+
+// Decline records that the user declined the cleanup of the current run at its confirmation
+// prompt. It does nothing if the record wasn't started.
+func Decline() {
+	if current == nil {
+		return
+	}
+	current.mu.Lock()
+	defer current.mu.Unlock()
+	current.run.Declined = true
+}
+
+// OnFinish registers hook to be called with every run Finish records, e.g. to notify the user.
+// Hooks are called even if the history file couldn't be written.
+func OnFinish(hook func(Run)) {
+	finishHooks = append(finishHooks, hook)
+}
+
+// Finish appends the record of the current run to the history, with the error the run ended
+// with (or nil), and ends it. It does nothing if no cleanup was recorded.
+func Finish(runErr error) error {
+	if current == nil {
+		return nil
+	}
+	current.mu.Lock()
+	run, cleaned := current.run, current.cleaned
+	current.mu.Unlock()
+	current = nil
+	if !cleaned {
+		return nil
+	}
+
+	run.Duration = time.Since(run.Time).Round(time.Millisecond)
+	if runErr != nil {
+		run.Error = runErr.Error()
+	}
+	err := appendRun(run)
+	for _, hook := range finishHooks {
+		hook(run)
+	}
+	return err
+}
+
+// appendRun writes run as the last line of the history file.
+func appendRun(run Run) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open the history: %w", err)
+	}
+	line, err := json.Marshal(run)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write the history: %w", err)
+	}
+	return file.Close()
+}
+
+// ====================================================================================================
+// READING
+// ====================================================================================================
+
+// Path returns the history file: `~/Library/Application Support/wiper/history.jsonl` on macOS.
+func Path() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// Runs returns the recorded runs, most recent first. Lines that can't be parsed (e.g., the last line
+// of a history written by a run that was killed) are skipped.
+func Runs() ([]Run, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the history: %w", err)
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Time.After(runs[j].Time) })
+	return runs, nil
+}
@@ -0,0 +1,277 @@
+package i18n
+
+// ====================================================================================================
+// MESSAGE CATALOGS
+// ====================================================================================================
+
+// catalogs maps a language code to its messages.
+// The English catalog is the reference: every key must exist there, other languages may be partial.
+var catalogs = map[string]map[string]string{
+	"en": {
+		// Answers accepted by confirmation prompts (comma-separated).
+		"answer.yes":  "y,yes",
+		"answer.no":   "n,no",
+		"answer.all":  "a,all",
+		"answer.quit": "q,quit",
+
+		// Cleanup target categories.
+		"category.user_temp":        "User Temporary Files",
+		"category.system_temp":      "System Temporary Files",
+		"category.user_caches":      "User Caches",
+		"category.system_caches":    "System Caches",
+		"category.user_logs":        "User Logs",
+		"category.browser_caches":   "Browser Caches",
+		"category.trash":            "Trash Bin",
+		"category.old_downloads":    "Downloads (old)",
+		"category.mail_downloads":   "Mail Downloads (old)",
+		"category.mail_caches":      "Mail Caches (old)",
+		"category.photos_caches":    "Photos Previews and Analysis (rebuilt by Photos)",
+		"category.volume_trash":     "Volume Trash Bin",
+		"category.thumbnails":       "Thumbnail Caches",
+		"category.package_caches":   "Package Manager Caches",
+		"category.journal_archives": "Archived System Journals",
+		"category.snap_revisions":   "Disabled Snap Revisions",
+		"category.flatpak_removed":  "Removed Flatpak Deployments",
+
+		// Developer tool categories.
+		"category.xcode_derived_data":   "Xcode Derived Data",
+		"category.xcode_archives":       "Xcode Archives (old)",
+		"category.xcode_device_support": "Xcode Device Support (old)",
+		"category.simulator_caches":     "Simulator Caches (old)",
+		"category.cocoapods_cache":      "CocoaPods Cache",
+		"category.cocoapods_repos":      "CocoaPods Spec Repos (old)",
+		"category.carthage_cache":       "Carthage Cache",
+		"category.npm_cache":            "npm Cache",
+		"category.yarn_cache":           "Yarn Cache",
+		"category.pnpm_store":           "pnpm Store",
+		"category.go_build_cache":       "Go Build Cache",
+		"category.go_mod_cache":         "Go Module Cache",
+		"category.pip_cache":            "pip Cache",
+		"category.pipenv_cache":         "Pipenv Cache",
+		"category.conda_pkgs":           "Conda Package Cache (old)",
+		"category.gradle_cache":         "Gradle Cache (old)",
+		"category.gradle_daemon_logs":   "Gradle Daemon Logs",
+		"category.maven_repository":     "Maven Repository (old)",
+
+		// Docker categories.
+		"category.docker_containers":  "Docker Stopped Containers",
+		"category.docker_images":      "Docker Dangling Images",
+		"category.docker_build_cache": "Docker Build Cache",
+		"category.docker_volumes":     "Docker Unused Volumes",
+
+		// Confirmation prompts.
+		"prompt.confirm_suffix":  "(y/N)",
+		"prompt.invalid_input":   "Invalid input. Please enter 'y' or 'n'.",
+		"prompt.uninstall":       "Do you really want to uninstall application: %s?",
+		"prompt.cleanup_all":     "Do you want to clean up these items (Total: %s)?",
+		"prompt.delete_item":     "Delete %s (%s, Category: %s)?",
+		"prompt.clean_category":  "Clean %s (%s)?",
+		"prompt.category_suffix": "(y/N/all/quit)",
+		"prompt.invalid_choice":  "Invalid input. Please enter 'y', 'n', 'all' or 'quit'.",
+
+		"prompt.remove_package_files": "Also remove the %d files (%s) its %d installer package(s) put outside the app, and forget their receipts?",
+		"prompt.did_you_mean":         "Did you mean %s?",
+		"prompt.did_you_mean_one_of":  "Did you mean one of these?",
+		"prompt.pick_app":             "Pick an application (1-%d), or press Enter to cancel:",
+		"prompt.invalid_pick":         "Invalid input. Please enter a number from 1 to %d.",
+
+		// Summary tables.
+		"summary.estimated_title":      "Estimated Reclaimed Summary",
+		"summary.reclaimed_title":      "Reclaimed Disk Summary",
+		"summary.header_category":      "CATEGORY",
+		"summary.header_path":          "PATH",
+		"summary.header_size":          "SIZE",
+		"summary.header_status":        "STATUS",
+		"summary.header_error":         "ERROR",
+		"summary.header_reason":        "REASON",
+		"summary.header_scan":          "SCAN",
+		"summary.header_delete":        "DELETE",
+		"summary.header_total":         "TOTAL",
+		"summary.timings_total":        "TOTAL:",
+		"summary.header_reclaimed":     "RECLAIMED",
+		"summary.header_percent_disk":  "% OF DISK",
+		"summary.header_percent_total": "% OF TOTAL",
+		"summary.header_items":         "ITEMS",
+		"summary.header_cloned":        "SHARED WITH CLONES",
+		"summary.footer_total":         "TOTAL RECLAIMED:",
+		"summary.failed_title":         "Failed to remove",
+		"summary.skipped_title":        "Skipped items",
+		"summary.details_title":        "Items",
+		"summary.cloned_note":          "%s more is shared with APFS clones of these files. It is only freed once every copy is removed, so it isn't counted as reclaimed.",
+		"summary.timings_title":        "Timings",
+	},
+	"de": {
+		"answer.yes":  "j,ja",
+		"answer.no":   "n,nein",
+		"answer.all":  "a,alle",
+		"answer.quit": "b,beenden",
+
+		"category.user_temp":        "Temporäre Benutzerdateien",
+		"category.system_temp":      "Temporäre Systemdateien",
+		"category.user_caches":      "Benutzer-Caches",
+		"category.system_caches":    "System-Caches",
+		"category.user_logs":        "Benutzerprotokolle",
+		"category.browser_caches":   "Browser-Caches",
+		"category.trash":            "Papierkorb",
+		"category.old_downloads":    "Downloads (alt)",
+		"category.mail_downloads":   "Mail-Downloads (alt)",
+		"category.mail_caches":      "Mail-Caches (alt)",
+		"category.photos_caches":    "Fotos-Vorschauen und -Analyse (von Fotos neu erstellt)",
+		"category.volume_trash":     "Papierkorb des Volumes",
+		"category.thumbnails":       "Miniaturansichten-Caches",
+		"category.package_caches":   "Paketmanager-Caches",
+		"category.journal_archives": "Archivierte Systemjournale",
+		"category.snap_revisions":   "Deaktivierte Snap-Revisionen",
+		"category.flatpak_removed":  "Entfernte Flatpak-Installationen",
+
+		"category.xcode_derived_data":   "Xcode-DerivedData",
+		"category.xcode_archives":       "Xcode-Archive (alt)",
+		"category.xcode_device_support": "Xcode-Gerätesupport (alt)",
+		"category.simulator_caches":     "Simulator-Caches (alt)",
+		"category.cocoapods_cache":      "CocoaPods-Cache",
+		"category.cocoapods_repos":      "CocoaPods-Spec-Repos (alt)",
+		"category.carthage_cache":       "Carthage-Cache",
+		"category.npm_cache":            "npm-Cache",
+		"category.yarn_cache":           "Yarn-Cache",
+		"category.pnpm_store":           "pnpm-Speicher",
+		"category.go_build_cache":       "Go-Build-Cache",
+		"category.go_mod_cache":         "Go-Modul-Cache",
+		"category.pip_cache":            "pip-Cache",
+		"category.pipenv_cache":         "Pipenv-Cache",
+		"category.conda_pkgs":           "Conda-Paket-Cache (alt)",
+		"category.gradle_cache":         "Gradle-Cache (alt)",
+		"category.gradle_daemon_logs":   "Gradle-Daemon-Protokolle",
+		"category.maven_repository":     "Maven-Repository (alt)",
+
+		"category.docker_containers":  "Gestoppte Docker-Container",
+		"category.docker_images":      "Verwaiste Docker-Images",
+		"category.docker_build_cache": "Docker-Build-Cache",
+		"category.docker_volumes":     "Ungenutzte Docker-Volumes",
+
+		"prompt.confirm_suffix":  "(j/N)",
+		"prompt.invalid_input":   "Ungültige Eingabe. Bitte 'j' oder 'n' eingeben.",
+		"prompt.uninstall":       "Möchten Sie die Anwendung wirklich deinstallieren: %s?",
+		"prompt.cleanup_all":     "Möchten Sie diese Elemente bereinigen (Gesamt: %s)?",
+		"prompt.delete_item":     "%s löschen (%s, Kategorie: %s)?",
+		"prompt.clean_category":  "%s bereinigen (%s)?",
+		"prompt.category_suffix": "(j/N/alle/beenden)",
+		"prompt.invalid_choice":  "Ungültige Eingabe. Bitte 'j', 'n', 'alle' oder 'beenden' eingeben.",
+
+		"prompt.remove_package_files": "Auch die %d Dateien (%s) entfernen, die ihre %d Installationspakete außerhalb der App abgelegt haben, und deren Belege vergessen?",
+		"prompt.did_you_mean":         "Meinten Sie %s?",
+		"prompt.did_you_mean_one_of":  "Meinten Sie eine dieser Anwendungen?",
+		"prompt.pick_app":             "Anwendung wählen (1-%d) oder Eingabetaste zum Abbrechen:",
+		"prompt.invalid_pick":         "Ungültige Eingabe. Bitte eine Zahl von 1 bis %d eingeben.",
+
+		"summary.estimated_title":      "Geschätzte Freigabe",
+		"summary.reclaimed_title":      "Freigegebener Speicher",
+		"summary.header_category":      "KATEGORIE",
+		"summary.header_path":          "PFAD",
+		"summary.header_size":          "GRÖSSE",
+		"summary.header_status":        "STATUS",
+		"summary.header_error":         "FEHLER",
+		"summary.header_reason":        "GRUND",
+		"summary.header_scan":          "SUCHE",
+		"summary.header_delete":        "LÖSCHEN",
+		"summary.header_total":         "GESAMT",
+		"summary.timings_total":        "GESAMT:",
+		"summary.header_reclaimed":     "FREIGEGEBEN",
+		"summary.header_percent_disk":  "% DER FESTPLATTE",
+		"summary.header_percent_total": "% DER SUMME",
+		"summary.header_items":         "ELEMENTE",
+		"summary.header_cloned":        "MIT KLONEN GETEILT",
+		"summary.footer_total":         "GESAMT FREIGEGEBEN:",
+		"summary.failed_title":         "Entfernen fehlgeschlagen",
+		"summary.skipped_title":        "Übersprungene Elemente",
+		"summary.details_title":        "Elemente",
+		"summary.cloned_note":          "Weitere %s teilen diese Dateien mit APFS-Klonen. Sie werden erst frei, wenn alle Kopien entfernt sind, und zählen daher nicht als freigegeben.",
+		"summary.timings_title":        "Laufzeiten",
+	},
+	"es": {
+		"answer.yes":  "s,si,sí",
+		"answer.no":   "n,no",
+		"answer.all":  "t,todo",
+		"answer.quit": "q,salir",
+
+		"category.user_temp":        "Archivos temporales del usuario",
+		"category.system_temp":      "Archivos temporales del sistema",
+		"category.user_caches":      "Cachés del usuario",
+		"category.system_caches":    "Cachés del sistema",
+		"category.user_logs":        "Registros del usuario",
+		"category.browser_caches":   "Cachés del navegador",
+		"category.trash":            "Papelera",
+		"category.old_downloads":    "Descargas (antiguas)",
+		"category.mail_downloads":   "Descargas de Mail (antiguas)",
+		"category.mail_caches":      "Cachés de Mail (antiguas)",
+		"category.photos_caches":    "Previsualizaciones y análisis de Fotos (Fotos los regenera)",
+		"category.volume_trash":     "Papelera del volumen",
+		"category.thumbnails":       "Cachés de miniaturas",
+		"category.package_caches":   "Cachés de gestores de paquetes",
+		"category.journal_archives": "Diarios del sistema archivados",
+		"category.snap_revisions":   "Revisiones de Snap desactivadas",
+		"category.flatpak_removed":  "Instalaciones de Flatpak eliminadas",
+
+		"category.xcode_derived_data":   "Datos derivados de Xcode",
+		"category.xcode_archives":       "Archivos de Xcode (antiguos)",
+		"category.xcode_device_support": "Soporte de dispositivos de Xcode (antiguo)",
+		"category.simulator_caches":     "Cachés del simulador (antiguas)",
+		"category.cocoapods_cache":      "Caché de CocoaPods",
+		"category.cocoapods_repos":      "Repositorios de specs de CocoaPods (antiguos)",
+		"category.carthage_cache":       "Caché de Carthage",
+		"category.npm_cache":            "Caché de npm",
+		"category.yarn_cache":           "Caché de Yarn",
+		"category.pnpm_store":           "Almacén de pnpm",
+		"category.go_build_cache":       "Caché de compilación de Go",
+		"category.go_mod_cache":         "Caché de módulos de Go",
+		"category.pip_cache":            "Caché de pip",
+		"category.pipenv_cache":         "Caché de Pipenv",
+		"category.conda_pkgs":           "Caché de paquetes de Conda (antiguos)",
+		"category.gradle_cache":         "Caché de Gradle (antigua)",
+		"category.gradle_daemon_logs":   "Registros del daemon de Gradle",
+		"category.maven_repository":     "Repositorio de Maven (antiguo)",
+
+		"category.docker_containers":  "Contenedores de Docker detenidos",
+		"category.docker_images":      "Imágenes de Docker huérfanas",
+		"category.docker_build_cache": "Caché de compilación de Docker",
+		"category.docker_volumes":     "Volúmenes de Docker sin usar",
+
+		"prompt.confirm_suffix":  "(s/N)",
+		"prompt.invalid_input":   "Entrada no válida. Introduzca 's' o 'n'.",
+		"prompt.uninstall":       "¿Realmente desea desinstalar la aplicación: %s?",
+		"prompt.cleanup_all":     "¿Desea limpiar estos elementos (Total: %s)?",
+		"prompt.delete_item":     "¿Eliminar %s (%s, Categoría: %s)?",
+		"prompt.clean_category":  "¿Limpiar %s (%s)?",
+		"prompt.category_suffix": "(s/N/todo/salir)",
+		"prompt.invalid_choice":  "Entrada no válida. Introduzca 's', 'n', 'todo' o 'salir'.",
+
+		"prompt.remove_package_files": "¿Eliminar también los %d archivos (%s) que sus %d paquetes de instalación dejaron fuera de la aplicación y olvidar sus recibos?",
+		"prompt.did_you_mean":         "¿Quiso decir %s?",
+		"prompt.did_you_mean_one_of":  "¿Quiso decir una de estas?",
+		"prompt.pick_app":             "Elija una aplicación (1-%d) o pulse Intro para cancelar:",
+		"prompt.invalid_pick":         "Entrada no válida. Introduzca un número del 1 al %d.",
+
+		"summary.estimated_title":      "Resumen estimado",
+		"summary.reclaimed_title":      "Resumen de espacio recuperado",
+		"summary.header_category":      "CATEGORÍA",
+		"summary.header_path":          "RUTA",
+		"summary.header_size":          "TAMAÑO",
+		"summary.header_status":        "ESTADO",
+		"summary.header_error":         "ERROR",
+		"summary.header_reason":        "MOTIVO",
+		"summary.header_scan":          "ANÁLISIS",
+		"summary.header_delete":        "BORRADO",
+		"summary.header_total":         "TOTAL",
+		"summary.timings_total":        "TOTAL:",
+		"summary.header_reclaimed":     "RECUPERADO",
+		"summary.header_percent_disk":  "% DEL DISCO",
+		"summary.header_percent_total": "% DEL TOTAL",
+		"summary.header_items":         "ELEMENTOS",
+		"summary.header_cloned":        "COMPARTIDO CON CLONES",
+		"summary.footer_total":         "TOTAL RECUPERADO:",
+		"summary.failed_title":         "No se pudo eliminar",
+		"summary.skipped_title":        "Elementos omitidos",
+		"summary.details_title":        "Elementos",
+		"summary.cloned_note":          "Otros %s se comparten con clones APFS de estos archivos. Solo se liberan al eliminar todas las copias, por lo que no cuentan como recuperados.",
+		"summary.timings_title":        "Tiempos",
+	},
+}
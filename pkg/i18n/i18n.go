@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ====================================================================================================
+// GLOBAL VARIABLES
+// ====================================================================================================
+
+// defaultLanguage is the catalog used when no translation exists for the active language.
+const defaultLanguage = "en"
+
+// currentLanguage is the language selected for this run.
+// It can be changed via the SetLanguage function.
+var currentLanguage = defaultLanguage
+
+// ====================================================================================================
+// PUBLIC FUNCTIONS
+// ====================================================================================================
+
+// SetLanguage selects the message catalog used by T.
+// Unknown languages fall back to English, so callers don't need to validate the value first.
+func SetLanguage(lang string) {
+	lang = normalizeLanguage(lang)
+	if _, ok := catalogs[lang]; !ok {
+		lang = defaultLanguage
+	}
+	currentLanguage = lang
+}
+
+// Language returns the language currently in use.
+func Language() string {
+	return currentLanguage
+}
+
+// DetectLanguage derives a language code from the standard locale environment variables.
+// LC_ALL takes precedence over LC_MESSAGES, which takes precedence over LANG, mirroring POSIX.
+func DetectLanguage() string {
+	for _, envVar := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(envVar); value != "" {
+			return normalizeLanguage(value)
+		}
+	}
+	return defaultLanguage
+}
+
+// T returns the localized message for key in the current language.
+// If args are given, the message is treated as a format string.
+// Missing translations fall back to English, and missing keys fall back to the key itself.
+func T(key string, args ...interface{}) string {
+	message, ok := catalogs[currentLanguage][key]
+	if !ok {
+		message, ok = catalogs[defaultLanguage][key]
+	}
+	if !ok {
+		message = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// IsYes reports whether input is an affirmative answer in the current language.
+// English answers are always accepted as well.
+func IsYes(input string) bool {
+	return matchesAnswer(input, "answer.yes")
+}
+
+// IsNo reports whether input is a negative answer in the current language.
+// English answers are always accepted as well.
+func IsNo(input string) bool {
+	return matchesAnswer(input, "answer.no")
+}
+
+// IsAll reports whether input means "yes to this and everything that follows" in the current language.
+// English answers are always accepted as well.
+func IsAll(input string) bool {
+	return matchesAnswer(input, "answer.all")
+}
+
+// IsQuit reports whether input means "stop asking and skip the rest" in the current language.
+// English answers are always accepted as well.
+func IsQuit(input string) bool {
+	return matchesAnswer(input, "answer.quit")
+}
+
+// ====================================================================================================
+// HELPER FUNCTIONS
+// ====================================================================================================
+
+// normalizeLanguage turns a locale such as "de_DE.UTF-8" into a bare language code ("de").
+func normalizeLanguage(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if idx := strings.IndexAny(locale, "_.@-"); idx >= 0 {
+		locale = locale[:idx]
+	}
+	if locale == "" || locale == "c" || locale == "posix" {
+		return defaultLanguage
+	}
+	return locale
+}
+
+// matchesAnswer checks input against the comma-separated answers stored under key.
+func matchesAnswer(input string, key string) bool {
+	answers := T(key)
+	if currentLanguage != defaultLanguage {
+		answers += "," + catalogs[defaultLanguage][key]
+	}
+	for _, answer := range strings.Split(answers, ",") {
+		if input == strings.TrimSpace(answer) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/fatih/color"
+)
+
+// ====================================================================================================
+// CONSOLE HANDLER
+// ====================================================================================================
+
+// consoleHandler is a slog.Handler that reproduces wiper's original console output:
+// a color-coded level prefix, the date and time, and the message. Errors additionally
+// carry the short source file and line, like the previous log.Lshortfile flag.
+type consoleHandler struct {
+	out   io.Writer
+	level slog.Leveler
+	attrs []slog.Attr
+	group string
+	mu    *sync.Mutex
+}
+
+// consoleHiddenKeys are attributes that belong in structured output but are too noisy for a terminal.
+// The path and size of items are part of the messages already.
+var consoleHiddenKeys = map[string]bool{
+	"run_id": true,
+	"path":   true,
+	"size":   true,
+}
+
+// newConsoleHandler creates the default colored console handler.
+func newConsoleHandler(out io.Writer, level slog.Leveler) *consoleHandler {
+	return &consoleHandler{out: out, level: level, mu: &sync.Mutex{}}
+}
+
+// consoleValue formats an attribute value, quoted if it is empty or contains spaces, quotes, '='
+// or characters that aren't printable.
+func consoleValue(value slog.Value) string {
+	text := value.Resolve().String()
+	if text == "" || strings.IndexFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+	}) >= 0 {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
+// Enabled reports whether records at the given level should be emitted.
+func (h *consoleHandler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return lvl >= h.level.Level()
+}
+
+// Handle formats and writes a single record.
+func (h *consoleHandler) Handle(_ context.Context, record slog.Record) error {
+	var sb strings.Builder
+	sb.WriteString(levelPrefix(record.Level))
+	sb.WriteString(record.Time.Format("2006/01/02 15:04:05 "))
+
+	// Errors keep the file:line location for easier troubleshooting.
+	if record.Level >= slog.LevelError && record.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{record.PC})
+		frame, _ := frames.Next()
+		fmt.Fprintf(&sb, "%s:%d: ", filepath.Base(frame.File), frame.Line)
+	}
+	sb.WriteString(record.Message)
+
+	// Append context fields as key=value pairs after the message, quoting values such as
+	// category="User Caches" like the text handler does, so they can't run into the next pair.
+	writeAttr := func(attr slog.Attr) {
+		if consoleHiddenKeys[attr.Key] {
+			return
+		}
+		key := attr.Key
+		if h.group != "" {
+			key = h.group + "." + key
+		}
+		fmt.Fprintf(&sb, " %s=%s", key, consoleValue(attr.Value))
+	}
+	for _, attr := range h.attrs {
+		writeAttr(attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(attr)
+		return true
+	})
+
+	if !strings.HasSuffix(sb.String(), "\n") {
+		sb.WriteString("\n")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.out, sb.String())
+	return err
+}
+
+// WithAttrs returns a handler that includes the given attributes on every record.
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup returns a handler that prefixes attribute keys with the group name.
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	if clone.group != "" {
+		name = clone.group + "." + name
+	}
+	clone.group = name
+	return &clone
+}
+
+// levelPrefix returns the color-coded prefix for a level.
+func levelPrefix(lvl slog.Level) string {
+	switch {
+	case lvl >= slog.LevelError:
+		return color.New(color.FgRed, color.Bold).Sprint("ERROR: ") // Errors are bold red for emphasis.
+	case lvl >= slog.LevelWarn:
+		return color.New(color.FgYellow).Sprint("WARN:  ")
+	case lvl >= slog.LevelInfo:
+		return color.New(color.FgGreen).Sprint("INFO:  ")
+	default:
+		return color.New(color.FgHiBlack).Sprint("DEBUG: ") // Debug logs are a subtle, high-intensity black.
+	}
+}
@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ====================================================================================================
+// WARNING DEDUPLICATION
+// ====================================================================================================
+
+// warningGroupDepth is the number of leading path components used to group warnings,
+// e.g. "/private/var/folders" or "/Users/alice/Library".
+const warningGroupDepth = 3
+
+// WarningCollector collapses repetitive per-path warnings (such as thousands of
+// "permission denied" errors below the same directory) into one line per reason and root.
+// Every individual warning is still available at debug level. It is safe for concurrent use.
+type WarningCollector struct {
+	mu     sync.Mutex
+	groups map[warningKey]int
+	order  []warningKey
+	// single remembers the path and error of groups with exactly one warning,
+	// so those can be reported in full instead of as "(1 path)".
+	single map[warningKey]string
+	// categories records which cleanup categories reported each reason, for Report's summary line.
+	categories map[string]map[string]bool
+	reasons    []string
+}
+
+// RunWarnings collects the scan warnings of the current run. Scans add to it as they go,
+// and commands print it with Report once their summary tables are shown, so warnings
+// don't interleave with progress output mid-run.
+var RunWarnings = NewWarningCollector()
+
+// warningKey identifies a group of collapsed warnings.
+type warningKey struct {
+	reason string
+	root   string
+}
+
+// NewWarningCollector creates an empty WarningCollector.
+func NewWarningCollector() *WarningCollector {
+	return &WarningCollector{
+		groups:     make(map[warningKey]int),
+		single:     make(map[warningKey]string),
+		categories: make(map[string]map[string]bool),
+	}
+}
+
+// Add records a warning about path. The full detail is logged immediately at debug level,
+// while the collapsed summary is emitted by Flush or Report.
+//
+// Parameters:
+//   - category: The cleanup category that was being scanned (e.g., "User Caches").
+//   - reason: A short, human-readable reason used for grouping (e.g., "permission denied").
+//   - path: The path the warning is about.
+//   - err: The underlying error, if any.
+func (c *WarningCollector) Add(category, reason, path string, err error) {
+	Log.With("category", category).Debugf("%s: %s: %v", reason, path, err)
+
+	key := warningKey{reason: reason, root: warningRoot(path)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.categories[reason] == nil {
+		c.categories[reason] = make(map[string]bool)
+		c.reasons = append(c.reasons, reason)
+	}
+	c.categories[reason][category] = true
+	if _, ok := c.groups[key]; !ok {
+		c.order = append(c.order, key)
+		c.single[key] = fmt.Sprintf("%s: %v", path, err)
+	} else {
+		delete(c.single, key)
+	}
+	c.groups[key]++
+}
+
+// Count returns the total number of warnings recorded, across all groups.
+func (c *WarningCollector) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	total := 0
+	for _, n := range c.groups {
+		total += n
+	}
+	return total
+}
+
+// Flush writes one warning per group to l, in the order the groups were first seen,
+// and resets the collector.
+func (c *WarningCollector) Flush(l *Logger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range c.order {
+		reason := capitalize(key.reason)
+		if detail, ok := c.single[key]; ok {
+			l.Warnf("%s: %s", reason, detail)
+			continue
+		}
+		l.Warnf("%s under %s (%s paths)", reason, key.root, formatCount(c.groups[key]))
+	}
+	c.reset()
+}
+
+// Report prints the collected warnings and resets the collector. With detailed set (e.g., -v),
+// every group is listed as by Flush; either way a compact summary line follows, such as
+// "Warnings: permission denied (1,243 paths in 3 categories), in use (2 paths in 1 category)".
+func (c *WarningCollector) Report(l *Logger, detailed bool) {
+	c.mu.Lock()
+	if len(c.order) == 0 {
+		c.mu.Unlock()
+		return
+	}
+	counts := make(map[string]int)
+	for key, n := range c.groups {
+		counts[key.reason] += n
+	}
+	parts := make([]string, 0, len(c.reasons))
+	for _, reason := range c.reasons {
+		parts = append(parts, fmt.Sprintf("%s (%s in %s)", reason,
+			plural(counts[reason], "path", "paths"), plural(len(c.categories[reason]), "category", "categories")))
+	}
+	c.mu.Unlock()
+
+	if detailed {
+		c.Flush(l)
+	} else {
+		c.mu.Lock()
+		c.reset()
+		c.mu.Unlock()
+	}
+
+	summary := "Warnings: " + strings.Join(parts, ", ")
+	if !detailed {
+		summary += ". Re-run with -v (or --show-warnings) to see the affected directories."
+	}
+	l.Warn(summary)
+}
+
+// reset clears all collected warnings. The caller must hold c.mu.
+func (c *WarningCollector) reset() {
+	c.groups = make(map[warningKey]int)
+	c.single = make(map[warningKey]string)
+	c.categories = make(map[string]map[string]bool)
+	c.order = nil
+	c.reasons = nil
+}
+
+// warningRoot returns the ancestor of path that its warnings are grouped under.
+func warningRoot(path string) string {
+	cleaned := filepath.Clean(path)
+	parts := strings.Split(strings.TrimPrefix(cleaned, string(filepath.Separator)), string(filepath.Separator))
+	if len(parts) <= warningGroupDepth {
+		return filepath.Dir(cleaned)
+	}
+	root := filepath.Join(parts[:warningGroupDepth]...)
+	if filepath.IsAbs(cleaned) {
+		root = string(filepath.Separator) + root
+	}
+	return root
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// plural formats n with the singular or plural form of a noun (e.g., "1 path", "1,243 paths").
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return formatCount(n) + " " + pluralForm
+}
+
+// formatCount formats n with thousands separators (e.g., 1,243).
+func formatCount(n int) string {
+	digits := fmt.Sprintf("%d", n)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
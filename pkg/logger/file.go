@@ -0,0 +1,218 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sync"
+)
+
+// ====================================================================================================
+// LOG FILE
+// ====================================================================================================
+
+// Defaults for EnableLogFile.
+const (
+	// DefaultLogFileMaxSize is the size at which the log file is rotated (10 MiB).
+	DefaultLogFileMaxSize = 10 << 20
+	// DefaultLogFileBackups is the number of rotated log files that are kept.
+	DefaultLogFileBackups = 5
+)
+
+// logFile is the active log file, or nil when logs aren't mirrored to a file.
+var logFile *rotatingFile
+
+// fileOnlyKey marks the context of records that are only written to the log file (see Detailf).
+type fileOnlyKey struct{}
+
+// DefaultLogFilePath returns where the log file is kept unless configured otherwise:
+// `~/Library/Logs/wiper/wiper.log` on macOS, and `$XDG_STATE_HOME/wiper/wiper.log`
+// (`~/.local/state/wiper/wiper.log`) on other systems.
+func DefaultLogFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library", "Logs", "wiper", "wiper.log"), nil
+	}
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, "wiper", "wiper.log"), nil
+}
+
+// EnableLogFile mirrors every record to the file at path, in addition to the regular output: in
+// the text format, without colors, and including informational records and per-item details (see
+// Detailf) even when the console is quiet. Once the file reaches maxSize bytes, it is rotated to
+// path.1 (and path.1 to path.2, ...), keeping at most backups rotated files.
+// This leaves an audit trail of what scheduled runs removed.
+func EnableLogFile(path string, maxSize int64, backups int) error {
+	if maxSize <= 0 {
+		maxSize = DefaultLogFileMaxSize
+	}
+	if backups < 0 {
+		backups = 0
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	file := &rotatingFile{path: path, maxSize: maxSize, backups: backups}
+	if err := file.open(); err != nil {
+		return err
+	}
+	logFile = file
+	Log = NewLogger(output)
+	return nil
+}
+
+// LogFilePath returns the path of the active log file, or an empty string if there is none.
+func LogFilePath() string {
+	if logFile == nil {
+		return ""
+	}
+	return logFile.path
+}
+
+// Detailf logs a formatted per-item detail (e.g., "Removed <path>"). It is printed on the console
+// with -vv (see ShowDetails), and always written to the log file.
+func (l *Logger) Detailf(format string, v ...interface{}) {
+	switch {
+	case ShowDetails():
+		l.log(context.Background(), slog.LevelInfo, fmt.Sprintf(format, v...))
+	case logFile != nil:
+		l.log(context.WithValue(context.Background(), fileOnlyKey{}, true), slog.LevelInfo, fmt.Sprintf(format, v...))
+	}
+}
+
+// fileLevel is the minimum level of the log file: informational records are always written, and
+// debug records when debug logging is enabled.
+type fileLevel struct{}
+
+// Level implements slog.Leveler.
+func (fileLevel) Level() slog.Level {
+	return min(level.Level(), slog.LevelInfo)
+}
+
+// fileLogHandler wraps another handler and mirrors records to the log file.
+type fileLogHandler struct {
+	next slog.Handler
+	file slog.Handler
+}
+
+// newFileLogHandler wraps next so records are also written to file.
+func newFileLogHandler(next slog.Handler, file io.Writer) *fileLogHandler {
+	return &fileLogHandler{
+		next: next,
+		file: slog.NewTextHandler(&ansiStripWriter{out: file}, &slog.HandlerOptions{Level: fileLevel{}}),
+	}
+}
+
+// fileOnly reports whether the record of ctx is only meant for the log file.
+func fileOnly(ctx context.Context) bool {
+	only, _ := ctx.Value(fileOnlyKey{}).(bool)
+	return only
+}
+
+// Enabled reports whether the record is written to the log file or to the wrapped handler.
+func (h *fileLogHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.file.Enabled(ctx, lvl) || (!fileOnly(ctx) && h.next.Enabled(ctx, lvl))
+}
+
+// Handle writes the record to the log file and, unless it's file-only, to the wrapped handler.
+func (h *fileLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if h.file.Enabled(ctx, record.Level) {
+		_ = h.file.Handle(ctx, record.Clone())
+	}
+	if !fileOnly(ctx) && h.next.Enabled(ctx, record.Level) {
+		return h.next.Handle(ctx, record)
+	}
+	return nil
+}
+
+// WithAttrs returns a handler that includes the given attributes on every record.
+func (h *fileLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &fileLogHandler{next: h.next.WithAttrs(attrs), file: h.file.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler that groups subsequent attributes under name.
+func (h *fileLogHandler) WithGroup(name string) slog.Handler {
+	return &fileLogHandler{next: h.next.WithGroup(name), file: h.file.WithGroup(name)}
+}
+
+// ansiEscape matches the color sequences of messages formatted for the terminal.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// ansiStripWriter removes terminal color sequences before writing to out.
+type ansiStripWriter struct {
+	out io.Writer
+}
+
+// Write writes p without color sequences. It reports len(p) bytes written on success.
+func (w *ansiStripWriter) Write(p []byte) (int, error) {
+	if _, err := w.out.Write(ansiEscape.ReplaceAll(p, nil)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// rotatingFile is an append-only file that is rotated once it grows beyond maxSize.
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+// open opens (or creates) the file for appending.
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open the log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open the log file: %w", err)
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// Write appends p, rotating the file first if p would take it beyond maxSize.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate shifts path.N to path.N+1 (dropping the oldest), moves the current file to path.1, and
+// starts a new one. Without backups, the file is truncated instead.
+func (f *rotatingFile) rotate() error {
+	f.file.Close()
+	if f.backups == 0 {
+		_ = os.Remove(f.path)
+	} else {
+		_ = os.Remove(fmt.Sprintf("%s.%d", f.path, f.backups))
+		for i := f.backups - 1; i >= 1; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+		}
+		_ = os.Rename(f.path, f.path+".1")
+	}
+	return f.open()
+}
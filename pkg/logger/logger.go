@@ -1,32 +1,59 @@
 package logger
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
-
-	"github.com/fatih/color"
+	"runtime"
+	"time"
 )
 
 // ====================================================================================================
 // DATA STRUCTURES AND GLOBAL VARIABLES
 // ====================================================================================================
 
-// Logger provides a simple, color-coded logging interface.
-// It wraps standard log.Logger instances for different log levels.
+// Logger provides a simple, leveled logging interface on top of `log/slog`.
+// The default handler keeps wiper's familiar color-coded console output, while the
+// text and JSON handlers emit structured records for log pipelines.
 type Logger struct {
-	info  *log.Logger
-	warn  *log.Logger
-	err   *log.Logger
-	debug *log.Logger
+	slog *slog.Logger
 }
 
+// Supported values for SetFormat.
+const (
+	// FormatConsole is the default, human-friendly colored output.
+	FormatConsole = "console"
+	// FormatText emits slog's logfmt-style key=value records.
+	FormatText = "text"
+	// FormatJSON emits one JSON object per record.
+	FormatJSON = "json"
+)
+
 // Log is the global logger instance used throughout the application.
 // This provides a single, easy-to-use logging interface.
 var Log *Logger
 
-// debugEnabled is a flag to control whether debug logs are printed.
-// It can be toggled via the SetDebug function.
-var debugEnabled bool
+// level holds the minimum level that is emitted. It is shared by all handlers
+// so SetDebug takes effect regardless of the selected format.
+var level = new(slog.LevelVar)
+
+// output is the destination for log records, kept so SetFormat can rebuild the handler.
+var output io.Writer = os.Stdout
+
+// format is the currently selected output format.
+var format = FormatConsole
+
+// verbosity is the detail level selected with -v/-vv/-vvv.
+// 0 is the default, 1 shows warnings, 2 adds per-item details, and 3 enables debug logging.
+var verbosity int
+
+// quiet is set by SetQuiet; informational records are dropped.
+var quiet bool
+
+// baseAttrs are context fields (such as the run ID) attached to every record of the global logger.
+var baseAttrs []any
 
 // ====================================================================================================
 // INITIALIZATION
@@ -35,83 +62,196 @@ var debugEnabled bool
 // init sets up the global logger instance with color-coded output.
 // This function is automatically called by the Go runtime at startup.
 func init() {
-	Log = NewLogger(os.Stdout)
-}
-
-// NewLogger creates a new Logger instance.
-// It configures different loggers for info, warn, error, and debug levels,
-// each with its own color-coded prefix.
-func NewLogger(out *os.File) *Logger {
-	// Define color-coded sprint functions for each log level.
-	info := color.New(color.FgGreen).SprintFunc()
-	warn := color.New(color.FgYellow).SprintFunc()
-	err := color.New(color.FgRed, color.Bold).SprintFunc() // Errors are bold red for emphasis.
-	debug := color.New(color.FgHiBlack).SprintFunc()       // Debug logs are a subtle, high-intensity black.
-
-	return &Logger{
-		// Info logs are prefixed with "INFO:  " and are green.
-		info: log.New(out, info("INFO:  "), log.Ldate|log.Ltime),
-		// Warn logs are prefixed with "WARN:  " and are yellow.
-		warn: log.New(out, warn("WARN:  "), log.Ldate|log.Ltime),
-		// Error logs are prefixed with "ERROR: " and are bold red.
-		err: log.New(out, err("ERROR: "), log.Ldate|log.Ltime|log.Lshortfile),
-		// Debug logs are prefixed with "DEBUG: " and are high-intensity black.
-		debug: log.New(out, debug("DEBUG: "), log.Ldate|log.Ltime),
+	level.Set(slog.LevelInfo)
+	Log = NewLogger(output)
+}
+
+// NewLogger creates a new Logger instance writing to out in the current format.
+func NewLogger(out io.Writer) *Logger {
+	// Records are written around the live status line, if one is active (see SetStatusLine).
+	return &Logger{slog: slog.New(newHandler(&statusAwareWriter{out: out}, format)).With(baseAttrs...)}
+}
+
+// newHandler builds the slog.Handler that corresponds to the given format.
+// When system log forwarding is enabled, the handler is wrapped so warnings and errors are mirrored there,
+// and when a log file is enabled, so that every record is mirrored to it.
+func newHandler(out io.Writer, format string) slog.Handler {
+	var handler slog.Handler
+	switch format {
+	// Structured records are meant for log pipelines, so terminal colors are removed from their messages.
+	case FormatJSON:
+		handler = slog.NewJSONHandler(&ansiStripWriter{out: out}, &slog.HandlerOptions{Level: level, AddSource: true})
+	case FormatText:
+		handler = slog.NewTextHandler(&ansiStripWriter{out: out}, &slog.HandlerOptions{Level: level})
+	default:
+		handler = newConsoleHandler(out, level)
 	}
+	if logFile != nil {
+		handler = newFileLogHandler(handler, logFile)
+	}
+	if systemLog != nil {
+		handler = &systemLogHandler{next: handler, writer: systemLog}
+	}
+	return handler
 }
 
 // ====================================================================================================
-// PUBLIC METHODS
+// PUBLIC FUNCTIONS
 // ====================================================================================================
 
 // SetDebug enables or disables debug logging.
 // This function is typically called based on a command-line flag.
 func SetDebug(enabled bool) {
-	debugEnabled = enabled
+	if enabled {
+		level.Set(slog.LevelDebug)
+	} else {
+		level.Set(slog.LevelInfo)
+	}
+}
+
+// SetVerbosity sets the detail level selected on the command line.
+// Levels of 3 and above also enable debug logging.
+func SetVerbosity(v int) {
+	verbosity = v
+	if v >= 3 {
+		SetDebug(true)
+	}
+}
+
+// SetQuiet drops informational records, so only warnings, errors, and what commands print
+// themselves (such as summary tables) remain. It replaces the level chosen by SetDebug.
+func SetQuiet(enabled bool) {
+	quiet = enabled
+	if enabled {
+		level.Set(slog.LevelWarn)
+	}
+}
+
+// Quiet reports whether informational output is suppressed (see SetQuiet).
+func Quiet() bool {
+	return quiet
+}
+
+// Verbosity returns the current detail level.
+func Verbosity() int {
+	return verbosity
+}
+
+// ShowWarnings reports whether per-path warnings (e.g., permission denied while scanning) should be printed.
+// When false, RunWarnings.Report collapses them into a single summary line.
+func ShowWarnings() bool {
+	return verbosity >= 1
+}
+
+// ShowDetails reports whether per-item details (each found or removed path) should be printed.
+func ShowDetails() bool {
+	return verbosity >= 2
+}
+
+// SetFormat switches the global logger to the console, text, or JSON handler. Structured records
+// are written to standard error, so they don't mix with the tables and reports on standard output.
+func SetFormat(newFormat string) error {
+	switch newFormat {
+	case "", FormatConsole:
+		newFormat = FormatConsole
+	case FormatText, FormatJSON:
+	default:
+		return fmt.Errorf("unknown log format %q (expected %s, %s or %s)", newFormat, FormatConsole, FormatText, FormatJSON)
+	}
+	format = newFormat
+	if format != FormatConsole {
+		output = os.Stderr
+	}
+	Log = NewLogger(output)
+	return nil
+}
+
+// SetOutput sends the records of the global logger to out instead of standard output, e.g. to
+// standard error for commands whose standard output is a report.
+func SetOutput(out io.Writer) {
+	output = out
+	Log = NewLogger(output)
+}
+
+// SetRunID attaches a run identifier to every record emitted by the global logger.
+// This makes it possible to correlate all log lines of a single wiper invocation. Commands that
+// run several cleanups (e.g., `watch`) call it again for each one, replacing the previous ID.
+func SetRunID(runID string) {
+	attrs := baseAttrs[:0:0]
+	for _, attr := range baseAttrs {
+		if a, ok := attr.(slog.Attr); !ok || a.Key != "run_id" {
+			attrs = append(attrs, attr)
+		}
+	}
+	baseAttrs = append(attrs, slog.String("run_id", runID))
+	Log = NewLogger(output)
+}
+
+// ====================================================================================================
+// PUBLIC METHODS
+// ====================================================================================================
+
+// With returns a child logger that adds the given key/value pairs (e.g., "category", "User Caches")
+// to every record it emits.
+func (l *Logger) With(args ...any) *Logger {
+	return &Logger{slog: l.slog.With(args...)}
 }
 
 // Info logs an informational message.
 func (l *Logger) Info(v ...interface{}) {
-	l.info.Println(v...)
+	l.log(context.Background(), slog.LevelInfo, fmt.Sprint(v...))
 }
 
 // Infof logs a formatted informational message.
 func (l *Logger) Infof(format string, v ...interface{}) {
-	l.info.Printf(format, v...)
+	l.log(context.Background(), slog.LevelInfo, fmt.Sprintf(format, v...))
 }
 
 // Warn logs a warning message.
 func (l *Logger) Warn(v ...interface{}) {
-	l.warn.Println(v...)
+	l.log(context.Background(), slog.LevelWarn, fmt.Sprint(v...))
 }
 
 // Warnf logs a formatted warning message.
 func (l *Logger) Warnf(format string, v ...interface{}) {
-	l.warn.Printf(format, v...)
+	l.log(context.Background(), slog.LevelWarn, fmt.Sprintf(format, v...))
 }
 
 // Error logs an error message.
 func (l *Logger) Error(v ...interface{}) {
-	l.err.Println(v...)
+	l.log(context.Background(), slog.LevelError, fmt.Sprint(v...))
 }
 
 // Errorf logs a formatted error message.
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	l.err.Printf(format, v...)
+	l.log(context.Background(), slog.LevelError, fmt.Sprintf(format, v...))
 }
 
 // Debug logs a debug message.
 // The message is only printed if debug logging is enabled.
 func (l *Logger) Debug(v ...interface{}) {
-	if debugEnabled {
-		l.debug.Println(v...)
+	if l.slog.Enabled(context.Background(), slog.LevelDebug) {
+		l.log(context.Background(), slog.LevelDebug, fmt.Sprint(v...))
 	}
 }
 
 // Debugf logs a formatted debug message.
 // The message is only printed if debug logging is enabled.
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if debugEnabled {
-		l.debug.Printf(format, v...)
+	if l.slog.Enabled(context.Background(), slog.LevelDebug) {
+		l.log(context.Background(), slog.LevelDebug, fmt.Sprintf(format, v...))
+	}
+}
+
+// log emits a record at the given level with ctx (see Detailf), recording the caller of the public method
+// (not this wrapper) as the source location.
+func (l *Logger) log(ctx context.Context, lvl slog.Level, msg string) {
+	if !l.slog.Enabled(ctx, lvl) {
+		return
 	}
+	var pcs [1]uintptr
+	// Skip runtime.Callers, this function, and the public wrapper (Info, Errorf, Detailf, ...).
+	runtime.Callers(3, pcs[:])
+	record := slog.NewRecord(time.Now(), lvl, msg, pcs[0])
+	_ = l.slog.Handler().Handle(ctx, record)
 }
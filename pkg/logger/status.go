@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"io"
+	"sync"
+)
+
+// ====================================================================================================
+// STATUS LINE COORDINATION
+// ====================================================================================================
+
+// StatusLine is a live, single-line display (such as a progress bar or spinner) drawn in place
+// on the terminal. Log writes hide the line first and redraw it afterwards, so log lines are
+// printed above it instead of being shredded by carriage returns.
+type StatusLine interface {
+	// Hide erases the line and keeps it from being redrawn until Show is called.
+	Hide()
+	// Show redraws the line.
+	Show()
+}
+
+// statusMu guards statusLine and serializes log writes against it.
+var statusMu sync.Mutex
+
+// statusLine is the currently active status line, or nil.
+var statusLine StatusLine
+
+// SetStatusLine registers the live status line that log output must be printed around.
+// Pass nil to unregister it once the line is finished.
+func SetStatusLine(line StatusLine) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	statusLine = line
+}
+
+// statusAwareWriter writes log records around the active status line.
+// Every handler writes a complete record with a single Write call.
+type statusAwareWriter struct {
+	out io.Writer
+}
+
+// Write hides the status line, writes p, and redraws the line below it.
+func (w *statusAwareWriter) Write(p []byte) (int, error) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	if statusLine == nil {
+		return w.out.Write(p)
+	}
+	statusLine.Hide()
+	defer statusLine.Show()
+	return w.out.Write(p)
+}
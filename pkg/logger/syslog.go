@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// ====================================================================================================
+// SYSTEM LOG FORWARDING
+// ====================================================================================================
+
+// systemLogWriter is the subset of *syslog.Writer used to forward records.
+type systemLogWriter interface {
+	Warning(m string) error
+	Err(m string) error
+}
+
+// systemLog is the active system log connection, or nil when forwarding is disabled.
+var systemLog systemLogWriter
+
+// EnableSystemLog forwards warnings and errors to the operating system's log service
+// (the unified log on macOS, syslog/journald on Linux) in addition to the regular output.
+// This lets administrators collect wiper failures with standard tooling on managed machines.
+func EnableSystemLog() error {
+	writer, err := dialSystemLog()
+	if err != nil {
+		return err
+	}
+	systemLog = writer
+	Log = NewLogger(output)
+	return nil
+}
+
+// systemLogHandler wraps another handler and mirrors warning and error records to the system log.
+type systemLogHandler struct {
+	next   slog.Handler
+	writer systemLogWriter
+	attrs  []slog.Attr
+}
+
+// Enabled defers to the wrapped handler, except that warnings and errors are always forwarded.
+func (h *systemLogHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return lvl >= slog.LevelWarn || h.next.Enabled(ctx, lvl)
+}
+
+// Handle writes the record to the wrapped handler and, for warnings and errors, to the system log.
+func (h *systemLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelWarn {
+		// System log entries are plain text: the message followed by all context fields.
+		var sb strings.Builder
+		sb.WriteString(record.Message)
+		for _, attr := range h.attrs {
+			fmt.Fprintf(&sb, " %s=%v", attr.Key, attr.Value)
+		}
+		record.Attrs(func(attr slog.Attr) bool {
+			fmt.Fprintf(&sb, " %s=%v", attr.Key, attr.Value)
+			return true
+		})
+		if record.Level >= slog.LevelError {
+			_ = h.writer.Err(sb.String())
+		} else {
+			_ = h.writer.Warning(sb.String())
+		}
+	}
+
+	if h.next.Enabled(ctx, record.Level) {
+		return h.next.Handle(ctx, record)
+	}
+	return nil
+}
+
+// WithAttrs returns a handler that includes the given attributes on every record.
+func (h *systemLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &systemLogHandler{
+		next:   h.next.WithAttrs(attrs),
+		writer: h.writer,
+		attrs:  append(append([]slog.Attr{}, h.attrs...), attrs...),
+	}
+}
+
+// WithGroup returns a handler that groups subsequent attributes under name.
+func (h *systemLogHandler) WithGroup(name string) slog.Handler {
+	return &systemLogHandler{next: h.next.WithGroup(name), writer: h.writer, attrs: h.attrs}
+}
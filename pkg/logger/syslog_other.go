@@ -0,0 +1,10 @@
+//go:build windows || plan9
+
+package logger
+
+import "fmt"
+
+// dialSystemLog is not supported on platforms without a syslog daemon.
+func dialSystemLog() (systemLogWriter, error) {
+	return nil, fmt.Errorf("system log integration is not supported on this platform")
+}
@@ -0,0 +1,19 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"fmt"
+	"log/syslog"
+)
+
+// dialSystemLog connects to the local syslog daemon. On macOS, messages sent to the
+// syslog socket are ingested by the unified logging system (os_log), so they show up in
+// Console.app and `log show --predicate 'process == "wiper"'`.
+func dialSystemLog() (systemLogWriter, error) {
+	writer, err := syslog.New(syslog.LOG_WARNING|syslog.LOG_USER, "wiper")
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the system log: %w", err)
+	}
+	return writer, nil
+}
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// DESKTOP NOTIFICATIONS
+// ====================================================================================================
+
+// ErrUnsupported is returned by Post where no notification service is available.
+var ErrUnsupported = errors.New("desktop notifications are not supported on this system")
+
+// Post shows a desktop notification: in Notification Center on macOS (through osascript), and
+// through notify-send on Linux desktops.
+func Post(title, subtitle, message string) error {
+	return post(title, subtitle, message)
+}
+
+// RunMessage returns the notification of a finished run, e.g. "wiper reclaimed 12.4 GB", with the
+// command and mode of the run (e.g., "wipe: system cleanup") as the subtitle.
+func RunMessage(run history.Run) (title, subtitle, message string) {
+	title = "wiper"
+	subtitle = run.Command
+	if run.Mode != "" {
+		subtitle += ": " + run.Mode
+	}
+	reclaimed := utils.FormatBytes(run.Reclaimed)
+	switch run.Status() {
+	case "dry run":
+		message = fmt.Sprintf("wiper could reclaim %s (%d items)", reclaimed, run.Items)
+	case "declined":
+		message = "The cleanup was declined; nothing was removed"
+	case "failed":
+		message = "The cleanup failed: " + run.Error
+	case "partial":
+		message = fmt.Sprintf("wiper reclaimed %s; %d items could not be removed", reclaimed, run.Failed)
+	default:
+		message = fmt.Sprintf("wiper reclaimed %s (%d items)", reclaimed, run.Items)
+	}
+	return title, subtitle, message
+}
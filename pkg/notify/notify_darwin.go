@@ -0,0 +1,33 @@
+//go:build darwin
+
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// post shows the notification with AppleScript's `display notification`.
+func post(title, subtitle, message string) error {
+	script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+	if subtitle != "" {
+		script += " subtitle " + appleScriptString(subtitle)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("osascript", "-e", script)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("osascript: %w: %s", err, msg)
+		}
+		return fmt.Errorf("osascript: %w", err)
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
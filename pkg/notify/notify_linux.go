@@ -0,0 +1,31 @@
+//go:build linux
+
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// post shows the notification with notify-send, which desktop environments provide.
+func post(title, subtitle, message string) error {
+	notifySend, err := exec.LookPath("notify-send")
+	if err != nil {
+		return ErrUnsupported
+	}
+	if subtitle != "" {
+		title += ": " + subtitle
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(notifySend, "--app-name=wiper", title, message)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("notify-send: %w: %s", err, msg)
+		}
+		return fmt.Errorf("notify-send: %w", err)
+	}
+	return nil
+}
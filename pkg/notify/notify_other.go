@@ -0,0 +1,8 @@
+//go:build !darwin && !linux
+
+package notify
+
+// post is not available on this platform.
+func post(title, subtitle, message string) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/history"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// WEBHOOKS
+// ====================================================================================================
+
+// webhookTimeout bounds how long a webhook may take, so a slow endpoint doesn't hold up wiper.
+const webhookTimeout = 10 * time.Second
+
+// WebhookPayload is the summary of a run that is posted to a webhook. Without a template, it is
+// posted as JSON; templates can refer to its fields (e.g., {{.Host}}, {{.ReclaimedHuman}}).
+type WebhookPayload struct {
+	history.Run
+	// Status is how the run ended: "ok", "partial", "failed", "declined", or "dry run".
+	Status string `json:"status"`
+	// ReclaimedHuman is Reclaimed as a human-readable size (e.g., "12.40 GB").
+	ReclaimedHuman string `json:"reclaimed_human"`
+	// Host is the name of the machine that was cleaned.
+	Host string `json:"host"`
+	// Version is the version of wiper.
+	Version string `json:"version"`
+}
+
+// NewWebhookPayload returns the payload of run on host, cleaned by wiper version.
+func NewWebhookPayload(run history.Run, host, version string) WebhookPayload {
+	return WebhookPayload{
+		Run:            run,
+		Status:         run.Status(),
+		ReclaimedHuman: utils.FormatBytes(run.Reclaimed),
+		Host:           host,
+		Version:        version,
+	}
+}
+
+// Webhook posts run summaries to a URL, as JSON or rendered with a template.
+type Webhook struct {
+	url      string
+	template *template.Template
+	client   *http.Client
+}
+
+// NewWebhook returns a webhook posting to url. If body isn't empty, it is a text/template for the
+// request body (e.g., a Slack message), rendered with a WebhookPayload; its "json" function quotes
+// a value for use in JSON, e.g. {"text": {{json .Mode}}}.
+func NewWebhook(url, body string) (*Webhook, error) {
+	if url == "" {
+		return nil, fmt.Errorf("the webhook has no URL")
+	}
+	webhook := &Webhook{url: url, client: &http.Client{Timeout: webhookTimeout}}
+	if body != "" {
+		tmpl, err := template.New("webhook").Funcs(template.FuncMap{"json": jsonValue}).Parse(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook template: %w", err)
+		}
+		webhook.template = tmpl
+	}
+	return webhook, nil
+}
+
+// jsonValue returns v encoded as JSON, for use in templates.
+func jsonValue(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// Post sends the payload to the webhook as a JSON POST request.
+// Responses other than 2xx are reported as errors.
+func (w *Webhook) Post(payload WebhookPayload) error {
+	var body bytes.Buffer
+	if w.template != nil {
+		if err := w.template.Execute(&body, payload); err != nil {
+			return fmt.Errorf("failed to render the webhook template: %w", err)
+		}
+	} else if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", &body)
+	if err != nil {
+		// The URL of a webhook is often its secret (e.g., Slack's), so it is left out of the error.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post to the webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("the webhook responded with %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
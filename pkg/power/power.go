@@ -0,0 +1,86 @@
+package power
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/logger"
+)
+
+// ====================================================================================================
+// RUN CONDITIONS
+// ====================================================================================================
+
+// State is a snapshot of the machine's power and activity state. Fields whose value couldn't be
+// determined on this machine are reported through the corresponding Known flag.
+type State struct {
+	// OnAC is true when running on external power (always true for machines without a battery).
+	OnAC    bool
+	ACKnown bool
+	// Idle is how long there has been no keyboard or mouse input.
+	Idle      time.Duration
+	IdleKnown bool
+	// LowPower is true while Low Power Mode (or a power-saver profile on Linux) is active.
+	LowPower      bool
+	LowPowerKnown bool
+}
+
+// Conditions describes when an unattended cleanup may run. The zero value always allows it.
+type Conditions struct {
+	// RequireAC defers the cleanup while running on battery.
+	RequireAC bool
+	// MinIdle defers the cleanup until there has been no user input for this long. 0 means no requirement.
+	MinIdle time.Duration
+	// SkipLowPower defers the cleanup while Low Power Mode is on.
+	SkipLowPower bool
+}
+
+// IsZero reports whether no condition is set.
+func (c Conditions) IsZero() bool {
+	return !c.RequireAC && c.MinIdle <= 0 && !c.SkipLowPower
+}
+
+// Check reads the current State and returns why the cleanup must be deferred, or an empty string if
+// it may run now. Conditions that can't be evaluated on this machine (e.g., idle time on a headless
+// Linux server) are logged and treated as met, so they never block a run forever.
+func (c Conditions) Check() string {
+	if c.IsZero() {
+		return ""
+	}
+	return c.evaluate(CurrentState())
+}
+
+// evaluate returns the first unmet condition for state.
+func (c Conditions) evaluate(state State) string {
+	if c.RequireAC {
+		if !state.ACKnown {
+			logger.Log.Warn("Could not determine the power source; ignoring the AC power requirement")
+		} else if !state.OnAC {
+			return "running on battery power"
+		}
+	}
+	if c.SkipLowPower {
+		if !state.LowPowerKnown {
+			logger.Log.Debug("Could not determine whether Low Power Mode is on; assuming it is off")
+		} else if state.LowPower {
+			return "Low Power Mode is on"
+		}
+	}
+	if c.MinIdle > 0 {
+		if !state.IdleKnown {
+			logger.Log.Warn("Could not determine the idle time; ignoring the idle requirement")
+		} else if state.Idle < c.MinIdle {
+			return fmt.Sprintf("idle for %s, less than the required %s", state.Idle.Round(time.Second), c.MinIdle)
+		}
+	}
+	return ""
+}
+
+// CurrentState returns the machine's current power and activity state.
+func CurrentState() State {
+	var state State
+	state.OnAC, state.ACKnown = onACPower()
+	state.Idle, state.IdleKnown = idleTime()
+	state.LowPower, state.LowPowerKnown = lowPowerMode()
+	return state
+}
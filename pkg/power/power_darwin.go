@@ -0,0 +1,62 @@
+//go:build darwin
+
+package power
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hidIdlePattern extracts the idle time in nanoseconds from `ioreg -c IOHIDSystem`.
+var hidIdlePattern = regexp.MustCompile(`"HIDIdleTime" = (\d+)`)
+
+// onACPower reads the power source from `pmset -g batt` ("Now drawing from 'AC Power'").
+func onACPower() (bool, bool) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return false, false
+	}
+	switch {
+	case strings.Contains(string(out), "'AC Power'"):
+		return true, true
+	case strings.Contains(string(out), "'Battery Power'"):
+		return false, true
+	}
+	return false, false
+}
+
+// idleTime reads the time since the last keyboard or mouse input from the HID system.
+func idleTime() (time.Duration, bool) {
+	out, err := exec.Command("ioreg", "-c", "IOHIDSystem", "-d", "4").Output()
+	if err != nil {
+		return 0, false
+	}
+	match := hidIdlePattern.FindSubmatch(out)
+	if match == nil {
+		return 0, false
+	}
+	nanos, err := strconv.ParseInt(string(match[1]), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(nanos), true
+}
+
+// lowPowerMode reads the Low Power Mode setting of the active power source from `pmset -g`.
+// macOS 12 reports it as "lowpowermode 1"; newer versions as "powermode 1" (0 automatic, 2 high power).
+func lowPowerMode() (bool, bool) {
+	out, err := exec.Command("pmset", "-g").Output()
+	if err != nil {
+		return false, false
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && (fields[0] == "lowpowermode" || fields[0] == "powermode") {
+			return fields[1] == "1", true
+		}
+	}
+	return false, false
+}
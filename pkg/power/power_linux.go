@@ -0,0 +1,74 @@
+//go:build linux
+
+package power
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// powerSupplyDir lists the power supplies known to the kernel.
+const powerSupplyDir = "/sys/class/power_supply"
+
+// onACPower checks the kernel's power supplies: an online mains adapter means AC power, a
+// discharging battery means battery power, and machines without a battery are always on AC.
+func onACPower() (bool, bool) {
+	supplies, err := filepath.Glob(filepath.Join(powerSupplyDir, "*"))
+	if err != nil {
+		return false, false
+	}
+	for _, supply := range supplies {
+		switch readSysfs(filepath.Join(supply, "type")) {
+		case "Mains", "USB":
+			if readSysfs(filepath.Join(supply, "online")) == "1" {
+				return true, true
+			}
+		case "Battery":
+			if readSysfs(filepath.Join(supply, "status")) == "Discharging" {
+				return false, true
+			}
+		}
+	}
+	// No battery, or one that is charging or full.
+	return true, true
+}
+
+// idleTime asks `xprintidle` for the time since the last input of the graphical session.
+// It is unknown on headless machines or without xprintidle.
+func idleTime() (time.Duration, bool) {
+	out, err := exec.Command("xprintidle").Output()
+	if err != nil {
+		return 0, false
+	}
+	millis, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(millis) * time.Millisecond, true
+}
+
+// lowPowerMode reports whether a power-saving profile is active, from the ACPI platform
+// profile or, failing that, power-profiles-daemon.
+func lowPowerMode() (bool, bool) {
+	if profile := readSysfs("/sys/firmware/acpi/platform_profile"); profile != "" {
+		return profile == "low-power", true
+	}
+	out, err := exec.Command("powerprofilesctl", "get").Output()
+	if err != nil {
+		return false, false
+	}
+	return strings.TrimSpace(string(out)) == "power-saver", true
+}
+
+// readSysfs returns the trimmed contents of a sysfs attribute, or an empty string if it can't be read.
+func readSysfs(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,20 @@
+//go:build !darwin && !linux
+
+package power
+
+import "time"
+
+// onACPower is not available on this platform.
+func onACPower() (bool, bool) {
+	return false, false
+}
+
+// idleTime is not available on this platform.
+func idleTime() (time.Duration, bool) {
+	return 0, false
+}
+
+// lowPowerMode is not available on this platform.
+func lowPowerMode() (bool, bool) {
+	return false, false
+}
@@ -0,0 +1,249 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/logger"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// DATA STRUCTURES
+// ====================================================================================================
+
+// Indicator is a live status line that shows either a progress bar (when the total is known)
+// or a spinner (when it is not). It registers itself with the logger while running, so log
+// lines are printed above it rather than through it.
+//
+// The indicator only draws when standard output is a terminal and output isn't quiet; otherwise
+// every method is a no-op, which keeps piped output and log files free of control characters.
+type Indicator struct {
+	mu      sync.Mutex
+	out     io.Writer
+	enabled bool
+
+	label   string
+	total   int64
+	current int64
+	unit    string // What current counts (e.g., "files"), shown after the count
+	detail  string // Changing text shown after the label, e.g. the current directory
+
+	hidden  int  // Number of outstanding Hide calls; the line is only drawn when 0.
+	drawn   bool // Whether the line is currently visible on screen.
+	frame   int  // Current spinner frame.
+	started bool
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// refreshInterval is how often the line is redrawn while running.
+const refreshInterval = 100 * time.Millisecond
+
+// maxLineWidth keeps the line shorter than a default terminal, since a wrapped
+// line can no longer be erased with a carriage return.
+const maxLineWidth = 79
+
+// minDetailWidth is the least room worth showing a detail in.
+const minDetailWidth = 12
+
+// barWidth is the number of cells in the progress bar.
+const barWidth = 30
+
+// spinnerFrames are the animation frames used when the total is unknown.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// ====================================================================================================
+// CONSTRUCTOR AND METHODS
+// ====================================================================================================
+
+// New creates an indicator with the given label. A total of 0 shows a spinner instead of a bar.
+// Call Start to begin drawing and Stop when the work is done.
+func New(label string, total int64) *Indicator {
+	return &Indicator{
+		out:     os.Stdout,
+		enabled: utils.IsTerminal(os.Stdout) && !logger.Quiet(),
+		label:   label,
+		total:   total,
+	}
+}
+
+// Start begins drawing the indicator and routes log output around it.
+func (p *Indicator) Start() {
+	p.mu.Lock()
+	if !p.enabled || p.started {
+		p.mu.Unlock()
+		return
+	}
+	p.started = true
+	p.done = make(chan struct{})
+	p.mu.Unlock()
+	// Registered without holding p.mu: log writes lock the logger first and then the indicator.
+	logger.SetStatusLine(p)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				p.frame = (p.frame + 1) % len(spinnerFrames)
+				p.draw()
+				p.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// Stop erases the indicator and stops routing log output around it.
+func (p *Indicator) Stop() {
+	p.mu.Lock()
+	if !p.started {
+		p.mu.Unlock()
+		return
+	}
+	p.started = false
+	close(p.done)
+	p.mu.Unlock()
+	p.wg.Wait()
+
+	logger.SetStatusLine(nil)
+	p.mu.Lock()
+	p.erase()
+	p.mu.Unlock()
+}
+
+// SetLabel changes the text shown next to the bar or spinner.
+func (p *Indicator) SetLabel(label string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.label = label
+}
+
+// SetTotal changes the amount of work the bar represents; 0 switches to a spinner.
+func (p *Indicator) SetTotal(total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = total
+}
+
+// SetUnit names what the spinner counts (e.g., "files"), so the count reads "(1234 files)".
+func (p *Indicator) SetUnit(unit string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.unit = unit
+}
+
+// SetDetail changes the text shown after the label and count, such as the directory being
+// scanned. It is shortened from the left to fit the line, since the end of a path says the most.
+func (p *Indicator) SetDetail(detail string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.detail = detail
+}
+
+// Add advances the indicator by n units of work.
+func (p *Indicator) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current += n
+}
+
+// Hide erases the line until Show is called. It implements logger.StatusLine.
+func (p *Indicator) Hide() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hidden++
+	p.erase()
+}
+
+// Show redraws the line after Hide. It implements logger.StatusLine.
+func (p *Indicator) Show() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.hidden > 0 {
+		p.hidden--
+	}
+	p.draw()
+}
+
+// ====================================================================================================
+// RENDERING
+// ====================================================================================================
+
+// draw renders the line in place. The caller must hold p.mu.
+func (p *Indicator) draw() {
+	if !p.enabled || !p.started || p.hidden > 0 {
+		return
+	}
+	fmt.Fprint(p.out, "\r\033[K"+truncate(p.render(), maxLineWidth))
+	p.drawn = true
+}
+
+// erase clears the line if it is visible. The caller must hold p.mu.
+func (p *Indicator) erase() {
+	if !p.drawn {
+		return
+	}
+	fmt.Fprint(p.out, "\r\033[K")
+	p.drawn = false
+}
+
+// render builds the text of the line without control characters.
+func (p *Indicator) render() string {
+	if p.total <= 0 {
+		line := fmt.Sprintf("%s %s", spinnerFrames[p.frame], p.label)
+		if p.current > 0 && p.unit != "" {
+			line += fmt.Sprintf(" (%d %s)", p.current, p.unit)
+		} else if p.current > 0 {
+			line += fmt.Sprintf(" (%d)", p.current)
+		}
+		// The detail only gets the room that's left, and is dropped if too little is.
+		if room := maxLineWidth - len([]rune(line)) - 1; p.detail != "" && room >= minDetailWidth {
+			line += " " + truncateLeft(p.detail, room)
+		}
+		return line
+	}
+
+	current := p.current
+	if current > p.total {
+		current = p.total
+	}
+	filled := int(int64(barWidth) * current / p.total)
+	bar := strings.Repeat("=", filled)
+	if filled < barWidth {
+		bar += ">" + strings.Repeat(" ", barWidth-filled-1)
+	}
+	return fmt.Sprintf("[%s] %3d%% %s", bar, 100*current/p.total, p.label)
+}
+
+// ====================================================================================================
+// HELPER FUNCTIONS
+// ====================================================================================================
+
+// truncate shortens s to at most width runes, marking the cut with an ellipsis.
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// truncateLeft shortens s to at most width runes by cutting its start, marking the cut with an ellipsis.
+func truncateLeft(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return "…" + string(runes[len(runes)-width+1:])
+}
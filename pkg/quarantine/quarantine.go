@@ -0,0 +1,266 @@
+package quarantine
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/config"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// DATA STRUCTURES
+// ====================================================================================================
+
+// Actions recorded in a manifest, describing what happened to an item.
+const (
+	// ActionDeleted means the item was deleted permanently and can't be restored.
+	ActionDeleted = "deleted"
+	// ActionTrashed means the item was moved to the Trash; it is restored from there.
+	ActionTrashed = "trashed"
+	// ActionArchived means the item was moved into an archive directory (e.g., old downloads).
+	ActionArchived = "archived"
+	// ActionQuarantined means the item was moved into the quarantine of its run.
+	ActionQuarantined = "quarantined"
+)
+
+// Entry is one removed item in the manifest of a run.
+type Entry struct {
+	// Path is where the item was before the cleanup.
+	Path string `json:"path"`
+	// Size is the size of the item in bytes.
+	Size int64 `json:"size"`
+	// Category is the cleanup category the item belonged to (e.g., "User Caches").
+	Category string `json:"category"`
+	// Time is when the item was removed.
+	Time time.Time `json:"time"`
+	// Action is what happened to the item; see the Action constants.
+	Action string `json:"action"`
+	// Location is where the item is now, for archived and quarantined items.
+	Location string `json:"location,omitempty"`
+	// Restored is set once the item was put back by `wiper restore`.
+	Restored bool `json:"restored,omitempty"`
+}
+
+// Restorable reports whether the item can still be put back from its location.
+func (e Entry) Restorable() bool {
+	return e.Location != "" && !e.Restored && (e.Action == ActionQuarantined || e.Action == ActionArchived)
+}
+
+// manifestName is the file, inside a run's directory, that lists the run's entries as JSON lines.
+const manifestName = "manifest.jsonl"
+
+// itemsDirName is the directory, inside a run's directory, that holds the quarantined items.
+const itemsDirName = "items"
+
+// volumeDirName is the directory, at the root of volumes other than the one of Dir, that holds the
+// quarantined items of each run that were on that volume, so quarantining them never copies data
+// across volumes (e.g., "/Volumes/External/.wiper-quarantine/<run ID>").
+const volumeDirName = ".wiper-quarantine"
+
+// recorder appends the entries of the current run to its manifest.
+type recorder struct {
+	mu      sync.Mutex
+	runID   string
+	enabled bool
+}
+
+// current is the recorder of this run. It is nil until Start is called, and nothing is recorded then.
+var current *recorder
+
+// ====================================================================================================
+// RECORDING
+// ====================================================================================================
+
+// Start begins recording the removals of run runID. When keep is true, removed items are moved into
+// the run's quarantine (see Keep) instead of being deleted, so `wiper restore` can put them back.
+// Nothing is written until the first removal, so runs that delete nothing leave no manifest behind.
+func Start(runID string, keep bool) {
+	current = &recorder{runID: runID, enabled: keep}
+}
+
+// Enabled reports whether removed items are moved into the quarantine.
+func Enabled() bool {
+	return current != nil && current.enabled
+}
+
+// Dir returns the directory holding the quarantine and manifests of all runs:
+// `~/Library/Application Support/wiper/quarantine` on macOS.
+func Dir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "quarantine"), nil
+}
+
+// runDir returns the directory of run runID.
+func runDir(runID string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	if runID == "" || filepath.Base(runID) != runID || runID == "." || runID == ".." {
+		return "", fmt.Errorf("invalid run ID %q", runID)
+	}
+	return filepath.Join(dir, runID), nil
+}
+
+// Record appends entry to the manifest of the current run. The time is filled in if it's unset.
+// It does nothing if recording wasn't started.
+func Record(entry Entry) error {
+	if current == nil {
+		return nil
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	dir, err := runDir(current.runID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, manifestName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open the manifest: %w", err)
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write the manifest: %w", err)
+	}
+	return file.Close()
+}
+
+// Keep moves path into the quarantine of the current run and records it in the manifest.
+// Items on another volume than Dir are moved into the run's directory on their own volume (see
+// volumeDirName) instead of being copied across volumes; their space is freed when the run is
+// purged. It returns false, without moving anything, when path is already inside
+// a quarantine and must be deleted instead.
+//
+// Parameters:
+//   - path: The absolute path of the item to quarantine.
+//   - size: The size of the item in bytes, for the manifest.
+//   - category: The cleanup category of the item, for the manifest.
+//
+// Returns:
+//   - Whether the item was quarantined, where it went, and an error if the move failed.
+func Keep(path string, size int64, category string) (bool, utils.MoveResult, error) {
+	if !Enabled() {
+		return false, utils.MoveResult{}, fmt.Errorf("the quarantine is not enabled")
+	}
+	if dir, err := Dir(); err == nil && utils.ContainsPath(path, []string{dir}) {
+		return false, utils.MoveResult{}, nil
+	}
+	dir, err := runDir(current.runID)
+	if err != nil {
+		return false, utils.MoveResult{}, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return false, utils.MoveResult{}, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	itemsDir := filepath.Join(dir, itemsDirName)
+	mount, err := utils.OtherVolumeMount(path, dir)
+	if err != nil {
+		return false, utils.MoveResult{}, fmt.Errorf("failed to find the volume of %s: %w", path, err)
+	}
+	if mount != "" {
+		volumeDir := filepath.Join(mount, volumeDirName)
+		if utils.ContainsPath(path, []string{volumeDir}) {
+			return false, utils.MoveResult{}, nil
+		}
+		itemsDir = filepath.Join(volumeDir, current.runID)
+		if err := os.MkdirAll(itemsDir, 0o700); err != nil {
+			return false, utils.MoveResult{}, fmt.Errorf("failed to create %s: %w", itemsDir, err)
+		}
+	}
+
+	current.mu.Lock()
+	moved, err := utils.MovePath(path, itemsDir)
+	current.mu.Unlock()
+	if err != nil {
+		return false, utils.MoveResult{}, fmt.Errorf("failed to move to the quarantine: %w", err)
+	}
+	err = Record(Entry{Path: path, Size: size, Category: category, Action: ActionQuarantined, Location: moved.Dest})
+	if err != nil {
+		err = fmt.Errorf("moved %s to %s, but failed to record it: %w", path, moved.Dest, err)
+	}
+	return true, moved, err
+}
+
+// readManifest reads the entries of run runID.
+func readManifest(runID string) ([]Entry, error) {
+	dir, err := runDir(runID)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(filepath.Join(dir, manifestName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no manifest for run %s", runID)
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid manifest entry on line %d of run %s: %w", line, runID, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// writeManifest replaces the manifest of run runID with entries.
+func writeManifest(runID string, entries []Entry) error {
+	dir, err := runDir(runID)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, manifestName+".*")
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(tmp)
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return err
+		}
+		writer.Write(append(line, '\n'))
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, manifestName))
+}
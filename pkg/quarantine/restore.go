@@ -0,0 +1,211 @@
+package quarantine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// RUN LISTING
+// ====================================================================================================
+
+// Run summarizes the manifest of one cleanup run.
+type Run struct {
+	// ID is the run ID, as printed in the logs of the run.
+	ID string
+	// Time is when the first item of the run was removed.
+	Time time.Time
+	// Entries are all items removed by the run.
+	Entries []Entry
+}
+
+// Size returns the combined size of every item of the run.
+func (r Run) Size() int64 {
+	var total int64
+	for _, entry := range r.Entries {
+		total += entry.Size
+	}
+	return total
+}
+
+// Restorable returns the items of the run that can still be put back.
+func (r Run) Restorable() []Entry {
+	var entries []Entry
+	for _, entry := range r.Entries {
+		if entry.Restorable() {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Runs returns every run with a manifest, most recent first.
+func Runs() ([]Run, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var runs []Run
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() {
+			continue
+		}
+		run, err := LoadRun(dirEntry.Name())
+		if err != nil {
+			continue // A run directory without a (readable) manifest, e.g. an interrupted run
+		}
+		runs = append(runs, run)
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Time.After(runs[j].Time) })
+	return runs, nil
+}
+
+// LoadRun reads the manifest of run runID.
+func LoadRun(runID string) (Run, error) {
+	entries, err := readManifest(runID)
+	if err != nil {
+		return Run{}, err
+	}
+	run := Run{ID: runID, Entries: entries}
+	if len(entries) > 0 {
+		run.Time = entries[0].Time
+	}
+	return run, nil
+}
+
+// ====================================================================================================
+// RESTORE AND PURGE
+// ====================================================================================================
+
+// RestoreResult is the outcome of restoring one item.
+type RestoreResult struct {
+	Entry Entry
+	// LostMetadata lists metadata that couldn't be preserved when the item was copied back across volumes.
+	LostMetadata []utils.MetadataLoss
+	// Err is why the item couldn't be restored, or nil.
+	Err error
+}
+
+// Restore puts the restorable items of run runID back where they were. Items whose original path
+// is taken again are left in the quarantine and reported as failed, so nothing is overwritten.
+// The manifest is updated to mark every restored item.
+//
+// Parameters:
+//   - runID: The run to restore.
+//   - only: When not empty, only items at or below one of these paths are restored.
+//   - dryRun: If true, nothing is moved and the results only tell what would be restored.
+//
+// Returns:
+//   - The outcome of every item considered, and an error if the manifest couldn't be read or updated.
+func Restore(runID string, only []string, dryRun bool) ([]RestoreResult, error) {
+	entries, err := readManifest(runID)
+	if err != nil {
+		return nil, err
+	}
+	var results []RestoreResult
+	changed := false
+	for i, entry := range entries {
+		if !entry.Restorable() || (len(only) > 0 && !utils.ContainsPath(entry.Path, only)) {
+			continue
+		}
+		result := RestoreResult{Entry: entry}
+		root, inQuarantine := itemsRoot(runID, entry.Location)
+		if _, err := os.Lstat(entry.Location); err != nil {
+			result.Err = fmt.Errorf("no longer available at %s: %w", entry.Location, err)
+		} else if !inQuarantine {
+			result.Err = fmt.Errorf("%s is not in the quarantine of run %s", entry.Location, runID)
+		} else if _, err := utils.CheckWithinRoot(entry.Location, root); err != nil {
+			result.Err = err
+		} else if _, err := os.Lstat(entry.Path); err == nil {
+			result.Err = fmt.Errorf("%s exists again; remove it first", entry.Path)
+		} else if !dryRun {
+			moved, err := utils.MovePathTo(entry.Location, entry.Path)
+			result.LostMetadata = moved.LostMetadata
+			if err != nil {
+				result.Err = err
+			} else {
+				entries[i].Restored = true
+				changed = true
+			}
+		}
+		results = append(results, result)
+	}
+	if changed {
+		if err := writeManifest(runID, entries); err != nil {
+			return results, fmt.Errorf("restored items, but failed to update the manifest: %w", err)
+		}
+	}
+	return results, nil
+}
+
+// itemsRoot returns the directory holding the quarantined items of run runID that location is in:
+// the run's items directory, or the run's directory at the root of another volume (see Keep).
+// It returns false if location isn't in either, e.g. in a manifest that was edited.
+func itemsRoot(runID string, location string) (string, bool) {
+	if dir, err := runDir(runID); err == nil {
+		itemsDir := filepath.Join(dir, itemsDirName)
+		if utils.ContainsPath(location, []string{itemsDir}) && location != itemsDir {
+			return itemsDir, true
+		}
+	}
+	volumeRunDir := filepath.Dir(location)
+	if filepath.Base(volumeRunDir) != runID || filepath.Base(filepath.Dir(volumeRunDir)) != volumeDirName {
+		return "", false
+	}
+	return volumeRunDir, true
+}
+
+// Purge deletes run runID: its quarantined items are deleted permanently and its manifest is
+// removed, so the run can no longer be restored. Archived items are left in their archive.
+//
+// Returns:
+//   - The number of bytes freed by deleting quarantined items, and an error, if any.
+func Purge(runID string, dryRun bool) (int64, error) {
+	run, err := LoadRun(runID)
+	if err != nil {
+		return 0, err
+	}
+	var freed int64
+	for _, entry := range run.Entries {
+		if entry.Action == ActionQuarantined && !entry.Restored {
+			freed += entry.Size
+		}
+	}
+	if dryRun {
+		return freed, nil
+	}
+	dir, err := runDir(runID)
+	if err != nil {
+		return 0, err
+	}
+	// os.RemoveAll doesn't follow symbolic links, and the quarantine only holds what wiper put there.
+	// Items quarantined on other volumes are in the run's directory on their volume.
+	for _, entry := range run.Entries {
+		if entry.Action != ActionQuarantined || entry.Restored || utils.ContainsPath(entry.Location, []string{dir}) {
+			continue
+		}
+		volumeRunDir, ok := itemsRoot(runID, entry.Location)
+		if !ok {
+			continue
+		}
+		if err := os.RemoveAll(volumeRunDir); err != nil {
+			return freed, fmt.Errorf("failed to remove %s: %w", volumeRunDir, err)
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return freed, fmt.Errorf("failed to remove %s: %w", dir, err)
+	}
+	return freed, nil
+}
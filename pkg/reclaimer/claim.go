@@ -1,10 +1,14 @@
 package reclaimer
 
 import (
-	"os"
+	"errors"
+	"fmt"
+	"io/fs"
 	"sort"
+	"strconv"
+	"syscall"
 
-	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/kodelint/wiper/pkg/i18n"
 	"github.com/kodelint/wiper/pkg/logger"
 	"github.com/kodelint/wiper/pkg/utils"
 )
@@ -13,21 +17,129 @@ import (
 // DATA STRUCTURES
 // ====================================================================================================
 
+// EntryStatus describes what happened to a single item during a cleanup.
+type EntryStatus int
+
+const (
+	// StatusUnknown is the zero value, so an entry whose status was never set (e.g., one decoded from
+	// a summary that doesn't record it) isn't mistaken for a removed item.
+	StatusUnknown EntryStatus = iota
+	// StatusRemoved means the item was deleted and its space reclaimed.
+	StatusRemoved
+	// StatusSkipped means the item was deliberately left in place (e.g., declined in interactive mode).
+	StatusSkipped
+	// StatusFailed means deleting the item was attempted but returned an error.
+	StatusFailed
+	// StatusDryRun means the item was only estimated and would be removed by a real run.
+	StatusDryRun
+)
+
+// String returns a human-readable name for the status.
+func (s EntryStatus) String() string {
+	switch s {
+	case StatusRemoved:
+		return "Removed"
+	case StatusSkipped:
+		return "Skipped"
+	case StatusFailed:
+		return "Failed"
+	case StatusDryRun:
+		return "DryRun"
+	default:
+		return "Unknown"
+	}
+}
+
+// MarshalText encodes the status by name, so serialized summaries stay readable and stable.
+func (s EntryStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a status name produced by MarshalText.
+func (s *EntryStatus) UnmarshalText(text []byte) error {
+	for _, status := range []EntryStatus{StatusRemoved, StatusSkipped, StatusFailed, StatusDryRun} {
+		if status.String() == string(text) {
+			*s = status
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown entry status %q", text)
+}
+
 // ReclaimedEntry represents a single entry in the cleanup summary before aggregation.
 // It holds all the details of one file or directory that was processed.
 type ReclaimedEntry struct {
-	Path          string // The path of the file or directory that was processed.
-	SizeReclaimed int64  // The size of the file/directory.
-	WasRemoved    bool   // A boolean flag indicating if the item was actually deleted.
-	Category      string // The high-level category of the item (e.g., "User Cache", "Application Bundle").
+	Path          string      `json:"path"`             // The path of the file or directory that was processed.
+	SizeReclaimed int64       `json:"size"`             // The size of the file/directory.
+	WasRemoved    bool        `json:"removed"`          // A boolean flag indicating if the item was actually deleted.
+	Category      string      `json:"category"`         // The high-level category of the item (e.g., "User Cache", "Application Bundle").
+	Status        EntryStatus `json:"status"`           // What happened to the item (removed, skipped, failed, or estimated).
+	Error         string      `json:"error,omitempty"`  // The reason the removal failed, only set when Status is StatusFailed.
+	Reason        string      `json:"reason,omitempty"` // Why the item was skipped, only set when Status is StatusSkipped.
+	Cloned        int64       `json:"cloned,omitempty"` // The bytes the item shared with APFS clones, not included in the size.
+}
+
+// Reasons recorded for skipped items, shown by --show-skipped.
+const (
+	SkipReasonIgnored      = "ignored path"
+	SkipReasonTooNew       = "too new"
+	SkipReasonPermission   = "permission denied"
+	SkipReasonInUse        = "in use"
+	SkipReasonProtected    = "protected"
+	SkipReasonInaccessible = "inaccessible"
+	SkipReasonDeclined     = "declined by user"
+	SkipReasonCloudSynced  = "synced with iCloud"
+	SkipReasonTagged       = "protected by tag"
+	SkipReasonCancelled    = "cancelled"
+	SkipReasonKept         = "settings kept"
+	SkipReasonHook         = "refused by hook"
+	SkipReasonChanged      = "changed since scan"
+	SkipReasonSameFile     = "same file as kept copy"
+)
+
+// SkipReasonForError maps a filesystem error to the closest skip reason.
+func SkipReasonForError(err error) string {
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return SkipReasonPermission
+	case errors.Is(err, syscall.EBUSY), errors.Is(err, syscall.ETXTBSY):
+		return SkipReasonInUse
+	case errors.Is(err, utils.ErrProtectedPath):
+		return SkipReasonProtected
+	case errors.Is(err, utils.ErrChangedSinceScan):
+		return SkipReasonChanged
+	default:
+		return SkipReasonInaccessible
+	}
 }
 
 // SummaryTable holds all the ReclaimedEntry items for a single cleanup operation.
-// This is used to generate the final summary report.
+// This is used to generate the final summary report. It can be encoded with encoding/json;
+// consumers should prefer the accessor and aggregate methods over reading Entries directly.
 type SummaryTable struct {
-	Entries []ReclaimedEntry
+	Entries []ReclaimedEntry `json:"entries"`
+	// Volume is the path whose disk capacity is used for the "% OF DISK" column.
+	// It defaults to the root volume.
+	Volume string `json:"volume"`
+	// Timings, when set, receives the scan and deletion duration of every category (see --timings).
+	// Several tables of one run usually share the same recorder.
+	Timings *Timings `json:"-"`
 }
 
+// drillDownTopN is the number of largest individual paths listed under each category row.
+// A value of 0 keeps the compact, category-only table.
+var drillDownTopN int
+
+// showSkipped controls whether a table of skipped items and their reasons is printed.
+var showSkipped bool
+
+// showDetails controls whether a table of every item is printed after the summary, and
+// detailsLimit is the number of rows it has at most (0 for no limit).
+var (
+	showDetails  bool
+	detailsLimit int
+)
+
 // ====================================================================================================
 // CONSTRUCTOR AND METHODS
 // ====================================================================================================
@@ -35,7 +147,7 @@ type SummaryTable struct {
 // NewSummaryTable creates a new empty SummaryTable instance.
 // This is the standard way to initialize a summary report.
 func NewSummaryTable() *SummaryTable {
-	return &SummaryTable{}
+	return &SummaryTable{Volume: "/"}
 }
 
 // AddEntry adds a new entry to the summary table.
@@ -43,22 +155,88 @@ func NewSummaryTable() *SummaryTable {
 // sizeReclaimed: The size in bytes.
 // wasRemoved: True if the item was deleted, false otherwise (e.g., in a dry run).
 // category: The category of the item for aggregation and display.
+//
+// Entries added this way are recorded as StatusRemoved or StatusSkipped; use the
+// status-specific helpers below to record failures and dry-run estimates.
 func (st *SummaryTable) AddEntry(path string, sizeReclaimed int64, wasRemoved bool, category string) {
+	status := StatusSkipped
+	if wasRemoved {
+		status = StatusRemoved
+	}
+	st.addWithStatus(path, sizeReclaimed, category, status, "")
+}
+
+// AddRemoved records an item that was deleted.
+func (st *SummaryTable) AddRemoved(path string, sizeReclaimed int64, category string) {
+	st.addWithStatus(path, sizeReclaimed, category, StatusRemoved, "")
+}
+
+// AddSkipped records an item that the user declined to remove.
+func (st *SummaryTable) AddSkipped(path string, size int64, category string) {
+	st.AddSkippedReason(path, size, category, SkipReasonDeclined)
+}
+
+// AddSkippedReason records an item that was excluded from the cleanup, along with why.
+// A size of 0 means the size was not determined (e.g., the item could not be read).
+func (st *SummaryTable) AddSkippedReason(path string, size int64, category string, reason string) {
+	st.addWithStatus(path, size, category, StatusSkipped, "")
+	st.Entries[len(st.Entries)-1].Reason = reason
+}
+
+// AddFailed records an item whose removal returned an error, keeping the reason for the report.
+func (st *SummaryTable) AddFailed(path string, size int64, category string, err error) {
+	reason := ""
+	if err != nil {
+		reason = err.Error()
+	}
+	st.addWithStatus(path, size, category, StatusFailed, reason)
+}
+
+// AddEstimated records an item that a real run would remove (used for dry runs and estimates).
+func (st *SummaryTable) AddEstimated(path string, size int64, category string) {
+	st.addWithStatus(path, size, category, StatusDryRun, "")
+}
+
+// MarkCloned records that the entry added last shared cloned bytes with other files. They stay
+// allocated for the clones (see utils.Usage), so they are reported apart from the reclaimed size.
+func (st *SummaryTable) MarkCloned(cloned int64) {
+	if len(st.Entries) > 0 {
+		st.Entries[len(st.Entries)-1].Cloned = cloned
+	}
+}
+
+// addWithStatus appends an entry with the given status. WasRemoved is derived from the status
+// so existing consumers of the field keep working.
+func (st *SummaryTable) addWithStatus(path string, size int64, category string, status EntryStatus, reason string) {
 	st.Entries = append(st.Entries, ReclaimedEntry{
 		Path:          path,
-		SizeReclaimed: sizeReclaimed,
-		WasRemoved:    wasRemoved,
+		SizeReclaimed: size,
+		WasRemoved:    status == StatusRemoved,
 		Category:      category,
+		Status:        status,
+		Error:         reason,
 	})
 }
 
-// TotalReclaimedBytes calculates the total bytes reclaimed from all entries in the summary table.
-func (st *SummaryTable) TotalReclaimedBytes() int64 {
-	var total int64
-	for _, entry := range st.Entries {
-		total += entry.SizeReclaimed
+// SetShowSkipped enables or disables the "Skipped items" table in printed summaries.
+func SetShowSkipped(enabled bool) {
+	showSkipped = enabled
+}
+
+// SetDetails enables or disables the per-item "Items" table in printed summaries. It lists the
+// largest limit items, or all of them if limit is 0.
+func SetDetails(enabled bool, limit int) {
+	showDetails = enabled
+	detailsLimit = max(limit, 0)
+}
+
+// SetDrillDown configures how many of the largest paths are nested under each category
+// when tables are printed. Pass 0 to disable the expanded view.
+func SetDrillDown(topN int) {
+	if topN < 0 {
+		topN = 0
 	}
-	return total
+	drillDownTopN = topN
 }
 
 // PrintTable renders and prints a formatted summary table to standard output.
@@ -67,6 +245,9 @@ func (st *SummaryTable) TotalReclaimedBytes() int64 {
 // Parameters:
 //   - title: The title of the summary table.
 func (st *SummaryTable) PrintTable(dryRun bool, title string) {
+	// Skipped items are reported on their own, even if nothing else was found.
+	defer st.printSkipped()
+
 	// If there are no entries, there's nothing to display.
 	if len(st.Entries) == 0 {
 		logger.Log.Debugf("No files or directories were processed for cleanup.")
@@ -75,50 +256,225 @@ func (st *SummaryTable) PrintTable(dryRun bool, title string) {
 
 	// Step 1: Group entries by category to aggregate totals. {New}
 	groupedTotals := make(map[string]int64)
-	if dryRun {
-		for _, entry := range st.Entries {
+	groupedCloned := make(map[string]int64)
+	groupedEntries := make(map[string][]ReclaimedEntry)
+	var totalCloned int64
+	for _, entry := range st.Entries {
+		// Only aggregate space from items that were removed (or would be, in a dry run).
+		if entry.Status == StatusRemoved || (dryRun && entry.Status == StatusDryRun) {
 			groupedTotals[entry.Category] += entry.SizeReclaimed
-		}
-	} else {
-		for _, entry := range st.Entries {
-			// Only aggregate space from items that were actually removed.
-			if entry.WasRemoved {
-				groupedTotals[entry.Category] += entry.SizeReclaimed
-			}
+			groupedCloned[entry.Category] += entry.Cloned
+			groupedEntries[entry.Category] = append(groupedEntries[entry.Category], entry)
+			totalCloned += entry.Cloned
 		}
 	}
 
-	// Step 2: Sort categories for a consistent and predictable table order. {New}
+	// Step 2: Sort categories by the configured order (see SetTableSort), largest first by default.
 	categories := make([]string, 0, len(groupedTotals))
 	for category := range groupedTotals {
 		categories = append(categories, category)
 	}
+	sortCategories(categories, groupedTotals, groupedEntries)
 
-	sort.Strings(categories)
 	// Step 3: Configure and render the table using the `go-pretty/v6/table` library.
-	tw := table.NewWriter()
-	tw.SetOutputMirror(os.Stdout)
-	// Add a newline for better visual separation.
-	println("")
-	tw.SetTitle(title)
-	tw.AppendHeader(table.Row{utils.Blue("CATEGORY"), utils.Blue("RECLAIMED")})
-	// Use a dark table style that works well with colored text.
-	tw.SetStyle(table.StyleColoredDark)
+	// The style, width, and plain-text mode come from the table rendering options.
+	// Item counts (see SetTableCounts) and bytes shared with APFS clones, when there are any, get
+	// columns of their own.
+	tr := newTableRenderer(title)
+	row := func(name, count interface{}, cells []interface{}, cloned interface{}) []interface{} {
+		columns := []interface{}{name}
+		if tableCounts {
+			columns = append(columns, count)
+		}
+		columns = append(columns, cells...)
+		if totalCloned > 0 {
+			columns = append(columns, cloned)
+		}
+		return columns
+	}
+	tr.header(row(utils.Blue(i18n.T("summary.header_category")), utils.Blue(i18n.T("summary.header_items")),
+		[]interface{}{utils.Blue(i18n.T("summary.header_reclaimed")), utils.Blue(i18n.T("summary.header_percent_disk")), utils.Blue(i18n.T("summary.header_percent_total"))},
+		utils.Blue(i18n.T("summary.header_cloned")))...)
 
+	// Only the aggregated categories count, so skipped and failed items don't inflate the total.
+	var total int64
+	for _, size := range groupedTotals {
+		total += size
+	}
+	// The disk capacity gives context to raw byte counts; it is optional if the volume can't be read.
+	var diskCapacity int64
+	if _, capacity, err := utils.VolumeSpace(st.Volume); err == nil {
+		diskCapacity = capacity
+	} else {
+		logger.Log.Debugf("Could not determine disk capacity of %s: %v", st.Volume, err)
+	}
+
+	var totalCount int
 	for _, category := range categories {
 		totalSize := groupedTotals[category]
-		tw.AppendRow(table.Row{category, utils.Green(utils.FormatBytes(totalSize))})
+		totalCount += len(groupedEntries[category])
+		tr.append(row(category, len(groupedEntries[category]),
+			[]interface{}{utils.Green(utils.FormatBytes(totalSize)), percentOf(totalSize, diskCapacity), percentOf(totalSize, total)},
+			clonedBytes(groupedCloned[category]))...)
+
+		// In drill-down mode, nest the largest individual paths under their category row.
+		if drillDownTopN > 0 {
+			for _, entry := range largestEntries(groupedEntries[category], drillDownTopN) {
+				tr.append(row(utils.White(treeBranch()+entry.Path), "", []interface{}{utils.White(utils.FormatBytes(entry.SizeReclaimed)), "", ""},
+					clonedBytes(entry.Cloned))...)
+			}
+		}
 	}
 	// Step 4: Add a footer row with the total reclaimed size.
-	tw.AppendFooter(table.Row{utils.Blue("TOTAL RECLAIMED:"), utils.Blue(utils.FormatBytes(st.TotalReclaimedBytes()))})
+	tr.footer(row(utils.Blue(i18n.T("summary.footer_total")), utils.Blue(strconv.Itoa(totalCount)),
+		[]interface{}{utils.Blue(utils.FormatBytes(total)), utils.Blue(percentOf(total, diskCapacity)), ""},
+		utils.Blue(utils.FormatBytes(totalCloned)))...)
+
+	tr.render()
+	if totalCloned > 0 {
+		logger.Log.Infof(i18n.T("summary.cloned_note"), utils.FormatBytes(totalCloned))
+	}
+
+	// Step 5: List every item with --details, and failed removals separately otherwise, so they
+	// can't be mistaken for skipped items.
+	if showDetails {
+		st.printDetails(dryRun)
+	} else if !dryRun {
+		st.printFailures()
+	}
+}
+
+// printDetails renders an "Items" table with every removed, failed or (in a dry run) estimated
+// entry, largest first: its size, status and error. Skipped items are left to printSkipped. The
+// table is cut after detailsLimit rows, with a note saying how many were left out.
+func (st *SummaryTable) printDetails(dryRun bool) {
+	statuses := []EntryStatus{StatusRemoved, StatusFailed}
+	if dryRun {
+		statuses = []EntryStatus{StatusDryRun}
+	}
+	entries := st.ByStatus(statuses...)
+	if len(entries) == 0 {
+		return
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].SizeReclaimed > entries[j].SizeReclaimed })
+	hidden := 0
+	if detailsLimit > 0 && len(entries) > detailsLimit {
+		hidden = len(entries) - detailsLimit
+		entries = entries[:detailsLimit]
+	}
+
+	tr := newTableRenderer(i18n.T("summary.details_title"))
+	tr.header(utils.Blue(i18n.T("summary.header_path")), utils.Blue(i18n.T("summary.header_category")), utils.Blue(i18n.T("summary.header_size")),
+		utils.Blue(i18n.T("summary.header_status")), utils.Blue(i18n.T("summary.header_error")))
+	for _, entry := range entries {
+		status := utils.Green(entry.Status.String())
+		if entry.Status == StatusFailed {
+			status = utils.Red(entry.Status.String())
+		}
+		tr.append(entry.Path, entry.Category, utils.FormatBytes(entry.SizeReclaimed), status, utils.Yellow(entry.Error))
+	}
+	tr.render()
+	if hidden > 0 {
+		logger.Log.Infof("%d smaller items are not listed. Use --details-limit 0 to list them all.", hidden)
+	}
+}
+
+// printSkipped renders a "Skipped items" table when --show-skipped is enabled,
+// or a one-line hint about how many items were skipped otherwise.
+func (st *SummaryTable) printSkipped() {
+	skipped := st.Skipped()
+	if len(skipped) == 0 {
+		return
+	}
+	if !showSkipped {
+		logger.Log.Infof("%d items were skipped. Use --show-skipped to see why.", len(skipped))
+		return
+	}
+
+	tr := newTableRenderer(i18n.T("summary.skipped_title"))
+	tr.header(utils.Blue(i18n.T("summary.header_path")), utils.Blue(i18n.T("summary.header_category")), utils.Blue(i18n.T("summary.header_size")),
+		utils.Blue(i18n.T("summary.header_reason")))
+	for _, entry := range skipped {
+		size := "-"
+		if entry.SizeReclaimed > 0 {
+			size = utils.FormatBytes(entry.SizeReclaimed)
+		}
+		tr.append(entry.Path, entry.Category, size, utils.Yellow(entry.Reason))
+	}
+	tr.render()
+}
+
+// printFailures renders a "Failed to remove" table with the error reason of every failed entry.
+func (st *SummaryTable) printFailures() {
+	failed := st.Failures()
+	if len(failed) == 0 {
+		return
+	}
 
-	tw.Render()
+	tr := newTableRenderer(i18n.T("summary.failed_title"))
+	tr.header(utils.Red(i18n.T("summary.header_path")), utils.Red(i18n.T("summary.header_category")), utils.Red(i18n.T("summary.header_error")))
+	for _, entry := range failed {
+		tr.append(entry.Path, entry.Category, utils.Yellow(entry.Error))
+	}
+	tr.render()
 }
 
 // ====================================================================================================
 // HELPER FUNCTIONS
 // ====================================================================================================
 
+// percentOf formats part as a percentage of whole, or "-" when whole is unknown.
+func percentOf(part int64, whole int64) string {
+	if whole <= 0 {
+		return "-"
+	}
+	percent := 100 * float64(part) / float64(whole)
+	if part > 0 && percent < 0.1 {
+		return "<0.1%"
+	}
+	return fmt.Sprintf("%.1f%%", percent)
+}
+
+// clonedBytes formats the cloned bytes of a row, or "-" when it has none.
+func clonedBytes(cloned int64) string {
+	if cloned <= 0 {
+		return "-"
+	}
+	return utils.Yellow(utils.FormatBytes(cloned))
+}
+
+// sortCategories orders the categories of a summary table by the configured sort order: by
+// reclaimed size or item count, largest first, or by name. Ties are broken by name.
+func sortCategories(categories []string, sizes map[string]int64, entries map[string][]ReclaimedEntry) {
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := categories[i], categories[j]
+		switch tableSort {
+		case "size":
+			if sizes[a] != sizes[b] {
+				return sizes[a] > sizes[b]
+			}
+		case "count":
+			if len(entries[a]) != len(entries[b]) {
+				return len(entries[a]) > len(entries[b])
+			}
+		}
+		return a < b
+	})
+}
+
+// largestEntries returns up to n entries sorted by size, largest first.
+// The input slice is left untouched.
+func largestEntries(entries []ReclaimedEntry, n int) []ReclaimedEntry {
+	sorted := append([]ReclaimedEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].SizeReclaimed > sorted[j].SizeReclaimed
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
 // FormatBytes is a convenience function that wraps the `utils.FormatBytes` function.
 // It is exposed here to be used directly by other packages that import `reclaimer`.
 func FormatBytes(b int64) string {
@@ -0,0 +1,186 @@
+package reclaimer
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+)
+
+// ====================================================================================================
+// HTML REPORT
+// ====================================================================================================
+
+// ReportInfo describes the run a summary table belongs to, for the header of the HTML report.
+type ReportInfo struct {
+	// Title is the heading of the report (e.g., "wiper wipe: system cleanup").
+	Title string
+	// RunID is the ID of the run, or empty for a fresh estimate.
+	RunID string
+	// Time is when the run took place.
+	Time time.Time
+	// Host is the name of the machine that was cleaned.
+	Host string
+	// Version is the version of wiper that produced the report.
+	Version string
+	// DryRun marks the report as an estimate: nothing was removed.
+	DryRun bool
+	// Failed is the number of failed removals, if the summary table doesn't list them itself.
+	Failed int
+}
+
+// htmlReport is the data rendered by reportTemplate.
+type htmlReport struct {
+	ReportInfo
+	Total      int64
+	Items      int
+	Skipped    int
+	Categories []htmlCategory
+	Entries    []ReclaimedEntry
+}
+
+// htmlCategory is one bar of the per-category chart.
+type htmlCategory struct {
+	CategoryTotal
+	// Percent is the share of the category in the total, and Width the length of its bar relative
+	// to the largest category.
+	Percent, Width float64
+}
+
+// WriteHTML renders the summary table as a standalone HTML page: the totals, a chart of the space
+// per category, and a table of every item that can be sorted by clicking its column headers. The
+// page has no external dependencies, so it can be attached to a ticket or opened offline.
+// Removed items are counted for a run, and estimated items for a dry run (info.DryRun).
+func (st *SummaryTable) WriteHTML(w io.Writer, info ReportInfo) error {
+	status := StatusRemoved
+	if info.DryRun {
+		status = StatusDryRun
+	}
+	report := htmlReport{
+		ReportInfo: info,
+		Total:      st.TotalBytes(status),
+		Items:      len(st.ByStatus(status)),
+		Skipped:    len(st.Skipped()),
+		Entries:    st.All(),
+	}
+	if failed := st.FailedCount(); failed > report.Failed {
+		report.Failed = failed
+	}
+	// The table starts out sorted by size, like the drill-down of the terminal tables.
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		return report.Entries[i].SizeReclaimed > report.Entries[j].SizeReclaimed
+	})
+
+	totals := st.TotalsByCategory(status)
+	for _, total := range totals {
+		category := htmlCategory{CategoryTotal: total}
+		if report.Total > 0 {
+			category.Percent = 100 * float64(total.Bytes) / float64(report.Total)
+		}
+		if totals[0].Bytes > 0 {
+			category.Width = 100 * float64(total.Bytes) / float64(totals[0].Bytes)
+		}
+		report.Categories = append(report.Categories, category)
+	}
+
+	if err := reportTemplate.Execute(w, report); err != nil {
+		return fmt.Errorf("failed to render the report: %w", err)
+	}
+	return nil
+}
+
+// reportTemplate is the HTML report. Like the dashboard, it uses no external JS or CSS.
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"bytes": FormatBytes,
+	"date":  func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05 MST") },
+	"pct":   func(f float64) string { return fmt.Sprintf("%.1f%%", f) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2rem; color: #222; }
+  h1 { font-weight: 600; margin-bottom: 0.2rem; }
+  section { margin-bottom: 2rem; }
+  .muted { color: #777; }
+  .cards { display: flex; gap: 1rem; flex-wrap: wrap; }
+  .card { border: 1px solid #ddd; border-radius: 6px; padding: 0.8rem 1.2rem; min-width: 9rem; }
+  .card b { display: block; font-size: 1.5rem; }
+  .bar { background: #eee; border-radius: 4px; height: 14px; width: 100%; }
+  .fill { background: #4caf50; border-radius: 4px; height: 14px; }
+  table { border-collapse: collapse; width: 100%; }
+  td, th { padding: 0.3rem 0.6rem; text-align: left; border-bottom: 1px solid #eee; }
+  th.sortable { cursor: pointer; user-select: none; }
+  th.sortable:after { content: " \2195"; color: #aaa; }
+  td.num, th.num { text-align: right; white-space: nowrap; }
+  td.path { word-break: break-all; font-family: ui-monospace, Menlo, monospace; font-size: 0.9em; }
+  .Removed { color: #2e7d32; } .Failed { color: #c62828; } .Skipped { color: #777; } .DryRun { color: #ef6c00; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="muted">{{date .Time}}{{if .Host}} &middot; {{.Host}}{{end}}{{if .RunID}} &middot; run {{.RunID}}{{end}}{{if .Version}} &middot; wiper {{.Version}}{{end}}</p>
+{{if .DryRun}}<p><b>Dry run:</b> nothing was removed; sizes are estimates.</p>{{end}}
+
+<section class="cards">
+  <div class="card">{{if .DryRun}}Reclaimable{{else}}Reclaimed{{end}}<b>{{bytes .Total}}</b></div>
+  <div class="card">Items<b>{{.Items}}</b></div>
+  <div class="card">Failed<b>{{.Failed}}</b></div>
+  <div class="card">Skipped<b>{{.Skipped}}</b></div>
+</section>
+
+<section>
+  <h2>By category</h2>
+  {{if .Categories}}
+  <table>
+    <tr><th>Category</th><th class="num">Items</th><th class="num">Size</th><th class="num">Share</th><th style="width:40%"></th></tr>
+    {{range .Categories}}
+    <tr><td>{{.Category}}</td><td class="num">{{.Count}}</td><td class="num">{{bytes .Bytes}}</td><td class="num">{{pct .Percent}}</td>
+      <td><div class="bar"><div class="fill" style="width:{{printf "%.1f" .Width}}%"></div></div></td></tr>
+    {{end}}
+  </table>
+  {{else}}<p class="muted">Nothing was reclaimed.</p>{{end}}
+</section>
+
+<section>
+  <h2>Items</h2>
+  <table id="items">
+    <thead><tr>
+      <th class="sortable" data-type="text">Path</th>
+      <th class="sortable" data-type="text">Category</th>
+      <th class="sortable num" data-type="num">Size</th>
+      <th class="sortable" data-type="text">Status</th>
+      <th class="sortable" data-type="text">Reason</th>
+    </tr></thead>
+    <tbody>
+    {{range .Entries}}
+    <tr><td class="path">{{.Path}}</td><td>{{.Category}}</td><td class="num" data-value="{{.SizeReclaimed}}">{{bytes .SizeReclaimed}}</td>
+      <td class="{{.Status}}">{{.Status}}</td><td>{{if .Error}}{{.Error}}{{else}}{{.Reason}}{{end}}</td></tr>
+    {{end}}
+    </tbody>
+  </table>
+</section>
+
+<script>
+document.querySelectorAll("#items th.sortable").forEach((th, column) => {
+  let ascending = th.dataset.type !== "num";
+  th.addEventListener("click", () => {
+    const body = document.querySelector("#items tbody");
+    const value = row => {
+      const cell = row.cells[column];
+      return th.dataset.type === "num" ? Number(cell.dataset.value) : cell.textContent.toLowerCase();
+    };
+    const rows = Array.from(body.rows).sort((a, b) => {
+      const x = value(a), y = value(b);
+      return (x < y ? -1 : x > y ? 1 : 0) * (ascending ? 1 : -1);
+    });
+    rows.forEach(row => body.appendChild(row));
+    ascending = !ascending;
+  });
+});
+</script>
+</body>
+</html>
+`))
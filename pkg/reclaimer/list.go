@@ -0,0 +1,28 @@
+package reclaimer
+
+import (
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// LIST TABLES
+// ====================================================================================================
+
+// PrintListTable renders a simple table of rows under the given header, using the configured
+// table style and width. It is meant for listings that aren't cleanup summaries
+// (e.g., installed packages). A nil footer omits the footer row.
+func PrintListTable(title string, header []string, rows [][]interface{}, footer []interface{}) {
+	tr := newTableRenderer(title)
+	headerCells := make([]interface{}, len(header))
+	for i, cell := range header {
+		headerCells[i] = utils.Blue(cell)
+	}
+	tr.header(headerCells...)
+	for _, row := range rows {
+		tr.append(row...)
+	}
+	if footer != nil {
+		tr.footer(footer...)
+	}
+	tr.render()
+}
@@ -0,0 +1,175 @@
+package reclaimer
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// TABLE RENDERING OPTIONS
+// ====================================================================================================
+
+// tableStyles maps the style names accepted in the config file and on the command line
+// to go-pretty table styles.
+var tableStyles = map[string]table.Style{
+	"colored-dark":   table.StyleColoredDark,
+	"colored-bright": table.StyleColoredBright,
+	"light":          table.StyleLight,
+	"rounded":        table.StyleRounded,
+	"double":         table.StyleDouble,
+	"bold":           table.StyleBold,
+	"ascii":          table.StyleDefault,
+}
+
+// DefaultTableStyle is used when no style has been configured.
+const DefaultTableStyle = "colored-dark"
+
+// tableStyle is the go-pretty style applied to every summary table.
+var tableStyle = table.StyleColoredDark
+
+// plainTables strips Unicode decorations and ANSI colors from table output.
+// It is enabled by the "ascii" style, which is safe for captured logs and basic terminals.
+var plainTables bool
+
+// tableWidth limits the width of rendered rows in characters. 0 means unlimited.
+var tableWidth int
+
+// tableSorts are the orders summary rows can be sorted in (see SetTableSort).
+var tableSorts = []string{"size", "name", "count"}
+
+// DefaultTableSort is used when no sort order has been configured.
+const DefaultTableSort = "size"
+
+// tableSort is the order of the category rows of summary tables, and tableCounts adds a column
+// with the number of items in each category.
+var (
+	tableSort   = DefaultTableSort
+	tableCounts bool
+)
+
+// SetTableStyle selects the style used for summary tables by name (see TableStyles).
+func SetTableStyle(name string) error {
+	if name == "" {
+		name = DefaultTableStyle
+	}
+	style, ok := tableStyles[name]
+	if !ok {
+		return fmt.Errorf("unknown table style %q (available: %s)", name, strings.Join(TableStyles(), ", "))
+	}
+	tableStyle = style
+	plainTables = name == "ascii"
+	return nil
+}
+
+// SetTableWidth limits summary table rows to the given number of characters. Pass 0 for no limit.
+func SetTableWidth(width int) {
+	if width < 0 {
+		width = 0
+	}
+	tableWidth = width
+}
+
+// SetTableSort selects the order of the category rows in summary tables: "size" (largest first,
+// the default), "name", or "count" (most items first).
+func SetTableSort(name string) error {
+	if name == "" {
+		name = DefaultTableSort
+	}
+	if !slices.Contains(tableSorts, name) {
+		return fmt.Errorf("unknown table sort %q (available: %s)", name, strings.Join(tableSorts, ", "))
+	}
+	tableSort = name
+	return nil
+}
+
+// SetTableCounts adds or removes the column with the number of items in each category.
+func SetTableCounts(enabled bool) {
+	tableCounts = enabled
+}
+
+// TableStyles returns the names of all available table styles in sorted order.
+func TableStyles() []string {
+	names := make([]string, 0, len(tableStyles))
+	for name := range tableStyles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ====================================================================================================
+// TABLE RENDERER
+// ====================================================================================================
+
+// tableRenderer wraps a go-pretty table writer and applies the configured style, width,
+// and plain-text mode consistently to every summary table.
+type tableRenderer struct {
+	tw table.Writer
+}
+
+// newTableRenderer creates a renderer that writes a table with the given title to standard output.
+func newTableRenderer(title string) *tableRenderer {
+	tw := table.NewWriter()
+	tw.SetOutputMirror(os.Stdout)
+	tw.SetTitle(title)
+	tw.SetStyle(tableStyle)
+	if tableWidth > 0 {
+		tw.Style().Size.WidthMax = tableWidth
+	}
+	// Keep the borders of colored styles but drop their ANSI colors when output isn't a terminal.
+	if !utils.ColorEnabled() {
+		tw.Style().Color = table.ColorOptions{}
+		tw.Style().Title.Colors = text.Colors{}
+	}
+	return &tableRenderer{tw: tw}
+}
+
+// header sets the header row.
+func (r *tableRenderer) header(cells ...interface{}) {
+	r.tw.AppendHeader(r.row(cells))
+}
+
+// append adds a body row.
+func (r *tableRenderer) append(cells ...interface{}) {
+	r.tw.AppendRow(r.row(cells))
+}
+
+// footer sets the footer row.
+func (r *tableRenderer) footer(cells ...interface{}) {
+	r.tw.AppendFooter(r.row(cells))
+}
+
+// render prints the table, preceded by an empty line for visual separation.
+func (r *tableRenderer) render() {
+	println("")
+	r.tw.Render()
+}
+
+// row converts cells into a table.Row, stripping colors in plain mode or when colors are disabled.
+func (r *tableRenderer) row(cells []interface{}) table.Row {
+	row := make(table.Row, len(cells))
+	for i, cell := range cells {
+		if plainTables || !utils.ColorEnabled() {
+			if s, ok := cell.(string); ok {
+				cell = text.StripEscape(s)
+			}
+		}
+		row[i] = cell
+	}
+	return row
+}
+
+// treeBranch returns the prefix used for nested rows, falling back to ASCII in plain mode.
+func treeBranch() string {
+	if plainTables {
+		return "  - "
+	}
+	return "  └ "
+}
@@ -0,0 +1,193 @@
+package reclaimer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ====================================================================================================
+// ACCESSORS
+// ====================================================================================================
+
+// Len returns the number of recorded entries, regardless of their status.
+func (st *SummaryTable) Len() int {
+	return len(st.Entries)
+}
+
+// All returns a copy of every recorded entry, in the order they were recorded.
+func (st *SummaryTable) All() []ReclaimedEntry {
+	return append([]ReclaimedEntry(nil), st.Entries...)
+}
+
+// ByStatus returns the entries with any of the given statuses, in the order they were recorded.
+func (st *SummaryTable) ByStatus(statuses ...EntryStatus) []ReclaimedEntry {
+	var matched []ReclaimedEntry
+	for _, entry := range st.Entries {
+		if hasStatus(entry.Status, statuses) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// Skipped returns all entries that were excluded from the cleanup, in the order they were recorded.
+func (st *SummaryTable) Skipped() []ReclaimedEntry {
+	return st.ByStatus(StatusSkipped)
+}
+
+// Failures returns all entries whose removal failed, in the order they were recorded.
+func (st *SummaryTable) Failures() []ReclaimedEntry {
+	return st.ByStatus(StatusFailed)
+}
+
+// SkippedEntries returns all entries that were excluded from the cleanup, in the order they were recorded.
+//
+// Deprecated: Use Skipped.
+func (st *SummaryTable) SkippedEntries() []ReclaimedEntry {
+	return st.Skipped()
+}
+
+// FailedEntries returns all entries whose removal failed, in the order they were recorded.
+//
+// Deprecated: Use Failures.
+func (st *SummaryTable) FailedEntries() []ReclaimedEntry {
+	return st.Failures()
+}
+
+// FailedCount returns the number of items whose removal failed.
+// A non-zero count means the cleanup finished but did not fully succeed.
+func (st *SummaryTable) FailedCount() int {
+	return len(st.Failures())
+}
+
+// ====================================================================================================
+// AGGREGATES
+// ====================================================================================================
+
+// CategoryTotal is the aggregated size of all entries in one category.
+type CategoryTotal struct {
+	Category string `json:"category"`
+	Bytes    int64  `json:"bytes"`
+	Count    int    `json:"count"`
+	// Cloned is the part of the entries shared with APFS clones, which isn't included in Bytes.
+	Cloned int64 `json:"cloned,omitempty"`
+}
+
+// TotalsByCategory aggregates the entries with any of the given statuses by category, largest first.
+// Without statuses, removed items are counted, which matches what a finished cleanup actually reclaimed.
+func (st *SummaryTable) TotalsByCategory(statuses ...EntryStatus) []CategoryTotal {
+	if len(statuses) == 0 {
+		statuses = []EntryStatus{StatusRemoved}
+	}
+	grouped := make(map[string]*CategoryTotal)
+	for _, entry := range st.ByStatus(statuses...) {
+		total, ok := grouped[entry.Category]
+		if !ok {
+			total = &CategoryTotal{Category: entry.Category}
+			grouped[entry.Category] = total
+		}
+		total.Bytes += entry.SizeReclaimed
+		total.Cloned += entry.Cloned
+		total.Count++
+	}
+
+	totals := make([]CategoryTotal, 0, len(grouped))
+	for _, total := range grouped {
+		totals = append(totals, *total)
+	}
+	// Ties are broken by name so the order is deterministic.
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Bytes != totals[j].Bytes {
+			return totals[i].Bytes > totals[j].Bytes
+		}
+		return totals[i].Category < totals[j].Category
+	})
+	return totals
+}
+
+// TotalBytes sums the sizes of the entries with any of the given statuses.
+// Without statuses, removed items are counted.
+func (st *SummaryTable) TotalBytes(statuses ...EntryStatus) int64 {
+	if len(statuses) == 0 {
+		statuses = []EntryStatus{StatusRemoved}
+	}
+	var total int64
+	for _, entry := range st.ByStatus(statuses...) {
+		total += entry.SizeReclaimed
+	}
+	return total
+}
+
+// TotalReclaimedBytes returns the number of bytes that were actually removed.
+// Skipped, failed, and estimated entries are not counted.
+func (st *SummaryTable) TotalReclaimedBytes() int64 {
+	return st.TotalBytes(StatusRemoved)
+}
+
+// ====================================================================================================
+// ENCODING
+// ====================================================================================================
+
+// WriteJSON encodes the summary table as indented JSON.
+func (st *SummaryTable) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(st); err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+	return nil
+}
+
+// csvHeader is the header row written by WriteCSV.
+var csvHeader = []string{"record", "path", "category", "items", "size_bytes", "size", "status", "reason", "cloned_bytes"}
+
+// WriteCSV writes the summary table as CSV, for spreadsheets: one "item" row per entry, in the order
+// they were recorded, followed by one "category" row per category and status with the item count and
+// total size. Failed items carry their error in the reason column, and cloned_bytes is the part
+// shared with APFS clones, which isn't included in the size.
+func (st *SummaryTable) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write(csvHeader)
+	for _, entry := range st.Entries {
+		reason := entry.Reason
+		if entry.Status == StatusFailed {
+			reason = entry.Error
+		}
+		writer.Write([]string{"item", entry.Path, entry.Category, "1", strconv.FormatInt(entry.SizeReclaimed, 10),
+			FormatBytes(entry.SizeReclaimed), entry.Status.String(), reason, strconv.FormatInt(entry.Cloned, 10)})
+	}
+	for _, status := range []EntryStatus{StatusRemoved, StatusDryRun, StatusFailed, StatusSkipped} {
+		for _, total := range st.TotalsByCategory(status) {
+			writer.Write([]string{"category", "", total.Category, strconv.Itoa(total.Count), strconv.FormatInt(total.Bytes, 10),
+				FormatBytes(total.Bytes), status.String(), "", strconv.FormatInt(total.Cloned, 10)})
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write summary CSV: %w", err)
+	}
+	return nil
+}
+
+// ReadSummaryJSON decodes a summary table written by WriteJSON.
+func ReadSummaryJSON(r io.Reader) (*SummaryTable, error) {
+	st := NewSummaryTable()
+	if err := json.NewDecoder(r).Decode(st); err != nil {
+		return nil, fmt.Errorf("failed to decode summary: %w", err)
+	}
+	return st, nil
+}
+
+// hasStatus reports whether status is one of statuses.
+func hasStatus(status EntryStatus, statuses []EntryStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package reclaimer
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/i18n"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// PER-CATEGORY TIMINGS
+// ====================================================================================================
+
+// Timings accumulates how long scanning and deleting took for each category, so slow targets
+// (e.g., the /private/var/folders glob) can be identified. A nil *Timings ignores all records,
+// which lets callers time unconditionally. It is safe for concurrent use.
+type Timings struct {
+	mu     sync.Mutex
+	scan   map[string]time.Duration
+	delete map[string]time.Duration
+}
+
+// NewTimings creates an empty Timings recorder.
+func NewTimings() *Timings {
+	return &Timings{
+		scan:   make(map[string]time.Duration),
+		delete: make(map[string]time.Duration),
+	}
+}
+
+// AddScan records time spent finding and sizing the items of a category.
+func (t *Timings) AddScan(category string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scan[category] += d
+}
+
+// AddDelete records time spent removing the items of a category.
+func (t *Timings) AddDelete(category string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.delete[category] += d
+}
+
+// PrintTable renders the recorded timings, slowest category first.
+func (t *Timings) PrintTable(title string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.scan) == 0 && len(t.delete) == 0 {
+		return
+	}
+
+	totals := make(map[string]time.Duration)
+	for category, d := range t.scan {
+		totals[category] += d
+	}
+	for category, d := range t.delete {
+		totals[category] += d
+	}
+	categories := make([]string, 0, len(totals))
+	for category := range totals {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if totals[categories[i]] != totals[categories[j]] {
+			return totals[categories[i]] > totals[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+
+	tr := newTableRenderer(title)
+	tr.header(utils.Blue(i18n.T("summary.header_category")), utils.Blue(i18n.T("summary.header_scan")), utils.Blue(i18n.T("summary.header_delete")),
+		utils.Blue(i18n.T("summary.header_total")))
+	var scanTotal, deleteTotal time.Duration
+	for _, category := range categories {
+		scanTotal += t.scan[category]
+		deleteTotal += t.delete[category]
+		tr.append(category, formatDuration(t.scan[category]), formatDuration(t.delete[category]), utils.Yellow(formatDuration(totals[category])))
+	}
+	tr.footer(utils.Blue(i18n.T("summary.timings_total")), utils.Blue(formatDuration(scanTotal)), utils.Blue(formatDuration(deleteTotal)), utils.Blue(formatDuration(scanTotal+deleteTotal)))
+	tr.render()
+}
+
+// formatDuration rounds d to a readable precision, or "-" when nothing was recorded.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "-"
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	default:
+		return d.Round(10 * time.Millisecond).String()
+	}
+}
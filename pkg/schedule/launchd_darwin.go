@@ -0,0 +1,66 @@
+//go:build darwin
+
+package schedule
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// launchdAvailable reports whether jobs can be loaded into launchd on this platform.
+const launchdAvailable = true
+
+// lastExitPattern extracts the exit status of the last run from `launchctl print`.
+var lastExitPattern = regexp.MustCompile(`last exit code = (.+)`)
+
+// domain is the launchd domain of the user's LaunchAgents (e.g., "gui/501").
+func domain() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+// load loads the plist at path into the user's launchd domain with `launchctl bootstrap`.
+func load(path string) error {
+	if _, err := launchctlOutput("bootstrap", domain(), path); err != nil {
+		return fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	return nil
+}
+
+// unload removes the plist at path from the user's launchd domain with `launchctl bootout`.
+func unload(path string) error {
+	_, err := launchctlOutput("bootout", domain(), path)
+	return err
+}
+
+// Status asks launchd whether the job is loaded and how its last run ended.
+func Status() (State, error) {
+	out, err := launchctlOutput("print", domain()+"/"+Label)
+	if err != nil {
+		// `launchctl print` fails for services that aren't loaded.
+		return State{}, nil
+	}
+	state := State{Loaded: true}
+	if match := lastExitPattern.FindStringSubmatch(out); match != nil {
+		state.LastExitCode = strings.TrimSpace(match[1])
+	}
+	return state, nil
+}
+
+// launchctlOutput runs launchctl with args and returns its standard output. Failures include
+// what launchctl printed on standard error.
+func launchctlOutput(args ...string) (string, error) {
+	cmd := exec.Command("launchctl", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,21 @@
+//go:build !darwin
+
+package schedule
+
+// launchdAvailable reports whether jobs can be loaded into launchd on this platform.
+const launchdAvailable = false
+
+// load is not available without launchd.
+func load(path string) error {
+	return ErrUnsupported
+}
+
+// unload is not available without launchd.
+func unload(path string) error {
+	return ErrUnsupported
+}
+
+// Status is not available without launchd.
+func Status() (State, error) {
+	return State{}, ErrUnsupported
+}
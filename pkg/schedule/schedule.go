@@ -0,0 +1,278 @@
+package schedule
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ====================================================================================================
+// DATA STRUCTURES
+// ====================================================================================================
+
+// Label is the launchd label of the scheduled cleanup, and the name of its LaunchAgent plist.
+const Label = "com.github.kodelint.wiper"
+
+// Supported values of Job.Interval.
+const (
+	// Daily runs the job every day at Hour:Minute.
+	Daily = "daily"
+	// Weekly runs the job every week on Weekday at Hour:Minute.
+	Weekly = "weekly"
+)
+
+// ErrNotInstalled is returned when no scheduled cleanup is installed.
+var ErrNotInstalled = errors.New("no scheduled cleanup is installed")
+
+// ErrUnsupported is returned on platforms without launchd.
+var ErrUnsupported = errors.New("scheduled cleanups need launchd, which is only available on macOS")
+
+// Job is a scheduled cleanup: the wiper command line and when launchd runs it.
+type Job struct {
+	// Program is the wiper executable.
+	Program string
+	// Args are the arguments of Program (e.g., ["wipe", "--yes"]).
+	Args []string
+	// Interval is Daily or Weekly.
+	Interval string
+	// Weekday is the day of a weekly job, 0 (Sunday) to 6 (Saturday).
+	Weekday int
+	// Hour and Minute are the local time of day the job runs at.
+	Hour, Minute int
+	// LogPath receives the standard output and error of the job.
+	LogPath string
+}
+
+// weekdays are the names of Job.Weekday, as accepted by ParseWeekday.
+var weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// String describes when the job runs (e.g., "weekly on sunday at 03:00").
+func (j Job) String() string {
+	if j.Interval == Weekly && j.Weekday >= 0 && j.Weekday < len(weekdays) {
+		return fmt.Sprintf("weekly on %s at %02d:%02d", weekdays[j.Weekday], j.Hour, j.Minute)
+	}
+	return fmt.Sprintf("daily at %02d:%02d", j.Hour, j.Minute)
+}
+
+// ParseWeekday returns the Job.Weekday of a day name or its first three letters (e.g., "sun").
+func ParseWeekday(name string) (int, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, day := range weekdays {
+		if len(name) >= 3 && strings.HasPrefix(day, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q: expected sunday, monday, ..., or saturday", name)
+}
+
+// ParseTime returns the hour and minute of a time of day in 24-hour HH:MM notation.
+func ParseTime(s string) (hour, minute int, err error) {
+	hourStr, minuteStr, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if ok {
+		hour, err = strconv.Atoi(hourStr)
+	}
+	if ok && err == nil {
+		minute, err = strconv.Atoi(minuteStr)
+	}
+	if !ok || err != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid time %q: expected HH:MM, e.g. 03:00", s)
+	}
+	return hour, minute, nil
+}
+
+// ====================================================================================================
+// LAUNCH AGENT PLIST
+// ====================================================================================================
+
+// Path returns the LaunchAgent plist of the job: `~/Library/LaunchAgents/com.github.kodelint.wiper.plist`.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", Label+".plist"), nil
+}
+
+// Plist renders the LaunchAgent property list of job.
+func Plist(job Job) []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	writeString := func(indent, s string) {
+		b.WriteString(indent + "<string>")
+		_ = xml.EscapeText(&b, []byte(s))
+		b.WriteString("</string>\n")
+	}
+	b.WriteString("\t<key>Label</key>\n")
+	writeString("\t", Label)
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{job.Program}, job.Args...) {
+		writeString("\t\t", arg)
+	}
+	b.WriteString("\t</array>\n")
+	b.WriteString("\t<key>StartCalendarInterval</key>\n\t<dict>\n")
+	if job.Interval == Weekly {
+		fmt.Fprintf(&b, "\t\t<key>Weekday</key>\n\t\t<integer>%d</integer>\n", job.Weekday)
+	}
+	fmt.Fprintf(&b, "\t\t<key>Hour</key>\n\t\t<integer>%d</integer>\n", job.Hour)
+	fmt.Fprintf(&b, "\t\t<key>Minute</key>\n\t\t<integer>%d</integer>\n", job.Minute)
+	b.WriteString("\t</dict>\n")
+	if job.LogPath != "" {
+		b.WriteString("\t<key>StandardOutPath</key>\n")
+		writeString("\t", job.LogPath)
+		b.WriteString("\t<key>StandardErrorPath</key>\n")
+		writeString("\t", job.LogPath)
+	}
+	// Run in the background, at a low priority, like other maintenance jobs.
+	b.WriteString("\t<key>ProcessType</key>\n\t<string>Background</string>\n")
+	b.WriteString("\t<key>LowPriorityIO</key>\n\t<true/>\n")
+	b.WriteString("</dict>\n</plist>\n")
+	return b.Bytes()
+}
+
+// plistNode is an element of a property list, decoded generically.
+type plistNode struct {
+	XMLName xml.Name
+	Text    string      `xml:",chardata"`
+	Nodes   []plistNode `xml:",any"`
+}
+
+// dict returns the entries of a <dict> node by key.
+func (n plistNode) dict() map[string]plistNode {
+	entries := make(map[string]plistNode)
+	for i := 0; i+1 < len(n.Nodes); i += 2 {
+		if n.Nodes[i].XMLName.Local == "key" {
+			entries[strings.TrimSpace(n.Nodes[i].Text)] = n.Nodes[i+1]
+		}
+	}
+	return entries
+}
+
+// Installed returns the job described by the installed plist, or ErrNotInstalled.
+func Installed() (Job, error) {
+	path, err := Path()
+	if err != nil {
+		return Job{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Job{}, ErrNotInstalled
+		}
+		return Job{}, err
+	}
+	return parsePlist(data)
+}
+
+// parsePlist reads a job from a plist written by Plist.
+func parsePlist(data []byte) (Job, error) {
+	var root plistNode
+	if err := xml.Unmarshal(data, &root); err != nil || len(root.Nodes) == 0 {
+		return Job{}, fmt.Errorf("failed to parse the launch agent: %v", err)
+	}
+	entries := root.Nodes[0].dict()
+
+	job := Job{Interval: Daily, LogPath: strings.TrimSpace(entries["StandardOutPath"].Text)}
+	for i, arg := range entries["ProgramArguments"].Nodes {
+		if i == 0 {
+			job.Program = arg.Text
+		} else {
+			job.Args = append(job.Args, arg.Text)
+		}
+	}
+	calendar := entries["StartCalendarInterval"].dict()
+	integer := func(key string) int {
+		value, _ := strconv.Atoi(strings.TrimSpace(calendar[key].Text))
+		return value
+	}
+	if _, ok := calendar["Weekday"]; ok {
+		job.Interval = Weekly
+		job.Weekday = integer("Weekday") % 7 // launchd accepts 7 for Sunday as well
+	}
+	job.Hour, job.Minute = integer("Hour"), integer("Minute")
+	return job, nil
+}
+
+// Install writes the plist of job, replacing an installed one, and loads it into launchd.
+func Install(job Job) error {
+	if !launchdAvailable {
+		return ErrUnsupported
+	}
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if job.LogPath != "" {
+		if err := os.MkdirAll(filepath.Dir(job.LogPath), 0o700); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(job.LogPath), err)
+		}
+	}
+	// An installed job has to be unloaded before launchd picks up the new plist.
+	_ = unload(path)
+	if err := os.WriteFile(path, Plist(job), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return load(path)
+}
+
+// Remove unloads the job and deletes its plist, or returns ErrNotInstalled.
+func Remove() error {
+	if !launchdAvailable {
+		return ErrUnsupported
+	}
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return ErrNotInstalled
+	}
+	_ = unload(path)
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return nil
+}
+
+// MaxLogSize is the size above which RotateLog starts a new log of the job (10 MiB).
+const MaxLogSize = 10 << 20
+
+// RotateLog moves the log of the job at path to path.1, replacing an older one, once it is larger
+// than MaxLogSize. launchd appends the output of every run to the log and never trims it; it opens
+// the log again for each run, so the run that rotates it finishes writing to path.1 and the next
+// one starts a new file.
+func RotateLog(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size() <= MaxLogSize {
+		return nil
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", path, err)
+	}
+	return nil
+}
+
+// State is what launchd reports about the loaded job.
+type State struct {
+	// Loaded is true when launchd knows the job.
+	Loaded bool
+	// LastExitCode is the exit status of the last run, empty if the job hasn't run since it was loaded.
+	LastExitCode string
+}
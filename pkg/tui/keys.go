@@ -0,0 +1,87 @@
+package tui
+
+import "unicode/utf8"
+
+// ====================================================================================================
+// KEY DECODING
+// ====================================================================================================
+
+// keyCode identifies a key that doesn't produce a character.
+type keyCode int
+
+const (
+	keyNone keyCode = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyEnter
+	keyEscape
+)
+
+// key is one key press: either a special key or a printable character.
+type key struct {
+	code keyCode
+	char rune
+}
+
+// escapeSequences maps the sequences terminals send for special keys, without the leading ESC.
+var escapeSequences = map[string]keyCode{
+	"[A":  keyUp,
+	"OA":  keyUp,
+	"[B":  keyDown,
+	"OB":  keyDown,
+	"[C":  keyRight,
+	"OC":  keyRight,
+	"[D":  keyLeft,
+	"OD":  keyLeft,
+	"[5~": keyPageUp,
+	"[6~": keyPageDown,
+	"[H":  keyHome,
+	"OH":  keyHome,
+	"[1~": keyHome,
+	"[F":  keyEnd,
+	"OF":  keyEnd,
+	"[4~": keyEnd,
+}
+
+// parseKeys decodes the key presses in input, which may hold several keys (e.g., when a key is held).
+// Unknown escape sequences are dropped.
+func parseKeys(input []byte) []key {
+	var keys []key
+	for len(input) > 0 {
+		switch input[0] {
+		case '\r', '\n':
+			keys = append(keys, key{code: keyEnter})
+			input = input[1:]
+		case 0x1b:
+			n, code := parseEscape(input[1:])
+			keys = append(keys, key{code: code})
+			input = input[1+n:]
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, key{char: r})
+			input = input[size:]
+		}
+	}
+	return keys
+}
+
+// parseEscape decodes the escape sequence at the start of input (after ESC). It returns the number
+// of bytes it consumed and the key; a lone ESC is the Escape key.
+func parseEscape(input []byte) (int, keyCode) {
+	if len(input) == 0 || (input[0] != '[' && input[0] != 'O') {
+		return 0, keyEscape
+	}
+	// A sequence ends with its first byte in the range '@' to '~' after the introducer.
+	for i := 1; i < len(input); i++ {
+		if input[i] >= '@' && input[i] <= '~' {
+			return i + 1, escapeSequences[string(input[:i+1])]
+		}
+	}
+	return len(input), keyNone
+}
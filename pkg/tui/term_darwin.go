@@ -0,0 +1,11 @@
+//go:build darwin
+
+package tui
+
+import "golang.org/x/sys/unix"
+
+// ioctls reading and changing the terminal settings.
+const (
+	ioctlGetTermios = unix.TIOCGETA
+	ioctlSetTermios = unix.TIOCSETA
+)
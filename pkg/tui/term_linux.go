@@ -0,0 +1,11 @@
+//go:build linux
+
+package tui
+
+import "golang.org/x/sys/unix"
+
+// ioctls reading and changing the terminal settings.
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
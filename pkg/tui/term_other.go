@@ -0,0 +1,18 @@
+//go:build !darwin && !linux
+
+package tui
+
+import "time"
+
+// terminal is not available on this platform; Select always returns ErrNoTerminal.
+type terminal struct{}
+
+// openTerminal always fails on this platform.
+func openTerminal() (*terminal, error) {
+	return nil, ErrNoTerminal
+}
+
+func (t *terminal) close()                             {}
+func (t *terminal) size() (int, int)                   { return 80, 24 }
+func (t *terminal) read(time.Duration) ([]byte, error) { return nil, ErrNoTerminal }
+func (t *terminal) write(string)                       {}
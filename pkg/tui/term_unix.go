@@ -0,0 +1,87 @@
+//go:build darwin || linux
+
+package tui
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminal is the controlling terminal while a list is shown.
+type terminal struct {
+	in, out  *os.File
+	original unix.Termios
+}
+
+// openTerminal switches the terminal on standard input to reading single key presses without
+// echo, and shows the alternate screen with a hidden cursor. Interrupt keys still send signals.
+func openTerminal() (*terminal, error) {
+	in, out := os.Stdin, os.Stdout
+	original, err := unix.IoctlGetTermios(int(in.Fd()), ioctlGetTermios)
+	if err != nil {
+		return nil, ErrNoTerminal
+	}
+	if _, err := unix.IoctlGetWinsize(int(out.Fd()), unix.TIOCGWINSZ); err != nil {
+		return nil, ErrNoTerminal
+	}
+	raw := *original
+	raw.Lflag &^= unix.ICANON | unix.ECHO
+	raw.Iflag &^= unix.ICRNL | unix.IXON
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(int(in.Fd()), ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	t := &terminal{in: in, out: out, original: *original}
+	t.write("\033[?1049h\033[?25l")
+	return t, nil
+}
+
+// close restores the screen and the terminal settings.
+func (t *terminal) close() {
+	t.write("\033[?25h\033[?1049l")
+	unix.IoctlSetTermios(int(t.in.Fd()), ioctlSetTermios, &t.original)
+}
+
+// size returns the width and height of the terminal, with a conventional fallback.
+func (t *terminal) size() (int, int) {
+	ws, err := unix.IoctlGetWinsize(int(t.out.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 {
+		return 80, 24
+	}
+	return int(ws.Col), int(ws.Row)
+}
+
+// read returns the bytes of the keys pressed, waiting at most timeout. It returns no bytes if no
+// key was pressed in time, so callers can check for cancellation between reads.
+func (t *terminal) read(timeout time.Duration) ([]byte, error) {
+	fds := []unix.PollFd{{Fd: int32(t.in.Fd()), Events: unix.POLLIN}}
+	n, err := unix.Poll(fds, int(timeout/time.Millisecond))
+	if errors.Is(err, unix.EINTR) || n == 0 {
+		// A signal (e.g., a resize or Ctrl+C) interrupted the wait.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 64)
+	n, err = unix.Read(int(t.in.Fd()), buf)
+	if err != nil {
+		if errors.Is(err, unix.EINTR) || errors.Is(err, unix.EAGAIN) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if n == 0 {
+		return nil, errors.New("the terminal was closed")
+	}
+	return buf[:n], nil
+}
+
+// write writes s to the terminal.
+func (t *terminal) write(s string) {
+	t.out.WriteString(s)
+}
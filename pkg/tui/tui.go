@@ -0,0 +1,393 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/i18n"
+	"github.com/kodelint/wiper/pkg/utils"
+)
+
+// ====================================================================================================
+// DATA STRUCTURES
+// ====================================================================================================
+
+// Item is one entry of a selection list.
+type Item struct {
+	// Label is shown for the item, usually its path.
+	Label string
+	// Group is the heading the item is listed under, usually its category.
+	Group string
+	// Size is the size of the item in bytes; selected sizes are added up live.
+	Size int64
+}
+
+// ErrAborted is returned by Select when the user leaves the list without confirming.
+var ErrAborted = errors.New("selection aborted")
+
+// ErrNoTerminal is returned by Select when standard input or output is not a terminal.
+var ErrNoTerminal = errors.New("the selection list needs a terminal")
+
+// pollInterval is how often Select checks for cancellation and terminal resizes while waiting for a key.
+const pollInterval = 100 * time.Millisecond
+
+// helpLine lists the keys of the selection list.
+const helpLine = "↑/↓ move · space toggle · →/← expand/collapse · a all · enter confirm · q quit"
+
+// group is a heading of the list and the items listed under it.
+type group struct {
+	name     string
+	items    []int // Indices into selector.items, largest first
+	size     int64
+	expanded bool
+}
+
+// row is one visible line of the list: a group heading (item < 0) or an item of a group.
+type row struct {
+	group int
+	item  int
+}
+
+// selector is the state of one Select call.
+type selector struct {
+	title    string
+	items    []Item
+	selected []bool
+	groups   []group
+
+	cursor     int  // Index into rows()
+	offset     int  // First row shown, for scrolling
+	confirming bool // Whether the final confirmation is shown
+}
+
+// ====================================================================================================
+// SELECTION
+// ====================================================================================================
+
+// Select shows items as a checkbox list grouped by Item.Group, with the total size of the selected
+// items updated as they are toggled, and returns which items the user selected. Every item starts
+// selected. Groups are listed largest first and start collapsed; toggling a group toggles all of its
+// items. Confirming with Enter asks once more before returning.
+//
+// The list takes over the terminal until it returns. Ctrl+C still interrupts the program, so
+// callers that handle interrupts through ctx see Select return ctx's error.
+//
+// Parameters:
+//   - ctx: Closes the list and returns ctx's error when cancelled.
+//   - title: Shown above the list.
+//   - items: The items to choose from.
+//
+// Returns:
+//   - selected[i] reports whether items[i] was selected, and an error: ErrAborted if the user quit,
+//     ErrNoTerminal if there is no terminal to show the list on, or ctx's error.
+func Select(ctx context.Context, title string, items []Item) ([]bool, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	term, err := openTerminal()
+	if err != nil {
+		return nil, err
+	}
+	defer term.close()
+
+	s := newSelector(title, items)
+	width, height := term.size()
+	term.write(s.render(width, height))
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		input, err := term.read(pollInterval)
+		if err != nil {
+			return nil, err
+		}
+		// Without input, the list is only redrawn if the terminal was resized.
+		newWidth, newHeight := term.size()
+		if len(input) == 0 && newWidth == width && newHeight == height {
+			continue
+		}
+		width, height = newWidth, newHeight
+		for _, k := range parseKeys(input) {
+			done, err := s.handle(k)
+			if err != nil {
+				return nil, err
+			}
+			if done {
+				return s.selected, nil
+			}
+		}
+		term.write(s.render(width, height))
+	}
+}
+
+// newSelector groups items and selects all of them.
+func newSelector(title string, items []Item) *selector {
+	s := &selector{title: title, items: items, selected: make([]bool, len(items))}
+	byName := make(map[string]int)
+	for i, item := range items {
+		s.selected[i] = true
+		index, ok := byName[item.Group]
+		if !ok {
+			index = len(s.groups)
+			byName[item.Group] = index
+			s.groups = append(s.groups, group{name: item.Group})
+		}
+		s.groups[index].items = append(s.groups[index].items, i)
+		s.groups[index].size += item.Size
+	}
+	for _, g := range s.groups {
+		sort.SliceStable(g.items, func(a, b int) bool { return items[g.items[a]].Size > items[g.items[b]].Size })
+	}
+	sort.SliceStable(s.groups, func(a, b int) bool { return s.groups[a].size > s.groups[b].size })
+	// A single group has nothing to collapse into.
+	if len(s.groups) == 1 {
+		s.groups[0].expanded = true
+	}
+	return s
+}
+
+// rows returns the visible lines of the list.
+func (s *selector) rows() []row {
+	var rows []row
+	for g, grp := range s.groups {
+		rows = append(rows, row{group: g, item: -1})
+		if grp.expanded {
+			for _, i := range grp.items {
+				rows = append(rows, row{group: g, item: i})
+			}
+		}
+	}
+	return rows
+}
+
+// handle applies one key press. It reports whether the selection is confirmed.
+func (s *selector) handle(k key) (bool, error) {
+	if s.confirming {
+		s.confirming = false
+		if k.char != 0 && i18n.IsYes(strings.ToLower(string(k.char))) {
+			return true, nil
+		}
+		return false, nil
+	}
+
+	rows := s.rows()
+	current := rows[s.cursor]
+	switch {
+	case k.code == keyUp || k.char == 'k':
+		s.move(-1, len(rows))
+	case k.code == keyDown || k.char == 'j':
+		s.move(1, len(rows))
+	case k.code == keyPageUp:
+		s.move(-10, len(rows))
+	case k.code == keyPageDown:
+		s.move(10, len(rows))
+	case k.code == keyHome || k.char == 'g':
+		s.cursor = 0
+	case k.code == keyEnd || k.char == 'G':
+		s.cursor = len(rows) - 1
+	case k.code == keyRight || k.char == 'l':
+		s.groups[current.group].expanded = true
+	case k.code == keyLeft || k.char == 'h':
+		if s.groups[current.group].expanded {
+			s.groups[current.group].expanded = false
+			// Keep the cursor on the group heading, which is still visible.
+			for i, r := range s.rows() {
+				if r.group == current.group && r.item < 0 {
+					s.cursor = i
+				}
+			}
+		}
+	case k.char == ' ':
+		if current.item >= 0 {
+			s.selected[current.item] = !s.selected[current.item]
+		} else {
+			s.setGroup(current.group, s.groupState(current.group) != stateAll)
+		}
+	case k.char == 'a':
+		all := true
+		for _, selected := range s.selected {
+			all = all && selected
+		}
+		for i := range s.selected {
+			s.selected[i] = !all
+		}
+	case k.code == keyEnter:
+		s.confirming = true
+	case k.code == keyEscape || k.char == 'q':
+		return false, ErrAborted
+	}
+	return false, nil
+}
+
+// move moves the cursor by delta rows, staying within the list.
+func (s *selector) move(delta int, rowCount int) {
+	s.cursor = max(0, min(rowCount-1, s.cursor+delta))
+}
+
+// selection states of a group.
+const (
+	stateNone = iota
+	statePartial
+	stateAll
+)
+
+// groupState reports whether none, some, or all items of group g are selected.
+func (s *selector) groupState(g int) int {
+	count := 0
+	for _, i := range s.groups[g].items {
+		if s.selected[i] {
+			count++
+		}
+	}
+	switch count {
+	case 0:
+		return stateNone
+	case len(s.groups[g].items):
+		return stateAll
+	default:
+		return statePartial
+	}
+}
+
+// setGroup selects or deselects every item of group g.
+func (s *selector) setGroup(g int, selected bool) {
+	for _, i := range s.groups[g].items {
+		s.selected[i] = selected
+	}
+}
+
+// totals returns the number and combined size of the selected items.
+func (s *selector) totals() (int, int64) {
+	count := 0
+	var size int64
+	for i, selected := range s.selected {
+		if selected {
+			count++
+			size += s.items[i].Size
+		}
+	}
+	return count, size
+}
+
+// ====================================================================================================
+// RENDERING
+// ====================================================================================================
+
+// groupCheckboxes are the checkboxes of a group heading, by groupState.
+var groupCheckboxes = [...]string{stateNone: "[ ]", statePartial: "[~]", stateAll: "[x]"}
+
+// sizeWidth is the width of the size column.
+const sizeWidth = 10
+
+// render draws the whole screen for a terminal of the given size.
+func (s *selector) render(width, height int) string {
+	// Writing to the last column makes some terminals wrap, so it is left empty.
+	width--
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	line := func(text string) {
+		b.WriteString(text)
+		b.WriteString("\033[K\r\n")
+	}
+
+	line(utils.CyanBold(truncateRight(s.title, width)))
+	line(truncateRight(helpLine, width))
+	line("")
+
+	// Title, help, blank line, and the blank and status lines below the list.
+	listHeight := max(1, height-5)
+	rows := s.rows()
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	} else if s.cursor >= s.offset+listHeight {
+		s.offset = s.cursor - listHeight + 1
+	}
+	s.offset = max(0, min(s.offset, len(rows)-listHeight))
+
+	for r := s.offset; r < len(rows) && r < s.offset+listHeight; r++ {
+		pointer := "  "
+		if r == s.cursor {
+			pointer = utils.CyanBold("> ")
+		}
+		current := rows[r]
+		grp := s.groups[current.group]
+		if current.item < 0 {
+			marker := "+"
+			if grp.expanded {
+				marker = "-"
+			}
+			// Pointer, checkbox and expand marker take 7 columns, followed by the label, size and count.
+			count := fmt.Sprintf(" (%d)", len(grp.items))
+			labelWidth := width - 7 - 1 - sizeWidth - len(count)
+			line(fmt.Sprintf("%s%s %s %s %s%s", pointer, groupCheckboxes[s.groupState(current.group)], marker,
+				utils.Blue(padRight(truncateRight(grp.name, labelWidth), labelWidth)),
+				utils.Green(padLeft(utils.FormatBytes(grp.size), sizeWidth)), count))
+			continue
+		}
+		checkbox := "[ ]"
+		if s.selected[current.item] {
+			checkbox = "[x]"
+		}
+		item := s.items[current.item]
+		// Pointer, indentation and checkbox take 10 columns. Paths are shortened from the left,
+		// since their end tells items apart.
+		labelWidth := width - 10 - 1 - sizeWidth
+		line(fmt.Sprintf("%s    %s %s %s", pointer, checkbox, padRight(truncateLeft(item.Label, labelWidth), labelWidth),
+			padLeft(utils.FormatBytes(item.Size), sizeWidth)))
+	}
+
+	line("")
+	count, size := s.totals()
+	if s.confirming {
+		b.WriteString(utils.Yellow(truncateRight(fmt.Sprintf("%d items: %s %s ", count, i18n.T("prompt.cleanup_all", utils.FormatBytes(size)),
+			i18n.T("prompt.confirm_suffix")), width)))
+	} else {
+		b.WriteString(utils.GreenBold(truncateRight(fmt.Sprintf("Selected: %d of %d items, %s", count, len(s.items), utils.FormatBytes(size)), width)))
+	}
+	b.WriteString("\033[K")
+	return b.String()
+}
+
+// truncateRight shortens s to at most width runes, marking the cut at the end with an ellipsis.
+func truncateRight(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// truncateLeft shortens s to at most width runes, marking the cut at the start with an ellipsis.
+func truncateLeft(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) <= width {
+		return s
+	}
+	return "…" + string(runes[len(runes)-width+1:])
+}
+
+// padRight pads s with spaces to width runes.
+func padRight(s string, width int) string {
+	if n := width - len([]rune(s)); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// padLeft pads s with leading spaces to width runes.
+func padLeft(s string, width int) string {
+	if n := width - len([]rune(s)); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
@@ -0,0 +1,13 @@
+//go:build !darwin && !linux
+
+package utils
+
+import (
+	"os"
+	"time"
+)
+
+// accessTime is not available on this platform.
+func accessTime(info os.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
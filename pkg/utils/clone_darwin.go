@@ -0,0 +1,45 @@
+//go:build darwin
+
+package utils
+
+import (
+	"encoding/binary"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// attrCmnextPrivateSize is ATTR_CMNEXT_PRIVATESIZE from <sys/attr.h>: the bytes of a file that
+// are not shared with clones. It is requested in the forkattr group with FSOPT_ATTR_CMN_EXTENDED.
+const attrCmnextPrivateSize = 0x00000008
+
+// privateSize returns the bytes of the file at path that aren't shared with APFS clones, with
+// getattrlist(2). It reports false on filesystems that don't know about clones (e.g., HFS+).
+func privateSize(path string) (int64, bool) {
+	pathPtr, err := unix.BytePtrFromString(path)
+	if err != nil {
+		return 0, false
+	}
+	attrs := unix.Attrlist{
+		Bitmapcount: unix.ATTR_BIT_MAP_COUNT,
+		Commonattr:  unix.ATTR_CMN_RETURNED_ATTRS,
+		Forkattr:    attrCmnextPrivateSize,
+	}
+	// The buffer holds its length, the attribute_set_t of the returned attributes, and the size.
+	var buf [4 + 5*4 + 8]byte
+	_, _, errno := unix.Syscall6(unix.SYS_GETATTRLIST,
+		uintptr(unsafe.Pointer(pathPtr)),
+		uintptr(unsafe.Pointer(&attrs)),
+		uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(len(buf)),
+		uintptr(unix.FSOPT_NOFOLLOW|unix.FSOPT_ATTR_CMN_EXTENDED),
+		0)
+	if errno != 0 {
+		return 0, false
+	}
+	// The forkattr field of the returned attribute set says whether the size was filled in.
+	if binary.NativeEndian.Uint32(buf[20:24])&attrCmnextPrivateSize == 0 {
+		return 0, false
+	}
+	return int64(binary.NativeEndian.Uint64(buf[24:32])), true
+}
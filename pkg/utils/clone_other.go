@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package utils
+
+// privateSize is not available on this platform, so files are assumed not to share blocks.
+func privateSize(path string) (int64, bool) {
+	return 0, false
+}
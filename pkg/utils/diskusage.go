@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ====================================================================================================
+// DISK USAGE FUNCTIONS
+// ====================================================================================================
+
+// DiskUsage returns the space a single filesystem entry occupies on disk (not recursive).
+// It uses `os.Lstat` so symbolic links are measured themselves, not their targets.
+//
+// Parameters:
+//   - path: The file or directory entry to measure.
+//
+// Returns:
+//   - The on-disk size in bytes and an error, if any.
+func DiskUsage(path string) (int64, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get info for %s: %w", path, err)
+	}
+	return FileInfoDiskUsage(info), nil
+}
+
+// FileInfoDiskUsage returns the on-disk size described by info.
+// On platforms that expose allocated blocks (darwin, linux) this is the "actual disk usage",
+// which is more accurate for sparse and compressed files. Elsewhere it falls back to the logical size.
+func FileInfoDiskUsage(info os.FileInfo) int64 {
+	if usage, ok := blockUsage(info); ok {
+		return usage
+	}
+	return info.Size()
+}
+
+// Usage is the disk usage of a file or directory tree.
+type Usage struct {
+	// Bytes is the allocated size, as FileInfoDiskUsage reports it.
+	Bytes int64
+	// Cloned is the part of Bytes that is shared with clones on copy-on-write filesystems such as
+	// APFS (e.g., files copied with `cp -c` or duplicated in Finder). Removing one copy doesn't
+	// free shared blocks, so only Private is reclaimed until every clone is gone.
+	Cloned int64
+}
+
+// Private returns the bytes that removing the file or tree actually frees.
+func (u Usage) Private() int64 {
+	return u.Bytes - u.Cloned
+}
+
+// add returns the sum of two usages.
+func (u Usage) add(other Usage) Usage {
+	return Usage{Bytes: u.Bytes + other.Bytes, Cloned: u.Cloned + other.Cloned}
+}
+
+// cloneCheckMinSize is the allocated size below which files aren't checked for clones: the check
+// costs a system call per file, and sharing a few small files hardly changes what is freed.
+const cloneCheckMinSize = 64 << 10
+
+// LinkSet remembers the files with several hard links that have been counted, so each of them is
+// counted once however many of its names a scan comes across. It is safe for concurrent use.
+type LinkSet struct {
+	mu   sync.Mutex
+	seen map[linkKey]bool
+}
+
+// linkKey identifies a file by its device and inode, which all of its hard links share.
+type linkKey struct {
+	device, inode uint64
+}
+
+// NewLinkSet creates an empty LinkSet, e.g. for all the items of one scan.
+func NewLinkSet() *LinkSet {
+	return &LinkSet{seen: make(map[linkKey]bool)}
+}
+
+// counted reports whether the entry described by info has other hard links and one of them
+// was counted already. The entry is remembered as counted.
+func (s *LinkSet) counted(info os.FileInfo) bool {
+	id, ok := FileInfoIdentity(info)
+	if !ok || id.Links < 2 {
+		return false
+	}
+	key := linkKey{id.Device, id.Inode}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[key] {
+		return true
+	}
+	s.seen[key] = true
+	return false
+}
+
+// FileUsage returns the usage of the single (non-directory) entry described by info, or an
+// empty usage if it is another name of a hard-linked file the set has counted already.
+func (s *LinkSet) FileUsage(path string, info os.FileInfo) Usage {
+	if s.counted(info) {
+		return Usage{}
+	}
+	return FileUsage(path, info)
+}
+
+// FileUsage returns the usage of the single (non-directory) entry described by info.
+func FileUsage(path string, info os.FileInfo) Usage {
+	usage := Usage{Bytes: FileInfoDiskUsage(info)}
+	if !info.Mode().IsRegular() || usage.Bytes < cloneCheckMinSize {
+		return usage
+	}
+	if private, ok := privateSize(path); ok && private < usage.Bytes {
+		usage.Cloned = usage.Bytes - max(private, 0)
+	}
+	return usage
+}
+
+// AccessTime returns when the file described by info was last accessed, falling back to its
+// modification time where the platform doesn't record access times. Note that many filesystems
+// update access times lazily (relatime) or not at all (noatime), so it is only a hint.
+func AccessTime(info os.FileInfo) time.Time {
+	if atime, ok := accessTime(info); ok {
+		return atime
+	}
+	return info.ModTime()
+}
+
+// FileIdentity identifies a filesystem entry independently of its path.
+type FileIdentity struct {
+	// Device is the ID of the device holding the entry.
+	Device uint64
+	// Inode is the entry's inode number on that device.
+	Inode uint64
+	// Links is the number of hard links to the entry.
+	Links uint64
+}
+
+// FileInfoIdentity returns the device, inode, and link count of the entry described by info.
+// The second result is false on platforms that don't expose them.
+func FileInfoIdentity(info os.FileInfo) (FileIdentity, bool) {
+	dev, hasDev := deviceID(info)
+	ino, links, hasIno := fileID(info)
+	if !hasDev || !hasIno {
+		return FileIdentity{}, false
+	}
+	return FileIdentity{Device: dev, Inode: ino, Links: links}, true
+}
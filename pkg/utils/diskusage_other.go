@@ -0,0 +1,28 @@
+//go:build !darwin && !linux
+
+package utils
+
+import (
+	"fmt"
+	"os"
+)
+
+// blockUsage is not available on this platform; callers fall back to the logical size.
+func blockUsage(info os.FileInfo) (int64, bool) {
+	return 0, false
+}
+
+// deviceID is not available on this platform.
+func deviceID(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
+
+// fileID is not available on this platform.
+func fileID(info os.FileInfo) (uint64, uint64, bool) {
+	return 0, 0, false
+}
+
+// filesystemSpace is not available on this platform.
+func filesystemSpace(path string) (int64, int64, error) {
+	return 0, 0, fmt.Errorf("filesystem statistics are not supported on this platform")
+}
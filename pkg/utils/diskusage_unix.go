@@ -0,0 +1,50 @@
+//go:build darwin || linux
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// blockUsage returns the allocated size of a file from its 512-byte block count.
+func blockUsage(info os.FileInfo) (int64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return stat.Blocks * 512, true
+}
+
+// deviceID returns the ID of the device holding the file described by info.
+func deviceID(info os.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	// Dev is an int32 on darwin and a uint64 on linux.
+	return uint64(stat.Dev), true
+}
+
+// filesystemSpace returns the free (available to the current user) and total bytes of the
+// filesystem containing path.
+func filesystemSpace(path string) (int64, int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, fmt.Errorf("failed to stat filesystem for %s: %w", path, err)
+	}
+	// Bsize has a different integer type on darwin and linux, so normalize it first.
+	blockSize := int64(stat.Bsize)
+	return int64(stat.Bavail) * blockSize, int64(stat.Blocks) * blockSize, nil
+}
+
+// fileID returns the inode number and link count of the file described by info.
+func fileID(info os.FileInfo) (uint64, uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	// Nlink is a uint16 on darwin and a uint64 on linux.
+	return uint64(stat.Ino), uint64(stat.Nlink), true
+}
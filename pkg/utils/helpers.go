@@ -1,12 +1,17 @@
 package utils
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io/fs"
 	"os"
+	"os/user"
 	"path/filepath"
 	"sort"
 	"strings"
-	"syscall"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/kodelint/wiper/pkg/logger"
@@ -35,8 +40,11 @@ var (
 // ====================================================================================================
 
 // GetFileSizeInBytes calculates the total size of a file or directory recursively.
-// It uses `os.Lstat` to correctly handle symbolic links and `syscall.Stat_t` to get
+// It uses `os.Lstat` to correctly handle symbolic links and DiskUsage to get
 // the more accurate "actual disk usage" rather than the logical file size.
+// Subdirectories are sized concurrently by a bounded pool of goroutines (see SetJobs),
+// so very large trees such as an app's Application Support folder don't dominate the runtime.
+// A file with several hard links in the tree is counted once.
 //
 // Parameters:
 //   - path: The file or directory path to check.
@@ -44,117 +52,171 @@ var (
 // Returns:
 //   - The total size in bytes and an error, if any.
 func GetFileSizeInBytes(path string) (int64, error) {
-	var totalSize int64
+	usage, err := GetFileUsage(path)
+	return usage.Bytes, err
+}
+
+// GetFileUsage is GetFileSizeInBytes, but also reports how much of the size is shared with
+// clones (see Usage).
+func GetFileUsage(path string) (Usage, error) {
+	return NewLinkSet().Usage(path)
+}
 
+// Usage is GetFileUsage, but files with several hard links are only counted the first time the
+// set sees them, in this walk or an earlier one (e.g., another item of the same scan).
+func (s *LinkSet) Usage(path string) (Usage, error) {
 	// First, check if the path exists
 	info, err := os.Lstat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return 0, nil // Path doesn't exist, size is 0
+			return Usage{}, nil // Path doesn't exist, size is 0
 		}
-		return 0, fmt.Errorf("failed to get info for %s: %w", path, err)
+		return Usage{}, fmt.Errorf("failed to get info for %s: %w", path, err)
 	}
 
-	// Use stat.Blocks * 512 for a more accurate size on disk
-	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
-		// Use the number of 512-byte blocks, which is more accurate for sparse files
-		if !info.IsDir() {
-			return stat.Blocks * 512, nil
-		}
-	} else {
-		// Fallback to logical size if not a syscall.Stat_t
-		if !info.IsDir() {
-			logger.Log.Debugf("Could not get actual disk usage for %s, falling back to logical size.", path)
-			return info.Size(), nil
-		}
+	// A single file only needs its own on-disk size.
+	if !info.IsDir() {
+		return s.FileUsage(path, info), nil
 	}
 
 	// For a directory, we need to walk it to get the total size of all its contents
-	err = filepath.Walk(path, func(subPath string, subInfo os.FileInfo, err error) error {
-		if err != nil {
-			logger.Log.Debugf("Error walking path %s for size calculation: %v", subPath, err)
-			return filepath.SkipDir
-		}
-
-		if subInfo.IsDir() {
-			// Get the size of the directory itself
-			if stat, ok := subInfo.Sys().(*syscall.Stat_t); ok {
-				totalSize += stat.Blocks * 512
-			} else {
-				totalSize += subInfo.Size()
-			}
-			return nil
-		}
+	return s.dirDiskUsage(path, info), nil
+}
 
-		// Get the size of the file
-		if stat, ok := subInfo.Sys().(*syscall.Stat_t); ok {
-			totalSize += stat.Blocks * 512
-		} else {
-			totalSize += subInfo.Size()
-		}
-		return nil
-	})
+// sizeWarningCategory and sizeIncompleteReason are used for the run's warnings about entries that
+// couldn't be measured.
+const (
+	sizeWarningCategory  = "Disk Usage"
+	sizeIncompleteReason = "size incomplete"
+)
 
-	if err != nil {
-		return 0, fmt.Errorf("failed to walk path %s: %w", path, err)
-	}
-	return totalSize, nil
-}
+// dirDiskUsage returns the on-disk size of dir and everything below it, without following
+// symbolic links. Subdirectories are handed to a worker of the shared pool when one is free and
+// sized inline otherwise (see runWorker).
+// Entries that can't be read are left out of the size and reported with the run's warnings, so a
+// partial size isn't taken for an exact one.
+func (s *LinkSet) dirDiskUsage(dir string, info os.FileInfo) Usage {
+	// Count the on-disk size of every entry, including the directories themselves.
+	total := Usage{Bytes: FileInfoDiskUsage(info)}
 
-// RemovePath removes a file or directory.
-// It handles symbolic links and includes a dry-run option.
-//
-// Parameters:
-//   - path: The path of the file or directory to remove.
-//   - dryRun: If true, the function will only log what it would do, without making changes.
-//
-// Returns:
-//   - The size of the removed item in bytes and an error, if any.
-func RemovePath(path string, dryRun bool) (int64, error) {
-	size, err := GetFileSizeInBytes(path)
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return 0, fmt.Errorf("could not get size of %s before removal: %w", path, err)
+		logger.RunWarnings.Add(sizeWarningCategory, sizeIncompleteReason, dir, err)
+		return total
 	}
 
-	if dryRun {
-		logger.Log.Debugf(Yellow("DRY RUN: Would remove granular item: %s (Size: %s)"), path, FormatBytes(size))
-		return size, nil
-	}
+	var wg sync.WaitGroup
+	var concurrentBytes, concurrentCloned atomic.Int64
+	for _, entry := range entries {
+		subPath := filepath.Join(dir, entry.Name())
+		// ReadDir entries report Lstat information, so links are measured, not followed.
+		subInfo, err := entry.Info()
+		if err != nil {
+			logger.RunWarnings.Add(sizeWarningCategory, sizeIncompleteReason, subPath, err)
+			continue
+		}
+		if !subInfo.IsDir() {
+			total = total.add(s.FileUsage(subPath, subInfo))
+			continue
+		}
 
-	logger.Log.Debugf(Red("Removing granular item: %s (Size: %s)"), path, FormatBytes(size))
-	//Enable it if we really need to remove it
-	logger.Log.Infof("Removing granular item: %s (Size: %s)", path, FormatBytes(size))
-	if err := os.RemoveAll(path); err != nil {
-		return 0, fmt.Errorf("failed to remove %s: %w", path, err)
+		runWorker(&wg, func() {
+			usage := s.dirDiskUsage(subPath, subInfo)
+			concurrentBytes.Add(usage.Bytes)
+			concurrentCloned.Add(usage.Cloned)
+		})
 	}
-	return size, nil
+	wg.Wait()
+	return total.add(Usage{Bytes: concurrentBytes.Load(), Cloned: concurrentCloned.Load()})
 }
 
 // ====================================================================================================
 // PATH AND STRING UTILITY FUNCTIONS
 // ====================================================================================================
 
-// ExpandPath replaces `~` with the user's home directory and expands
-// environment variables like `$HOME` or `${HOME}`.
-// This is crucial for handling user-provided paths reliably.
+// ExpandPath expands environment variables and a leading `~` in a user-provided path.
+// This is crucial for handling user-provided paths reliably, since custom targets and
+// ignore lists depend on it.
+//
+// The following forms are supported:
+//   - `$VAR` and `${VAR}` for any environment variable (e.g., `$HOME`, `${TMPDIR}`, `$XDG_CACHE_HOME`).
+//     Undefined variables are kept exactly as written rather than collapsing to an empty string,
+//     so a typo like `$HOEM/Library` can never turn into `/Library`, and a `$` that doesn't start
+//     a variable (e.g., `My$Drive`, `cost$`) keeps naming the same file.
+//   - `~` and `~/...` for the current user's home directory.
+//   - `~user` and `~user/...` for another user's home directory.
+//
+// A `~` anywhere other than at the start of the path (e.g., `/Volumes/Backup/~old`) is a
+// regular character and is kept as-is, matching shell behavior.
 func ExpandPath(path string) string {
-	if strings.HasPrefix(path, "~") {
-		homeDir, err := os.UserHomeDir()
-		if err == nil {
-			path = filepath.Join(homeDir, path[1:])
+	return expandTilde(expandVariables(path))
+}
+
+// expandVariables replaces the `$VAR` and `${VAR}` references to set environment variables in
+// path. Unlike os.Expand, it keeps everything else as written: unset variables, an unterminated
+// `${`, and a `$` that isn't followed by a variable name.
+func expandVariables(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] != '$' {
+			b.WriteByte(path[i])
+			continue
 		}
-	} else if strings.Contains(path, "$HOME") {
-		homeDir := os.Getenv("HOME")
-		if homeDir != "" {
-			path = strings.ReplaceAll(path, "$HOME", homeDir)
+		name, end := "", i+1
+		if end < len(path) && path[end] == '{' {
+			if closing := strings.IndexByte(path[end:], '}'); closing > 0 {
+				name, end = path[end+1:end+closing], end+closing+1
+			}
+		} else {
+			for end < len(path) && isVariableChar(path[end]) {
+				end++
+			}
+			name = path[i+1 : end]
+		}
+		if value, ok := os.LookupEnv(name); ok && name != "" {
+			b.WriteString(value)
+		} else {
+			b.WriteString(path[i:max(end, i+1)])
+		}
+		i = max(end, i+1) - 1
+	}
+	return b.String()
+}
+
+// isVariableChar reports whether c can be part of an environment variable name in `$VAR`.
+func isVariableChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// expandTilde replaces a leading `~` or `~user` with the corresponding home directory.
+// Paths whose user can't be resolved are returned unchanged.
+func expandTilde(path string) string {
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+
+	// Split "~user/rest" into the user name and the remainder (which keeps its leading separator).
+	name, rest := path[1:], ""
+	if idx := strings.IndexRune(name, os.PathSeparator); idx >= 0 {
+		name, rest = name[:idx], name[idx:]
+	}
+
+	var homeDir string
+	if name == "" {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return path
 		}
-	} else if strings.Contains(path, "${HOME}") {
-		homeDir := os.Getenv("HOME")
-		if homeDir != "" {
-			path = strings.ReplaceAll(path, "${HOME}", homeDir)
+		homeDir = dir
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil || u.HomeDir == "" {
+			logger.Log.Debugf("Could not resolve home directory for user %s: %v", name, err)
+			return path
 		}
+		homeDir = u.HomeDir
 	}
-	return path
+	return homeDir + rest
 }
 
 // FormatBytes converts an integer size in bytes into a human-readable string.
@@ -185,30 +247,64 @@ func FormatBytes(b int64) string {
 // ContainsPath checks if a given path is a sub-path of any path in a list.
 // This is used to implement the `--ignore` functionality.
 // It handles cases where an item to be checked is a child of an ignored directory.
+//
+// Matching is aware of the filesystem it runs on:
+//   - On case-insensitive volumes (the APFS default), `~/library/caches` also ignores `~/Library/Caches`.
+//   - Symlinked ignore roots (e.g., `/tmp` -> `/private/tmp`) match both the link and its real path.
+//   - Ignore entries may end in glob patterns (e.g., `~/Library/Caches/com.google.*`, `/tmp/*.log`),
+//     in which case any target at or below a matching path is ignored. `**` matches any number of
+//     directories (e.g., `~/Projects/**/node_modules`), and patterns starting with `**` match
+//     anywhere (e.g., `**/*.sqlite`).
+//   - Entries starting with `re:` are regular expressions matched against the absolute path of the
+//     target and its parent directories (e.g., `re:\.(sqlite|db)$`).
 func ContainsPath(targetPath string, ignorePaths []string) bool {
 	absTargetPath, err := filepath.Abs(targetPath)
 	if err != nil {
 		logger.Log.Warnf("Could not get absolute path for target %s: %v", targetPath, err)
 		return false
 	}
+	// Clean the path to handle cases like /tmp/../var or double slashes
+	targetForms := pathForms(filepath.Clean(absTargetPath))
 
 	for _, ignored := range ignorePaths {
+		if expr, ok := strings.CutPrefix(ignored, RegexIgnorePrefix); ok {
+			if matchesRegexOrAncestor(expr, targetForms) {
+				logger.Log.Debugf("Path %s is ignored because it matches %s", targetPath, ignored)
+				return true
+			}
+			continue
+		}
+
 		// IMPORTANT: Expand the ignored path first, then absolutize it
-		expandedIgnored := ExpandPath(ignored)
-		absIgnoredPath, err := filepath.Abs(expandedIgnored)
+		cleanIgnoredPath, err := AbsIgnorePath(ignored)
 		if err != nil {
-			logger.Log.Warnf("Could not get absolute path for ignore path %s (expanded from %s): %v", expandedIgnored, ignored, err)
+			logger.Log.Warnf("Could not get absolute path for ignore path %s: %v", ignored, err)
 			continue
 		}
+		anywhere := isAnywherePattern(cleanIgnoredPath)
+		caseInsensitive := false
+		if !anywhere {
+			caseInsensitive = isCaseInsensitive(globRoot(cleanIgnoredPath))
+		}
 
-		// Clean the paths to handle cases like /tmp/../var or double slashes
-		cleanTargetPath := filepath.Clean(absTargetPath)
-		cleanIgnoredPath := filepath.Clean(absIgnoredPath)
-
-		// Check if the targetPath is the ignoredPath itself or a sub-path of the ignoredPath
-		if cleanTargetPath == cleanIgnoredPath || strings.HasPrefix(cleanTargetPath, cleanIgnoredPath+string(os.PathSeparator)) {
-			logger.Log.Debugf("Path %s is ignored because it's under %s", targetPath, ignored)
-			return true
+		for _, candidate := range targetForms {
+			var matched bool
+			if anywhere {
+				matched = matchesGlobOrAncestor(cleanIgnoredPath, candidate, isCaseInsensitive(filepath.Dir(candidate)))
+			} else if hasGlobMeta(cleanIgnoredPath) {
+				matched = matchesGlobOrAncestor(cleanIgnoredPath, candidate, caseInsensitive)
+			} else {
+				for _, ignoredForm := range ignoreRootForms(cleanIgnoredPath) {
+					if isSubPath(candidate, ignoredForm, caseInsensitive) {
+						matched = true
+						break
+					}
+				}
+			}
+			if matched {
+				logger.Log.Debugf("Path %s is ignored because it's under %s", targetPath, ignored)
+				return true
+			}
 		}
 	}
 	return false
@@ -220,6 +316,69 @@ func IsPathIgnored(path string, ignorePaths []string) bool {
 	return ContainsPath(path, ignorePaths)
 }
 
+// IgnoredBelow returns a path inside dir that one of ignorePaths ignores, so removing dir as a whole
+// would remove it too, or "" if there is none. ContainsPath only looks at dir and its parents, which
+// is enough for entries that name dir or a parent, but not for entries below dir: plain paths are
+// looked up directly, and dir is walked for glob patterns that can match inside it, patterns
+// starting with `**` and regular expressions.
+//
+// Returns:
+//   - The first ignored path found, or "", and an error if part of dir can't be read.
+func IgnoredBelow(dir string, ignorePaths []string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	absDir = filepath.Clean(absDir)
+	caseInsensitive := isCaseInsensitive(absDir)
+
+	var patterns []string
+	for _, ignored := range ignorePaths {
+		if strings.HasPrefix(ignored, RegexIgnorePrefix) {
+			patterns = append(patterns, ignored)
+			continue
+		}
+		cleanIgnoredPath, err := AbsIgnorePath(ignored)
+		if err != nil {
+			continue
+		}
+		if isAnywherePattern(cleanIgnoredPath) {
+			patterns = append(patterns, ignored)
+			continue
+		}
+		if hasGlobMeta(cleanIgnoredPath) {
+			root := globRoot(cleanIgnoredPath)
+			if isSubPath(root, absDir, caseInsensitive) || isSubPath(absDir, root, caseInsensitive) {
+				patterns = append(patterns, ignored)
+			}
+			continue
+		}
+		for _, form := range ignoreRootForms(cleanIgnoredPath) {
+			if form != absDir && isSubPath(form, absDir, caseInsensitive) {
+				if _, err := os.Lstat(form); err == nil {
+					return form, nil
+				}
+			}
+		}
+	}
+	if len(patterns) == 0 {
+		return "", nil
+	}
+
+	var found string
+	err = filepath.WalkDir(absDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != absDir && ContainsPath(path, patterns) {
+			found = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found, err
+}
+
 // FindPaths searches for application bundles and associated data in a list of root directories.
 // This function is specifically designed to support the application uninstallation logic.
 //
@@ -292,3 +451,13 @@ func FindPaths(basePaths []string, name string) []string {
 	sort.Strings(result)
 	return result
 }
+
+// NewRunID returns a unique, sortable identifier for a wiper run, e.g. "20250102-150405-a1b2c3".
+// It combines the start time with random bytes so concurrent runs never collide.
+func NewRunID() string {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return time.Now().Format("20060102-150405")
+	}
+	return fmt.Sprintf("%s-%x", time.Now().Format("20060102-150405"), suffix)
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %v", err)
+	}
+	t.Setenv("WIPER_TEST_DIR", "/data/wiper")
+	t.Setenv("WIPER_TEST_NAME", "wiper")
+	os.Unsetenv("WIPER_TEST_UNSET")
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"dollar variable", "$WIPER_TEST_DIR/cache", "/data/wiper/cache"},
+		{"braced variable", "${WIPER_TEST_DIR}cache", "/data/wipercache"},
+		{"unset variable", "$WIPER_TEST_UNSET/Library", "$WIPER_TEST_UNSET/Library"},
+		{"unset braced variable", "${WIPER_TEST_UNSET}/Library", "${WIPER_TEST_UNSET}/Library"},
+		{"literal dollar in a name", "/Volumes/My$-Drive/x", "/Volumes/My$-Drive/x"},
+		{"trailing dollar", "/tmp/cost$", "/tmp/cost$"},
+		{"unterminated brace", "/tmp/${WIPER_TEST_DIR", "/tmp/${WIPER_TEST_DIR"},
+		{"empty braces", "/tmp/${}", "/tmp/${}"},
+		{"home", "~", home},
+		{"home subpath", "~/Library/Caches", filepath.Join(home, "Library", "Caches")},
+		{"tilde inside a path", "/Volumes/Backup/~old", "/Volumes/Backup/~old"},
+		{"home and variable", "~/$WIPER_TEST_NAME", filepath.Join(home, "wiper")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandPath(tt.path); got != tt.want {
+				t.Errorf("ExpandPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandPathKeepsLiteralDollarWhenVariableIsSet(t *testing.T) {
+	// "My$Drive" references $Drive; only its value may replace it, never "${Drive}".
+	os.Unsetenv("Drive")
+	if got := ExpandPath("/Volumes/My$Drive"); got != "/Volumes/My$Drive" {
+		t.Errorf("ExpandPath = %q, want /Volumes/My$Drive", got)
+	}
+	t.Setenv("Drive", "Stick")
+	if got := ExpandPath("/Volumes/My$Drive"); got != "/Volumes/MyStick" {
+		t.Errorf("ExpandPath = %q, want /Volumes/MyStick", got)
+	}
+}
+
+func TestContainsPath(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		target string
+		ignore string
+		want   bool
+	}{
+		{"exact path", "cache", "cache", true},
+		{"below an ignored path", "cache/a/b", "cache", true},
+		{"sibling with the ignored path as prefix", "cache-old", "cache", false},
+		{"parent of an ignored path", "cache", "cache/a", false},
+		{"glob", "build-1", "build-*", true},
+		{"below a glob match", "build-1/out/app", "build-*", true},
+		{"glob not matching", "builds", "build-*", false},
+		{"double star in the middle", "src/a/b/node_modules/x", "src/**/node_modules", true},
+		{"double star matching no directory", "src/node_modules", "src/**/node_modules", true},
+		{"double star below another root", "other/node_modules", "src/**/node_modules", false},
+		{"double star anywhere", "a/b/index.sqlite", "**/*.sqlite", true},
+		{"double star anywhere not matching", "a/b/index.sqlite-wal", "**/*.sqlite", false},
+		{"regular expression", "a/index.db", `re:\.(sqlite|db)$`, true},
+		{"regular expression matching a parent", "a/logs.db/entry", `re:\.(sqlite|db)$`, true},
+		{"regular expression not matching", "a/index.dbx", `re:\.(sqlite|db)$`, false},
+		{"invalid regular expression", "a/index.db", `re:(`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := filepath.Join(dir, tt.target)
+			ignore := tt.ignore
+			if !strings.HasPrefix(ignore, RegexIgnorePrefix) && !strings.HasPrefix(ignore, "**") {
+				ignore = filepath.Join(dir, ignore)
+			}
+			if got := ContainsPath(target, []string{ignore}); got != tt.want {
+				t.Errorf("ContainsPath(%q, %q) = %v, want %v", target, ignore, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsPathCaseInsensitive(t *testing.T) {
+	dir := t.TempDir()
+	ignored := filepath.Join(dir, "Caches")
+	target := filepath.Join(dir, "caches", "com.example.app")
+	if ContainsPath(target, []string{ignored}) {
+		t.Fatalf("ContainsPath(%q) = true on a case-sensitive volume", target)
+	}
+
+	// Pretend the volume holding dir is case-insensitive, like APFS by default.
+	caseSensitivityCache.Store(ignored, true)
+	caseSensitivityCache.Store(filepath.Join(dir, "Build-*"), true)
+	caseSensitivityCache.Store(dir, true)
+	t.Cleanup(func() {
+		caseSensitivityCache.entries.Clear()
+		caseSensitivityCache.count.Store(0)
+	})
+	tests := []struct {
+		target string
+		ignore string
+	}{
+		{target, ignored},
+		{filepath.Join(dir, "build-1", "out"), filepath.Join(dir, "Build-*")},
+	}
+	for _, tt := range tests {
+		if !ContainsPath(tt.target, []string{tt.ignore}) {
+			t.Errorf("ContainsPath(%q, %q) = false on a case-insensitive volume, want true", tt.target, tt.ignore)
+		}
+	}
+}
+
+func TestIgnoredBelow(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{"app/Cache/data", "app/prefs/settings.plist", "app/db/index.sqlite"} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	app := filepath.Join(dir, "app")
+
+	tests := []struct {
+		name   string
+		ignore string
+		want   string
+	}{
+		{"nothing ignored below", filepath.Join(dir, "other"), ""},
+		{"plain path below", filepath.Join(app, "prefs"), filepath.Join(app, "prefs")},
+		{"missing plain path below", filepath.Join(app, "gone"), ""},
+		{"directory itself", app, ""},
+		{"glob below", filepath.Join(app, "prefs", "*.plist"), filepath.Join(app, "prefs", "settings.plist")},
+		{"glob above", filepath.Join(dir, "*", "prefs"), filepath.Join(app, "prefs")},
+		{"double star anywhere", "**/*.sqlite", filepath.Join(app, "db", "index.sqlite")},
+		{"double star below", filepath.Join(app, "**", "data"), filepath.Join(app, "Cache", "data")},
+		{"regular expression", `re:/prefs$`, filepath.Join(app, "prefs")},
+		{"regular expression not matching", `re:\.db$`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IgnoredBelow(app, []string{tt.ignore})
+			if err != nil {
+				t.Fatalf("IgnoredBelow() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IgnoredBelow(%q, %q) = %q, want %q", app, tt.ignore, got, tt.want)
+			}
+		})
+	}
+}
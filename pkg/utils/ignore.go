@@ -0,0 +1,305 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+)
+
+// ====================================================================================================
+// IGNORE MATCHING HELPERS
+// ====================================================================================================
+
+// caseSensitivityCache remembers whether the volume holding a path is case-insensitive, so the
+// probe only runs once per path.
+var caseSensitivityCache pathCache // map[string]bool
+
+// resolvedDirCache remembers the real path of directories whose symlinks were already evaluated.
+var resolvedDirCache pathCache // map[string]string
+
+// maxCachedPaths bounds the entries of a pathCache.
+const maxCachedPaths = 10000
+
+// pathCache is a concurrency-safe map from paths to values that is emptied once it holds
+// maxCachedPaths entries, so long-running commands (e.g., watch or dashboard) that look at many
+// paths don't grow it without bound.
+type pathCache struct {
+	entries sync.Map
+	count   atomic.Int64
+}
+
+// Load returns the value cached for path, if any.
+func (c *pathCache) Load(path string) (any, bool) {
+	return c.entries.Load(path)
+}
+
+// Store caches value for path, first emptying the cache if it is full.
+func (c *pathCache) Store(path string, value any) {
+	if c.count.Add(1) > maxCachedPaths {
+		c.entries.Clear()
+		c.count.Store(1)
+	}
+	c.entries.Store(path, value)
+}
+
+// RegexIgnorePrefix marks ignore entries that are regular expressions (e.g., `re:\.sqlite$`).
+const RegexIgnorePrefix = "re:"
+
+// ignoreRegexCache holds the compiled regular expressions of ignore entries, or their errors.
+var ignoreRegexCache sync.Map // map[string]*regexp.Regexp or error
+
+// ExpandIgnorePath expands `~` and environment variables in an ignore entry, leaving regular
+// expressions (whose `$` anchors aren't variables) unchanged.
+func ExpandIgnorePath(ignored string) string {
+	if strings.HasPrefix(ignored, RegexIgnorePrefix) {
+		return ignored
+	}
+	return ExpandPath(ignored)
+}
+
+// AbsIgnorePath expands an ignore entry and makes it absolute. Regular expressions and patterns
+// that match anywhere (starting with `**`) are returned as they are.
+func AbsIgnorePath(ignored string) (string, error) {
+	expanded := ExpandIgnorePath(ignored)
+	if strings.HasPrefix(expanded, RegexIgnorePrefix) {
+		return expanded, nil
+	}
+	if isAnywherePattern(expanded) {
+		return filepath.Clean(expanded), nil
+	}
+	abs, err := filepath.Abs(expanded)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Clean(abs), nil
+}
+
+// ValidateIgnorePath reports whether an ignore entry is a valid glob pattern or regular expression.
+func ValidateIgnorePath(ignored string) error {
+	if expr, ok := strings.CutPrefix(ignored, RegexIgnorePrefix); ok {
+		_, err := ignoreRegex(expr)
+		return err
+	}
+	for _, segment := range strings.Split(filepath.ToSlash(ignored), "/") {
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", ignored, err)
+		}
+	}
+	return nil
+}
+
+// ignoreRegex compiles the regular expression of an ignore entry, caching the result.
+func ignoreRegex(expr string) (*regexp.Regexp, error) {
+	if cached, ok := ignoreRegexCache.Load(expr); ok {
+		if err, isErr := cached.(error); isErr {
+			return nil, err
+		}
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		err = fmt.Errorf("invalid regular expression %q: %w", expr, err)
+		ignoreRegexCache.Store(expr, err)
+		return nil, err
+	}
+	ignoreRegexCache.Store(expr, re)
+	return re, nil
+}
+
+// matchesRegexOrAncestor reports whether one of the target forms, or one of their parent
+// directories, matches the regular expression expr. Invalid expressions match nothing.
+func matchesRegexOrAncestor(expr string, targetForms []string) bool {
+	re, err := ignoreRegex(expr)
+	if err != nil {
+		return false
+	}
+	for _, target := range targetForms {
+		for current := target; ; current = filepath.Dir(current) {
+			if re.MatchString(current) {
+				return true
+			}
+			parent := filepath.Dir(current)
+			if parent == current {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// isAnywherePattern reports whether pattern starts with `**`, so it matches below any directory.
+func isAnywherePattern(pattern string) bool {
+	return strings.HasPrefix(pattern, "**")
+}
+
+// hasGlobMeta reports whether path contains glob metacharacters understood by filepath.Match.
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// globRoot returns the longest leading part of a pattern that contains no glob metacharacters.
+// For a plain path, it is the path itself.
+func globRoot(pattern string) string {
+	root := pattern
+	for hasGlobMeta(root) {
+		root = filepath.Dir(root)
+	}
+	return root
+}
+
+// isSubPath reports whether target is root itself or located below root.
+func isSubPath(target string, root string, caseInsensitive bool) bool {
+	if caseInsensitive {
+		target, root = strings.ToLower(target), strings.ToLower(root)
+	}
+	if root == string(os.PathSeparator) {
+		return true
+	}
+	return target == root || strings.HasPrefix(target, root+string(os.PathSeparator))
+}
+
+// matchesGlobOrAncestor reports whether target, or any of its parent directories, matches pattern.
+// This makes an ignore pattern such as `/tmp/build-*` also cover everything inside matching directories.
+func matchesGlobOrAncestor(pattern string, target string, caseInsensitive bool) bool {
+	if caseInsensitive {
+		pattern, target = strings.ToLower(pattern), strings.ToLower(target)
+	}
+	for current := target; ; current = filepath.Dir(current) {
+		if matchGlob(pattern, current) {
+			return true
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return false
+		}
+	}
+}
+
+// matchGlob reports whether path matches pattern like filepath.Match, except that a `**`
+// component matches any number of directories, including none.
+func matchGlob(pattern, path string) bool {
+	if !strings.Contains(pattern, "**") {
+		matched, err := filepath.Match(pattern, path)
+		return err == nil && matched
+	}
+	return matchComponents(strings.Split(pattern, string(os.PathSeparator)), strings.Split(path, string(os.PathSeparator)))
+}
+
+// matchComponents matches the components of a path against those of a pattern with `**`.
+func matchComponents(pattern, components []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(components); i++ {
+				if matchComponents(pattern[1:], components[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(components) == 0 {
+			return false
+		}
+		if matched, err := filepath.Match(pattern[0], components[0]); err != nil || !matched {
+			return false
+		}
+		pattern, components = pattern[1:], components[1:]
+	}
+	return len(components) == 0
+}
+
+// pathForms returns the literal target path plus, if different, the path with its parent
+// directory's symlinks resolved. The final component is never followed, since the link itself
+// (not its destination) is what a cleanup would remove.
+func pathForms(path string) []string {
+	forms := []string{path}
+	parent := filepath.Dir(path)
+	if resolvedParent := resolveDir(parent); resolvedParent != parent {
+		forms = append(forms, filepath.Join(resolvedParent, filepath.Base(path)))
+	}
+	return forms
+}
+
+// ignoreRootForms returns the ignore root plus its fully resolved real path, if different.
+func ignoreRootForms(root string) []string {
+	forms := []string{root}
+	if resolved := resolveDir(root); resolved != root {
+		forms = append(forms, resolved)
+	}
+	return forms
+}
+
+// resolveDir evaluates symlinks in dir, caching the result. Directories that don't exist
+// (or can't be resolved) are returned unchanged.
+func resolveDir(dir string) string {
+	if cached, ok := resolvedDirCache.Load(dir); ok {
+		return cached.(string)
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		resolved = dir
+	}
+	resolvedDirCache.Store(dir, resolved)
+	return resolved
+}
+
+// isCaseInsensitive probes whether the volume holding path treats names case-insensitively.
+// It looks up the nearest existing ancestor under a case-swapped name and checks whether it
+// resolves to the same file. If no probe is possible, case-sensitive matching is assumed.
+func isCaseInsensitive(path string) bool {
+	if cached, ok := caseSensitivityCache.Load(path); ok {
+		return cached.(bool)
+	}
+	probe := path
+	for {
+		if _, err := os.Lstat(probe); err == nil && hasLetters(filepath.Base(probe)) {
+			break
+		}
+		parent := filepath.Dir(probe)
+		if parent == probe {
+			return false
+		}
+		probe = parent
+	}
+
+	if cached, ok := caseSensitivityCache.Load(probe); ok {
+		caseSensitivityCache.Store(path, cached)
+		return cached.(bool)
+	}
+
+	original, err := os.Lstat(probe)
+	insensitive := false
+	if err == nil {
+		swapped := filepath.Join(filepath.Dir(probe), swapCase(filepath.Base(probe)))
+		if other, err := os.Lstat(swapped); err == nil {
+			insensitive = os.SameFile(original, other)
+		}
+	}
+	caseSensitivityCache.Store(probe, insensitive)
+	caseSensitivityCache.Store(path, insensitive)
+	return insensitive
+}
+
+// hasLetters reports whether s contains at least one letter whose case can be swapped.
+func hasLetters(s string) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) || unicode.IsLower(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// swapCase inverts the case of every letter in s.
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
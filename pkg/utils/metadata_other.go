@@ -0,0 +1,16 @@
+//go:build !darwin && !linux
+
+package utils
+
+import "os"
+
+// copyMetadata carries the modification time of src over to its copy at dest. Extended
+// attributes and ownership are not available on this platform.
+func copyMetadata(src, dest string, info os.FileInfo, lost *[]MetadataLoss) {
+	if info.Mode()&os.ModeSymlink != 0 {
+		return
+	}
+	if err := os.Chtimes(dest, info.ModTime(), info.ModTime()); err != nil {
+		*lost = append(*lost, MetadataLoss{Path: src, What: "timestamps", Err: err})
+	}
+}
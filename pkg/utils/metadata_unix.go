@@ -0,0 +1,48 @@
+//go:build darwin || linux
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// copyMetadata carries the metadata of src over to its copy at dest: extended attributes
+// (which include Finder tags, quarantine flags and, on Linux, POSIX ACLs), ownership, and
+// access and modification times. Symbolic links are updated themselves, not their targets.
+// Metadata that can't be applied is recorded in lost instead of failing the copy.
+func copyMetadata(src, dest string, info os.FileInfo, lost *[]MetadataLoss) {
+	names, err := listXattrs(src)
+	if err != nil {
+		*lost = append(*lost, MetadataLoss{Path: src, What: "extended attributes", Err: err})
+	}
+	for _, name := range names {
+		value, err := readXattr(src, name)
+		if err == nil {
+			err = writeXattr(dest, name, value)
+		}
+		if err != nil {
+			*lost = append(*lost, MetadataLoss{Path: src, What: fmt.Sprintf("extended attribute %s", name), Err: err})
+		}
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	// Only root can give files away; files of the current user already have the right owner.
+	if int(stat.Uid) != os.Getuid() || int(stat.Gid) != os.Getgid() {
+		if err := os.Lchown(dest, int(stat.Uid), int(stat.Gid)); err != nil {
+			*lost = append(*lost, MetadataLoss{Path: src, What: "ownership", Err: err})
+		}
+	}
+
+	// Set the times last, as copying the contents and attributes updates them.
+	times := []unix.Timespec{unix.NsecToTimespec(AccessTime(info).UnixNano()), unix.NsecToTimespec(info.ModTime().UnixNano())}
+	if err := unix.UtimesNanoAt(unix.AT_FDCWD, dest, times, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		*lost = append(*lost, MetadataLoss{Path: src, What: "timestamps", Err: err})
+	}
+}
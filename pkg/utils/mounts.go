@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kodelint/wiper/pkg/logger"
+)
+
+// ====================================================================================================
+// MOUNTED VOLUMES
+// ====================================================================================================
+
+// Mount is a mounted filesystem.
+type Mount struct {
+	// Path is the mount point (e.g., "/" or "/Volumes/External").
+	Path string
+	// Device is what is mounted there, e.g. "/dev/disk3s1" or "//user@server/share".
+	Device string
+	// FSType is the filesystem type, e.g. "apfs", "msdos" or "ext4".
+	FSType string
+	// ReadOnly is true for filesystems mounted read-only, where nothing can be removed.
+	ReadOnly bool
+	// Network is true for filesystems on another machine (SMB, AFP, NFS, WebDAV, ...), where
+	// listing a large tree can take minutes.
+	Network bool
+	// Hidden is true for filesystems that aren't volumes to users: pseudo filesystems such as devfs
+	// and proc, and the system volumes macOS keeps out of Finder (/System/Volumes/VM, ...).
+	Hidden bool
+}
+
+// Volumes returns the mounted volumes a user would recognize, sorted by mount point, leaving out
+// hidden filesystems (see Mount.Hidden). Of several filesystems mounted on the same path, only the
+// last one mounted is visible, so only that one is returned.
+func Volumes() ([]Mount, error) {
+	mounts, err := mounts()
+	if err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]Mount)
+	for _, mount := range mounts {
+		byPath[mount.Path] = mount
+	}
+	var volumes []Mount
+	for _, mount := range byPath {
+		if !mount.Hidden {
+			volumes = append(volumes, mount)
+		}
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Path < volumes[j].Path })
+	return volumes, nil
+}
+
+// networkVolumes makes walks descend into network volumes, which they skip by default.
+var networkVolumes bool
+
+// SetScanNetworkVolumes controls whether scans descend into network volumes (see Mount.Network)
+// they come across, which they skip by default. Paths on a network volume that a scan starts
+// from are always scanned.
+func SetScanNetworkVolumes(enabled bool) {
+	networkVolumes = enabled
+}
+
+// ScanNetworkVolumes reports whether scans descend into network volumes.
+func ScanNetworkVolumes() bool {
+	return networkVolumes
+}
+
+// networkMountsBelow returns the mount points of the network volumes below root, which a walk of
+// root skips, or nil if network volumes are scanned.
+func networkMountsBelow(root string) map[string]bool {
+	if networkVolumes {
+		return nil
+	}
+	mounts, err := mounts()
+	if err != nil {
+		logger.Log.Debugf("Failed to look for network volumes: %v", err)
+		return nil
+	}
+	var below map[string]bool
+	for _, mount := range mounts {
+		if mount.Network && mount.Path != filepath.Clean(root) && isWithinMount(mount.Path, root) {
+			if below == nil {
+				below = make(map[string]bool)
+			}
+			below[mount.Path] = true
+		}
+	}
+	return below
+}
+
+// VolumeOf returns the volume of volumes whose mount point is the longest prefix of path, or false
+// if none contains it. path must be absolute.
+func VolumeOf(path string, volumes []Mount) (Mount, bool) {
+	var best Mount
+	found := false
+	for _, volume := range volumes {
+		if !isWithinMount(path, volume.Path) {
+			continue
+		}
+		if !found || len(volume.Path) > len(best.Path) {
+			best, found = volume, true
+		}
+	}
+	return best, found
+}
+
+// isWithinMount reports whether path is the mount point mountPath or below it.
+func isWithinMount(path string, mountPath string) bool {
+	path, mountPath = filepath.Clean(path), filepath.Clean(mountPath)
+	if mountPath == string(filepath.Separator) || path == mountPath {
+		return true
+	}
+	return strings.HasPrefix(path, mountPath+string(filepath.Separator))
+}
@@ -0,0 +1,45 @@
+//go:build darwin
+
+package utils
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// hiddenFSTypes are pseudo filesystems that aren't volumes to users.
+var hiddenFSTypes = map[string]bool{"devfs": true, "autofs": true, "nullfs": true}
+
+// networkFSTypes are the filesystems of file servers.
+var networkFSTypes = map[string]bool{"smbfs": true, "afpfs": true, "nfs": true, "webdav": true, "ftp": true}
+
+// mounts lists the mounted filesystems with getfsstat. MNT_NOWAIT returns the cached statistics,
+// so an unresponsive network volume doesn't block the call.
+func mounts() ([]Mount, error) {
+	n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mounted filesystems: %w", err)
+	}
+	// Leave room for volumes mounted between the two calls.
+	buf := make([]unix.Statfs_t, n+8)
+	n, err = unix.Getfsstat(buf, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mounted filesystems: %w", err)
+	}
+	result := make([]Mount, 0, n)
+	for _, stat := range buf[:n] {
+		mount := Mount{
+			Path:     unix.ByteSliceToString(stat.Mntonname[:]),
+			Device:   unix.ByteSliceToString(stat.Mntfromname[:]),
+			FSType:   unix.ByteSliceToString(stat.Fstypename[:]),
+			ReadOnly: stat.Flags&unix.MNT_RDONLY != 0,
+		}
+		// Volumes of file servers aren't local; webdav and FUSE mounts may not say so, but their type does.
+		mount.Network = stat.Flags&unix.MNT_LOCAL == 0 || networkFSTypes[mount.FSType]
+		// Finder doesn't show volumes marked "don't browse", such as /System/Volumes/VM and Preboot.
+		mount.Hidden = stat.Flags&unix.MNT_DONTBROWSE != 0 || hiddenFSTypes[mount.FSType]
+		result = append(result, mount)
+	}
+	return result, nil
+}
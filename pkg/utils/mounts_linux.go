@@ -0,0 +1,75 @@
+//go:build linux
+
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// hiddenFSTypes are pseudo and in-memory filesystems that aren't volumes to users.
+var hiddenFSTypes = map[string]bool{
+	"proc": true, "sysfs": true, "devtmpfs": true, "devpts": true, "tmpfs": true, "cgroup": true,
+	"cgroup2": true, "securityfs": true, "pstore": true, "bpf": true, "debugfs": true, "tracefs": true,
+	"mqueue": true, "hugetlbfs": true, "configfs": true, "fusectl": true, "binfmt_misc": true,
+	"autofs": true, "rpc_pipefs": true, "nsfs": true, "efivarfs": true, "ramfs": true, "squashfs": true,
+}
+
+// networkFSTypes are the filesystems of file servers, including FUSE clients such as sshfs.
+var networkFSTypes = map[string]bool{
+	"nfs": true, "nfs4": true, "cifs": true, "smb3": true, "smbfs": true, "afs": true, "9p": true,
+	"ceph": true, "glusterfs": true, "lustre": true, "fuse.sshfs": true, "fuse.davfs": true, "davfs": true,
+	"fuse.rclone": true, "fuse.s3fs": true,
+}
+
+// mounts lists the mounted filesystems from /proc/self/mounts.
+func mounts() ([]Mount, error) {
+	file, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mounted filesystems: %w", err)
+	}
+	defer file.Close()
+	var result []Mount
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Each line is "device mount-point type options dump pass".
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		mount := Mount{Device: unescapeMountField(fields[0]), Path: unescapeMountField(fields[1]), FSType: fields[2]}
+		mount.Hidden = hiddenFSTypes[mount.FSType]
+		mount.Network = networkFSTypes[mount.FSType]
+		if len(fields) > 3 {
+			mount.ReadOnly = slices.Contains(strings.Split(fields[3], ","), "ro")
+		}
+		result = append(result, mount)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list mounted filesystems: %w", err)
+	}
+	return result, nil
+}
+
+// unescapeMountField decodes the octal escapes (e.g., "\040" for a space) of /proc/self/mounts.
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+4 <= len(field) {
+			if code, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !linux
+
+package utils
+
+import "fmt"
+
+// mounts is not supported on this platform.
+func mounts() ([]Mount, error) {
+	return nil, fmt.Errorf("listing mounted volumes is not supported on this platform")
+}
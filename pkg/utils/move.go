@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/kodelint/wiper/pkg/logger"
+)
+
+// ====================================================================================================
+// MOVE FUNCTIONS
+// ====================================================================================================
+
+// MoveResult describes where MovePath put an item.
+type MoveResult struct {
+	// Dest is the path the item was moved to.
+	Dest string
+	// CrossedDevice is true when the item left its filesystem, i.e. its space was freed on the source volume.
+	CrossedDevice bool
+	// LostMetadata lists the metadata that couldn't be carried over by a cross-device copy.
+	// Renames keep everything, so it is always empty for moves within one filesystem.
+	LostMetadata []MetadataLoss
+}
+
+// MetadataLoss records a piece of metadata that couldn't be preserved when copying an entry.
+type MetadataLoss struct {
+	// Path is the source entry the metadata belonged to.
+	Path string
+	// What names the metadata (e.g., "extended attribute com.apple.metadata:_kMDItemUserTags", "ownership").
+	What string
+	// Err is why it couldn't be applied to the copy.
+	Err error
+}
+
+// MovePath moves a file or directory into destDir, creating destDir if necessary.
+// If an entry with the same name already exists there, a numbered name such as "report (1).pdf"
+// is used instead, so nothing in the destination is ever overwritten. Moves within one filesystem
+// are a simple rename; moves to another volume copy the data, flush the copy to disk and then
+// remove the original, without descending into other filesystems mounted below it.
+// Copies preserve permissions, extended attributes (including Finder tags and, on Linux, ACLs),
+// ownership where permitted, and access and modification times. ACLs on macOS are not extended
+// attributes and are not copied. Like RemovePath, it refuses protected paths (see IsProtectedPath).
+//
+// Parameters:
+//   - path: The file or directory to move.
+//   - destDir: The directory to move it into.
+//
+// Returns:
+//   - Where the item went and which metadata, if any, was lost on the way; see MoveResult.
+//   - An error if the move failed. A failed cross-device copy leaves the original in place.
+func MovePath(path string, destDir string) (MoveResult, error) {
+	if _, err := os.Lstat(path); err != nil {
+		return MoveResult{}, err
+	}
+	if err := checkNotProtected(path); err != nil {
+		return MoveResult{}, fmt.Errorf("%w: %s", err, path)
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return MoveResult{}, fmt.Errorf("failed to create %s: %w", destDir, err)
+	}
+
+	dest, err := availableName(destDir, filepath.Base(path))
+	if err != nil {
+		return MoveResult{}, err
+	}
+	return MovePathTo(path, dest)
+}
+
+// MovePathTo moves a file or directory to exactly dest, creating dest's parent directories if
+// necessary. Unlike MovePath it doesn't pick another name: it fails if dest already exists.
+// Metadata is preserved the same way as by MovePath.
+//
+// Parameters:
+//   - path: The file or directory to move.
+//   - dest: The new path of the item.
+//
+// Returns:
+//   - Where the item went and which metadata, if any, was lost on the way; see MoveResult.
+//   - An error if the move failed. A failed cross-device copy leaves the original in place.
+func MovePathTo(path string, dest string) (MoveResult, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return MoveResult{}, err
+	}
+	if _, err := os.Lstat(dest); err == nil {
+		return MoveResult{}, fmt.Errorf("%s already exists", dest)
+	} else if !os.IsNotExist(err) {
+		return MoveResult{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return MoveResult{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+	}
+
+	logger.Log.Infof("Moving %s to %s", path, dest)
+	err = os.Rename(path, dest)
+	if err == nil {
+		return MoveResult{Dest: dest}, nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return MoveResult{}, err
+	}
+
+	// Renames can't cross filesystems, so copy the item and remove the original afterwards. The
+	// copy is on disk before the original is removed, so a power loss in between loses neither.
+	// Like a removal, it stays on the item's filesystem: volumes mounted below it are refused.
+	dev, hasDev := deviceID(info)
+	result := MoveResult{Dest: dest, CrossedDevice: true}
+	err = copyTree(path, dest, info, dev, hasDev, &result.LostMetadata)
+	if err == nil {
+		err = syncDir(filepath.Dir(dest))
+	}
+	if err != nil {
+		os.RemoveAll(dest) // Don't leave a partial copy behind
+		return MoveResult{}, fmt.Errorf("failed to copy %s to %s: %w", path, dest, err)
+	}
+	if info.IsDir() {
+		err = removeTree(path, dev, hasDev)
+	} else {
+		err = os.Remove(path)
+	}
+	if err != nil {
+		return result, fmt.Errorf("copied %s to %s but failed to remove the original: %w", path, dest, err)
+	}
+	return result, nil
+}
+
+// syncDir flushes the entries of the directory at path to disk. Filesystems that can't sync
+// directories are left as they are.
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	if err := dir.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTSUP) {
+		return err
+	}
+	return nil
+}
+
+// availableName returns a path in dir for name that doesn't exist yet, appending " (1)", " (2)", ...
+// before the extension as needed.
+func availableName(dir string, name string) (string, error) {
+	candidate := filepath.Join(dir, name)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 1; i < 10000; i++ {
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+			return candidate, nil
+		} else if err != nil {
+			return "", err
+		}
+		candidate = filepath.Join(dir, fmt.Sprintf("%s (%d)%s", stem, i, ext))
+	}
+	return "", fmt.Errorf("no free name for %s in %s", name, dir)
+}
+
+// copyTree copies a file, symbolic link, or directory tree from src to dest, preserving
+// permissions and the metadata handled by copyMetadata, and flushes the copy to disk. When the
+// device ID is known, directories on another device are refused. Metadata that couldn't be
+// preserved is appended to lost.
+func copyTree(src string, dest string, info os.FileInfo, dev uint64, hasDev bool, lost *[]MetadataLoss) error {
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(target, dest); err != nil {
+			return err
+		}
+	case info.IsDir():
+		if hasDev {
+			if entryDev, ok := deviceID(info); ok && entryDev != dev {
+				return errCrossesDevice
+			}
+		}
+		if err := os.Mkdir(dest, info.Mode().Perm()); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			entryInfo, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			if err := copyTree(filepath.Join(src, entry.Name()), filepath.Join(dest, entry.Name()), entryInfo, dev, hasDev, lost); err != nil {
+				return err
+			}
+		}
+		if err := syncDir(dest); err != nil {
+			return err
+		}
+	case info.Mode().IsRegular():
+		if err := copyFile(src, dest, info); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot copy special file %s", src)
+	}
+	// Directories get their metadata after their entries, which would otherwise update their times.
+	copyMetadata(src, dest, info, lost)
+	return nil
+}
+
+// copyFile copies the contents and permissions of a regular file.
+func copyFile(src string, dest string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+)
+
+// ====================================================================================================
+// PROTECTED PATHS
+// ====================================================================================================
+
+// ErrProtectedPath is the underlying error of a removal refused because the path is protected
+// (see IsProtectedPath).
+var ErrProtectedPath = errors.New("refusing to remove a protected path")
+
+// defaultProtectedTrees are paths whose contents wiper never removes, whatever a target or
+// glob matched: user data that can't be recreated, and the operating system itself.
+var defaultProtectedTrees = []string{
+	"~/Library/Keychains",
+	"~/Library/Mail",
+	"~/Pictures/*.photoslibrary",
+	"~/.ssh",
+	"~/.gnupg",
+	"/System",
+	"/Library/Frameworks",
+	"/Library/Keychains",
+	"/bin",
+	"/sbin",
+	"/usr/bin",
+	"/usr/sbin",
+	"/usr/lib",
+	"/etc",
+	"/boot",
+}
+
+// defaultProtectedRoots are directories that are never removed themselves, though their contents
+// may be (e.g., a large file in ~/Documents).
+var defaultProtectedRoots = []string{
+	"/",
+	"~",
+	"~/Library",
+	"~/Documents",
+	"~/Desktop",
+	"~/Downloads",
+	"~/Pictures",
+	"~/Movies",
+	"~/Music",
+	"/Applications",
+	"/Library",
+	"/Users",
+	"/home",
+	"/usr",
+	"/opt",
+	"/var",
+	"/private",
+	"/private/var",
+	"/Volumes",
+}
+
+// protectedMu guards extraProtectedTrees, the protected paths added with AddProtectedPaths, and
+// unprotectedTrees, the paths set with SetUnprotectedPaths.
+var (
+	protectedMu         sync.Mutex
+	extraProtectedTrees []string
+	unprotectedTrees    []string
+)
+
+// AddProtectedPaths protects more paths (e.g., from the configuration file), in addition to
+// the built-in ones. Like ignore entries, they may start with `~` and end in glob patterns;
+// nothing at or below a matching path is removed.
+func AddProtectedPaths(paths ...string) {
+	protectedMu.Lock()
+	defer protectedMu.Unlock()
+	extraProtectedTrees = append(extraProtectedTrees, paths...)
+}
+
+// SetUnprotectedPaths lifts the protection of the built-in protected trees from paths at or below
+// these patterns, replacing the previous ones. It is meant for opt-in targets that remove
+// regenerable data inside a protected tree (e.g., the previews of a Photos library, whose originals
+// stay protected). Protected roots and the paths added with AddProtectedPaths stay protected.
+func SetUnprotectedPaths(paths ...string) {
+	protectedMu.Lock()
+	defer protectedMu.Unlock()
+	unprotectedTrees = append([]string(nil), paths...)
+}
+
+// IsProtectedPath reports whether removing path is refused: it is at or below a protected tree,
+// is a protected root, or contains one of them (removing ~/Library would remove the keychains).
+// Symbolic links in the parent directories of path are resolved as well.
+func IsProtectedPath(path string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	protectedMu.Lock()
+	trees := append(append([]string(nil), defaultProtectedTrees...), extraProtectedTrees...)
+	extraTrees := append([]string(nil), extraProtectedTrees...)
+	unprotected := append([]string(nil), unprotectedTrees...)
+	protectedMu.Unlock()
+
+	for _, candidate := range pathForms(filepath.Clean(absPath)) {
+		candidateTrees := trees
+		if matchesAnyTree(unprotected, candidate) {
+			candidateTrees = extraTrees
+		}
+		for _, tree := range candidateTrees {
+			pattern := filepath.Clean(ExpandPath(tree))
+			caseInsensitive := isCaseInsensitive(globRoot(pattern))
+			if matchesGlobOrAncestor(pattern, candidate, caseInsensitive) || isSubPath(globRoot(pattern), candidate, caseInsensitive) {
+				return true
+			}
+		}
+		for _, root := range defaultProtectedRoots {
+			root = filepath.Clean(ExpandPath(root))
+			caseInsensitive := isCaseInsensitive(root)
+			for _, form := range ignoreRootForms(root) {
+				if isSubPath(form, candidate, caseInsensitive) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// matchesAnyTree reports whether path is at or below one of the patterns.
+func matchesAnyTree(patterns []string, path string) bool {
+	for _, tree := range patterns {
+		pattern := filepath.Clean(ExpandPath(tree))
+		if matchesGlobOrAncestor(pattern, path, isCaseInsensitive(globRoot(pattern))) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNotProtected returns ErrProtectedPath if absPath is protected.
+func checkNotProtected(absPath string) error {
+	if IsProtectedPath(absPath) {
+		return ErrProtectedPath
+	}
+	return nil
+}
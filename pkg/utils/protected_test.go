@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setTestHome points `~` at a new temporary directory and returns it.
+func setTestHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	return home
+}
+
+func TestIsProtectedPath(t *testing.T) {
+	home := setTestHome(t)
+	photos := filepath.Join(home, "Pictures", "Photos Library.photoslibrary")
+	if err := os.MkdirAll(filepath.Join(home, ".ssh"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	// A link to ~/.ssh, so the protection of what is below it can't be sidestepped.
+	sshLink := filepath.Join(t.TempDir(), "keys")
+	if err := os.Symlink(filepath.Join(home, ".ssh"), sshLink); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"protected tree", "/etc", true},
+		{"below a protected tree", "/etc/hosts", true},
+		{"below a protected tree in the home", filepath.Join(home, "Library", "Keychains", "login.keychain-db"), true},
+		{"below a protected glob", filepath.Join(photos, "originals", "A", "IMG_0001.heic"), true},
+		{"protected glob itself", photos, true},
+		{"below a linked protected tree", filepath.Join(sshLink, "id_ed25519"), true},
+		{"filesystem root", "/", true},
+		{"home", home, true},
+		{"protected root", filepath.Join(home, "Documents"), true},
+		{"below a protected root", filepath.Join(home, "Documents", "old.iso"), false},
+		{"directory containing a protected path", filepath.Dir(home), true},
+		{"cache directory", filepath.Join(home, "Library", "Caches", "com.example.app"), false},
+		{"sibling of a protected tree", filepath.Join(home, ".sshd"), false},
+		{"sibling of a protected glob", filepath.Join(home, "Pictures", "export", "IMG_0001.heic"), false},
+		{"below a protected root of the system", "/usr/local/share/doc", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsProtectedPath(tt.path); got != tt.want {
+				t.Errorf("IsProtectedPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetUnprotectedPaths(t *testing.T) {
+	home := setTestHome(t)
+	photos := filepath.Join(home, "Pictures", "Photos Library.photoslibrary")
+	SetUnprotectedPaths("~/Pictures/*.photoslibrary/resources/derivatives")
+	AddProtectedPaths("~/Pictures/*.photoslibrary/resources/derivatives/masters")
+	t.Cleanup(func() {
+		SetUnprotectedPaths()
+		protectedMu.Lock()
+		extraProtectedTrees = nil
+		protectedMu.Unlock()
+	})
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"unprotected path", filepath.Join(photos, "resources", "derivatives"), false},
+		{"below an unprotected path", filepath.Join(photos, "resources", "derivatives", "0", "IMG_0001.jpeg"), false},
+		{"rest of the protected tree", filepath.Join(photos, "originals", "0", "IMG_0001.heic"), true},
+		{"parent of an unprotected path", filepath.Join(photos, "resources"), true},
+		{"configured protected path below an unprotected path", filepath.Join(photos, "resources", "derivatives", "masters", "x"), true},
+		{"protected root", filepath.Join(home, "Pictures"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsProtectedPath(tt.path); got != tt.want {
+				t.Errorf("IsProtectedPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	// Setting the paths again replaces the previous ones.
+	SetUnprotectedPaths()
+	if path := filepath.Join(photos, "resources", "derivatives"); !IsProtectedPath(path) {
+		t.Errorf("IsProtectedPath(%q) = false after SetUnprotectedPaths(), want true", path)
+	}
+}
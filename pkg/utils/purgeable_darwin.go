@@ -0,0 +1,43 @@
+//go:build darwin
+
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// importantCapacityScript prints the capacity of the volume containing argv[0] available for
+// "important" usage, which Finder shows as available: the free space plus what macOS would purge
+// (caches, local Time Machine snapshots, iCloud files that can be downloaded again) to make room.
+const importantCapacityScript = `function run(argv) {
+	ObjC.import('Foundation');
+	var key = $.NSURLVolumeAvailableCapacityForImportantUsageKey;
+	var values = $.NSURL.fileURLWithPath(argv[0]).resourceValuesForKeysError($.NSArray.arrayWithObject(key), null);
+	return String(values.objectForKey(key).longLongValue);
+}`
+
+// PurgeableSpace returns the bytes macOS can free on the volume containing path on its own when
+// space runs low. It is the difference between the capacity available for important usage and the
+// free space statfs (and df) reports, which is why Finder shows more space available than df. The
+// former is only available from Foundation, so it is asked through osascript.
+func PurgeableSpace(path string) (int64, error) {
+	out, err := exec.Command("osascript", "-l", "JavaScript", "-e", importantCapacityScript, path).Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the available capacity of %s: %w", path, err)
+	}
+	available, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the available capacity of %s: %w", path, err)
+	}
+	free, _, err := VolumeSpace(path)
+	if err != nil {
+		return 0, err
+	}
+	if available < free {
+		return 0, nil
+	}
+	return available - free, nil
+}
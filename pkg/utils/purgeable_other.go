@@ -0,0 +1,10 @@
+//go:build !darwin
+
+package utils
+
+import "fmt"
+
+// PurgeableSpace is specific to APFS on macOS.
+func PurgeableSpace(path string) (int64, error) {
+	return 0, fmt.Errorf("purgeable space is not reported on this platform")
+}
@@ -0,0 +1,408 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/kodelint/wiper/pkg/logger"
+)
+
+// ====================================================================================================
+// REMOVAL ERRORS
+// ====================================================================================================
+
+// RemoveErrorKind classifies why a removal failed, so callers can report or retry accordingly.
+type RemoveErrorKind int
+
+const (
+	// RemoveFailed is any error that does not fit one of the more specific kinds.
+	RemoveFailed RemoveErrorKind = iota
+	// RemoveNotFound means the path no longer exists.
+	RemoveNotFound
+	// RemovePermissionDenied means the current user may not delete the path.
+	RemovePermissionDenied
+	// RemoveInUse means the path is busy (e.g., an open executable or a mounted directory).
+	RemoveInUse
+	// RemoveOutsideRoot means the path resolves, through symbolic links, to a location outside the intended root.
+	RemoveOutsideRoot
+	// RemoveCrossesDevice means the path contains a mounted filesystem that must not be deleted.
+	RemoveCrossesDevice
+	// RemoveProtected means the path is, is inside, or contains a protected path (see IsProtectedPath).
+	RemoveProtected
+	// RemoveChanged means the path no longer matches what the scan found (see RemoveScanned).
+	RemoveChanged
+)
+
+// String returns a human-readable name for the kind.
+func (k RemoveErrorKind) String() string {
+	switch k {
+	case RemoveNotFound:
+		return "not found"
+	case RemovePermissionDenied:
+		return "permission denied"
+	case RemoveInUse:
+		return "in use"
+	case RemoveOutsideRoot:
+		return "outside of cleanup root"
+	case RemoveCrossesDevice:
+		return "crosses into another filesystem"
+	case RemoveProtected:
+		return "protected path"
+	case RemoveChanged:
+		return "changed since scan"
+	default:
+		return "failed"
+	}
+}
+
+// RemoveError is returned by RemovePath and RemovePathWithin.
+// It unwraps to the underlying error, so checks such as errors.Is(err, fs.ErrPermission) keep working.
+type RemoveError struct {
+	Path string
+	Kind RemoveErrorKind
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *RemoveError) Error() string {
+	return fmt.Sprintf("failed to remove %s (%s): %v", e.Path, e.Kind, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RemoveError) Unwrap() error {
+	return e.Err
+}
+
+// errOutsideRoot and errCrossesDevice are the underlying errors of the boundary checks, and
+// errStillExists that of a removal that reported success while the path is still there.
+var (
+	errOutsideRoot   = errors.New("path resolves outside of the cleanup root")
+	errCrossesDevice = errors.New("refusing to descend into a different filesystem")
+	errStillExists   = errors.New("the path still exists after it was removed")
+)
+
+// ErrChangedSinceScan is the underlying error of a removal refused because the path was replaced
+// or modified after it was scanned (see RemoveScanned).
+var ErrChangedSinceScan = errors.New("the path changed since it was scanned")
+
+// newRemoveError wraps err in a RemoveError, deriving its kind from the error.
+func newRemoveError(path string, err error) *RemoveError {
+	kind := RemoveFailed
+	switch {
+	case errors.Is(err, errOutsideRoot):
+		kind = RemoveOutsideRoot
+	case errors.Is(err, errCrossesDevice):
+		kind = RemoveCrossesDevice
+	case errors.Is(err, ErrProtectedPath):
+		kind = RemoveProtected
+	case errors.Is(err, ErrChangedSinceScan):
+		kind = RemoveChanged
+	case errors.Is(err, fs.ErrNotExist):
+		kind = RemoveNotFound
+	case errors.Is(err, fs.ErrPermission):
+		kind = RemovePermissionDenied
+	case errors.Is(err, syscall.EBUSY), errors.Is(err, syscall.ETXTBSY):
+		kind = RemoveInUse
+	}
+	return &RemoveError{Path: path, Kind: kind, Err: err}
+}
+
+// ====================================================================================================
+// SCAN FINGERPRINTS
+// ====================================================================================================
+
+// Fingerprint is what a scan saw of a path, to check before removing it that it is still the
+// same item: not replaced by another file or directory, and, for files, not modified since.
+// Directories are only checked for identity: applications touch their cache directories all the
+// time, and what is in them is measured again when they are removed.
+type Fingerprint struct {
+	// ModTime and Size are the modification time and logical size of files.
+	ModTime time.Time
+	Size    int64
+	// Dir tells whether the entry was a directory.
+	Dir bool
+	// Device and Inode identify the entry, where the platform exposes them.
+	Device, Inode uint64
+}
+
+// FingerprintOf returns the fingerprint of the entry described by info, which must come from
+// `os.Lstat` (or a directory listing) as removals don't follow symbolic links.
+func FingerprintOf(info os.FileInfo) Fingerprint {
+	fingerprint := Fingerprint{ModTime: info.ModTime(), Size: info.Size(), Dir: info.IsDir()}
+	if id, ok := FileInfoIdentity(info); ok {
+		fingerprint.Device, fingerprint.Inode = id.Device, id.Inode
+	}
+	return fingerprint
+}
+
+// FingerprintPath returns the fingerprint of path, or the zero Fingerprint (which matches
+// anything) if it can't be read.
+func FingerprintPath(path string) Fingerprint {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return Fingerprint{}
+	}
+	return FingerprintOf(info)
+}
+
+// VerifyScanned checks that path is still the entry the scan fingerprinted, for removals that don't
+// go through RemoveScanned (e.g., moves and the removers of package managers). It returns a
+// *RemoveError wrapping ErrChangedSinceScan if it isn't, or the error of reading it.
+func VerifyScanned(path string, scanned Fingerprint) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return newRemoveError(path, err)
+	}
+	if !scanned.matches(info) {
+		return newRemoveError(path, ErrChangedSinceScan)
+	}
+	return nil
+}
+
+// matches reports whether the entry described by info is the one fingerprinted. The zero
+// Fingerprint matches anything.
+func (f Fingerprint) matches(info os.FileInfo) bool {
+	if f == (Fingerprint{}) {
+		return true
+	}
+	now := FingerprintOf(info)
+	if now.Dir != f.Dir || (f.Inode != 0 && (now.Device != f.Device || now.Inode != f.Inode)) {
+		return false
+	}
+	return f.Dir || (now.ModTime.Equal(f.ModTime) && now.Size == f.Size)
+}
+
+// ====================================================================================================
+// REMOVAL FUNCTIONS
+// ====================================================================================================
+
+// RemovePath removes a file or directory.
+// It handles symbolic links and includes a dry-run option. See RemovePathWithin for the safety checks;
+// RemovePath uses the path itself as the root.
+//
+// Parameters:
+//   - path: The path of the file or directory to remove.
+//   - dryRun: If true, the function will only log what it would do, without making changes.
+//
+// Returns:
+//   - The size of the removed item in bytes and an error, if any.
+func RemovePath(path string, dryRun bool) (int64, error) {
+	return RemovePathWithin(path, path, dryRun)
+}
+
+// RemovePathWithin removes a file or directory that is expected to live below root.
+//
+// When trash mode is on (see SetTrashMode), the checked item is moved to the Trash instead.
+//
+// The removal is guarded in several ways:
+//   - The path is inspected with `os.Lstat`, so a symbolic link is removed itself and never followed.
+//   - Symbolic links in the parent directories are resolved, and the removal is refused if the real
+//     location is outside of root (e.g., a cache directory that was replaced by a link to /System).
+//   - Directory trees are walked without following links, and any entry on a different device than
+//     the path itself (a mounted filesystem) stops the removal instead of being emptied.
+//   - With SetSecureErase, the contents of files are overwritten before they are unlinked.
+//   - Protected paths (see IsProtectedPath), such as ~/Library/Keychains or /System, are refused
+//     whatever a target or glob matched.
+//
+// After the removal, the path is checked again, and the size is only returned once it is gone.
+//
+// Failures are returned as a *RemoveError that distinguishes permission, in-use, not-found,
+// and boundary violations.
+//
+// Parameters:
+//   - path: The path of the file or directory to remove.
+//   - root: The directory the path must not escape from (e.g., ~/Library/Caches).
+//   - dryRun: If true, the function will only log what it would do, without making changes.
+//
+// Returns:
+//   - The size of the removed item in bytes, without the bytes it shared with clones (see Usage),
+//     and an error, if any.
+func RemovePathWithin(path string, root string, dryRun bool) (int64, error) {
+	return removeVerified(path, root, Fingerprint{}, dryRun)
+}
+
+// RemoveScanned is RemovePathWithin for an item found by a scan: it is only removed if it still
+// matches the fingerprint the scan took, and a *RemoveError wrapping ErrChangedSinceScan is
+// returned otherwise (e.g., a cache file rewritten since, or a directory replaced by another).
+func RemoveScanned(path string, root string, scanned Fingerprint) (int64, error) {
+	return removeVerified(path, root, scanned, false)
+}
+
+// removeVerified implements RemovePathWithin and RemoveScanned.
+func removeVerified(path string, root string, scanned Fingerprint, dryRun bool) (int64, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return 0, newRemoveError(path, err)
+	}
+	info, err := os.Lstat(absPath)
+	if err != nil {
+		return 0, newRemoveError(absPath, err)
+	}
+	if err := checkWithinRoot(absPath, root); err != nil {
+		return 0, newRemoveError(absPath, err)
+	}
+	if err := checkNotProtected(absPath); err != nil {
+		return 0, newRemoveError(absPath, err)
+	}
+	if !scanned.matches(info) {
+		return 0, newRemoveError(absPath, ErrChangedSinceScan)
+	}
+
+	// Blocks shared with clones stay allocated for the other copies, so they aren't reclaimed.
+	usage, err := GetFileUsage(absPath)
+	if err != nil {
+		return 0, newRemoveError(absPath, fmt.Errorf("could not get size before removal: %w", err))
+	}
+	size := usage.Private()
+
+	if dryRun {
+		logger.Log.Debugf(Yellow("DRY RUN: Would remove granular item: %s (Size: %s)"), absPath, FormatBytes(size))
+		return size, nil
+	}
+
+	if err := removeEntry(absPath, info, size); err != nil {
+		return 0, err
+	}
+	// Only a path that is really gone counts as reclaimed.
+	if _, err := os.Lstat(absPath); !errors.Is(err, fs.ErrNotExist) {
+		if err == nil {
+			err = errStillExists
+		}
+		return 0, newRemoveError(absPath, err)
+	}
+	return size, nil
+}
+
+// removeEntry removes the checked entry at absPath described by info, of size bytes: moves it to
+// the Trash in trash mode, and deletes it otherwise.
+func removeEntry(absPath string, info os.FileInfo, size int64) error {
+	// In trash mode, items are moved to the Trash so they can be recovered.
+	if trashMode {
+		inTrash, err := moveToTrash(absPath, info)
+		if err != nil {
+			return newRemoveError(absPath, err)
+		}
+		if !inTrash {
+			return nil
+		}
+	}
+
+	logger.Log.Debugf(Red("Removing granular item: %s (Size: %s)"), absPath, FormatBytes(size))
+	logger.Log.With("path", absPath, "size", size).Infof("Removing granular item: %s (Size: %s)", absPath, FormatBytes(size))
+	if !info.IsDir() {
+		// Regular files and symbolic links (including links to directories) are removed directly.
+		if err := eraseFile(absPath, info); err != nil {
+			return newRemoveError(absPath, err)
+		}
+		if err := os.Remove(absPath); err != nil {
+			return newRemoveError(absPath, err)
+		}
+		return nil
+	}
+
+	dev, hasDev := deviceID(info)
+	return removeTree(absPath, dev, hasDev)
+}
+
+// CheckWithinRoot applies the boundary and protected path checks of RemovePathWithin without removing anything, for
+// callers that dispose of items in their own way (e.g., by moving them into a quarantine).
+//
+// Returns:
+//   - The absolute path of the item, or a *RemoveError if it doesn't exist or escapes root.
+func CheckWithinRoot(path string, root string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", newRemoveError(path, err)
+	}
+	if _, err := os.Lstat(absPath); err != nil {
+		return "", newRemoveError(absPath, err)
+	}
+	if err := checkWithinRoot(absPath, root); err != nil {
+		return "", newRemoveError(absPath, err)
+	}
+	if err := checkNotProtected(absPath); err != nil {
+		return "", newRemoveError(absPath, err)
+	}
+	return absPath, nil
+}
+
+// checkWithinRoot verifies that the real location of path, after resolving symbolic links in its
+// parent directories, is root or lies below it, and isn't protected. The root is resolved as well,
+// so system-level links such as /tmp -> /private/tmp do not cause false positives.
+func checkWithinRoot(absPath string, root string) error {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	// The parent chain is resolved afresh (IsProtectedPath caches resolved directories), even when the
+	// path is the root itself, so a parent replaced by a link since the scan can't lead the removal
+	// somewhere protected.
+	realParent, err := filepath.EvalSymlinks(filepath.Dir(absPath))
+	if err != nil {
+		return err
+	}
+	realPath := filepath.Join(realParent, filepath.Base(absPath))
+	if err := checkNotProtected(realPath); err != nil {
+		return fmt.Errorf("%w: %s", err, realPath)
+	}
+	if absPath == absRoot {
+		// The path is the root itself; its contents are protected by removeTree.
+		return nil
+	}
+
+	realRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return err
+	}
+	if !isSubPath(realPath, realRoot, isCaseInsensitive(realRoot)) {
+		return fmt.Errorf("%w: %s is outside %s", errOutsideRoot, realPath, realRoot)
+	}
+	return nil
+}
+
+// removeTree deletes a directory tree depth-first without following symbolic links.
+// When the device ID is known, entries on another device are refused so mounted
+// volumes below the tree are never emptied.
+func removeTree(dir string, dev uint64, hasDev bool) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return newRemoveError(dir, err)
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Lstat(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // Already gone, e.g. removed by the application that owned it.
+			}
+			return newRemoveError(path, err)
+		}
+		if !info.IsDir() {
+			if err := eraseFile(path, info); err != nil {
+				return newRemoveError(path, err)
+			}
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return newRemoveError(path, err)
+			}
+			continue
+		}
+		if hasDev {
+			if entryDev, ok := deviceID(info); ok && entryDev != dev {
+				return newRemoveError(path, errCrossesDevice)
+			}
+		}
+		if err := removeTree(path, dev, hasDev); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return newRemoveError(dir, err)
+	}
+	return nil
+}
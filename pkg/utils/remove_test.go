@@ -0,0 +1,293 @@
+package utils
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestFile creates the file at path, and its parent directories, with content.
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// removeErrorKind returns the kind of the *RemoveError err wraps, and false if it wraps none.
+func removeErrorKind(err error) (RemoveErrorKind, bool) {
+	var removeErr *RemoveError
+	if !errors.As(err, &removeErr) {
+		return 0, false
+	}
+	return removeErr.Kind, true
+}
+
+func TestRemoveScanned(t *testing.T) {
+	tests := []struct {
+		name string
+		// change modifies the scanned item at path before it is removed.
+		change  func(t *testing.T, path string)
+		dir     bool
+		wantErr bool
+		want    RemoveErrorKind
+	}{
+		{name: "unchanged file"},
+		{name: "unchanged directory", dir: true},
+		{name: "file rewritten", change: func(t *testing.T, path string) {
+			writeTestFile(t, path, "a longer content")
+		}, wantErr: true, want: RemoveChanged},
+		{name: "file replaced by a directory", change: func(t *testing.T, path string) {
+			if err := os.Remove(path); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Mkdir(path, 0o755); err != nil {
+				t.Fatal(err)
+			}
+		}, wantErr: true, want: RemoveChanged},
+		{name: "directory with new entries", dir: true, change: func(t *testing.T, path string) {
+			writeTestFile(t, filepath.Join(path, "new"), "new")
+		}},
+		{name: "file gone", change: func(t *testing.T, path string) {
+			if err := os.Remove(path); err != nil {
+				t.Fatal(err)
+			}
+		}, wantErr: true, want: RemoveNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			path := filepath.Join(root, "item")
+			if tt.dir {
+				writeTestFile(t, filepath.Join(path, "file"), "content")
+			} else {
+				writeTestFile(t, path, "content")
+			}
+			scanned := FingerprintPath(path)
+			if tt.change != nil {
+				tt.change(t, path)
+			}
+
+			_, err := RemoveScanned(path, root, scanned)
+			_, statErr := os.Lstat(path)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("RemoveScanned() error = %v", err)
+				}
+				if !errors.Is(statErr, fs.ErrNotExist) {
+					t.Errorf("RemoveScanned() left %s behind", path)
+				}
+				return
+			}
+			if kind, ok := removeErrorKind(err); !ok || kind != tt.want {
+				t.Fatalf("RemoveScanned() error = %v, want kind %q", err, tt.want)
+			}
+			if tt.want != RemoveNotFound && statErr != nil {
+				t.Errorf("RemoveScanned() removed %s although it changed: %v", path, statErr)
+			}
+		})
+	}
+}
+
+func TestVerifyScanned(t *testing.T) {
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name    string
+		change  func(t *testing.T, path string)
+		scanned func(path string) Fingerprint
+		// needsIdentity marks changes only the device and inode of the file reveal.
+		needsIdentity bool
+		want          error
+	}{
+		{name: "unchanged", want: nil},
+		{name: "zero fingerprint", scanned: func(string) Fingerprint { return Fingerprint{} }, change: func(t *testing.T, path string) {
+			writeTestFile(t, path, "other content")
+		}, want: nil},
+		{name: "modified", change: func(t *testing.T, path string) {
+			if err := os.Chtimes(path, modTime.Add(time.Hour), modTime.Add(time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+		}, want: ErrChangedSinceScan},
+		{name: "replaced with the same size and time", change: func(t *testing.T, path string) {
+			// The scanned file stays linked elsewhere, so the replacement can't reuse its inode.
+			if err := os.Rename(path, path+".old"); err != nil {
+				t.Fatal(err)
+			}
+			writeTestFile(t, path, "content")
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+		}, needsIdentity: true, want: ErrChangedSinceScan},
+		{name: "gone", change: func(t *testing.T, path string) {
+			if err := os.Remove(path); err != nil {
+				t.Fatal(err)
+			}
+		}, want: fs.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "item")
+			writeTestFile(t, path, "content")
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+			scanned := FingerprintPath(path)
+			if tt.scanned != nil {
+				scanned = tt.scanned(path)
+			}
+			if tt.needsIdentity && scanned.Inode == 0 {
+				t.Skip("file identities are not available on this platform")
+			}
+			if tt.change != nil {
+				tt.change(t, path)
+			}
+
+			err := VerifyScanned(path, scanned)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("VerifyScanned() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifyScanned() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckWithinRoot(t *testing.T) {
+	tests := []struct {
+		name string
+		// path and root are relative to a temporary directory holding root, root-other and outside.
+		path, root string
+		wantErr    error
+	}{
+		{"file inside the root", "root/file", "root", nil},
+		{"nested path inside the root", "root/a/b/c", "root", nil},
+		{"file outside the root", "outside/file", "root", errOutsideRoot},
+		{"sibling with the root as prefix", "root-other/file", "root", errOutsideRoot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFile(t, filepath.Join(dir, "root", "file"), "content")
+			writeTestFile(t, filepath.Join(dir, "root", "a", "b", "c"), "content")
+			writeTestFile(t, filepath.Join(dir, "root-other", "file"), "content")
+			writeTestFile(t, filepath.Join(dir, "outside", "file"), "content")
+
+			err := checkWithinRoot(filepath.Join(dir, tt.path), filepath.Join(dir, tt.root))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("checkWithinRoot() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkWithinRoot() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckWithinRootResolvesParentLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		// path and root are relative to a temporary directory holding root/real/file and outside/file,
+		// with the links root/inner -> root/real, root/escape -> outside, rootlink -> root and
+		// etclink -> /etc.
+		path, root string
+		wantErr    error
+	}{
+		{"link to a directory inside the root", "root/inner/file", "root", nil},
+		{"link out of the root", "root/escape/file", "root", errOutsideRoot},
+		{"root given through a link", "rootlink/real/file", "rootlink", nil},
+		{"path is the root", "root", "root", nil},
+		{"path is the root below a link to a protected tree", "etclink/wiper", "etclink/wiper", ErrProtectedPath},
+		{"path is the root and itself a link", "root/escape", "root/escape", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFile(t, filepath.Join(dir, "root", "real", "file"), "content")
+			writeTestFile(t, filepath.Join(dir, "outside", "file"), "content")
+			for link, target := range map[string]string{
+				"root/inner":  filepath.Join(dir, "root", "real"),
+				"root/escape": filepath.Join(dir, "outside"),
+				"rootlink":    filepath.Join(dir, "root"),
+				"etclink":     "/etc",
+			} {
+				if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+					t.Skipf("symbolic links are not available: %v", err)
+				}
+			}
+
+			err := checkWithinRoot(filepath.Join(dir, tt.path), filepath.Join(dir, tt.root))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("checkWithinRoot() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkWithinRoot() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoveTree(t *testing.T) {
+	dir := t.TempDir()
+	tree := filepath.Join(dir, "tree")
+	outside := filepath.Join(dir, "outside", "file")
+	writeTestFile(t, filepath.Join(tree, "a", "b", "file"), "content")
+	writeTestFile(t, filepath.Join(tree, "file"), "content")
+	writeTestFile(t, outside, "content")
+	if err := os.Symlink(filepath.Dir(outside), filepath.Join(tree, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Lstat(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev, hasDev := deviceID(info)
+	if err := removeTree(tree, dev, hasDev); err != nil {
+		t.Fatalf("removeTree() error = %v", err)
+	}
+	if _, err := os.Lstat(tree); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("removeTree() left %s behind: %v", tree, err)
+	}
+	// The link is removed itself, never what it points to.
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("removeTree() followed a symbolic link: %v", err)
+	}
+}
+
+func TestRemoveTreeRefusesAnotherDevice(t *testing.T) {
+	tree := filepath.Join(t.TempDir(), "tree")
+	writeTestFile(t, filepath.Join(tree, "mounted", "file"), "content")
+	info, err := os.Lstat(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev, hasDev := deviceID(info)
+	if !hasDev {
+		t.Skip("device IDs are not available on this platform")
+	}
+
+	// A different device ID makes every subdirectory look like a mounted filesystem.
+	err = removeTree(tree, dev+1, true)
+	if kind, ok := removeErrorKind(err); !ok || kind != RemoveCrossesDevice {
+		t.Fatalf("removeTree() error = %v, want kind %q", err, RemoveCrossesDevice)
+	}
+	if _, err := os.Stat(filepath.Join(tree, "mounted", "file")); err != nil {
+		t.Errorf("removeTree() emptied the other device: %v", err)
+	}
+}
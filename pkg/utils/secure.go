@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ====================================================================================================
+// SECURE ERASE
+// ====================================================================================================
+
+// secureErasePasses is the number of times RemovePath and RemovePathWithin overwrite the contents
+// of a file before unlinking it. 0 unlinks files without overwriting them.
+var secureErasePasses int
+
+// secureEraseChunk is the size of the buffer file contents are overwritten with.
+const secureEraseChunk = 1 << 20
+
+// errOtherLinks is returned for files with several hard links, whose contents stay reachable (and
+// would be destroyed for the other names) when one name is erased.
+var errOtherLinks = errors.New("the file has other hard links, which would be erased as well")
+
+// SetSecureErase makes removals overwrite the contents of every regular file passes times before
+// unlinking it: with zeros and ones in turn, and with random data in the last pass. 0 turns it off.
+//
+// Overwriting only reaches the blocks the file occupies now. On SSDs and copy-on-write filesystems
+// such as APFS, earlier copies of the data may survive in blocks that were remapped or shared with
+// snapshots and clones; FileVault is the dependable protection there.
+func SetSecureErase(passes int) {
+	secureErasePasses = passes
+}
+
+// SecureErase returns the number of passes removals overwrite files with, 0 if they don't.
+func SecureErase() int {
+	return secureErasePasses
+}
+
+// overwriteFile overwrites the contents of the regular file at path passes times, syncing each pass
+// to disk, so the data is gone before the file is unlinked.
+func overwriteFile(path string, info os.FileInfo, passes int) error {
+	if _, links, ok := fileID(info); ok && links > 1 {
+		return errOtherLinks
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if errors.Is(err, os.ErrPermission) {
+		// Read-only files can still be unlinked, so they are made writable to be overwritten first.
+		if chmodErr := os.Chmod(path, info.Mode().Perm()|0o200); chmodErr == nil {
+			file, err = os.OpenFile(path, os.O_WRONLY, 0)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	buf := make([]byte, secureEraseChunk)
+	for pass := 1; pass <= passes; pass++ {
+		// Every pass but the last writes a fixed pattern.
+		random := pass == passes
+		if !random {
+			fill := byte(0x00)
+			if pass%2 == 0 {
+				fill = 0xFF
+			}
+			for i := range buf {
+				buf[i] = fill
+			}
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		for remaining := info.Size(); remaining > 0; {
+			chunk := buf[:min(remaining, secureEraseChunk)]
+			if random {
+				if _, err := rand.Read(chunk); err != nil {
+					return err
+				}
+			}
+			if _, err := file.Write(chunk); err != nil {
+				return fmt.Errorf("pass %d of %d: %w", pass, passes, err)
+			}
+			remaining -= int64(len(chunk))
+		}
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("pass %d of %d: %w", pass, passes, err)
+		}
+	}
+	return nil
+}
+
+// eraseFile overwrites the file described by info if secure erase is on. Symbolic links and other
+// non-regular files have no contents of their own and are left to be unlinked.
+func eraseFile(path string, info os.FileInfo) error {
+	if secureErasePasses == 0 || !info.Mode().IsRegular() {
+		return nil
+	}
+	if err := overwriteFile(path, info, secureErasePasses); err != nil {
+		return fmt.Errorf("secure erase failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ====================================================================================================
+// SEMANTIC VERSION FUNCTIONS
+// ====================================================================================================
+
+// Version is a parsed semantic version (https://semver.org), such as 1.4.0 or 0.1.0-alpha.2.
+type Version struct {
+	Major, Minor, Patch int
+	// PreRelease holds the dot-separated pre-release identifiers (e.g., ["alpha", "2"]).
+	PreRelease []string
+}
+
+// ParseVersion parses a semantic version string.
+// A leading "v" is accepted, missing minor and patch numbers default to 0 (e.g., "v1.2"),
+// and build metadata after a "+" is ignored, as the specification requires.
+func ParseVersion(s string) (Version, error) {
+	raw := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+
+	var v Version
+	core := s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		core = s[:i]
+		pre := s[i+1:]
+		if pre == "" {
+			return Version{}, fmt.Errorf("invalid version %q: empty pre-release", raw)
+		}
+		v.PreRelease = strings.Split(pre, ".")
+		for _, id := range v.PreRelease {
+			if id == "" {
+				return Version{}, fmt.Errorf("invalid version %q: empty pre-release identifier", raw)
+			}
+		}
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q: too many components", raw)
+	}
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q: %q is not a number", raw, part)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0, or 1 depending on whether v is lower than, equal to, or higher than other.
+// A pre-release sorts before the corresponding release (0.1.0-alpha < 0.1.0).
+func (v Version) Compare(other Version) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case len(v.PreRelease) == 0 && len(other.PreRelease) == 0:
+		return 0
+	case len(v.PreRelease) == 0:
+		return 1
+	case len(other.PreRelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.PreRelease) && i < len(other.PreRelease); i++ {
+		if c := comparePreRelease(v.PreRelease[i], other.PreRelease[i]); c != 0 {
+			return c
+		}
+	}
+	// A larger set of pre-release fields has a higher precedence when all preceding ones are equal.
+	switch {
+	case len(v.PreRelease) < len(other.PreRelease):
+		return -1
+	case len(v.PreRelease) > len(other.PreRelease):
+		return 1
+	}
+	return 0
+}
+
+// CompareVersions parses and compares two version strings; see Version.Compare.
+func CompareVersions(a, b string) (int, error) {
+	va, err := ParseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := ParseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+// comparePreRelease compares two pre-release identifiers. Numeric identifiers compare
+// numerically and always sort before alphanumeric ones, which compare lexically.
+func comparePreRelease(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		if na == nb {
+			return 0
+		}
+		if na < nb {
+			return -1
+		}
+		return 1
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
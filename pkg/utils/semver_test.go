@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Version
+		wantErr bool
+	}{
+		{input: "1.4.0", want: Version{Major: 1, Minor: 4}},
+		{input: "v1.4.2", want: Version{Major: 1, Minor: 4, Patch: 2}},
+		{input: " v0.10.0\n", want: Version{Minor: 10}},
+		{input: "v1.2", want: Version{Major: 1, Minor: 2}},
+		{input: "2", want: Version{Major: 2}},
+		{input: "0.1.0-alpha.2", want: Version{Minor: 1, PreRelease: []string{"alpha", "2"}}},
+		{input: "1.0.0+build.5", want: Version{Major: 1}},
+		{input: "v1.0.0-rc.1+20240501", want: Version{Major: 1, PreRelease: []string{"rc", "1"}}},
+		{input: "1.0.0-x-y", want: Version{Major: 1, PreRelease: []string{"x-y"}}},
+		{input: "", wantErr: true},
+		{input: "v", wantErr: true},
+		{input: "1.2.3.4", wantErr: true},
+		{input: "1.x.0", wantErr: true},
+		{input: "1.2.-3", wantErr: true},
+		{input: "1.0.0-", wantErr: true},
+		{input: "1.0.0-alpha..1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVersion(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseVersion(%q) = %+v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseVersion(%q) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseVersion(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.0.0", "1.0.0", 0},
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+		{"1.2", "1.2.0", 0},
+		{"0.9.0", "0.10.0", -1},
+		{"1.0.10", "1.0.9", 1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-rc.1+build.7", "1.0.0", -1},
+		{"0.9.0", "1.0.0-alpha", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got, err := CompareVersions(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("CompareVersions(%q, %q) error = %v", tt.a, tt.b, err)
+			}
+			if got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareVersionsPreReleasePrecedence(t *testing.T) {
+	// The precedence example of the specification, each lower than the next.
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			got, err := CompareVersions(ordered[i], ordered[j])
+			if err != nil {
+				t.Fatalf("CompareVersions(%q, %q) error = %v", ordered[i], ordered[j], err)
+			}
+			if got != want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+}
+
+func TestCompareVersionsRejectsInvalidVersions(t *testing.T) {
+	if _, err := CompareVersions("1.0.0", "latest"); err == nil {
+		t.Error("CompareVersions(\"1.0.0\", \"latest\") error = nil, want an error")
+	}
+	if _, err := CompareVersions("nightly", "1.0.0"); err == nil {
+		t.Error("CompareVersions(\"nightly\", \"1.0.0\") error = nil, want an error")
+	}
+}
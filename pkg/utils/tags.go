@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// ====================================================================================================
+// FILE TAGS
+// ====================================================================================================
+
+const (
+	// finderTagsXattr holds Finder tags on macOS as a binary property list of strings.
+	finderTagsXattr = "com.apple.metadata:_kMDItemUserTags"
+	// xdgTagsXattr holds tags on Linux desktops as a comma-separated list (freedesktop.org convention).
+	xdgTagsXattr = "user.xdg.tags"
+)
+
+// FileTags returns the user tags set on path: Finder tags on macOS, or the `user.xdg.tags`
+// attribute used by Linux file managers. Finder stores a tag's color after its name
+// ("Keep\n6"); only the name is returned. Entries without tags return an empty slice.
+func FileTags(path string) ([]string, error) {
+	data, err := readXattr(path, finderTagsXattr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags of %s: %w", path, err)
+	}
+	if len(data) > 0 {
+		values, err := parseBinaryPlistStrings(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Finder tags of %s: %w", path, err)
+		}
+		tags := make([]string, 0, len(values))
+		for _, value := range values {
+			name, _, _ := strings.Cut(value, "\n")
+			tags = append(tags, name)
+		}
+		return tags, nil
+	}
+
+	data, err = readXattr(path, xdgTagsXattr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags of %s: %w", path, err)
+	}
+	var tags []string
+	for _, tag := range strings.Split(string(data), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// HasTag reports whether path carries the given tag, compared case-insensitively.
+// Unreadable tags count as not tagged.
+func HasTag(path, tag string) bool {
+	tags, err := FileTags(path)
+	if err != nil {
+		return false
+	}
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBinaryPlistStrings decodes a binary property list ("bplist00") whose top object is an
+// array of strings, which is how Finder stores tags. Other property lists are rejected.
+func parseBinaryPlistStrings(data []byte) ([]string, error) {
+	const trailerSize = 32
+	if len(data) < 8+trailerSize || string(data[:8]) != "bplist00" {
+		return nil, fmt.Errorf("not a binary property list")
+	}
+	trailer := data[len(data)-trailerSize:]
+	offsetSize := int(trailer[6])
+	refSize := int(trailer[7])
+	numObjects := binary.BigEndian.Uint64(trailer[8:16])
+	topObject := binary.BigEndian.Uint64(trailer[16:24])
+	offsetTable := binary.BigEndian.Uint64(trailer[24:32])
+	// Each term is checked on its own, so a crafted trailer can't overflow the offset table's end.
+	limit := uint64(len(data) - trailerSize)
+	if offsetSize == 0 || offsetSize > 8 || refSize == 0 || refSize > 8 || topObject >= numObjects ||
+		offsetTable > limit || numObjects > (limit-offsetTable)/uint64(offsetSize) {
+		return nil, fmt.Errorf("corrupt property list trailer")
+	}
+
+	// objectOffset returns where object ref starts in data.
+	objectOffset := func(ref uint64) (int, error) {
+		if ref >= numObjects {
+			return 0, fmt.Errorf("object reference %d out of range", ref)
+		}
+		start := offsetTable + ref*uint64(offsetSize)
+		offset := readBigEndian(data[start : start+uint64(offsetSize)])
+		if offset >= uint64(len(data)-trailerSize) {
+			return 0, fmt.Errorf("object offset %d out of range", offset)
+		}
+		return int(offset), nil
+	}
+
+	offset, err := objectOffset(topObject)
+	if err != nil {
+		return nil, err
+	}
+	marker := data[offset]
+	if marker>>4 != 0xA {
+		return nil, fmt.Errorf("top object is not an array")
+	}
+	count, pos, err := plistObjectLength(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	if pos+count*refSize > len(data) {
+		return nil, fmt.Errorf("array out of range")
+	}
+
+	values := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		ref := readBigEndian(data[pos+i*refSize : pos+(i+1)*refSize])
+		objOffset, err := objectOffset(ref)
+		if err != nil {
+			return nil, err
+		}
+		length, start, err := plistObjectLength(data, objOffset)
+		if err != nil {
+			return nil, err
+		}
+		switch data[objOffset] >> 4 {
+		case 0x5: // ASCII string
+			if start+length > len(data) {
+				return nil, fmt.Errorf("string out of range")
+			}
+			values = append(values, string(data[start:start+length]))
+		case 0x6: // UTF-16BE string, length in code units
+			if start+2*length > len(data) {
+				return nil, fmt.Errorf("string out of range")
+			}
+			units := make([]uint16, length)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(data[start+2*j:])
+			}
+			values = append(values, string(utf16.Decode(units)))
+		default:
+			return nil, fmt.Errorf("array element %d is not a string", i)
+		}
+	}
+	return values, nil
+}
+
+// plistObjectLength returns the length stored in the marker of the object at offset and the
+// position of its contents. Lengths of 15 or more follow the marker as an integer object.
+func plistObjectLength(data []byte, offset int) (int, int, error) {
+	length := int(data[offset] & 0x0F)
+	pos := offset + 1
+	if length != 0x0F {
+		return length, pos, nil
+	}
+	if pos >= len(data) || data[pos]>>4 != 0x1 {
+		return 0, 0, fmt.Errorf("invalid object length")
+	}
+	size := 1 << (data[pos] & 0x0F)
+	if size > 8 || pos+1+size > len(data) {
+		return 0, 0, fmt.Errorf("invalid object length")
+	}
+	n := readBigEndian(data[pos+1 : pos+1+size])
+	if n > uint64(len(data)) {
+		return 0, 0, fmt.Errorf("object length %d out of range", n)
+	}
+	return int(n), pos + 1 + size, nil
+}
+
+// readBigEndian decodes an unsigned big-endian integer of up to 8 bytes.
+func readBigEndian(b []byte) uint64 {
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"os"
+
+	"github.com/fatih/color"
+)
+
+// ====================================================================================================
+// TERMINAL DETECTION
+// ====================================================================================================
+
+// colorEnabled reports whether ANSI colors are written. It is detected once at startup and
+// shared by the color functions, the logger, and the table renderer.
+var colorEnabled = detectColor()
+
+// init applies the detected color mode to the `fatih/color` functions used throughout wiper.
+func init() {
+	color.NoColor = !colorEnabled
+}
+
+// IsTerminal reports whether f is connected to a terminal rather than a pipe or a file.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorEnabled reports whether colored output is enabled.
+func ColorEnabled() bool {
+	return colorEnabled
+}
+
+// SetColorEnabled forces colored output on or off, overriding the automatic detection.
+func SetColorEnabled(enabled bool) {
+	colorEnabled = enabled
+	color.NoColor = !enabled
+}
+
+// detectColor enables colors only when both standard output and standard error are terminals,
+// so redirecting either stream (e.g., `wiper wipe 2>&1 | tee wipe.log`) yields plain text.
+// The NO_COLOR convention (https://no-color.org) and TERM=dumb always disable colors.
+func detectColor() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(os.Stdout) && IsTerminal(os.Stderr)
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kodelint/wiper/pkg/logger"
+)
+
+// ====================================================================================================
+// TRASH MODE
+// ====================================================================================================
+
+// trashMode makes RemovePath and RemovePathWithin move items to the Trash instead of deleting them.
+var trashMode bool
+
+// SetTrashMode controls whether removals move items to the Trash (recoverable) instead of
+// deleting them permanently. Items that are already in a Trash are still deleted, so emptying
+// the Trash keeps working.
+func SetTrashMode(enabled bool) {
+	trashMode = enabled
+}
+
+// TrashMode reports whether removals move items to the Trash.
+func TrashMode() bool {
+	return trashMode
+}
+
+// VolumeTrashDir returns the current user's Trash on the volume mounted at mount, where items
+// deleted from that volume are kept (e.g., "/Volumes/External/.Trashes/501" on macOS).
+func VolumeTrashDir(mount string) string {
+	return volumeTrashDir(mount)
+}
+
+// moveToTrash moves path into the Trash of the volume it lives on: the user's Trash for the home
+// volume, and the per-volume Trash otherwise, so trashing never copies data between volumes.
+// It returns true, without moving anything, when path is already inside that Trash and must be
+// deleted instead.
+func moveToTrash(path string, info os.FileInfo) (bool, error) {
+	trashDir, err := trashDirFor(path, info)
+	if err != nil {
+		return false, err
+	}
+	if root := trashRoot(trashDir); isSubPath(path, root, isCaseInsensitive(root)) {
+		return true, nil
+	}
+
+	moved, err := MovePath(path, trashDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to move to the Trash: %w", err)
+	}
+	if err := writeTrashInfo(path, moved.Dest); err != nil {
+		logger.Log.Warnf("Moved %s to the Trash, but failed to record its original location: %v", path, err)
+	}
+	return false, nil
+}
+
+// trashDirFor returns the Trash directory for path, choosing the home Trash when path is on the
+// same volume as the home directory and the volume's own Trash otherwise.
+func trashDirFor(path string, info os.FileInfo) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine the home directory: %w", err)
+	}
+	dev, hasDev := deviceID(info)
+	if homeInfo, err := os.Stat(homeDir); err == nil && hasDev {
+		if homeDev, ok := deviceID(homeInfo); ok && homeDev != dev {
+			mount, err := mountPointOf(filepath.Dir(path), dev)
+			if err != nil {
+				return "", err
+			}
+			return volumeTrashDir(mount), nil
+		}
+	}
+	return homeTrashDir(homeDir), nil
+}
+
+// mountPointOf returns the root of the filesystem with device dev that contains dir.
+func mountPointOf(dir string, dev uint64) (string, error) {
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		parent := filepath.Dir(current)
+		if parent == current {
+			return current, nil
+		}
+		info, err := os.Stat(parent)
+		if err != nil {
+			return "", fmt.Errorf("failed to find the mount point of %s: %w", dir, err)
+		}
+		if parentDev, ok := deviceID(info); !ok || parentDev != dev {
+			return current, nil
+		}
+		current = parent
+	}
+}
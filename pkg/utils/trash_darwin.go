@@ -0,0 +1,29 @@
+//go:build darwin
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// homeTrashDir returns the user's Trash on the startup volume.
+func homeTrashDir(homeDir string) string {
+	return filepath.Join(homeDir, ".Trash")
+}
+
+// volumeTrashDir returns the current user's Trash on another volume, as used by Finder.
+func volumeTrashDir(mount string) string {
+	return filepath.Join(mount, ".Trashes", strconv.Itoa(os.Getuid()))
+}
+
+// trashRoot returns the directory that holds everything belonging to trashDir.
+func trashRoot(trashDir string) string {
+	return trashDir
+}
+
+// writeTrashInfo is not needed on macOS, where the Trash keeps no separate index.
+func writeTrashInfo(original, trashed string) error {
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build linux
+
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// homeTrashDir returns the "files" directory of the user's Trash, following the freedesktop.org
+// Trash specification ($XDG_DATA_HOME/Trash).
+func homeTrashDir(homeDir string) string {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		dataDir = filepath.Join(homeDir, ".local", "share")
+	}
+	return filepath.Join(dataDir, "Trash", "files")
+}
+
+// volumeTrashDir returns the "files" directory of the current user's Trash on another volume.
+func volumeTrashDir(mount string) string {
+	return filepath.Join(mount, ".Trash-"+strconv.Itoa(os.Getuid()), "files")
+}
+
+// trashRoot returns the Trash directory that contains both "files" and "info".
+func trashRoot(trashDir string) string {
+	return filepath.Dir(trashDir)
+}
+
+// writeTrashInfo records where a trashed item came from, so file managers can restore it.
+func writeTrashInfo(original, trashed string) error {
+	infoDir := filepath.Join(trashRoot(filepath.Dir(trashed)), "info")
+	if err := os.MkdirAll(infoDir, 0o700); err != nil {
+		return err
+	}
+	escaped := (&url.URL{Path: original}).EscapedPath()
+	content := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n", escaped, time.Now().Format("2006-01-02T15:04:05"))
+	infoPath := filepath.Join(infoDir, filepath.Base(trashed)+".trashinfo")
+	return os.WriteFile(infoPath, []byte(content), 0o600)
+}
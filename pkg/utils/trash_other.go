@@ -0,0 +1,25 @@
+//go:build !darwin && !linux
+
+package utils
+
+import "path/filepath"
+
+// homeTrashDir returns a Trash directory in the home directory on platforms without a native one.
+func homeTrashDir(homeDir string) string {
+	return filepath.Join(homeDir, ".Trash")
+}
+
+// volumeTrashDir returns the Trash directory on another volume.
+func volumeTrashDir(mount string) string {
+	return filepath.Join(mount, ".Trash")
+}
+
+// trashRoot returns the directory that holds everything belonging to trashDir.
+func trashRoot(trashDir string) string {
+	return trashDir
+}
+
+// writeTrashInfo is not needed on this platform.
+func writeTrashInfo(original, trashed string) error {
+	return nil
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ====================================================================================================
+// HUMAN-READABLE UNIT PARSING
+// ====================================================================================================
+
+// byteUnits maps the accepted size suffixes (lower-cased) to their multiplier.
+// SI suffixes (KB, MB, ...) are powers of 1000, IEC suffixes (KiB, MiB, ...) are powers of 1024.
+// Single-letter suffixes (K, M, G, T) follow `du -h` and `ls -h` and are binary as well.
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"k":   1 << 10,
+	"m":   1 << 20,
+	"g":   1 << 30,
+	"t":   1 << 40,
+}
+
+// ParseBytes converts a human-readable size such as "1.5GB", "500 MiB", or "100M" into bytes.
+// Suffixes are case-insensitive, and a number without a suffix is interpreted as bytes.
+//
+// Returns:
+//   - The size in bytes and an error if the value or unit is invalid.
+func ParseBytes(s string) (int64, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	// Split the numeric part from the unit suffix.
+	idx := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	numberPart, unitPart := trimmed, ""
+	if idx >= 0 {
+		numberPart, unitPart = trimmed[:idx], strings.TrimSpace(trimmed[idx:])
+	}
+
+	value, err := strconv.ParseFloat(numberPart, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	multiplier, ok := byteUnits[strings.ToLower(unitPart)]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, unitPart)
+	}
+
+	// math.MaxInt64 rounds up to 2^63 as a float64, which no longer fits in an int64.
+	bytes := value * multiplier
+	if bytes >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q: value too large", s)
+	}
+	return int64(bytes), nil
+}
+
+// durationUnits are the day-based suffixes that time.ParseDuration doesn't understand.
+var durationUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+// ParseDuration extends time.ParseDuration with day ("90d"), week ("2w"), and year ("1y") units.
+// Values made only of standard units (e.g., "36h", "90m") are passed through to time.ParseDuration.
+func ParseDuration(s string) (time.Duration, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(s))
+	if trimmed == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	if len(trimmed) > 1 {
+		if unit, ok := durationUnits[trimmed[len(trimmed)-1:]]; ok {
+			value, err := strconv.ParseFloat(trimmed[:len(trimmed)-1], 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+			}
+			if value < 0 {
+				return 0, fmt.Errorf("invalid duration %q: must not be negative", s)
+			}
+			return time.Duration(value * float64(unit)), nil
+		}
+	}
+
+	duration, err := time.ParseDuration(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	return duration, nil
+}
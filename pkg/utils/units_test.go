@@ -0,0 +1,56 @@
+package utils
+
+import "testing"
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"0", 0},
+		{"512", 512},
+		{"512B", 512},
+		{"1KB", 1000},
+		{"1kb", 1000},
+		{"1KiB", 1024},
+		{"1K", 1024},
+		{"1.5GB", 1_500_000_000},
+		{"1.5GiB", 1_610_612_736},
+		{"500 MiB", 500 << 20},
+		{"100M", 100 << 20},
+		{"2TB", 2_000_000_000_000},
+		{"2TiB", 2 << 40},
+		{" 0.5 k ", 512},
+		{"8388607TiB", 8388607 << 40},
+	}
+	for _, tt := range tests {
+		got, err := ParseBytes(tt.in)
+		if err != nil {
+			t.Errorf("ParseBytes(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBytes(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseBytesRejectsInvalidSizes(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"   ",
+		"GB",
+		"1.2.3GB",
+		"-1GB",
+		"10XB",
+		"10 bytes",
+		"1e3",
+		"9223372036854775807", // rounds up to 2^63 as a float64
+		"8388608TiB",          // exactly 2^63
+		"10000000000TB",
+	} {
+		if got, err := ParseBytes(in); err == nil {
+			t.Errorf("ParseBytes(%q) = %d, want an error", in, got)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ====================================================================================================
+// VOLUME UTILITY FUNCTIONS
+// ====================================================================================================
+
+// VolumeSpace reports the capacity and free space of the filesystem containing path.
+//
+// Parameters:
+//   - path: Any path on the volume to inspect (usually its mount point).
+//
+// Returns:
+//   - The free bytes available to the current user, the total bytes of the volume, and an error, if any.
+func VolumeSpace(path string) (int64, int64, error) {
+	return filesystemSpace(path)
+}
+
+// IsMountPoint checks whether path is the root of a mounted filesystem.
+// A directory is considered a mount point when it lives on a different device than its parent,
+// or when it is the filesystem root itself.
+func IsMountPoint(path string) (bool, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve absolute path for %s: %w", path, err)
+	}
+	if absPath == string(os.PathSeparator) {
+		return true, nil
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to get info for %s: %w", absPath, err)
+	}
+	parentInfo, err := os.Stat(filepath.Dir(absPath))
+	if err != nil {
+		return false, fmt.Errorf("failed to get info for parent of %s: %w", absPath, err)
+	}
+
+	dev, ok := deviceID(info)
+	parentDev, parentOk := deviceID(parentInfo)
+	if !ok || !parentOk {
+		return false, fmt.Errorf("could not read device information for %s", absPath)
+	}
+	return dev != parentDev, nil
+}
+
+// ValidateVolume ensures that the given path exists, is a directory, and is a mount point,
+// so it can safely be used as the root of a per-volume cleanup.
+func ValidateVolume(path string) (string, error) {
+	absPath, err := filepath.Abs(ExpandPath(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve volume path %s: %w", path, err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", fmt.Errorf("volume %s is not accessible: %w", absPath, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("volume %s is not a directory", absPath)
+	}
+
+	mounted, err := IsMountPoint(absPath)
+	if err != nil {
+		return "", err
+	}
+	if !mounted {
+		return "", fmt.Errorf("%s is not the mount point of a volume", absPath)
+	}
+	return absPath, nil
+}
+
+// OtherVolumeMount returns the mount point of the volume path lives on if that is not the volume
+// of ref, so a move from path to ref would have to copy, or "" if both are on the same volume or
+// the platform doesn't tell volumes apart.
+func OtherVolumeMount(path string, ref string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+	refInfo, err := os.Stat(ref)
+	if err != nil {
+		return "", err
+	}
+	dev, ok := deviceID(info)
+	refDev, refOk := deviceID(refInfo)
+	if !ok || !refOk || dev == refDev {
+		return "", nil
+	}
+	return mountPointOf(filepath.Dir(path), dev)
+}
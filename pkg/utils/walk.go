@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kodelint/wiper/pkg/logger"
+)
+
+// ====================================================================================================
+// PARALLEL DIRECTORY WALK
+// ====================================================================================================
+
+// WalkParallel walks the file tree rooted at root like filepath.Walk, but reads directories
+// concurrently using the shared worker pool (see SetJobs). Symbolic links are reported, not followed.
+//
+// fn is never called concurrently, so it may update shared state without locking. Directories
+// are still visited before their contents, but entries of different directories are interleaved,
+// so callers that need a stable order must sort their results. Returning filepath.SkipDir for a
+// directory skips its contents, filepath.SkipAll ends the walk, and any other error ends the walk
+// and is returned. Network volumes mounted below root are reported but not entered, unless
+// SetScanNetworkVolumes enabled them: listing a file server can take minutes.
+//
+// Parameters:
+//   - root: The directory (or file) to walk.
+//   - fn: Called for every entry; see filepath.WalkFunc.
+//
+// Returns:
+//   - The first error returned by fn other than SkipDir and SkipAll, or nil.
+func WalkParallel(root string, fn filepath.WalkFunc) error {
+	w := &parallelWalker{fn: fn, skipMounts: networkMountsBelow(root)}
+	info, err := os.Lstat(root)
+	if err != nil {
+		w.call(root, nil, err)
+	} else if w.call(root, info, nil) == nil && info.IsDir() {
+		w.walkDir(root, info)
+	}
+	w.wg.Wait()
+	return w.err
+}
+
+// parallelWalker holds the state of one WalkParallel call.
+type parallelWalker struct {
+	fn filepath.WalkFunc
+	wg sync.WaitGroup
+	// skipMounts are the mount points of the network volumes that aren't entered.
+	skipMounts map[string]bool
+
+	// mu serializes calls to fn and guards the fields below.
+	mu      sync.Mutex
+	stopped bool
+	err     error
+}
+
+// call invokes fn for one entry and records whether the walk must stop.
+// It returns fn's result, or SkipAll once the walk has been stopped.
+func (w *parallelWalker) call(path string, info fs.FileInfo, err error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return filepath.SkipAll
+	}
+	result := w.fn(path, info, err)
+	switch result {
+	case nil, filepath.SkipDir:
+	case filepath.SkipAll:
+		w.stopped = true
+	default:
+		w.stopped = true
+		w.err = result
+	}
+	return result
+}
+
+// walkDir reports the entries of dir and walks its subdirectories, in worker goroutines where possible.
+func (w *parallelWalker) walkDir(dir string, info fs.FileInfo) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// Like filepath.Walk, a directory that can't be read is reported a second time with the error.
+		w.call(dir, info, err)
+		return
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		// ReadDir entries report Lstat information, so links are reported, not followed.
+		entryInfo, err := entry.Info()
+		if err != nil {
+			if w.call(path, nil, err) == filepath.SkipAll {
+				return
+			}
+			continue
+		}
+		switch w.call(path, entryInfo, nil) {
+		case nil:
+			if entryInfo.IsDir() && w.skipMounts[path] {
+				logger.Log.Infof("Skipping network volume %s (use --network-volumes to scan it)", path)
+			} else if entryInfo.IsDir() {
+				runWorker(&w.wg, func() { w.walkDir(path, entryInfo) })
+			}
+		case filepath.SkipDir:
+			if !entryInfo.IsDir() {
+				return // Like filepath.Walk, SkipDir on a file skips the rest of its directory
+			}
+		default:
+			return // SkipAll or an error ends the walk
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ====================================================================================================
+// WORKER POOL
+// ====================================================================================================
+
+// workerSlots bounds the number of extra goroutines scanning the filesystem at any time.
+// It is shared by all scans and size calculations, so nested or simultaneous work can't multiply
+// the bound. Work that can't get a slot runs inline in the calling goroutine, so the pool never
+// blocks and deep trees can't deadlock it.
+var workerSlots = make(chan struct{}, DefaultJobs()-1)
+
+// DefaultJobs returns the number of concurrent filesystem workers used unless configured otherwise.
+// Scanning is dominated by waiting for the disk rather than the CPU, so it is a multiple of the CPU count.
+func DefaultJobs() int {
+	return 4 * runtime.NumCPU()
+}
+
+// SetJobs sets how many goroutines may scan the filesystem concurrently, including the caller's.
+// 1 scans serially, and 0 or less restores DefaultJobs. It must be called before any scan starts.
+func SetJobs(jobs int) {
+	if jobs <= 0 {
+		jobs = DefaultJobs()
+	}
+	workerSlots = make(chan struct{}, jobs-1)
+}
+
+// Jobs returns the number of goroutines that may scan the filesystem concurrently.
+func Jobs() int {
+	return cap(workerSlots) + 1
+}
+
+// runWorker runs fn in a new goroutine tracked by wg if a worker slot is free, and inline otherwise.
+func runWorker(wg *sync.WaitGroup, fn func()) {
+	select {
+	case workerSlots <- struct{}{}:
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-workerSlots }()
+			fn()
+		}()
+	default:
+		fn()
+	}
+}
+
+// ParallelFor calls fn for every index from 0 to n-1, using the shared worker pool, and returns
+// once all calls have finished. Calls may run concurrently and in any order, so fn must only
+// write to state owned by its index (e.g., results[i]).
+func ParallelFor(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		runWorker(&wg, func() { fn(i) })
+	}
+	wg.Wait()
+}
@@ -0,0 +1,8 @@
+//go:build linux
+
+package utils
+
+import "golang.org/x/sys/unix"
+
+// errNoXattr is returned by getxattr(2) when the attribute is not set (ENOATTR is ENODATA on Linux).
+const errNoXattr = unix.ENODATA
@@ -0,0 +1,8 @@
+//go:build !darwin && !linux
+
+package utils
+
+// readXattr is not available on this platform; no attributes are ever reported.
+func readXattr(path, name string) ([]byte, error) {
+	return nil, nil
+}
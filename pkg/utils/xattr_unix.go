@@ -0,0 +1,77 @@
+//go:build darwin || linux
+
+package utils
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// readXattr returns the value of the extended attribute name on path, without following a
+// final symbolic link. It returns nil without an error if the attribute is not set or the
+// filesystem doesn't support extended attributes.
+func readXattr(path, name string) ([]byte, error) {
+	for {
+		size, err := unix.Lgetxattr(path, name, nil)
+		if err != nil {
+			return nil, ignoreMissingXattr(err)
+		}
+		if size == 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size)
+		n, err := unix.Lgetxattr(path, name, buf)
+		if errors.Is(err, unix.ERANGE) {
+			// The attribute grew between the two calls; try again with the new size.
+			continue
+		}
+		if err != nil {
+			return nil, ignoreMissingXattr(err)
+		}
+		return buf[:n], nil
+	}
+}
+
+// ignoreMissingXattr turns the errors for an absent attribute or an unsupported filesystem into nil.
+func ignoreMissingXattr(err error) error {
+	if errors.Is(err, errNoXattr) || errors.Is(err, unix.ENOTSUP) {
+		return nil
+	}
+	return err
+}
+
+// listXattrs returns the names of the extended attributes set on path, without following a
+// final symbolic link.
+func listXattrs(path string) ([]string, error) {
+	for {
+		size, err := unix.Llistxattr(path, nil)
+		if err != nil {
+			return nil, ignoreMissingXattr(err)
+		}
+		if size == 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size)
+		n, err := unix.Llistxattr(path, buf)
+		if errors.Is(err, unix.ERANGE) {
+			continue
+		}
+		if err != nil {
+			return nil, ignoreMissingXattr(err)
+		}
+		var names []string
+		for _, name := range strings.Split(string(buf[:n]), "\x00") {
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+		return names, nil
+	}
+}
+
+// writeXattr sets the extended attribute name on path, without following a final symbolic link.
+func writeXattr(path, name string, value []byte) error {
+	return unix.Lsetxattr(path, name, value, 0)
+}